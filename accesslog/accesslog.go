@@ -0,0 +1,157 @@
+// Package accesslog provides middleware which logs each request a router handles as a line of
+// JSON, so requests can be searched and aggregated with CloudWatch Logs Insights without handlers
+// logging them themselves.
+package accesslog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/mitchell/lambdarouter"
+	"github.com/mitchell/lambdarouter/internal/header"
+)
+
+// Field is a member of the entries of the access log.
+type Field string
+
+// The fields an access log entry may hold.
+const (
+	// Method is the method of the request.
+	Method Field = "method"
+	// Route is the path template of the route which handled the request, such as /users/{id}.
+	Route Field = "route"
+	// Path is the path requested.
+	Path Field = "path"
+	// Status is the status code of the response.
+	Status Field = "status"
+	// Latency is the time taken to handle the request, in milliseconds.
+	Latency Field = "latency_ms"
+	// RequestID is the ID of the invocation of the function.
+	RequestID Field = "request_id"
+	// SourceIP is the address of the client.
+	SourceIP Field = "source_ip"
+	// UserAgent is the User-Agent header of the request.
+	UserAgent Field = "user_agent"
+	// Error is the message of the error returned by the handler, if any.
+	Error Field = "error"
+)
+
+// DefaultFields are the fields logged if a Config has none.
+var DefaultFields = []Field{Method, Route, Path, Status, Latency, RequestID, SourceIP, UserAgent, Error}
+
+// Config configures the access logging middleware.
+type Config struct {
+	// Logger receives a line of JSON for each logged request. If nil, lines are written to
+	// standard output, which Lambda sends to CloudWatch Logs.
+	Logger lambdarouter.Logger
+
+	// Fields are the fields logged. If empty, they are DefaultFields.
+	Fields []Field
+
+	// SampleRate is the fraction of requests logged, between 0 and 1. Requests whose response has a
+	// 5xx status, including those whose handler returns an error other than an HTTPError, are
+	// always logged. If zero, every request is logged.
+	SampleRate float64
+}
+
+// Middleware returns middleware which logs the requests of the routes it is used by, after their
+// handlers return, as entries holding cfg.Fields. Fields with no value for a request, such as the
+// error of a request which succeeded, are omitted.
+func Middleware(cfg Config) lambdarouter.Middleware {
+	if cfg.Logger == nil {
+		cfg.Logger = log.New(os.Stdout, "", 0)
+	}
+	if len(cfg.Fields) == 0 {
+		cfg.Fields = DefaultFields
+	}
+	if cfg.SampleRate <= 0 || cfg.SampleRate > 1 {
+		cfg.SampleRate = 1
+	}
+
+	return func(next lambda.Handler) lambda.Handler {
+		return logger{cfg: cfg, next: next}
+	}
+}
+
+type logger struct {
+	cfg  Config
+	next lambda.Handler
+}
+
+func (l logger) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	start := time.Now()
+	res, err := l.next.Invoke(ctx, payload)
+	latency := time.Since(start)
+
+	status := http.StatusInternalServerError
+	if err != nil {
+		var httpErr *lambdarouter.HTTPError
+		if errors.As(err, &httpErr) {
+			status = httpErr.Status
+		}
+	} else {
+		var out struct {
+			StatusCode int `json:"statusCode"`
+		}
+		status = http.StatusOK
+		if lambdarouter.CodecFrom(ctx).Unmarshal(res, &out) == nil && out.StatusCode != 0 {
+			status = out.StatusCode
+		}
+	}
+
+	sampled := l.cfg.SampleRate == 1 || rand.Float64() < l.cfg.SampleRate
+	if !sampled && status < http.StatusInternalServerError {
+		return res, err
+	}
+
+	req, _ := lambdarouter.RequestFrom(ctx, payload)
+	rt, _ := lambdarouter.RouteFromContext(ctx)
+
+	entry := map[Field]interface{}{}
+	for _, field := range l.cfg.Fields {
+		var value interface{}
+
+		switch field {
+		case Method:
+			value = req.HTTPMethod
+		case Route:
+			value = rt.Path
+		case Path:
+			value = req.Path
+		case Status:
+			value = status
+		case Latency:
+			value = float64(latency) / float64(time.Millisecond)
+		case RequestID:
+			if lc, ok := lambdacontext.FromContext(ctx); ok {
+				value = lc.AwsRequestID
+			}
+		case SourceIP:
+			value = req.RequestContext.Identity.SourceIP
+		case UserAgent:
+			value = header.Get(req, "User-Agent")
+		case Error:
+			if err != nil {
+				value = err.Error()
+			}
+		}
+
+		if value != nil && value != "" {
+			entry[field] = value
+		}
+	}
+
+	if line, jsonErr := json.Marshal(entry); jsonErr == nil {
+		l.cfg.Logger.Printf("%s", line)
+	}
+
+	return res, err
+}
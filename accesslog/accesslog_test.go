@@ -0,0 +1,97 @@
+package accesslog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+type lines []string
+
+func (l *lines) Printf(format string, v ...interface{}) {
+	*l = append(*l, fmt.Sprintf(format, v...))
+}
+
+func TestMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Routers with access logging middleware and")
+	route := func(cfg Config) lambdarouter.Router {
+		r := lambdarouter.New("")
+		r.Use(Middleware(cfg))
+		r.Get("users/{id}", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+		}))
+		r.Get("broken", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+			return events.APIGatewayProxyResponse{}, errors.New("broken")
+		}))
+		return r
+	}
+	invoke := func(r lambdarouter.Router, path string) {
+		req := events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodGet,
+			Path:       path,
+			Headers:    map[string]string{"user-agent": "test"},
+		}
+		req.RequestContext.Identity.SourceIP = "203.0.113.7"
+		payload, _ := json.Marshal(req)
+		ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "req-1"})
+		_, _ = r.Invoke(ctx, payload)
+	}
+	entry := func(line string) map[string]interface{} {
+		var e map[string]interface{}
+		a.NoError(json.Unmarshal([]byte(line), &e))
+		return e
+	}
+
+	desc(t, 2, "Middleware should")
+	{
+		desc(t, 4, "log the default fields of requests")
+		var log lines
+		r := route(Config{Logger: &log})
+		invoke(r, "/users/42")
+		a.Len(log, 1)
+		e := entry(log[0])
+		a.Exactly("GET", e["method"])
+		a.Exactly("/users/{id}", e["route"])
+		a.Exactly("/users/42", e["path"])
+		a.Exactly(float64(200), e["status"])
+		a.Exactly("req-1", e["request_id"])
+		a.Exactly("203.0.113.7", e["source_ip"])
+		a.Exactly("test", e["user_agent"])
+		a.Contains(e, "latency_ms")
+		a.NotContains(e, "error")
+
+		desc(t, 4, "log only the configured fields")
+		log = nil
+		r = route(Config{Logger: &log, Fields: []Field{Route, Status, Error}})
+		invoke(r, "/broken")
+		a.Len(log, 1)
+		a.Exactly(map[string]interface{}{"route": "/broken", "status": float64(500), "error": "broken"}, entry(log[0]))
+
+		desc(t, 4, "sample successful requests, but log all failed ones")
+		log = nil
+		r = route(Config{Logger: &log, SampleRate: 1e-12})
+		invoke(r, "/users/42")
+		a.Len(log, 0)
+		invoke(r, "/broken")
+		a.Len(log, 1)
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
@@ -0,0 +1,100 @@
+// Package appconfig provides a lambdarouter.FlagProvider which takes feature flags from AWS
+// AppConfig, through the AppConfig Lambda extension, so routes gated by
+// lambdarouter.WithFeatureFlag can be enabled by deploying a configuration rather than the
+// function.
+package appconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Flags is a lambdarouter.FlagProvider holding the flags of an AppConfig feature flag
+// configuration profile. Flags are fetched from the extension when they are first needed, and
+// again once they are older than the TTL. Flags which cannot be fetched are disabled until they
+// first can be, after which the last flags fetched are used until fetching succeeds again.
+type Flags struct {
+	// HTTPClient sends requests to the extension. If nil, a client with a timeout of a second is
+	// used.
+	HTTPClient *http.Client
+
+	// TTL is how long flags are used before they are fetched again. The extension caches them
+	// itself, so this only saves the requests to it. If zero, it is 15 seconds.
+	TTL time.Duration
+
+	endpoint string
+
+	mu      sync.Mutex
+	flags   map[string]flag
+	fetched time.Time
+}
+
+type flag struct {
+	Enabled bool `json:"enabled"`
+}
+
+// New returns the flags of the feature flag profile of an application in an environment, which
+// may each be given by name or ID. The extension is reached at the port of the
+// AWS_APPCONFIG_EXTENSION_HTTP_PORT environment variable, or 2772.
+func New(application, environment, profile string) *Flags {
+	port := os.Getenv("AWS_APPCONFIG_EXTENSION_HTTP_PORT")
+	if port == "" {
+		port = "2772"
+	}
+
+	return &Flags{
+		endpoint: fmt.Sprintf("http://localhost:%s/applications/%s/environments/%s/configurations/%s",
+			port, url.PathEscape(application), url.PathEscape(environment), url.PathEscape(profile)),
+	}
+}
+
+// FlagEnabled implements the lambdarouter.FlagProvider interface for the Flags type.
+func (f *Flags) FlagEnabled(name string, _ events.APIGatewayProxyRequest) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ttl := f.TTL
+	if ttl == 0 {
+		ttl = 15 * time.Second
+	}
+
+	if time.Since(f.fetched) >= ttl {
+		if flags, err := f.fetch(); err == nil {
+			f.flags, f.fetched = flags, time.Now()
+		}
+	}
+
+	return f.flags[name].Enabled
+}
+
+// fetch requests the flags from the extension.
+func (f *Flags) fetch() (map[string]flag, error) {
+	client := f.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: time.Second}
+	}
+
+	res, err := client.Get(f.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("appconfig: %s", res.Status)
+	}
+
+	var flags map[string]flag
+	if err := json.NewDecoder(res.Body).Decode(&flags); err != nil {
+		return nil, err
+	}
+
+	return flags, nil
+}
@@ -0,0 +1,62 @@
+package appconfig
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlags(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a fake AppConfig extension and")
+	var path string
+	requests := 0
+	failing := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		requests++
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"new-checkout": {"enabled": true}, "dark-mode": {"enabled": false}}`))
+	}))
+	defer srv.Close()
+
+	t.Setenv("AWS_APPCONFIG_EXTENSION_HTTP_PORT", srv.URL[len("http://127.0.0.1:"):])
+	f := New("shop", "prod", "flags")
+	req := events.APIGatewayProxyRequest{}
+
+	desc(t, 2, "Flags type should")
+	{
+		desc(t, 4, "fetch the flags of the profile from the extension")
+		a.True(f.FlagEnabled("new-checkout", req))
+		a.Exactly("/applications/shop/environments/prod/configurations/flags", path)
+
+		desc(t, 4, "disable flags which are disabled or missing")
+		a.False(f.FlagEnabled("dark-mode", req))
+		a.False(f.FlagEnabled("unknown", req))
+
+		desc(t, 4, "reuse flags until they expire")
+		a.Exactly(1, requests)
+
+		desc(t, 4, "keep the last flags when fetching fails")
+		failing = true
+		f.fetched = f.fetched.Add(-f.TTL - 15e9)
+		a.True(f.FlagEnabled("new-checkout", req))
+		a.Exactly(2, requests)
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
@@ -0,0 +1,94 @@
+package lambdarouter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+type appSyncRoute struct {
+	typeName  string
+	fieldName string
+	h         lambda.Handler
+}
+
+// appSyncInfo holds the fields of the info object of AppSync resolver events which routes match.
+type appSyncInfo struct {
+	FieldName      string `json:"fieldName"`
+	ParentTypeName string `json:"parentTypeName"`
+}
+
+// AppSync adds a route for AppSync direct Lambda resolvers, so one function can resolve many
+// fields of a GraphQL schema. The typeName and fieldName parameters are patterns matched against
+// the type and field being resolved, such as Query and getPost, in which * matches any run of
+// characters, and which match anything if empty. The handler parameter is invoked with the
+// resolver event, which holds the arguments, identity, source, and info of the field, and its
+// response is the value of the field. It is wrapped by the middleware of the router, which should
+// not assume it handles HTTP requests.
+//
+// Events are routed to the first route which matches them in the order they were defined. Batched
+// invocations, made by resolvers with batching enabled, invoke the handler of each event in turn
+// and respond with the list of their responses, failing if any handler fails. Invocations with
+// events which match no route fail.
+func (r *Router) AppSync(typeName, fieldName string, handler lambda.Handler) {
+	rt := appSyncRoute{typeName: typeName, fieldName: fieldName, h: r.sourceHandler(handler)}
+
+	r.addSource(func(s *sourceRoutes) {
+		s.appSync = append(s.appSync[:len(s.appSync):len(s.appSync)], rt)
+	})
+}
+
+// invokeAppSyncBatch routes payload if it is a batch of AppSync resolver events, which is reported
+// by the second return value.
+func (r Router) invokeAppSyncBatch(ctx context.Context, routes []appSyncRoute, payload []byte) ([]byte, bool, error) {
+	codec := r.jsonCodec()
+
+	if trimmed := bytes.TrimSpace(payload); len(trimmed) == 0 || trimmed[0] != '[' {
+		return nil, false, nil
+	}
+
+	var batch []json.RawMessage
+	if err := codec.Unmarshal(payload, &batch); err != nil {
+		return nil, false, nil
+	}
+
+	infos := make([]appSyncInfo, len(batch))
+	for i, raw := range batch {
+		var e struct {
+			Info *appSyncInfo `json:"info"`
+		}
+		if err := codec.Unmarshal(raw, &e); err != nil || e.Info == nil {
+			return nil, false, nil
+		}
+		infos[i] = *e.Info
+	}
+
+	results := make([]json.RawMessage, len(batch))
+	for i, raw := range batch {
+		res, err := r.invokeAppSync(ctx, routes, infos[i], raw)
+		if err != nil {
+			return nil, true, err
+		}
+		results[i] = append(json.RawMessage(nil), res...)
+	}
+
+	res, err := codec.Marshal(results)
+	return res, true, err
+}
+
+func (r Router) invokeAppSync(ctx context.Context, routes []appSyncRoute, info appSyncInfo, payload []byte) ([]byte, error) {
+	for _, rt := range routes {
+		if matchPattern(rt.typeName, info.ParentTypeName) && matchPattern(rt.fieldName, info.FieldName) {
+			res, err := rt.h.Invoke(ctx, payload)
+			if err != nil {
+				r.logf("AppSync %s.%s: %v", info.ParentTypeName, info.FieldName, err)
+			}
+			return res, err
+		}
+	}
+
+	return nil, fmt.Errorf("no route for field %s.%s", info.ParentTypeName, info.FieldName)
+}
@@ -0,0 +1,73 @@
+package lambdarouter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppSync(t *testing.T) {
+	a := assert.New(t)
+
+	type post struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	}
+	type getPost struct {
+		Arguments struct {
+			ID string `json:"id"`
+		} `json:"arguments"`
+	}
+	type postAuthor struct {
+		Source post `json:"source"`
+	}
+
+	desc(t, 0, "Initialize Router with AppSync routes and")
+	r := New("prefix")
+	r.AppSync("Query", "getPost", lambda.NewHandler(func(e getPost) (post, error) {
+		if e.Arguments.ID == "" {
+			return post{}, errors.New("missing id")
+		}
+		return post{ID: e.Arguments.ID, Title: "Hello"}, nil
+	}))
+	r.AppSync("Post", "author", lambda.NewHandler(func(e postAuthor) (string, error) {
+		return "author of " + e.Source.ID, nil
+	}))
+
+	invoke := func(payload string) (string, error) {
+		res, err := r.Invoke(context.Background(), []byte(payload))
+		return string(res), err
+	}
+
+	desc(t, 2, "AppSync method should")
+	{
+		desc(t, 4, "route resolver events by type and field")
+		res, err := invoke(`{"arguments": {"id": "1"}, "source": null, "info": {"parentTypeName": "Query", "fieldName": "getPost"}}`)
+		a.NoError(err)
+		a.JSONEq(`{"id": "1", "title": "Hello"}`, res)
+
+		desc(t, 4, "route events whose source is an object")
+		res, err = invoke(`{"source": {"id": "1"}, "info": {"parentTypeName": "Post", "fieldName": "author"}}`)
+		a.NoError(err)
+		a.Exactly(`"author of 1"`, res)
+
+		desc(t, 4, "respond to batches with the list of responses")
+		res, err = invoke(`[
+			{"source": {"id": "1"}, "info": {"parentTypeName": "Post", "fieldName": "author"}},
+			{"source": {"id": "2"}, "info": {"parentTypeName": "Post", "fieldName": "author"}}
+		]`)
+		a.NoError(err)
+		a.Exactly(`["author of 1","author of 2"]`, res)
+
+		desc(t, 4, "fail batches when a handler fails")
+		_, err = invoke(`[{"arguments": {}, "info": {"parentTypeName": "Query", "fieldName": "getPost"}}]`)
+		a.EqualError(err, "missing id")
+
+		desc(t, 4, "fail for fields which have no route")
+		_, err = invoke(`{"info": {"parentTypeName": "Mutation", "fieldName": "createPost"}}`)
+		a.EqualError(err, "no route for field Mutation.createPost")
+	}
+}
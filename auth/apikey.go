@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/mitchell/lambdarouter/internal/header"
+)
+
+// APIKey describes the caller an API key was issued to.
+type APIKey struct {
+	// Name identifies the key without revealing it, for use in logs and authorization decisions.
+	Name string
+
+	// Metadata holds any other details the store keeps about the key, such as its owner or plan.
+	Metadata map[string]string
+}
+
+// KeyStore looks up API keys. Keys kept in a database or secret store, such as DynamoDB or
+// Secrets Manager, can be validated with a KeyStoreFunc which queries it.
+type KeyStore interface {
+	// Lookup returns the details of key. The found return value reports whether the key is valid.
+	// An error should only be returned if the store could not be queried.
+	Lookup(ctx context.Context, key string) (k APIKey, found bool, err error)
+}
+
+// KeyStoreFunc adapts a function to the KeyStore interface.
+type KeyStoreFunc func(ctx context.Context, key string) (APIKey, bool, error)
+
+// Lookup implements the KeyStore interface for the KeyStoreFunc type.
+func (f KeyStoreFunc) Lookup(ctx context.Context, key string) (APIKey, bool, error) {
+	return f(ctx, key)
+}
+
+// StaticKeys returns a KeyStore holding keys, which maps each API key to its details.
+func StaticKeys(keys map[string]APIKey) KeyStore {
+	return KeyStoreFunc(func(_ context.Context, key string) (APIKey, bool, error) {
+		k, ok := keys[key]
+		return k, ok, nil
+	})
+}
+
+// EnvKeys returns a KeyStore holding the comma separated API keys in the environment variable
+// name, as read when EnvKeys is called. Each key is named by its position in the list, as in
+// "key-1", so that keys are not logged.
+func EnvKeys(name string) KeyStore {
+	keys := map[string]APIKey{}
+
+	n := 0
+	for _, key := range strings.Split(os.Getenv(name), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			n++
+			keys[key] = APIKey{Name: "key-" + strconv.Itoa(n)}
+		}
+	}
+
+	return StaticKeys(keys)
+}
+
+// APIKeyFromContext returns the details of the API key which authenticated the current
+// invocation. The second return value reports whether the invocation was authenticated by the
+// APIKeys middleware.
+func APIKeyFromContext(ctx context.Context) (APIKey, bool) {
+	k, ok := ctx.Value(apiKeyKey).(APIKey)
+	return k, ok
+}
+
+// APIKeys returns middleware which authenticates requests by their API key, taken from the
+// x-api-key header, or from requestContext.identity.apiKey when API Gateway validated it. Keys
+// are validated against store, and the details of valid keys are placed in the context of the
+// handler, from which they can be retrieved with APIKeyFromContext. Requests without a valid key
+// are responded to with a 401, and the handler is not invoked. Errors returned by the store fail
+// the invocation.
+func APIKeys(store KeyStore) lambdarouter.Middleware {
+	return func(next lambda.Handler) lambda.Handler {
+		return apiKeyAuthenticator{store: store, next: next}
+	}
+}
+
+type apiKeyAuthenticator struct {
+	store KeyStore
+	next  lambda.Handler
+}
+
+func (aa apiKeyAuthenticator) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := lambdarouter.RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	const challenge = `APIKey header="x-api-key"`
+
+	key := header.Get(req, "X-Api-Key")
+	if key == "" {
+		key = req.RequestContext.Identity.APIKey
+	}
+	if key == "" {
+		return nil, unauthorized(challenge, "missing API key")
+	}
+
+	k, found, err := aa.store.Lookup(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, unauthorized(challenge, "invalid API key")
+	}
+
+	return aa.next.Invoke(context.WithValue(ctx, apiKeyKey, k), payload)
+}
@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeys(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with API key middleware and")
+	r := lambdarouter.New("prefix")
+	r.Group("partners", func(r *lambdarouter.Router) {
+		r.Use(APIKeys(StaticKeys(map[string]APIKey{
+			"k1": {Name: "acme", Metadata: map[string]string{"plan": "gold"}},
+		})))
+		r.Get("plan", lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+			k, _ := APIKeyFromContext(ctx)
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: k.Name + " " + k.Metadata["plan"]}, nil
+		}))
+	})
+	r.Group("broken", func(r *lambdarouter.Router) {
+		r.Use(APIKeys(KeyStoreFunc(func(context.Context, string) (APIKey, bool, error) {
+			return APIKey{}, false, errors.New("store unavailable")
+		})))
+		r.Get("plan", lambda.NewHandler(func() {}))
+	})
+
+	invoke := func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		req.HTTPMethod = http.MethodGet
+		payload, _ := json.Marshal(req)
+
+		var res events.APIGatewayProxyResponse
+		resjson, err := r.Invoke(context.Background(), payload)
+		if err == nil {
+			a.NoError(json.Unmarshal(resjson, &res))
+		}
+		return res, err
+	}
+
+	desc(t, 2, "APIKeys middleware should")
+	{
+		desc(t, 4, "place the details of valid keys in the context")
+		res, err := invoke(events.APIGatewayProxyRequest{
+			Path:    "/prefix/partners/plan",
+			Headers: map[string]string{"x-api-key": "k1"},
+		})
+		a.NoError(err)
+		a.Exactly("acme gold", res.Body)
+
+		desc(t, 4, "take the key from the identity of the request context")
+		req := events.APIGatewayProxyRequest{Path: "/prefix/partners/plan"}
+		req.RequestContext.Identity.APIKey = "k1"
+		res, err = invoke(req)
+		a.NoError(err)
+		a.Exactly(http.StatusOK, res.StatusCode)
+
+		desc(t, 4, "respond with a 401 for missing or unknown keys")
+		res, err = invoke(events.APIGatewayProxyRequest{Path: "/prefix/partners/plan"})
+		a.NoError(err)
+		a.Exactly(http.StatusUnauthorized, res.StatusCode)
+		a.Exactly(`APIKey header="x-api-key"`, res.Headers["WWW-Authenticate"])
+
+		res, err = invoke(events.APIGatewayProxyRequest{
+			Path:    "/prefix/partners/plan",
+			Headers: map[string]string{"X-API-Key": "k2"},
+		})
+		a.NoError(err)
+		a.Exactly(http.StatusUnauthorized, res.StatusCode)
+
+		desc(t, 4, "fail the invocation when the store fails")
+		_, err = invoke(events.APIGatewayProxyRequest{
+			Path:    "/prefix/broken/plan",
+			Headers: map[string]string{"x-api-key": "k1"},
+		})
+		a.EqualError(err, "store unavailable")
+	}
+
+	desc(t, 2, "EnvKeys function should")
+	{
+		desc(t, 4, "hold the keys listed in the environment variable")
+		os.Setenv("LAMBDAROUTER_TEST_KEYS", "a, b")
+		defer os.Unsetenv("LAMBDAROUTER_TEST_KEYS")
+
+		k, found, err := EnvKeys("LAMBDAROUTER_TEST_KEYS").Lookup(context.Background(), "b")
+		a.NoError(err)
+		a.True(found)
+		a.Exactly("key-2", k.Name)
+	}
+}
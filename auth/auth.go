@@ -0,0 +1,36 @@
+// Package auth provides middleware which authenticates the requests of routes and makes the
+// identity of the caller available to their handlers, for functions fronted by API Gateway
+// without an authorizer, or which need to act on the result of one.
+package auth
+
+import (
+	"net/http"
+
+	"github.com/mitchell/lambdarouter"
+)
+
+type contextKey int
+
+const (
+	claimsKey contextKey = iota
+	authorizerKey
+	apiKeyKey
+	basicUserKey
+)
+
+// unauthorized returns the error responded with when a request does not carry valid credentials.
+// The challenge parameter is the value of the WWW-Authenticate header, which tells the client how
+// to authenticate.
+func unauthorized(challenge, detail string) error {
+	return &lambdarouter.HTTPError{
+		Status:  http.StatusUnauthorized,
+		Detail:  detail,
+		Headers: map[string]string{"WWW-Authenticate": challenge},
+	}
+}
+
+// forbidden returns the error responded with when a request is authenticated, but its caller is
+// not permitted to use the route.
+func forbidden(detail string) error {
+	return &lambdarouter.HTTPError{Status: http.StatusForbidden, Detail: detail}
+}
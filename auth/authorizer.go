@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+)
+
+// BindAuthorizer returns middleware which decodes the context returned by a custom Lambda
+// authorizer, found in requestContext.authorizer, into a T and places it in the context of the
+// handler, from which it can be retrieved with AuthorizerFrom. The context is decoded as JSON, so
+// the fields of T may be tagged as for encoding/json. API Gateway passes the values of a REST API
+// authorizer context as strings, so the fields they populate should be strings as well. Requests
+// without an authorizer context are responded to with a 401.
+func BindAuthorizer[T any]() lambdarouter.Middleware {
+	return func(next lambda.Handler) lambda.Handler {
+		return authorizerBinder[T]{next: next}
+	}
+}
+
+type authorizerBinder[T any] struct {
+	next lambda.Handler
+}
+
+func (ab authorizerBinder[T]) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := lambdarouter.RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.RequestContext.Authorizer) == 0 {
+		return nil, unauthorized("Bearer", "missing authorizer context")
+	}
+
+	b, err := json.Marshal(req.RequestContext.Authorizer)
+	if err != nil {
+		return nil, err
+	}
+
+	var v T
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, fmt.Errorf("decoding authorizer context: %w", err)
+	}
+
+	return ab.next.Invoke(context.WithValue(ctx, authorizerKey, v), payload)
+}
+
+// AuthorizerFrom returns the authorizer context decoded by BindAuthorizer for the current
+// invocation. The second return value reports whether it was decoded into a T.
+func AuthorizerFrom[T any](ctx context.Context) (T, bool) {
+	v, ok := ctx.Value(authorizerKey).(T)
+	return v, ok
+}
+
+// RequireAuthorizerKeys returns a route option which responds with a 403 to requests whose
+// authorizer context lacks any of keys, so routes can demand the entries their handlers depend on,
+// such as a tenant ID. Requests without an authorizer context are responded to with a 401.
+func RequireAuthorizerKeys(keys ...string) lambdarouter.RouteOption {
+	return lambdarouter.WithMiddleware(func(next lambda.Handler) lambda.Handler {
+		return authorizerKeyRequirement{keys: keys, next: next}
+	})
+}
+
+type authorizerKeyRequirement struct {
+	keys []string
+	next lambda.Handler
+}
+
+func (ar authorizerKeyRequirement) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := lambdarouter.RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	authorizer := req.RequestContext.Authorizer
+	if len(authorizer) == 0 {
+		return nil, unauthorized("Bearer", "missing authorizer context")
+	}
+
+	var missing []string
+	for _, key := range ar.keys {
+		if v, ok := authorizer[key]; !ok || v == nil || v == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, forbidden("authorizer context is missing " + strings.Join(missing, ", "))
+	}
+
+	return ar.next.Invoke(ctx, payload)
+}
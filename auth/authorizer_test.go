@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantContext struct {
+	TenantID string `json:"tenantId"`
+	Role     string `json:"role"`
+}
+
+func TestAuthorizer(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with authorizer binding and")
+	r := lambdarouter.New("prefix")
+	r.Use(BindAuthorizer[tenantContext]())
+
+	handler := lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+		tc, _ := AuthorizerFrom[tenantContext](ctx)
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: tc.TenantID + " " + tc.Role}, nil
+	})
+	r.Get("me", handler)
+	r.Get("tenant", handler, RequireAuthorizerKeys("tenantId"))
+
+	invoke := func(path string, authorizer map[string]interface{}) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod:     http.MethodGet,
+			Path:           path,
+			RequestContext: events.APIGatewayProxyRequestContext{Authorizer: authorizer},
+		})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "BindAuthorizer middleware should")
+	{
+		desc(t, 4, "decode the authorizer context into the context of the handler")
+		res := invoke("/prefix/me", map[string]interface{}{"tenantId": "acme", "role": "admin", "principalId": "1"})
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("acme admin", res.Body)
+
+		desc(t, 4, "respond with a 401 without an authorizer context")
+		res = invoke("/prefix/me", nil)
+		a.Exactly(http.StatusUnauthorized, res.StatusCode)
+	}
+
+	desc(t, 2, "RequireAuthorizerKeys option should")
+	{
+		desc(t, 4, "invoke the handler when the keys are present")
+		res := invoke("/prefix/tenant", map[string]interface{}{"tenantId": "acme"})
+		a.Exactly(http.StatusOK, res.StatusCode)
+
+		desc(t, 4, "respond with a 403 when a key is missing")
+		res = invoke("/prefix/tenant", map[string]interface{}{"role": "admin"})
+		a.Exactly(http.StatusForbidden, res.StatusCode)
+	}
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/mitchell/lambdarouter/internal/header"
+)
+
+// Verifier checks the credentials of requests using HTTP Basic Authentication.
+type Verifier interface {
+	// Verify reports whether password is the password of user. An error should only be returned if
+	// the credentials could not be checked.
+	Verify(ctx context.Context, user, password string) (bool, error)
+}
+
+// VerifierFunc adapts a function to the Verifier interface.
+type VerifierFunc func(ctx context.Context, user, password string) (bool, error)
+
+// Verify implements the Verifier interface for the VerifierFunc type.
+func (f VerifierFunc) Verify(ctx context.Context, user, password string) (bool, error) {
+	return f(ctx, user, password)
+}
+
+// StaticCredentials returns a Verifier accepting the passwords of users, which maps each user to
+// their password. Passwords are compared in constant time.
+func StaticCredentials(users map[string]string) Verifier {
+	hashes := make(map[string][32]byte, len(users))
+	for user, password := range users {
+		hashes[user] = sha256.Sum256([]byte(password))
+	}
+
+	return VerifierFunc(func(_ context.Context, user, password string) (bool, error) {
+		want, ok := hashes[user]
+		got := sha256.Sum256([]byte(password))
+
+		return subtle.ConstantTimeCompare(want[:], got[:]) == 1 && ok, nil
+	})
+}
+
+// UserFromContext returns the user authenticated by the Basic middleware for the current
+// invocation. The second return value reports whether the invocation was authenticated by it.
+func UserFromContext(ctx context.Context) (string, bool) {
+	user, ok := ctx.Value(basicUserKey).(string)
+	return user, ok
+}
+
+// Basic returns middleware which authenticates requests using HTTP Basic Authentication, checking
+// their credentials with v, for internal or administrative routes which do not warrant tokens. The
+// authenticated user is placed in the context of the handler, from which it can be retrieved with
+// UserFromContext. Requests without valid credentials are responded to with a 401 challenging the
+// client for credentials of realm, and the handler is not invoked. Errors returned by v fail the
+// invocation.
+func Basic(realm string, v Verifier) lambdarouter.Middleware {
+	challenge := "Basic realm=" + strconv.Quote(realm) + `, charset="UTF-8"`
+
+	return func(next lambda.Handler) lambda.Handler {
+		return basicAuthenticator{challenge: challenge, v: v, next: next}
+	}
+}
+
+type basicAuthenticator struct {
+	challenge string
+	v         Verifier
+	next      lambda.Handler
+}
+
+func (ba basicAuthenticator) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := lambdarouter.RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	user, password, ok := basicCredentials(header.Get(req, "Authorization"))
+	if !ok {
+		return nil, unauthorized(ba.challenge, "missing credentials")
+	}
+
+	valid, err := ba.v.Verify(ctx, user, password)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, unauthorized(ba.challenge, "invalid credentials")
+	}
+
+	return ba.next.Invoke(context.WithValue(ctx, basicUserKey, user), payload)
+}
+
+// basicCredentials returns the user and password of an Authorization header value using the Basic
+// scheme.
+func basicCredentials(value string) (string, string, bool) {
+	const scheme = "basic "
+
+	if len(value) <= len(scheme) || !strings.EqualFold(value[:len(scheme)], scheme) {
+		return "", "", false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value[len(scheme):]))
+	if err != nil {
+		return "", "", false
+	}
+
+	return strings.Cut(string(decoded), ":")
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasic(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with basic auth middleware and")
+	r := lambdarouter.New("prefix")
+	r.Use(Basic("admin", StaticCredentials(map[string]string{"mitchell": "hunter2"})))
+	r.Get("whoami", lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+		user, _ := UserFromContext(ctx)
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: user}, nil
+	}))
+
+	invoke := func(authorization string) events.APIGatewayProxyResponse {
+		req := events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/prefix/whoami"}
+		if authorization != "" {
+			req.Headers = map[string]string{"Authorization": authorization}
+		}
+		payload, _ := json.Marshal(req)
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+	basic := func(credentials string) string {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+	}
+
+	desc(t, 2, "Basic middleware should")
+	{
+		desc(t, 4, "place the user of valid credentials in the context")
+		res := invoke(basic("mitchell:hunter2"))
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("mitchell", res.Body)
+
+		desc(t, 4, "challenge requests without valid credentials")
+		for _, authorization := range []string{"", basic("mitchell:wrong"), basic("nobody:hunter2"), "Basic !!", "Bearer x"} {
+			res = invoke(authorization)
+			a.Exactly(http.StatusUnauthorized, res.StatusCode, authorization)
+			a.Exactly(`Basic realm="admin", charset="UTF-8"`, res.Headers["WWW-Authenticate"])
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+)
+
+// CognitoClaimsFrom returns the claims of the Cognito user pool token which API Gateway verified
+// for req, found in requestContext.authorizer.claims when a route uses a Cognito authorizer. The
+// second return value reports whether the request has them.
+func CognitoClaimsFrom(req events.APIGatewayProxyRequest) (Claims, bool) {
+	claims, ok := req.RequestContext.Authorizer["claims"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	return Claims(claims), true
+}
+
+// Email returns the email claim.
+func (c Claims) Email() string {
+	return c.String("email")
+}
+
+// Username returns the cognito:username claim.
+func (c Claims) Username() string {
+	return c.String("cognito:username")
+}
+
+// Groups returns the Cognito groups of the caller, from the cognito:groups claim. API Gateway
+// passes the groups of a Cognito authorizer as a single string, such as "[admin users]" or
+// "admin,users", whereas a token verified by the JWT middleware holds them in an array, so both
+// forms are accepted.
+func (c Claims) Groups() []string {
+	s, ok := c["cognito:groups"].(string)
+	if !ok {
+		return c.Strings("cognito:groups")
+	}
+
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+// RequireGroup returns middleware which responds with a 403 to requests whose caller belongs to
+// none of groups. The groups are taken from the claims of the JWT middleware if it authenticated
+// the request, or else from those of a Cognito authorizer. Requests with neither are responded to
+// with a 401.
+func RequireGroup(groups ...string) lambdarouter.Middleware {
+	return func(next lambda.Handler) lambda.Handler {
+		return groupRequirement{groups: groups, next: next}
+	}
+}
+
+type groupRequirement struct {
+	groups []string
+	next   lambda.Handler
+}
+
+func (gr groupRequirement) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		req, err := lambdarouter.RequestFrom(ctx, payload)
+		if err != nil {
+			return nil, err
+		}
+
+		if claims, ok = CognitoClaimsFrom(req); !ok {
+			return nil, unauthorized("Bearer", "missing bearer token")
+		}
+	}
+
+	have := claims.Groups()
+	for _, group := range gr.groups {
+		if contains(have, group) {
+			return gr.next.Invoke(ctx, payload)
+		}
+	}
+
+	return nil, forbidden("caller is not a member of " + strings.Join(gr.groups, " or "))
+}
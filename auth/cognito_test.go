@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCognito(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with Cognito claims and")
+	r := lambdarouter.New("prefix")
+	r.Get("admin", lambda.NewHandler(func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		claims, _ := CognitoClaimsFrom(req)
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: claims.Email()}, nil
+	}), lambdarouter.WithMiddleware(RequireGroup("admin", "owner")))
+
+	invoke := func(authorizer map[string]interface{}) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod:     http.MethodGet,
+			Path:           "/prefix/admin",
+			RequestContext: events.APIGatewayProxyRequestContext{Authorizer: authorizer},
+		})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+	withClaims := func(groups interface{}) map[string]interface{} {
+		return map[string]interface{}{"claims": map[string]interface{}{
+			"sub":              "1234",
+			"email":            "mitchell@example.com",
+			"cognito:username": "mitchell",
+			"cognito:groups":   groups,
+		}}
+	}
+
+	desc(t, 2, "Groups method should")
+	{
+		desc(t, 4, "parse the groups in each of the forms they are passed in")
+		for _, groups := range []interface{}{"[users admin]", "users,admin", []interface{}{"users", "admin"}} {
+			a.Exactly([]string{"users", "admin"}, Claims{"cognito:groups": groups}.Groups())
+		}
+	}
+
+	desc(t, 2, "RequireGroup middleware should")
+	{
+		desc(t, 4, "invoke the handler for members of any of the groups")
+		res := invoke(withClaims("[users admin]"))
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("mitchell@example.com", res.Body)
+
+		desc(t, 4, "respond with a 403 for callers in none of the groups")
+		res = invoke(withClaims("users"))
+		a.Exactly(http.StatusForbidden, res.StatusCode)
+
+		desc(t, 4, "respond with a 401 without claims")
+		res = invoke(nil)
+		a.Exactly(http.StatusUnauthorized, res.StatusCode)
+	}
+}
@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/mitchell/lambdarouter/internal/header"
+)
+
+// IPRules lists the clients allowed to use routes, by address.
+type IPRules struct {
+	// Allow lists the CIDR blocks of the clients allowed to use the routes. If empty, every client
+	// not denied is allowed. Single addresses may be given without a prefix length.
+	Allow []string
+
+	// Deny lists the CIDR blocks of the clients which may not use the routes, even if allowed.
+	Deny []string
+
+	// ForwardedHops is the number of proxies, such as CloudFront, in front of API Gateway. The
+	// source IP of the request context is then the address of the nearest proxy, so the address of
+	// the client is taken from the X-Forwarded-For header instead, skipping the addresses the
+	// proxies added. The header is ignored when it is zero, as clients may put anything in it.
+	ForwardedHops int
+}
+
+// IPFilter returns middleware which responds with a 403 to requests from clients not allowed by
+// rules, by the source IP of the request context. It can be added to a route with WithMiddleware,
+// or to a group with Use. It panics if any of the CIDR blocks of rules are invalid, as the router
+// panics on invalid routes.
+func IPFilter(rules IPRules) lambdarouter.Middleware {
+	allow, deny := mustParseCIDRs(rules.Allow), mustParseCIDRs(rules.Deny)
+
+	return func(next lambda.Handler) lambda.Handler {
+		return ipFilter{allow: allow, deny: deny, hops: rules.ForwardedHops, next: next}
+	}
+}
+
+type ipFilter struct {
+	allow []*net.IPNet
+	deny  []*net.IPNet
+	hops  int
+	next  lambda.Handler
+}
+
+func (f ipFilter) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := lambdarouter.RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	ip := net.ParseIP(clientIP(req, f.hops))
+	if ip == nil {
+		return nil, forbidden("client address is unknown")
+	}
+
+	if inAny(f.deny, ip) || (len(f.allow) > 0 && !inAny(f.allow, ip)) {
+		return nil, forbidden("client address " + ip.String() + " is not allowed")
+	}
+
+	return f.next.Invoke(ctx, payload)
+}
+
+// clientIP returns the address of the client of req, hops proxies in front of API Gateway.
+func clientIP(req events.APIGatewayProxyRequest, hops int) string {
+	source := req.RequestContext.Identity.SourceIP
+	if hops <= 0 {
+		return source
+	}
+
+	var addrs []string
+	for _, addr := range strings.Split(header.Get(req, "X-Forwarded-For"), ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	if len(addrs) == 0 || addrs[len(addrs)-1] != source {
+		addrs = append(addrs, source)
+	}
+
+	if i := len(addrs) - 1 - hops; i > 0 {
+		return addrs[i]
+	}
+
+	return addrs[0]
+}
+
+func inAny(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func mustParseCIDRs(cidrs []string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(fmt.Sprintf("invalid CIDR block '%s': %v", cidr, err))
+		}
+		nets = append(nets, n)
+	}
+
+	return nets
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPFilter(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with IP filters and")
+	ok := lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	})
+
+	r := lambdarouter.New("prefix")
+	r.Group("internal", func(r *lambdarouter.Router) {
+		r.Use(IPFilter(IPRules{Allow: []string{"10.0.0.0/8", "2001:db8::/32"}, Deny: []string{"10.0.0.13"}}))
+		r.Get("status", ok)
+	})
+	r.Get("cdn", ok, lambdarouter.WithMiddleware(IPFilter(IPRules{Allow: []string{"192.0.2.0/24"}, ForwardedHops: 1})))
+
+	invoke := func(path, sourceIP, forwardedFor string) int {
+		req := events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path}
+		req.RequestContext.Identity.SourceIP = sourceIP
+		if forwardedFor != "" {
+			req.Headers = map[string]string{"X-Forwarded-For": forwardedFor}
+		}
+		payload, _ := json.Marshal(req)
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res.StatusCode
+	}
+
+	desc(t, 2, "IPFilter middleware should")
+	{
+		desc(t, 4, "allow clients within the allowed blocks")
+		a.Exactly(http.StatusOK, invoke("/prefix/internal/status", "10.1.2.3", ""))
+		a.Exactly(http.StatusOK, invoke("/prefix/internal/status", "2001:db8::1", ""))
+
+		desc(t, 4, "respond with a 403 for denied or unlisted clients")
+		a.Exactly(http.StatusForbidden, invoke("/prefix/internal/status", "10.0.0.13", ""))
+		a.Exactly(http.StatusForbidden, invoke("/prefix/internal/status", "203.0.113.9", ""))
+		a.Exactly(http.StatusForbidden, invoke("/prefix/internal/status", "", ""))
+
+		desc(t, 4, "ignore X-Forwarded-For unless there are proxies in front of API Gateway")
+		a.Exactly(http.StatusForbidden, invoke("/prefix/internal/status", "203.0.113.9", "10.1.2.3"))
+
+		desc(t, 4, "take the client from X-Forwarded-For behind proxies")
+		a.Exactly(http.StatusOK, invoke("/prefix/cdn", "198.51.100.1", "192.0.2.7, 198.51.100.1"))
+		a.Exactly(http.StatusForbidden, invoke("/prefix/cdn", "198.51.100.1", "192.0.2.7, 203.0.113.9, 198.51.100.1"))
+
+		desc(t, 4, "panic on invalid blocks")
+		a.Panics(func() {
+			IPFilter(IPRules{Deny: []string{"nope"}})
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// JWKS is a KeySource which fetches keys from a JSON Web Key Set, such as the one published by an
+// identity provider at its jwks_uri. The set is fetched when a key is first needed, and fetched
+// again when a token names a key which is not in it, so keys can be rotated without redeploying.
+// RSA and EC keys are supported.
+type JWKS struct {
+	// URL is the location of the key set.
+	URL string
+
+	// Client is used to fetch the key set. If nil, http.DefaultClient is used.
+	Client *http.Client
+
+	// MinRefresh is the shortest time between fetches of the key set, which prevents tokens naming
+	// unknown keys from causing a fetch on every request. If zero, it is one minute.
+	MinRefresh time.Duration
+
+	mu      sync.Mutex
+	keys    map[string]interface{}
+	fetched time.Time
+}
+
+// NewJWKS returns a JWKS which fetches keys from url.
+func NewJWKS(url string) *JWKS {
+	return &JWKS{URL: url}
+}
+
+// Key implements the KeySource interface for the JWKS type. When id is empty, the set must hold a
+// single key, which is returned.
+func (j *JWKS) Key(ctx context.Context, id string) (interface{}, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if key, ok := j.find(id); ok {
+		return key, nil
+	}
+
+	minRefresh := j.MinRefresh
+	if minRefresh == 0 {
+		minRefresh = time.Minute
+	}
+	if j.keys != nil && time.Since(j.fetched) < minRefresh {
+		return nil, ErrUnknownKey
+	}
+
+	keys, err := j.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	j.keys, j.fetched = keys, time.Now()
+
+	if key, ok := j.find(id); ok {
+		return key, nil
+	}
+
+	return nil, ErrUnknownKey
+}
+
+func (j *JWKS) find(id string) (interface{}, bool) {
+	if id == "" && len(j.keys) == 1 {
+		for _, key := range j.keys {
+			return key, true
+		}
+	}
+
+	key, ok := j.keys[id]
+	return key, ok
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetch retrieves the key set, returning its signing keys by ID. Keys of unsupported types are
+// skipped.
+func (j *JWKS) fetch(ctx context.Context) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := j.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("jwks: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks: fetching %s: %s", j.URL, res.Status)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("jwks: decoding %s: %w", j.URL, err)
+	}
+
+	keys := map[string]interface{}{}
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if key, err := jwk.publicKey(); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+
+	return keys, nil
+}
+
+func (jwk jsonWebKey) publicKey() (interface{}, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+
+		x, err := decodeInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWKS(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a key set server and")
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	enc := func(i *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(i.Bytes())
+	}
+
+	keys := []map[string]string{
+		{"kty": "RSA", "kid": "rsa", "n": enc(rsaKey.N), "e": enc(big.NewInt(int64(rsaKey.E)))},
+	}
+	fetches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	}))
+	defer srv.Close()
+
+	jwks := NewJWKS(srv.URL)
+	ctx := context.Background()
+
+	desc(t, 2, "Key method should")
+	{
+		desc(t, 4, "fetch the key set when a key is first needed")
+		key, err := jwks.Key(ctx, "rsa")
+		a.NoError(err)
+		a.Exactly(&rsaKey.PublicKey, key)
+		a.Exactly(1, fetches)
+
+		desc(t, 4, "return the only key for tokens without an ID")
+		key, err = jwks.Key(ctx, "")
+		a.NoError(err)
+		a.Exactly(&rsaKey.PublicKey, key)
+
+		desc(t, 4, "not fetch unknown keys more often than the minimum refresh interval")
+		_, err = jwks.Key(ctx, "ec")
+		a.ErrorIs(err, ErrUnknownKey)
+		a.Exactly(1, fetches)
+
+		desc(t, 4, "fetch the key set again for unknown keys")
+		keys = append(keys, map[string]string{
+			"kty": "EC", "kid": "ec", "crv": "P-256", "x": enc(ecKey.X), "y": enc(ecKey.Y),
+		})
+		jwks.MinRefresh = -1
+		key, err = jwks.Key(ctx, "ec")
+		a.NoError(err)
+		a.Exactly(&ecKey.PublicKey, key)
+		a.Exactly(2, fetches)
+
+		desc(t, 4, "verify tokens through the JWT middleware")
+		cfg := JWTConfig{Keys: jwks}
+		claims, err := cfg.verify(ctx, sign(t, "ec", ecKey, map[string]interface{}{"sub": "mitchell"}), time.Now())
+		a.NoError(err)
+		a.Exactly("mitchell", claims.Subject())
+	}
+}
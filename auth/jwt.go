@@ -0,0 +1,346 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256" // registers SHA-256 for RS256 and ES256
+	_ "crypto/sha512" // registers SHA-384 and SHA-512
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/mitchell/lambdarouter/internal/header"
+)
+
+// ErrUnknownKey is returned by a KeySource which has no key with the requested ID. Tokens signed
+// with unknown keys are responded to with a 401, whereas other errors of a KeySource fail the
+// invocation.
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// KeySource provides the keys which verify the signatures of tokens. Keys are []byte for the HMAC
+// algorithms, *rsa.PublicKey for the RSA algorithms, and *ecdsa.PublicKey for the ECDSA
+// algorithms.
+type KeySource interface {
+	// Key returns the key with the given ID, taken from the kid header of a token. The ID is empty
+	// for tokens without one.
+	Key(ctx context.Context, id string) (interface{}, error)
+}
+
+// StaticKey returns a KeySource which verifies every token with key, whatever its ID.
+func StaticKey(key interface{}) KeySource {
+	return staticKey{key: key}
+}
+
+type staticKey struct {
+	key interface{}
+}
+
+func (sk staticKey) Key(context.Context, string) (interface{}, error) {
+	return sk.key, nil
+}
+
+// JWTConfig configures the JWT middleware.
+type JWTConfig struct {
+	// Keys provides the keys which verify the signatures of tokens, such as a StaticKey or JWKS.
+	Keys KeySource
+
+	// Issuer is the required value of the iss claim. It is not checked if empty.
+	Issuer string
+
+	// Audience is a value the aud claim is required to contain. It is not checked if empty.
+	Audience string
+
+	// Leeway is the clock skew allowed when checking the exp and nbf claims.
+	Leeway time.Duration
+}
+
+// Claims are the claims of a verified JSON Web Token.
+type Claims map[string]interface{}
+
+// Subject returns the sub claim, which identifies the caller.
+func (c Claims) Subject() string {
+	return c.String("sub")
+}
+
+// String returns the named claim if it is a string, or an empty string otherwise.
+func (c Claims) String(name string) string {
+	s, _ := c[name].(string)
+	return s
+}
+
+// Strings returns the named claim as a list of strings. Claims which are arrays are returned as
+// they are, without any values which are not strings, and claims which are strings are split on
+// spaces, as the scope claim is.
+func (c Claims) Strings(name string) []string {
+	switch v := c[name].(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+
+	return nil
+}
+
+// ClaimsFromContext returns the claims of the token which authenticated the current invocation.
+// The second return value reports whether the invocation was authenticated by the JWT middleware.
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	c, ok := ctx.Value(claimsKey).(Claims)
+	return c, ok
+}
+
+// JWT returns middleware which authenticates requests by the JSON Web Token in their Authorization
+// header, given as "Bearer <token>". Tokens must be signed with a key of cfg.Keys using one of the
+// HS, RS, or ES algorithms, must not have expired, and must satisfy the issuer and audience of
+// cfg. The claims of valid tokens are placed in the context of the handler, from which they can be
+// retrieved with ClaimsFromContext. Requests without a valid token are responded to with a 401
+// carrying a Bearer challenge, and the handler is not invoked.
+func JWT(cfg JWTConfig) lambdarouter.Middleware {
+	return func(next lambda.Handler) lambda.Handler {
+		return jwtAuthenticator{cfg: cfg, next: next}
+	}
+}
+
+type jwtAuthenticator struct {
+	cfg  JWTConfig
+	next lambda.Handler
+}
+
+func (ja jwtAuthenticator) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := lambdarouter.RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	token := bearerToken(header.Get(req, "Authorization"))
+	if token == "" {
+		return nil, unauthorized("Bearer", "missing bearer token")
+	}
+
+	claims, err := ja.cfg.verify(ctx, token, time.Now())
+	if err != nil {
+		var invalid invalidTokenError
+		if !errors.As(err, &invalid) {
+			return nil, err
+		}
+
+		return nil, unauthorized(`Bearer error="invalid_token"`, err.Error())
+	}
+
+	return ja.next.Invoke(context.WithValue(ctx, claimsKey, claims), payload)
+}
+
+// RequireClaim returns middleware which responds with a 403 to requests whose token lacks any of
+// the given values in the named claim, as returned by the Strings method of Claims. With no values,
+// the claim only needs to be present. It must run after the JWT middleware, so it should be added
+// to routes with WithMiddleware, or to the router after JWT.
+func RequireClaim(name string, values ...string) lambdarouter.Middleware {
+	return func(next lambda.Handler) lambda.Handler {
+		return claimRequirement{name: name, values: values, next: next}
+	}
+}
+
+type claimRequirement struct {
+	name   string
+	values []string
+	next   lambda.Handler
+}
+
+func (cr claimRequirement) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return nil, unauthorized("Bearer", "missing bearer token")
+	}
+	if _, present := claims[cr.name]; !present {
+		return nil, forbidden("token is missing the " + cr.name + " claim")
+	}
+
+	have := claims.Strings(cr.name)
+	for _, want := range cr.values {
+		if !contains(have, want) {
+			return nil, forbidden(fmt.Sprintf("token %s claim does not include %s", cr.name, want))
+		}
+	}
+
+	return cr.next.Invoke(ctx, payload)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bearerToken returns the token of an Authorization header value using the Bearer scheme.
+func bearerToken(value string) string {
+	const scheme = "bearer "
+
+	if len(value) <= len(scheme) || !strings.EqualFold(value[:len(scheme)], scheme) {
+		return ""
+	}
+
+	return strings.TrimSpace(value[len(scheme):])
+}
+
+// invalidTokenError is returned for tokens which are malformed, or fail verification.
+type invalidTokenError struct {
+	msg string
+}
+
+func (e invalidTokenError) Error() string {
+	return e.msg
+}
+
+func invalidToken(format string, args ...interface{}) error {
+	return invalidTokenError{msg: fmt.Sprintf(format, args...)}
+}
+
+// verify checks the signature and claims of token, returning its claims.
+func (cfg JWTConfig) verify(ctx context.Context, token string, now time.Time) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, invalidToken("token is malformed")
+	}
+
+	var hdr struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &hdr); err != nil {
+		return nil, invalidToken("token header is malformed")
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, invalidToken("token signature is malformed")
+	}
+
+	if cfg.Keys == nil {
+		return nil, errors.New("jwt: no key source configured")
+	}
+	key, err := cfg.Keys.Key(ctx, hdr.Kid)
+	if errors.Is(err, ErrUnknownKey) {
+		return nil, invalidToken("token is signed with an unknown key")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifySignature(hdr.Alg, key, parts[0]+"."+parts[1], sig); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, invalidToken("token claims are malformed")
+	}
+
+	if exp, ok := claims["exp"].(float64); ok && now.After(unixTime(exp).Add(cfg.Leeway)) {
+		return nil, invalidToken("token has expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(cfg.Leeway).Before(unixTime(nbf)) {
+		return nil, invalidToken("token is not valid yet")
+	}
+	if cfg.Issuer != "" && claims.String("iss") != cfg.Issuer {
+		return nil, invalidToken("token has the wrong issuer")
+	}
+	if cfg.Audience != "" && !contains(claims.Strings("aud"), cfg.Audience) {
+		return nil, invalidToken("token has the wrong audience")
+	}
+
+	return claims, nil
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+func unixTime(seconds float64) time.Time {
+	return time.Unix(int64(seconds), 0)
+}
+
+var algHashes = map[string]crypto.Hash{
+	"256": crypto.SHA256,
+	"384": crypto.SHA384,
+	"512": crypto.SHA512,
+}
+
+// verifySignature checks that sig is the signature of input under alg with key.
+func verifySignature(alg string, key interface{}, input string, sig []byte) error {
+	if len(alg) != 5 {
+		return invalidToken("token algorithm %q is not supported", alg)
+	}
+
+	hash, ok := algHashes[alg[2:]]
+	if !ok {
+		return invalidToken("token algorithm %q is not supported", alg)
+	}
+
+	valid := false
+	switch alg[:2] {
+	case "HS":
+		secret, ok := key.([]byte)
+		if !ok {
+			return invalidToken("token algorithm %q does not match the key", alg)
+		}
+		mac := hmac.New(hash.New, secret)
+		mac.Write([]byte(input))
+		valid = hmac.Equal(mac.Sum(nil), sig)
+
+	case "RS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return invalidToken("token algorithm %q does not match the key", alg)
+		}
+		h := hash.New()
+		h.Write([]byte(input))
+		valid = rsa.VerifyPKCS1v15(pub, hash, h.Sum(nil), sig) == nil
+
+	case "ES":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return invalidToken("token algorithm %q does not match the key", alg)
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return invalidToken("token signature is invalid")
+		}
+		h := hash.New()
+		h.Write([]byte(input))
+		r, s := new(big.Int).SetBytes(sig[:size]), new(big.Int).SetBytes(sig[size:])
+		valid = ecdsa.Verify(pub, h.Sum(nil), r, s)
+
+	default:
+		return invalidToken("token algorithm %q is not supported", alg)
+	}
+
+	if !valid {
+		return invalidToken("token signature is invalid")
+	}
+
+	return nil
+}
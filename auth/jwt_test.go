@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+// sign returns a token of claims signed with key, which is a []byte, *rsa.PrivateKey, or
+// *ecdsa.PrivateKey.
+func sign(t *testing.T, kid string, key interface{}, claims map[string]interface{}) string {
+	alg := "HS256"
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		alg = "RS256"
+	case *ecdsa.PrivateKey:
+		alg = "ES256"
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT", "kid": kid})
+	body, _ := json.Marshal(claims)
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(body)
+	digest := sha256.Sum256([]byte(input))
+
+	var sig []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(input))
+		sig = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		var err error
+		if sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:]); err != nil {
+			t.Fatal(err)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	}
+
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestJWT(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with JWT middleware and")
+	secret := []byte("secret")
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	greet := lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+		claims, _ := ClaimsFromContext(ctx)
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: "hello " + claims.Subject()}, nil
+	})
+
+	route := func(key interface{}) lambdarouter.Router {
+		r := lambdarouter.New("prefix")
+		r.Use(JWT(JWTConfig{Keys: StaticKey(key), Issuer: "issuer", Audience: "api", Leeway: time.Second}))
+		r.Get("hello", greet)
+		r.Get("admin", greet, lambdarouter.WithMiddleware(RequireClaim("scope", "admin")))
+		return r
+	}
+	invoke := func(r lambdarouter.Router, path, token string) events.APIGatewayProxyResponse {
+		req := events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path}
+		if token != "" {
+			req.Headers = map[string]string{"authorization": "Bearer " + token}
+		}
+		payload, _ := json.Marshal(req)
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+	claims := func(overrides map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{
+			"sub": "mitchell",
+			"iss": "issuer",
+			"aud": []string{"api"},
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}
+		for name, value := range overrides {
+			c[name] = value
+		}
+		return c
+	}
+
+	desc(t, 2, "JWT middleware should")
+	{
+		desc(t, 4, "place the claims of valid tokens in the context")
+		for _, key := range []interface{}{secret, rsaKey, ecKey} {
+			verifyKey := key
+			switch k := key.(type) {
+			case *rsa.PrivateKey:
+				verifyKey = &k.PublicKey
+			case *ecdsa.PrivateKey:
+				verifyKey = &k.PublicKey
+			}
+
+			res := invoke(route(verifyKey), "/prefix/hello", sign(t, "", key, claims(nil)))
+			a.Exactly(http.StatusOK, res.StatusCode, fmt.Sprintf("%T", key))
+			a.Exactly("hello mitchell", res.Body)
+		}
+
+		r := route(secret)
+
+		desc(t, 4, "respond with a 401 challenge without a token")
+		res := invoke(r, "/prefix/hello", "")
+		a.Exactly(http.StatusUnauthorized, res.StatusCode)
+		a.Exactly("Bearer", res.Headers["WWW-Authenticate"])
+
+		desc(t, 4, "respond with a 401 for invalid tokens")
+		for _, token := range []string{
+			"nope",
+			sign(t, "", []byte("other"), claims(nil)),
+			sign(t, "", secret, claims(map[string]interface{}{"exp": time.Now().Add(-time.Minute).Unix()})),
+			sign(t, "", secret, claims(map[string]interface{}{"nbf": time.Now().Add(time.Minute).Unix()})),
+			sign(t, "", secret, claims(map[string]interface{}{"iss": "other"})),
+			sign(t, "", secret, claims(map[string]interface{}{"aud": "other"})),
+			sign(t, "", rsaKey, claims(nil)),
+		} {
+			res = invoke(r, "/prefix/hello", token)
+			a.Exactly(http.StatusUnauthorized, res.StatusCode, token)
+			a.Exactly(`Bearer error="invalid_token"`, res.Headers["WWW-Authenticate"])
+		}
+	}
+
+	desc(t, 2, "RequireClaim middleware should")
+	{
+		r := route(secret)
+
+		desc(t, 4, "invoke the handler when the claim includes the values")
+		res := invoke(r, "/prefix/admin", sign(t, "", secret, claims(map[string]interface{}{"scope": "read admin"})))
+		a.Exactly(http.StatusOK, res.StatusCode)
+
+		desc(t, 4, "respond with a 403 when it does not")
+		res = invoke(r, "/prefix/admin", sign(t, "", secret, claims(map[string]interface{}{"scope": "read"})))
+		a.Exactly(http.StatusForbidden, res.StatusCode)
+		res = invoke(r, "/prefix/admin", sign(t, "", secret, claims(nil)))
+		a.Exactly(http.StatusForbidden, res.StatusCode)
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
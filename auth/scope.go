@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+)
+
+// WithScopes returns a route option which requires the caller to have been granted every one of
+// scopes, as described by RequireScopes.
+func WithScopes(scopes ...string) lambdarouter.RouteOption {
+	return lambdarouter.WithMiddleware(RequireScopes(scopes...))
+}
+
+// RequireScopes returns middleware which responds with a 403 to requests whose caller has not been
+// granted every one of scopes. The granted scopes are taken from the scope or scp claim of the
+// token verified by the JWT middleware, or else from the claims of a Cognito authorizer, or the
+// scope entry of the context of a custom authorizer. The response carries a WWW-Authenticate
+// header with the insufficient_scope error and the required scopes, as described by RFC 6750, so
+// clients can tell which scopes to request. Requests with none of these sources of scopes are
+// responded to with a 401.
+func RequireScopes(scopes ...string) lambdarouter.Middleware {
+	challenge := `Bearer error="insufficient_scope", scope=` + strconv.Quote(strings.Join(scopes, " "))
+
+	return func(next lambda.Handler) lambda.Handler {
+		return scopeRequirement{scopes: scopes, challenge: challenge, next: next}
+	}
+}
+
+type scopeRequirement struct {
+	scopes    []string
+	challenge string
+	next      lambda.Handler
+}
+
+func (sr scopeRequirement) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := lambdarouter.RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	granted, ok := grantedScopes(ctx, req)
+	if !ok {
+		return nil, unauthorized("Bearer", "missing bearer token")
+	}
+
+	var missing []string
+	for _, scope := range sr.scopes {
+		if !contains(granted, scope) {
+			missing = append(missing, scope)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &lambdarouter.HTTPError{
+			Status:  http.StatusForbidden,
+			Detail:  "missing scopes: " + strings.Join(missing, " "),
+			Type:    "https://www.rfc-editor.org/rfc/rfc6750#section-3.1",
+			Headers: map[string]string{"WWW-Authenticate": sr.challenge},
+		}
+	}
+
+	return sr.next.Invoke(ctx, payload)
+}
+
+// grantedScopes returns the scopes granted to the caller of req, reporting whether the request
+// carries any source of them.
+func grantedScopes(ctx context.Context, req events.APIGatewayProxyRequest) ([]string, bool) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		claims, ok = CognitoClaimsFrom(req)
+	}
+	if ok {
+		return append(claims.Strings("scope"), claims.Strings("scp")...), true
+	}
+
+	if scope, ok := req.RequestContext.Authorizer["scope"].(string); ok {
+		return strings.Fields(scope), true
+	}
+
+	return nil, false
+}
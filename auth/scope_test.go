@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopes(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with scoped routes and")
+	secret := []byte("secret")
+	ok := lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	})
+
+	r := lambdarouter.New("prefix")
+	r.Post("orders", ok, WithScopes("orders:write"))
+	r.Group("jwt", func(r *lambdarouter.Router) {
+		r.Use(JWT(JWTConfig{Keys: StaticKey(secret)}))
+		r.Post("orders", ok, WithScopes("orders:read", "orders:write"))
+	})
+
+	invoke := func(req events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+		req.HTTPMethod = http.MethodPost
+		payload, _ := json.Marshal(req)
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+	withAuthorizer := func(authorizer map[string]interface{}) events.APIGatewayProxyRequest {
+		return events.APIGatewayProxyRequest{
+			Path:           "/prefix/orders",
+			RequestContext: events.APIGatewayProxyRequestContext{Authorizer: authorizer},
+		}
+	}
+
+	desc(t, 2, "WithScopes option should")
+	{
+		desc(t, 4, "invoke the handler when the scopes are granted")
+		res := invoke(withAuthorizer(map[string]interface{}{"scope": "orders:read orders:write"}))
+		a.Exactly(http.StatusOK, res.StatusCode)
+
+		res = invoke(withAuthorizer(map[string]interface{}{"claims": map[string]interface{}{"scope": "orders:write"}}))
+		a.Exactly(http.StatusOK, res.StatusCode)
+
+		res = invoke(events.APIGatewayProxyRequest{
+			Path: "/prefix/jwt/orders",
+			Headers: map[string]string{"Authorization": "Bearer " + sign(t, "", secret, map[string]interface{}{
+				"scp": []string{"orders:read", "orders:write"},
+			})},
+		})
+		a.Exactly(http.StatusOK, res.StatusCode)
+
+		desc(t, 4, "respond with a 403 naming the required scopes when any are missing")
+		res = invoke(events.APIGatewayProxyRequest{
+			Path: "/prefix/jwt/orders",
+			Headers: map[string]string{"Authorization": "Bearer " + sign(t, "", secret, map[string]interface{}{
+				"scope": "orders:read",
+			})},
+		})
+		a.Exactly(http.StatusForbidden, res.StatusCode)
+		a.Exactly(`Bearer error="insufficient_scope", scope="orders:read orders:write"`, res.Headers["WWW-Authenticate"])
+		a.Exactly("missing scopes: orders:write", res.Body)
+
+		desc(t, 4, "respond with a 401 without any source of scopes")
+		res = invoke(withAuthorizer(nil))
+		a.Exactly(http.StatusUnauthorized, res.StatusCode)
+	}
+}
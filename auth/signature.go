@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/mitchell/lambdarouter/internal/header"
+)
+
+// HMACConfig describes how a webhook provider signs the bodies of its requests with HMAC.
+type HMACConfig struct {
+	// Header is the name of the header carrying the signature.
+	Header string
+
+	// Secret is the key shared with the provider.
+	Secret []byte
+
+	// Hash constructs the hash the HMAC uses. If nil, SHA-256 is used.
+	Hash func() hash.Hash
+
+	// Prefix is text preceding the signature in the header, such as "sha256=".
+	Prefix string
+
+	// Base64 reports whether the signature is base64 encoded, rather than hex encoded.
+	Base64 bool
+}
+
+// HMACSignature returns middleware which verifies that the body of each request is signed as
+// described by cfg, before the handler is invoked. Signatures are computed over the raw body of the
+// request, so base64 encoded bodies are decoded first. Requests with missing or invalid signatures
+// are responded to with a 403.
+func HMACSignature(cfg HMACConfig) lambdarouter.Middleware {
+	return signatureMiddleware(func(req events.APIGatewayProxyRequest, body []byte) error {
+		sig := header.Get(req, cfg.Header)
+		if sig == "" || !strings.HasPrefix(sig, cfg.Prefix) {
+			return errors.New("missing signature")
+		}
+		sig = sig[len(cfg.Prefix):]
+
+		decode := hex.DecodeString
+		if cfg.Base64 {
+			decode = base64.StdEncoding.DecodeString
+		}
+
+		got, err := decode(sig)
+		if err != nil || !hmac.Equal(got, hmacSum(cfg.Hash, cfg.Secret, body)) {
+			return errors.New("invalid signature")
+		}
+
+		return nil
+	})
+}
+
+// GitHubSignature returns middleware which verifies the X-Hub-Signature-256 header of webhooks
+// delivered by GitHub, using the secret of the webhook. It is HMACSignature configured for GitHub.
+func GitHubSignature(secret []byte) lambdarouter.Middleware {
+	return HMACSignature(HMACConfig{Header: "X-Hub-Signature-256", Secret: secret, Prefix: "sha256="})
+}
+
+// StripeSignature returns middleware which verifies the Stripe-Signature header of webhooks
+// delivered by Stripe, using the signing secret of the endpoint. Events signed more than tolerance
+// ago are rejected to prevent replays; a tolerance of zero means five minutes, as Stripe's own
+// libraries use. Requests with missing, invalid, or stale signatures are responded to with a 403.
+func StripeSignature(secret []byte, tolerance time.Duration) lambdarouter.Middleware {
+	if tolerance == 0 {
+		tolerance = 5 * time.Minute
+	}
+
+	return signatureMiddleware(func(req events.APIGatewayProxyRequest, body []byte) error {
+		var timestamp string
+		var sigs [][]byte
+
+		for _, part := range strings.Split(header.Get(req, "Stripe-Signature"), ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+			switch name {
+			case "t":
+				timestamp = value
+			case "v1":
+				if sig, err := hex.DecodeString(value); err == nil {
+					sigs = append(sigs, sig)
+				}
+			}
+		}
+		if timestamp == "" || len(sigs) == 0 {
+			return errors.New("missing signature")
+		}
+
+		t, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return errors.New("invalid signature timestamp")
+		}
+		if age := time.Since(time.Unix(t, 0)); age > tolerance || age < -tolerance {
+			return errors.New("signature timestamp is outside the tolerance")
+		}
+
+		want := hmacSum(nil, secret, append([]byte(timestamp+"."), body...))
+		for _, sig := range sigs {
+			if hmac.Equal(sig, want) {
+				return nil
+			}
+		}
+
+		return errors.New("invalid signature")
+	})
+}
+
+func hmacSum(h func() hash.Hash, secret, body []byte) []byte {
+	if h == nil {
+		h = sha256.New
+	}
+
+	mac := hmac.New(h, secret)
+	mac.Write(body)
+
+	return mac.Sum(nil)
+}
+
+// signatureMiddleware returns middleware which invokes the handler if verify accepts the raw body
+// of the request, and responds with a 403 otherwise.
+func signatureMiddleware(verify func(req events.APIGatewayProxyRequest, body []byte) error) lambdarouter.Middleware {
+	return func(next lambda.Handler) lambda.Handler {
+		return signatureVerifier{verify: verify, next: next}
+	}
+}
+
+type signatureVerifier struct {
+	verify func(req events.APIGatewayProxyRequest, body []byte) error
+	next   lambda.Handler
+}
+
+func (sv signatureVerifier) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := lambdarouter.RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	body := []byte(req.Body)
+	if req.IsBase64Encoded {
+		if body, err = base64.StdEncoding.DecodeString(req.Body); err != nil {
+			return nil, forbidden("body is not valid base64")
+		}
+	}
+
+	if err := sv.verify(req, body); err != nil {
+		return nil, forbidden(err.Error())
+	}
+
+	return sv.next.Invoke(ctx, payload)
+}
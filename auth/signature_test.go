@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignatures(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with signature middleware and")
+	secret := []byte("whsec")
+	received := lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	})
+
+	r := lambdarouter.New("hooks")
+	r.Post("github", received, lambdarouter.WithMiddleware(GitHubSignature(secret)))
+	r.Post("stripe", received, lambdarouter.WithMiddleware(StripeSignature(secret, 0)))
+	r.Post("legacy", received, lambdarouter.WithMiddleware(HMACSignature(HMACConfig{
+		Header: "X-Signature",
+		Secret: secret,
+		Hash:   sha1.New,
+		Base64: true,
+	})))
+
+	invoke := func(req events.APIGatewayProxyRequest) int {
+		req.HTTPMethod = http.MethodPost
+		payload, _ := json.Marshal(req)
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res.StatusCode
+	}
+	mac := func(body string) []byte {
+		m := hmac.New(sha256.New, secret)
+		m.Write([]byte(body))
+		return m.Sum(nil)
+	}
+
+	body := `{"action": "opened"}`
+
+	desc(t, 2, "GitHubSignature middleware should")
+	{
+		desc(t, 4, "invoke the handler for correctly signed bodies")
+		a.Exactly(http.StatusOK, invoke(events.APIGatewayProxyRequest{
+			Path:    "/hooks/github",
+			Headers: map[string]string{"X-Hub-Signature-256": "sha256=" + hex.EncodeToString(mac(body))},
+			Body:    body,
+		}))
+
+		desc(t, 4, "verify the decoded body of base64 encoded requests")
+		a.Exactly(http.StatusOK, invoke(events.APIGatewayProxyRequest{
+			Path:            "/hooks/github",
+			Headers:         map[string]string{"x-hub-signature-256": "sha256=" + hex.EncodeToString(mac(body))},
+			Body:            base64.StdEncoding.EncodeToString([]byte(body)),
+			IsBase64Encoded: true,
+		}))
+
+		desc(t, 4, "respond with a 403 for missing or invalid signatures")
+		a.Exactly(http.StatusForbidden, invoke(events.APIGatewayProxyRequest{Path: "/hooks/github", Body: body}))
+		a.Exactly(http.StatusForbidden, invoke(events.APIGatewayProxyRequest{
+			Path:    "/hooks/github",
+			Headers: map[string]string{"X-Hub-Signature-256": "sha256=" + hex.EncodeToString(mac("tampered"))},
+			Body:    body,
+		}))
+	}
+
+	desc(t, 2, "StripeSignature middleware should")
+	{
+		stripe := func(at time.Time) string {
+			ts := strconv.FormatInt(at.Unix(), 10)
+			return "t=" + ts + ",v1=" + hex.EncodeToString(mac(ts+"."+body)) + ",v0=abc"
+		}
+
+		desc(t, 4, "invoke the handler for correctly signed bodies")
+		a.Exactly(http.StatusOK, invoke(events.APIGatewayProxyRequest{
+			Path:    "/hooks/stripe",
+			Headers: map[string]string{"Stripe-Signature": stripe(time.Now())},
+			Body:    body,
+		}))
+
+		desc(t, 4, "respond with a 403 for stale signatures")
+		a.Exactly(http.StatusForbidden, invoke(events.APIGatewayProxyRequest{
+			Path:    "/hooks/stripe",
+			Headers: map[string]string{"Stripe-Signature": stripe(time.Now().Add(-time.Hour))},
+			Body:    body,
+		}))
+	}
+
+	desc(t, 2, "HMACSignature middleware should")
+	{
+		desc(t, 4, "use the configured hash and encoding")
+		m := hmac.New(sha1.New, secret)
+		m.Write([]byte(body))
+		a.Exactly(http.StatusOK, invoke(events.APIGatewayProxyRequest{
+			Path:    "/hooks/legacy",
+			Headers: map[string]string{"X-Signature": base64.StdEncoding.EncodeToString(m.Sum(nil))},
+			Body:    body,
+		}))
+	}
+}
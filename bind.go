@@ -0,0 +1,402 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/mitchell/lambdarouter/respond"
+)
+
+// FieldError describes why a single field of a request could not be bound or failed validation.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// BindError is returned by Bind when one or more fields of a request are invalid. It lists every
+// invalid field rather than only the first.
+type BindError struct {
+	Fields []FieldError
+}
+
+// Error implements the error interface for the BindError type.
+func (e *BindError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + ": " + f.Message
+	}
+
+	return "invalid request: " + strings.Join(msgs, "; ")
+}
+
+// Bind populates the struct pointed to by v from req. The JSON body of the request is decoded into
+// v first, so json tags apply as usual. Fields tagged with `path:"name"`, `query:"name"`, or
+// `header:"name"` are then set from the path parameters, query string, and headers of the request,
+// converting the values to the type of the field. Slice fields receive every value of a
+// multi-value query parameter or header.
+//
+// Bodies of the application/x-www-form-urlencoded and multipart/form-data media types are not
+// decoded as JSON. Instead, fields tagged with `form:"name"` are set from the fields of the form,
+// as they are from the query string. Fields tagged with `default:"value"` are set to the value
+// when the request has none for them and they are still zero, with the value split on commas for
+// slice fields.
+//
+// Finally, fields tagged with `validate:"..."` are checked against a comma separated list of rules:
+// required, min=n, max=n, and oneof=a b c. The min and max rules apply to the value of numbers and
+// the length of strings and slices. If any field cannot be bound or fails validation a *BindError
+// is returned.
+func Bind(req events.APIGatewayProxyRequest, v interface{}) error {
+	return bind(StdCodec, req, v)
+}
+
+// BindContext is Bind, decoding the JSON body of the request with the codec of ctx rather than
+// encoding/json, as HandlerOf does.
+func BindContext(ctx context.Context, req events.APIGatewayProxyRequest, v interface{}) error {
+	return bind(CodecFrom(ctx), req, v)
+}
+
+func bind(codec Codec, req events.APIGatewayProxyRequest, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind target must be a pointer to a struct, got %T", v)
+	}
+
+	body := []byte(req.Body)
+	if req.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(req.Body)
+		if err != nil {
+			return err
+		}
+		body = decoded
+	}
+
+	b := binder{req: req}
+
+	switch mediaType(req) {
+	case "application/x-www-form-urlencoded":
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return err
+		}
+		b.form = form
+	case "multipart/form-data":
+		form, err := ParseMultipart(req, MultipartLimits{})
+		if err != nil {
+			return err
+		}
+		b.form = form.Fields
+	default:
+		if len(body) > 0 {
+			if err := codec.Unmarshal(body, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	b.bindStruct(rv.Elem())
+
+	if len(b.errs) > 0 {
+		return &BindError{Fields: b.errs}
+	}
+
+	return nil
+}
+
+type binder struct {
+	req  events.APIGatewayProxyRequest
+	form map[string][]string
+	errs []FieldError
+}
+
+func (b *binder) bindStruct(sv reflect.Value) {
+	st := sv.Type()
+
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		fv := sv.Field(i)
+
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			b.bindStruct(fv)
+			continue
+		}
+
+		name := fieldName(sf)
+
+		values := b.lookup(sf.Tag)
+		if def, ok := sf.Tag.Lookup("default"); ok && len(values) == 0 && fv.IsZero() {
+			values = []string{def}
+			if fv.Kind() == reflect.Slice {
+				values = strings.Split(def, ",")
+			}
+		}
+
+		if len(values) > 0 {
+			if err := setField(fv, values); err != nil {
+				b.errs = append(b.errs, FieldError{Field: name, Message: err.Error()})
+				continue
+			}
+		}
+
+		if rules, ok := sf.Tag.Lookup("validate"); ok {
+			if msg := validateField(fv, rules); msg != "" {
+				b.errs = append(b.errs, FieldError{Field: name, Message: msg})
+			}
+		}
+	}
+}
+
+// lookup returns the values of the request referred to by the path, query, header, or form tag.
+func (b *binder) lookup(tag reflect.StructTag) []string {
+	if name, ok := tag.Lookup("path"); ok {
+		if value, ok := b.req.PathParameters[name]; ok {
+			return []string{value}
+		}
+	}
+
+	if name, ok := tag.Lookup("query"); ok {
+		if values := queryValues(b.req, name); len(values) > 0 {
+			return values
+		}
+	}
+
+	if name, ok := tag.Lookup("header"); ok {
+		if values := headerValues(b.req, name); len(values) > 0 {
+			return values
+		}
+	}
+
+	if name, ok := tag.Lookup("form"); ok {
+		if values := b.form[name]; len(values) > 0 {
+			return values
+		}
+	}
+
+	return nil
+}
+
+// queryValues returns the values of the named query string parameter.
+func queryValues(req events.APIGatewayProxyRequest, name string) []string {
+	if values, ok := req.MultiValueQueryStringParameters[name]; ok {
+		return values
+	}
+	if value, ok := req.QueryStringParameters[name]; ok {
+		return []string{value}
+	}
+
+	return nil
+}
+
+// headerValues returns the values of the named header, ignoring the case of header names as
+// API Gateway preserves whatever case the client sent.
+func headerValues(req events.APIGatewayProxyRequest, name string) []string {
+	for key, values := range req.MultiValueHeaders {
+		if strings.EqualFold(key, name) {
+			return values
+		}
+	}
+	for key, value := range req.Headers {
+		if strings.EqualFold(key, name) {
+			return []string{value}
+		}
+	}
+
+	return nil
+}
+
+// mediaType returns the media type of the body of req, without its parameters.
+func mediaType(req events.APIGatewayProxyRequest) string {
+	values := headerValues(req, "Content-Type")
+	if len(values) == 0 {
+		return ""
+	}
+
+	mt, _, err := mime.ParseMediaType(values[0])
+	if err != nil {
+		return ""
+	}
+
+	return mt
+}
+
+// fieldName returns the name a field is known by in the request, for use in error messages.
+func fieldName(sf reflect.StructField) string {
+	for _, tag := range []string{"path", "query", "header", "form", "json"} {
+		if name := strings.Split(sf.Tag.Get(tag), ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+
+	return sf.Name
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+func setField(fv reflect.Value, values []string) error {
+	if fv.Kind() == reflect.Ptr {
+		ptr := reflect.New(fv.Type().Elem())
+		if err := setField(ptr.Elem(), values); err != nil {
+			return err
+		}
+		fv.Set(ptr)
+		return nil
+	}
+
+	if reflect.PtrTo(fv.Type()).Implements(textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(values[0]))
+	}
+
+	if fv.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(fv.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setValue(slice.Index(i), value); err != nil {
+				return err
+			}
+		}
+		fv.Set(slice)
+		return nil
+	}
+
+	return setValue(fv, values[0])
+}
+
+func setValue(fv reflect.Value, value string) error {
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", value)
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a valid integer", value)
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a valid unsigned integer", value)
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a valid number", value)
+		}
+		fv.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+
+	return nil
+}
+
+// validateField checks fv against a comma separated list of rules, returning a message describing
+// the first rule it fails, or an empty string if it passes them all.
+func validateField(fv reflect.Value, rules string) string {
+	for _, rule := range strings.Split(rules, ",") {
+		name, arg := rule, ""
+		if i := strings.IndexByte(rule, '='); i >= 0 {
+			name, arg = rule[:i], rule[i+1:]
+		}
+
+		if name == "required" {
+			if fv.IsZero() {
+				return "is required"
+			}
+			continue
+		}
+
+		// Only required applies to missing optional values.
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+
+		switch name {
+		case "min", "max":
+			limit, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				return fmt.Sprintf("has an invalid %s rule %q", name, arg)
+			}
+
+			n, isLen := measure(fv)
+			if (name == "min" && n < limit) || (name == "max" && n > limit) {
+				qualifier := "at least"
+				if name == "max" {
+					qualifier = "at most"
+				}
+				if isLen {
+					return fmt.Sprintf("must have a length of %s %s", qualifier, arg)
+				}
+				return fmt.Sprintf("must be %s %s", qualifier, arg)
+			}
+		case "oneof":
+			value := fmt.Sprint(fv.Interface())
+			options := strings.Fields(arg)
+
+			found := false
+			for _, option := range options {
+				found = found || option == value
+			}
+			if !found {
+				return "must be one of " + strings.Join(options, ", ")
+			}
+		case "":
+		default:
+			return fmt.Sprintf("has an unknown validation rule %q", name)
+		}
+	}
+
+	return ""
+}
+
+// measure returns the value of numbers, or the length of strings, slices, and maps. The second
+// return value reports whether a length was measured.
+func measure(fv reflect.Value) (float64, bool) {
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(fv.Int()), false
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(fv.Uint()), false
+	case reflect.Float32, reflect.Float64:
+		return fv.Float(), false
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return float64(fv.Len()), true
+	}
+
+	return 0, false
+}
+
+// bindErrorResponse renders err, returned by Bind, as a 400 response listing every invalid field.
+func bindErrorResponse(ctx context.Context, err error) events.APIGatewayProxyResponse {
+	var body struct {
+		Message string       `json:"message"`
+		Errors  []FieldError `json:"errors,omitempty"`
+	}
+
+	body.Message = err.Error()
+	if bindErr, ok := err.(*BindError); ok {
+		body.Message = "invalid request"
+		body.Errors = bindErr.Fields
+	}
+
+	return respond.JSONContext(ctx, http.StatusBadRequest, body)
+}
@@ -0,0 +1,92 @@
+package lambdarouter
+
+import (
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type listOrdersRequest struct {
+	UserID  int      `path:"userID" validate:"required"`
+	Page    *int     `query:"page" validate:"min=1"`
+	Status  []string `query:"status"`
+	Version string   `header:"X-Api-Version" validate:"oneof=1 2"`
+	Note    string   `json:"note" validate:"max=5"`
+}
+
+type searchRequest struct {
+	Query string   `form:"q" validate:"required"`
+	Limit int      `query:"limit" default:"20"`
+	Sort  []string `form:"sort" default:"score,date"`
+}
+
+func TestBind(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Bind function should")
+	{
+		desc(t, 2, "populate fields from the path, query string, headers, and body")
+		var in listOrdersRequest
+		err := Bind(events.APIGatewayProxyRequest{
+			PathParameters:                  map[string]string{"userID": "42"},
+			QueryStringParameters:           map[string]string{"page": "2"},
+			MultiValueQueryStringParameters: map[string][]string{"status": {"open", "paid"}},
+			Headers:                         map[string]string{"x-api-version": "2"},
+			Body:                            `{"note": "hi"}`,
+		}, &in)
+
+		a.NoError(err)
+		a.Exactly(42, in.UserID)
+		a.Exactly(2, *in.Page)
+		a.Exactly([]string{"open", "paid"}, in.Status)
+		a.Exactly("2", in.Version)
+		a.Exactly("hi", in.Note)
+
+		desc(t, 2, "report every invalid field")
+		in = listOrdersRequest{}
+		err = Bind(events.APIGatewayProxyRequest{
+			QueryStringParameters: map[string]string{"page": "zero"},
+			Headers:               map[string]string{"X-Api-Version": "3"},
+			Body:                  `{"note": "too long"}`,
+		}, &in)
+
+		a.Exactly(&BindError{Fields: []FieldError{
+			{Field: "userID", Message: "is required"},
+			{Field: "page", Message: `"zero" is not a valid integer`},
+			{Field: "X-Api-Version", Message: "must be one of 1, 2"},
+			{Field: "note", Message: "must have a length of at most 5"},
+		}}, err)
+
+		desc(t, 2, "populate fields from urlencoded form bodies")
+		var search searchRequest
+		err = Bind(events.APIGatewayProxyRequest{
+			Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded; charset=utf-8"},
+			Body:    "q=lambda+router&sort=date",
+		}, &search)
+
+		a.NoError(err)
+		a.Exactly("lambda router", search.Query)
+		a.Exactly([]string{"date"}, search.Sort)
+
+		desc(t, 2, "set fields without values to their defaults")
+		a.Exactly(20, search.Limit)
+
+		search = searchRequest{}
+		err = Bind(events.APIGatewayProxyRequest{
+			Headers:               map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			QueryStringParameters: map[string]string{"limit": "5"},
+			Body:                  "q=go",
+		}, &search)
+
+		a.NoError(err)
+		a.Exactly(5, search.Limit)
+		a.Exactly([]string{"score", "date"}, search.Sort)
+
+		desc(t, 2, "return an error when the body is malformed")
+		a.Error(Bind(events.APIGatewayProxyRequest{Body: "{"}, &in))
+
+		desc(t, 2, "return an error when not given a pointer to a struct")
+		a.Error(Bind(events.APIGatewayProxyRequest{}, in))
+	}
+}
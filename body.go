@@ -0,0 +1,233 @@
+package lambdarouter
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// WithBodyDecoding makes the router decode the bodies of requests before invoking their handlers,
+// so handlers and Bind see plain bytes. Bodies compressed with gzip or deflate, as named by their
+// Content-Encoding header, are decompressed, and the Content-Encoding and Content-Length headers
+// are removed. Base64 encoded bodies are decoded, unless the decoded body is not valid UTF-8, in
+// which case it remains base64 encoded with IsBase64Encoded set, as the proxy payload can only
+// carry text. Requests with other content encodings are responded to with a 415, and those whose
+// body cannot be decoded with a 400.
+func WithBodyDecoding() Option {
+	return func(r *Router) {
+		r.decodeBodies = true
+	}
+}
+
+// WithMaxBodySize makes the router respond with a 413 to requests whose body is larger than n bytes,
+// without invoking their handlers, to protect handlers from decoding pathological payloads. The
+// size of base64 encoded bodies is that of the bytes they encode, and with WithBodyDecoding the
+// limit applies to the decompressed body as well. Routes can be given their own limit with
+// WithRouteMaxBodySize. Payloads far larger than any body the limits allow are responded to before
+// they are decoded.
+func WithMaxBodySize(n int64) Option {
+	return func(r *Router) {
+		r.maxBodySize = n
+	}
+}
+
+// WithRouteMaxBodySize limits the bodies of requests to a single route to n bytes, as
+// WithMaxBodySize does for every route, taking precedence over the limit of the router. A negative
+// n removes the limit for the route.
+func WithRouteMaxBodySize(n int64) RouteOption {
+	return func(e *event) {
+		e.maxBodySize = n
+	}
+}
+
+// bodyLimit returns the largest body, in bytes, which requests to the route of e may have, or zero
+// if their bodies are unlimited.
+func (r Router) bodyLimit(e event) int64 {
+	switch {
+	case e.maxBodySize > 0:
+		return e.maxBodySize
+	case e.maxBodySize < 0:
+		return 0
+	}
+
+	return r.maxBodySize
+}
+
+// maxRequestOverhead is the most the rest of a request, such as its headers, query string and
+// request context, is assumed to take up of a payload, which is more than API Gateway allows.
+const maxRequestOverhead = 1 << 20
+
+// limitBody records the body limit of the route of e, which was added to the table.
+func (t *routeTable) limitBody(e event) {
+	switch {
+	case e.maxBodySize < 0:
+		t.largestBody = -1
+	case t.largestBody >= 0 && e.maxBodySize > t.largestBody:
+		t.largestBody = e.maxBodySize
+	}
+}
+
+// payloadTooLarge reports whether payload is too large to hold a request whose body is within the
+// body limit of any route, so it can be rejected before it is decoded, along with the largest of
+// those limits. A byte of a body takes up at most eight bytes of a payload, when it is base64
+// encoded and every character is escaped as in \u0041, so the check is loose, but it keeps
+// pathological payloads from being decoded.
+func (r Router) payloadTooLarge(payload []byte) (int64, bool) {
+	if r.maxBodySize <= 0 || r.table == nil {
+		return 0, false
+	}
+
+	r.table.mu.RLock()
+	limit := r.table.largestBody
+	r.table.mu.RUnlock()
+
+	switch {
+	case limit < 0:
+		return 0, false
+	case limit < r.maxBodySize:
+		limit = r.maxBodySize
+	}
+
+	return limit, int64(len(payload)) > 8*limit+maxRequestOverhead
+}
+
+// rejectPayload responds to an invocation whose payload is too large for the body of its request
+// to be within limit, as the router responds to requests whose body is, without decoding it. The
+// request is unknown, so the hooks added by OnRequest and OnResponse do not run.
+func (r Router) rejectPayload(ctx context.Context, limit int64) ([]byte, error) {
+	req := events.APIGatewayProxyRequest{}
+
+	res, err := r.errorResponse(ctx, req, bodyTooLarge(limit))
+	return r.finishResponse(ctx, req, Route{}, res, err)
+}
+
+// bodySize returns the size in bytes of the body of req, once base64 decoded.
+func bodySize(req events.APIGatewayProxyRequest) int64 {
+	if !req.IsBase64Encoded {
+		return int64(len(req.Body))
+	}
+
+	body := strings.TrimRight(req.Body, "=")
+	return int64(len(body)) * 3 / 4
+}
+
+func bodyTooLarge(limit int64) error {
+	return &HTTPError{
+		Status: http.StatusRequestEntityTooLarge,
+		Detail: "body exceeds " + strconv.FormatInt(limit, 10) + " bytes",
+	}
+}
+
+// decodeBody decodes the body of req as described by WithBodyDecoding, reporting whether it
+// changed. Decompressed bodies larger than limit bytes are rejected, unless limit is zero.
+func decodeBody(req *events.APIGatewayProxyRequest, limit int64) (bool, error) {
+	encoding := ""
+	if values := headerValues(*req, "Content-Encoding"); len(values) > 0 {
+		encoding = strings.ToLower(strings.TrimSpace(values[0]))
+	}
+
+	if !req.IsBase64Encoded && (encoding == "" || encoding == "identity") {
+		return false, nil
+	}
+
+	body := []byte(req.Body)
+	if req.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(req.Body)
+		if err != nil {
+			return false, &HTTPError{Status: http.StatusBadRequest, Detail: "body is not valid base64"}
+		}
+		body = decoded
+	}
+
+	switch encoding {
+	case "", "identity":
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return false, &HTTPError{Status: http.StatusBadRequest, Detail: "body is not valid gzip"}
+		}
+		if body, err = readAll(zr, limit); err != nil {
+			return false, decodeError(err, limit, "gzip")
+		}
+	case "deflate":
+		// Deflate bodies should be wrapped in the zlib format, but some clients send raw deflate
+		// data, so it is accepted as well.
+		rc, err := zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			rc = flate.NewReader(bytes.NewReader(body))
+		}
+		if body, err = readAll(rc, limit); err != nil {
+			return false, decodeError(err, limit, "deflate")
+		}
+	default:
+		return false, &HTTPError{
+			Status: http.StatusUnsupportedMediaType,
+			Detail: "unsupported content encoding " + encoding,
+		}
+	}
+
+	if encoding != "" {
+		deleteHeader(req, "Content-Encoding")
+		deleteHeader(req, "Content-Length")
+	}
+
+	if utf8.Valid(body) {
+		req.Body, req.IsBase64Encoded = string(body), false
+	} else {
+		req.Body, req.IsBase64Encoded = base64.StdEncoding.EncodeToString(body), true
+	}
+
+	return true, nil
+}
+
+// errTooLarge is returned by readAll when the data it reads exceeds its limit.
+var errTooLarge = errors.New("body too large")
+
+// readAll reads r until EOF, failing with errTooLarge once it has read more than limit bytes,
+// unless limit is zero.
+func readAll(r io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(r)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err == nil && int64(len(b)) > limit {
+		err = errTooLarge
+	}
+
+	return b, err
+}
+
+// decodeError returns the error for a body which could not be decompressed from encoding.
+func decodeError(err error, limit int64, encoding string) error {
+	if errors.Is(err, errTooLarge) {
+		return bodyTooLarge(limit)
+	}
+
+	return &HTTPError{Status: http.StatusBadRequest, Detail: "body is not valid " + encoding}
+}
+
+// deleteHeader removes the named header from req, regardless of the case of its name.
+func deleteHeader(req *events.APIGatewayProxyRequest, name string) {
+	for key := range req.Headers {
+		if strings.EqualFold(key, name) {
+			delete(req.Headers, key)
+		}
+	}
+	for key := range req.MultiValueHeaders {
+		if strings.EqualFold(key, name) {
+			delete(req.MultiValueHeaders, key)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package lambdarouter
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyDecoding(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with body decoding and")
+	r := New("prefix", WithBodyDecoding())
+	r.Post("echo", lambda.NewHandler(func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		fromCtx, _ := RequestFromContext(ctx)
+		a.Exactly(req.Body, fromCtx.Body)
+
+		var in struct {
+			Name string `json:"name"`
+		}
+		if err := Bind(req, &in); err != nil {
+			return events.APIGatewayProxyResponse{}, err
+		}
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusOK,
+			Body:       in.Name + " " + req.Headers["Content-Encoding"],
+		}, nil
+	}))
+	r.Post("binary", lambda.NewHandler(func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: req.Body, IsBase64Encoded: req.IsBase64Encoded}, nil
+	}))
+
+	compress := func(encoding string, body []byte) string {
+		var buf bytes.Buffer
+		var w io.WriteCloser
+		switch encoding {
+		case "gzip":
+			w = gzip.NewWriter(&buf)
+		case "deflate":
+			w = zlib.NewWriter(&buf)
+		default:
+			w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+		}
+		_, _ = w.Write(body)
+		_ = w.Close()
+		return base64.StdEncoding.EncodeToString(buf.Bytes())
+	}
+	invoke := func(path, encoding, body string, base64Encoded bool) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod:      http.MethodPost,
+			Path:            path,
+			Headers:         map[string]string{"Content-Encoding": encoding},
+			Body:            body,
+			IsBase64Encoded: base64Encoded,
+		})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "WithBodyDecoding option should")
+	{
+		desc(t, 4, "decompress gzip and deflate bodies")
+		body := []byte(`{"name": "mitchell"}`)
+		for _, encoding := range []string{"gzip", "deflate", "raw"} {
+			header := encoding
+			if encoding == "raw" {
+				header = "deflate"
+			}
+			res := invoke("/prefix/echo", header, compress(encoding, body), true)
+			a.Exactly(http.StatusOK, res.StatusCode)
+			a.Exactly("mitchell ", res.Body)
+		}
+
+		desc(t, 4, "decode base64 bodies")
+		res := invoke("/prefix/echo", "", base64.StdEncoding.EncodeToString(body), true)
+		a.Exactly("mitchell ", res.Body)
+
+		desc(t, 4, "keep binary bodies base64 encoded")
+		binary := base64.StdEncoding.EncodeToString([]byte{0xff, 0xfe})
+		res = invoke("/prefix/binary", "gzip", compress("gzip", []byte{0xff, 0xfe}), true)
+		a.True(res.IsBase64Encoded)
+		a.Exactly(binary, res.Body)
+
+		desc(t, 4, "respond with a 415 for unsupported encodings")
+		res = invoke("/prefix/echo", "br", "x", false)
+		a.Exactly(http.StatusUnsupportedMediaType, res.StatusCode)
+
+		desc(t, 4, "respond with a 400 for bodies which cannot be decoded")
+		res = invoke("/prefix/echo", "gzip", "not gzip", false)
+		a.Exactly(http.StatusBadRequest, res.StatusCode)
+		res = invoke("/prefix/echo", "", "%%%", true)
+		a.Exactly(http.StatusBadRequest, res.StatusCode)
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with body limits and")
+	invoked := 0
+	handler := lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		invoked++
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	})
+	r := New("prefix", WithMaxBodySize(8), WithBodyDecoding())
+	r.Post("small", handler)
+	r.Post("large", handler, WithRouteMaxBodySize(16))
+	r.Post("unlimited", handler, WithRouteMaxBodySize(-1))
+	r.Post("compressed", handler, WithRouteMaxBodySize(64))
+
+	invoke := func(path, body string, base64Encoded bool, headers map[string]string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod:      http.MethodPost,
+			Path:            path,
+			Headers:         headers,
+			Body:            body,
+			IsBase64Encoded: base64Encoded,
+		})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "WithMaxBodySize option should")
+	{
+		desc(t, 4, "respond with a 413 to larger bodies without invoking the handler")
+		res := invoke("/prefix/small", "123456789", false, nil)
+		a.Exactly(http.StatusRequestEntityTooLarge, res.StatusCode)
+		a.Exactly("body exceeds 8 bytes", res.Body)
+		a.Exactly(0, invoked)
+
+		desc(t, 4, "measure base64 bodies by the bytes they encode")
+		res = invoke("/prefix/small", base64.StdEncoding.EncodeToString([]byte("12345678")), true, nil)
+		a.Exactly(http.StatusOK, res.StatusCode)
+
+		desc(t, 4, "limit decompressed bodies")
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, _ = zw.Write(bytes.Repeat([]byte("a"), 1000))
+		_ = zw.Close()
+		res = invoke("/prefix/unlimited", base64.StdEncoding.EncodeToString(buf.Bytes()), true, map[string]string{"Content-Encoding": "gzip"})
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.True(buf.Len() < 64)
+		res = invoke("/prefix/compressed", base64.StdEncoding.EncodeToString(buf.Bytes()), true, map[string]string{"Content-Encoding": "gzip"})
+		a.Exactly(http.StatusRequestEntityTooLarge, res.StatusCode)
+
+		desc(t, 4, "respond to payloads far larger than any body it allows before decoding them")
+		codec := &countingCodec{}
+		limited := New("prefix", WithMaxBodySize(8), WithCodec(codec))
+		limited.Post("small", handler)
+		limited.Post("large", handler, WithRouteMaxBodySize(16))
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodPost,
+			Path:       "/prefix/large",
+			Body:       strings.Repeat("a", 2<<20),
+		})
+		resjson, err := limited.Invoke(context.Background(), payload)
+		a.NoError(err)
+		a.Contains(string(resjson), `"statusCode":413`)
+		a.Contains(string(resjson), "body exceeds 16 bytes")
+		a.Zero(codec.unmarshals)
+	}
+
+	desc(t, 2, "WithRouteMaxBodySize option should")
+	{
+		desc(t, 4, "take precedence over the limit of the router")
+		res := invoke("/prefix/large", "0123456789abcdef", false, nil)
+		a.Exactly(http.StatusOK, res.StatusCode)
+		res = invoke("/prefix/large", "0123456789abcdefg", false, nil)
+		a.Exactly(http.StatusRequestEntityTooLarge, res.StatusCode)
+		res = invoke("/prefix/unlimited", "0123456789abcdefg", false, nil)
+		a.Exactly(http.StatusOK, res.StatusCode)
+	}
+}
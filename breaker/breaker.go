@@ -0,0 +1,230 @@
+// Package breaker provides middleware which stops invoking the handlers of routes whose
+// dependencies are failing, responding with a 503 instead until a probe succeeds, so functions
+// neither wait on nor pile more load onto a downstream service which is known to be unavailable.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+)
+
+// State is the state of the circuit of a route.
+type State struct {
+	// Requests and Failures count the requests handled since WindowStart, and those which failed.
+	Requests    int64
+	Failures    int64
+	WindowStart time.Time
+
+	// Opened is when the circuit was opened, and is zero while it is closed.
+	Opened time.Time
+
+	// Probe is when the request probing whether an open circuit can be closed began, and is zero
+	// while no request is probing.
+	Probe time.Time
+}
+
+// Store holds the circuits of a breaker.
+type Store interface {
+	// Update applies fn to the state of the circuit of key, which is the zero State if it has none,
+	// and stores the result, which it returns. Stores shared by containers may apply fn more than
+	// once, to the latest state, when the state changes concurrently.
+	Update(ctx context.Context, key string, fn func(s *State)) (State, error)
+}
+
+// MemoryStore is a Store which holds circuits in the memory of the container, so each concurrent
+// execution environment of a function opens its circuits separately.
+type MemoryStore struct {
+	mu       sync.Mutex
+	circuits map[string]State
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{circuits: map[string]State{}}
+}
+
+// Update implements the Store interface for the MemoryStore type.
+func (s *MemoryStore) Update(_ context.Context, key string, fn func(s *State)) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.circuits[key]
+	fn(&st)
+	s.circuits[key] = st
+
+	return st, nil
+}
+
+// Config configures the circuit breaker middleware.
+type Config struct {
+	// Threshold is the fraction of requests which must fail within a window for the circuit to
+	// open. If zero, it is 0.5.
+	Threshold float64
+
+	// MinRequests is the number of requests a window must have before its failures can open the
+	// circuit, so a single failure does not. If zero, it is 10.
+	MinRequests int64
+
+	// Window is how long requests are counted before the counts start over. If zero, it is a
+	// minute.
+	Window time.Duration
+
+	// Cooldown is how long the circuit stays open before a request is let through to probe whether
+	// it can be closed. If zero, it is 30 seconds.
+	Cooldown time.Duration
+
+	// Store holds the circuits. If nil, a MemoryStore is used.
+	Store Store
+
+	// Failed reports whether an invocation of a handler failed. If nil, invocations which return
+	// an error other than an HTTPError of a status below 500, or a response with a 5xx status,
+	// fail, the response being decoded with the codec of the router.
+	Failed func(res []byte, err error) bool
+}
+
+// Middleware returns middleware which keeps a circuit for each route it is used by. The circuit
+// opens once cfg.Threshold of the requests of a window fail, after which requests are responded to
+// with a 503, whose Retry-After header gives the seconds until the cooldown ends, without invoking
+// the handler. After cfg.Cooldown one request at a time is let through: the circuit closes if it
+// succeeds and stays open for another cooldown if it fails. Errors of the store are ignored, so
+// requests are handled as if the circuit were closed rather than failing with the store.
+func Middleware(cfg Config) lambdarouter.Middleware {
+	if cfg.Threshold == 0 {
+		cfg.Threshold = 0.5
+	}
+	if cfg.MinRequests == 0 {
+		cfg.MinRequests = 10
+	}
+	if cfg.Window == 0 {
+		cfg.Window = time.Minute
+	}
+	if cfg.Cooldown == 0 {
+		cfg.Cooldown = 30 * time.Second
+	}
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryStore()
+	}
+
+	return func(next lambda.Handler) lambda.Handler {
+		return breaker{cfg: cfg, next: next}
+	}
+}
+
+type breaker struct {
+	cfg  Config
+	next lambda.Handler
+}
+
+func (b breaker) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	rt, _ := lambdarouter.RouteFromContext(ctx)
+	key := rt.String()
+
+	now := time.Now()
+	var allowed, probe bool
+
+	st, err := b.cfg.Store.Update(ctx, key, func(s *State) {
+		allowed, probe = b.admit(s, now)
+	})
+	if err != nil {
+		allowed, probe = true, false
+	}
+
+	if !allowed {
+		seconds := int(math.Ceil(st.Opened.Add(b.cfg.Cooldown).Sub(now).Seconds()))
+		if seconds < 1 {
+			seconds = 1
+		}
+
+		return nil, &lambdarouter.HTTPError{
+			Status:  http.StatusServiceUnavailable,
+			Headers: map[string]string{"Retry-After": strconv.Itoa(seconds)},
+		}
+	}
+
+	res, err := b.next.Invoke(ctx, payload)
+
+	var failed bool
+	if b.cfg.Failed != nil {
+		failed = b.cfg.Failed(res, err)
+	} else {
+		failed = defaultFailed(lambdarouter.CodecFrom(ctx), res, err)
+	}
+
+	_, _ = b.cfg.Store.Update(ctx, key, func(s *State) {
+		b.record(s, time.Now(), probe, failed)
+	})
+
+	return res, err
+}
+
+// admit reports whether a request may be handled given the state of its circuit, and whether it
+// probes an open circuit, marking the probe in s.
+func (b breaker) admit(s *State, now time.Time) (allowed, probe bool) {
+	if s.Opened.IsZero() {
+		return true, false
+	}
+	if now.Before(s.Opened.Add(b.cfg.Cooldown)) {
+		return false, false
+	}
+
+	// A probe which never reported back, such as one whose container was stopped, is given up on
+	// after a cooldown.
+	if !s.Probe.IsZero() && now.Before(s.Probe.Add(b.cfg.Cooldown)) {
+		return false, false
+	}
+
+	s.Probe = now
+	return true, true
+}
+
+// record counts the outcome of a request in the state of its circuit, opening or closing it.
+func (b breaker) record(s *State, now time.Time, probe, failed bool) {
+	if probe {
+		if failed {
+			s.Opened, s.Probe = now, time.Time{}
+		} else {
+			*s = State{WindowStart: now}
+		}
+		return
+	}
+
+	if !s.Opened.IsZero() {
+		return
+	}
+
+	if now.Sub(s.WindowStart) >= b.cfg.Window {
+		s.Requests, s.Failures, s.WindowStart = 0, 0, now
+	}
+
+	s.Requests++
+	if failed {
+		s.Failures++
+	}
+
+	if s.Requests >= b.cfg.MinRequests && float64(s.Failures) >= b.cfg.Threshold*float64(s.Requests) {
+		s.Opened = now
+	}
+}
+
+// defaultFailed reports whether an invocation failed for a Config without a Failed function.
+func defaultFailed(codec lambdarouter.Codec, res []byte, err error) bool {
+	if err != nil {
+		var httpErr *lambdarouter.HTTPError
+		return !errors.As(err, &httpErr) || httpErr.Status >= http.StatusInternalServerError
+	}
+
+	var out struct {
+		StatusCode int `json:"statusCode"`
+	}
+	_ = codec.Unmarshal(res, &out)
+
+	return out.StatusCode >= http.StatusInternalServerError
+}
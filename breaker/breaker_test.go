@@ -0,0 +1,117 @@
+package breaker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with circuit breaker middleware and")
+	store := NewMemoryStore()
+	failing := true
+	invoked := 0
+
+	r := lambdarouter.New("")
+	r.Use(Middleware(Config{MinRequests: 4, Threshold: 0.5, Cooldown: time.Hour, Store: store}))
+	r.Get("orders", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		invoked++
+		if failing {
+			return events.APIGatewayProxyResponse{}, errors.New("downstream unavailable")
+		}
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}))
+	r.Get("users", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}))
+
+	invoke := func(path string) (events.APIGatewayProxyResponse, error) {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path})
+
+		var res events.APIGatewayProxyResponse
+		resjson, err := r.Invoke(context.Background(), payload)
+		if err == nil {
+			a.NoError(json.Unmarshal(resjson, &res))
+		}
+		return res, err
+	}
+
+	desc(t, 2, "Middleware should")
+	{
+		desc(t, 4, "invoke handlers until enough requests fail")
+		for i := 0; i < 4; i++ {
+			_, err := invoke("/orders")
+			a.EqualError(err, "downstream unavailable")
+		}
+		a.Exactly(4, invoked)
+
+		desc(t, 4, "respond with a 503 while the circuit is open")
+		res, err := invoke("/orders")
+		a.NoError(err)
+		a.Exactly(http.StatusServiceUnavailable, res.StatusCode)
+		a.Exactly("3600", res.Headers["Retry-After"])
+		a.Exactly(4, invoked)
+
+		desc(t, 4, "keep a circuit for each route")
+		res, _ = invoke("/users")
+		a.Exactly(http.StatusOK, res.StatusCode)
+
+		desc(t, 4, "let one request probe the circuit after the cooldown")
+		_, _ = store.Update(context.Background(), "GET /orders", func(s *State) {
+			s.Opened = s.Opened.Add(-time.Hour)
+		})
+		_, err = invoke("/orders")
+		a.EqualError(err, "downstream unavailable")
+		a.Exactly(5, invoked)
+
+		desc(t, 4, "keep the circuit open when the probe fails")
+		res, _ = invoke("/orders")
+		a.Exactly(http.StatusServiceUnavailable, res.StatusCode)
+
+		desc(t, 4, "close the circuit when the probe succeeds")
+		failing = false
+		_, _ = store.Update(context.Background(), "GET /orders", func(s *State) {
+			s.Opened = s.Opened.Add(-time.Hour)
+		})
+		res, _ = invoke("/orders")
+		a.Exactly(http.StatusOK, res.StatusCode)
+		res, _ = invoke("/orders")
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly(7, invoked)
+	}
+}
+
+func TestDefaultFailed(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "defaultFailed function should")
+	{
+		desc(t, 2, "count errors and 5xx responses as failures")
+		a.True(defaultFailed(lambdarouter.StdCodec, nil, errors.New("broken")))
+		a.True(defaultFailed(lambdarouter.StdCodec, nil, &lambdarouter.HTTPError{Status: http.StatusBadGateway}))
+		a.True(defaultFailed(lambdarouter.StdCodec, []byte(`{"statusCode": 500}`), nil))
+
+		desc(t, 2, "not count client errors as failures")
+		a.False(defaultFailed(lambdarouter.StdCodec, nil, &lambdarouter.HTTPError{Status: http.StatusNotFound}))
+		a.False(defaultFailed(lambdarouter.StdCodec, []byte(`{"statusCode": 404}`), nil))
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
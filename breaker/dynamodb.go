@@ -0,0 +1,91 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mitchell/lambdarouter/internal/dynamo"
+)
+
+// dynamoAttempts is how many times a circuit is read and written before giving up, when other
+// containers write it at the same time.
+const dynamoAttempts = 3
+
+// DynamoDBStore is a Store which holds circuits in a DynamoDB table, so that a circuit opened by
+// one container is open for every container of a function. The table must have a string partition
+// key named pk.
+type DynamoDBStore struct {
+	table  string
+	client *dynamo.Client
+}
+
+// NewDynamoDBStore returns a store holding circuits in table. Requests to DynamoDB are signed with
+// the credentials of the execution role of the function, in the region it runs in.
+func NewDynamoDBStore(table string) *DynamoDBStore {
+	return &DynamoDBStore{table: table, client: dynamo.FromEnv()}
+}
+
+// Update implements the Store interface for the DynamoDBStore type. Circuits are written
+// conditionally on their version not having changed since they were read.
+func (s *DynamoDBStore) Update(ctx context.Context, key string, fn func(s *State)) (State, error) {
+	for attempt := 0; attempt < dynamoAttempts; attempt++ {
+		item, err := s.client.GetItem(ctx, s.table, dynamo.Item{"pk": dynamo.S(key)})
+		if err != nil {
+			return State{}, err
+		}
+
+		var st State
+		if item != nil {
+			st = State{
+				Requests:    item["requests"].Int(),
+				Failures:    item["failures"].Int(),
+				WindowStart: unixMilli(item["window"].Int()),
+				Opened:      unixMilli(item["opened"].Int()),
+				Probe:       unixMilli(item["probe"].Int()),
+			}
+		}
+		fn(&st)
+
+		next := dynamo.Item{
+			"pk":       dynamo.S(key),
+			"requests": dynamo.N(st.Requests),
+			"failures": dynamo.N(st.Failures),
+			"window":   dynamo.N(milli(st.WindowStart)),
+			"opened":   dynamo.N(milli(st.Opened)),
+			"probe":    dynamo.N(milli(st.Probe)),
+			"version":  dynamo.N(item["version"].Int() + 1),
+		}
+
+		if item == nil {
+			err = s.client.PutItem(ctx, s.table, next, "attribute_not_exists(pk)", nil, nil)
+		} else {
+			err = s.client.PutItem(ctx, s.table, next, "#version = :version",
+				map[string]string{"#version": "version"}, dynamo.Item{":version": item["version"]})
+		}
+		if err == nil {
+			return st, nil
+		}
+		if !dynamo.IsConditionFailed(err) {
+			return State{}, err
+		}
+	}
+
+	return State{}, errors.New("breaker: circuit " + key + " is contended")
+}
+
+// milli returns t in milliseconds since the epoch, or zero if t is zero.
+func milli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// unixMilli is the inverse of milli.
+func unixMilli(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
@@ -0,0 +1,48 @@
+package breaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mitchell/lambdarouter/internal/dynamotest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamoDBStore(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a fake DynamoDB and")
+	srv := dynamotest.NewServer()
+	defer srv.Close()
+
+	s := NewDynamoDBStore("circuits")
+	s.client = srv.Client()
+
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Millisecond)
+
+	desc(t, 2, "Update method should")
+	{
+		desc(t, 4, "start from the zero state")
+		st, err := s.Update(ctx, "GET /orders", func(s *State) {
+			a.Exactly(State{}, *s)
+			s.Requests, s.Failures, s.WindowStart = 1, 1, now
+		})
+		a.NoError(err)
+		a.Exactly(int64(1), st.Requests)
+
+		desc(t, 4, "store the state in the table")
+		st, err = s.Update(ctx, "GET /orders", func(s *State) {
+			s.Requests++
+			s.Opened = now
+		})
+		a.NoError(err)
+		a.Exactly(State{Requests: 2, Failures: 1, WindowStart: now, Opened: now}, st)
+
+		item := srv.Items("circuits")["GET /orders"]
+		a.Exactly(now.UnixMilli(), item["opened"].Int())
+		a.Exactly(int64(0), item["probe"].Int())
+		a.Exactly(int64(2), item["version"].Int())
+	}
+}
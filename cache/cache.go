@@ -0,0 +1,273 @@
+// Package cache provides middleware which caches the responses of routes, so expensive GET
+// handlers can be cached without placing a CDN in front of the API.
+package cache
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/mitchell/lambdarouter/internal/header"
+)
+
+// Store holds cached responses. Other stores, such as one backed by ElastiCache, can be used by
+// implementing it with their client.
+type Store interface {
+	// Get returns the response stored under key, reporting whether there is one which has not
+	// expired.
+	Get(ctx context.Context, key string) (response []byte, found bool, err error)
+
+	// Set stores response under key for ttl.
+	Set(ctx context.Context, key string, response []byte, ttl time.Duration) error
+}
+
+// MemoryStore is a Store which holds responses in the memory of the container, so each concurrent
+// execution environment of a function caches responses separately.
+type MemoryStore struct {
+	// MaxEntries is the most responses the store holds, after which the responses closest to
+	// expiring are evicted. If zero, the number is unlimited.
+	MaxEntries int
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+type entry struct {
+	response []byte
+	expires  time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore holding up to maxEntries responses, or any number if
+// maxEntries is zero.
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	return &MemoryStore{MaxEntries: maxEntries, entries: map[string]entry{}}
+}
+
+// Get implements the Store interface for the MemoryStore type.
+func (s *MemoryStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		return nil, false, nil
+	}
+
+	return e.response, true, nil
+}
+
+// Set implements the Store interface for the MemoryStore type.
+func (s *MemoryStore) Set(_ context.Context, key string, response []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.entries == nil {
+		s.entries = map[string]entry{}
+	}
+	s.entries[key] = entry{response: response, expires: time.Now().Add(ttl)}
+
+	if s.MaxEntries > 0 && len(s.entries) > s.MaxEntries {
+		s.evict()
+	}
+
+	return nil
+}
+
+// evict removes expired entries, and then the entries closest to expiring until the store is
+// within its size.
+func (s *MemoryStore) evict() {
+	now := time.Now()
+	keys := make([]string, 0, len(s.entries))
+
+	for key, e := range s.entries {
+		if now.After(e.expires) {
+			delete(s.entries, key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return s.entries[keys[i]].expires.Before(s.entries[keys[j]].expires)
+	})
+	for i := 0; len(s.entries) > s.MaxEntries; i++ {
+		delete(s.entries, keys[i])
+	}
+}
+
+// Config configures the caching middleware.
+type Config struct {
+	// Store holds the responses. If nil, a MemoryStore without a limit is used.
+	Store Store
+
+	// TTL is how long responses are cached for, unless their Cache-Control header gives a max-age.
+	TTL time.Duration
+
+	// Vary lists the request headers whose values responses depend on, such as Accept-Language.
+	// Requests with different values are cached separately.
+	Vary []string
+}
+
+// Middleware returns middleware which caches the successful responses of GET and HEAD requests,
+// keyed by their method, path, query string, and the headers in cfg.Vary. Responses are cached for
+// the s-maxage or max-age of their Cache-Control header if they have one, and for cfg.TTL
+// otherwise. Responses marked no-store or private are not cached, and requests marked no-cache
+// bypass the cache, storing a fresh response. Each response carries an X-Cache header of HIT or
+// MISS.
+func Middleware(cfg Config) lambdarouter.Middleware {
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryStore(0)
+	}
+
+	return func(next lambda.Handler) lambda.Handler {
+		return cacher{cfg: cfg, next: next}
+	}
+}
+
+type cacher struct {
+	cfg  Config
+	next lambda.Handler
+}
+
+func (c cacher) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	codec := lambdarouter.CodecFrom(ctx)
+
+	req, err := lambdarouter.RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.HTTPMethod != http.MethodGet && req.HTTPMethod != http.MethodHead {
+		return c.next.Invoke(ctx, payload)
+	}
+
+	key := c.key(req)
+
+	if !hasDirective(header.Get(req, "Cache-Control"), "no-cache") {
+		cached, found, err := c.cfg.Store.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			return withCacheHeader(codec, cached, "HIT")
+		}
+	}
+
+	res, err := c.next.Invoke(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var proxyRes events.APIGatewayProxyResponse
+	if err := codec.Unmarshal(res, &proxyRes); err != nil {
+		return res, nil
+	}
+
+	if ttl, ok := c.ttl(proxyRes); ok && proxyRes.StatusCode == http.StatusOK {
+		// Handlers such as those created by lambda.NewHandler reuse the buffer of their responses,
+		// so the response is copied before it is stored.
+		if err := c.cfg.Store.Set(ctx, key, append([]byte(nil), res...), ttl); err != nil {
+			return nil, err
+		}
+	}
+
+	return withCacheHeader(codec, res, "MISS")
+}
+
+// key returns the key a request is cached under.
+func (c cacher) key(req events.APIGatewayProxyRequest) string {
+	var b strings.Builder
+	b.WriteString(req.HTTPMethod + " " + req.Path)
+
+	query := req.MultiValueQueryStringParameters
+	if len(query) == 0 && len(req.QueryStringParameters) > 0 {
+		query = map[string][]string{}
+		for name, value := range req.QueryStringParameters {
+			query[name] = []string{value}
+		}
+	}
+
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		sep := "&"
+		if i == 0 {
+			sep = "?"
+		}
+		b.WriteString(sep + name + "=" + strings.Join(query[name], ","))
+	}
+
+	for _, name := range c.cfg.Vary {
+		b.WriteString("\n" + strings.ToLower(name) + ": " + header.Get(req, name))
+	}
+
+	return b.String()
+}
+
+// ttl returns how long res may be cached for, reporting false if it must not be.
+func (c cacher) ttl(res events.APIGatewayProxyResponse) (time.Duration, bool) {
+	cc := ""
+	for name, value := range res.Headers {
+		if strings.EqualFold(name, "Cache-Control") {
+			cc = value
+		}
+	}
+
+	if hasDirective(cc, "no-store") || hasDirective(cc, "private") || hasDirective(cc, "no-cache") {
+		return 0, false
+	}
+
+	for _, name := range []string{"s-maxage", "max-age"} {
+		if v, ok := directive(cc, name); ok {
+			seconds, err := strconv.Atoi(v)
+			if err != nil || seconds <= 0 {
+				return 0, false
+			}
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+
+	return c.cfg.TTL, c.cfg.TTL > 0
+}
+
+func withCacheHeader(codec lambdarouter.Codec, response []byte, status string) ([]byte, error) {
+	var res events.APIGatewayProxyResponse
+	if err := codec.Unmarshal(response, &res); err != nil {
+		return nil, err
+	}
+
+	if res.Headers == nil {
+		res.Headers = map[string]string{}
+	}
+	res.Headers["X-Cache"] = status
+
+	return codec.Marshal(res)
+}
+
+// directive returns the value of the named directive of a Cache-Control header value.
+func directive(cc, name string) (string, bool) {
+	for _, d := range strings.Split(cc, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(d), "=")
+		if strings.EqualFold(key, name) {
+			return strings.Trim(value, `"`), true
+		}
+	}
+
+	return "", false
+}
+
+func hasDirective(cc, name string) bool {
+	_, ok := directive(cc, name)
+	return ok
+}
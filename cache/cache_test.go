@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with cached routes and")
+	reports, private := 0, 0
+	r := lambdarouter.New("prefix")
+	r.Use(Middleware(Config{TTL: time.Minute, Vary: []string{"Accept-Language"}}))
+	r.Get("reports", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		reports++
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: "report " + strconv.Itoa(reports)}, nil
+	}))
+	r.Get("me", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		private++
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusOK,
+			Headers:    map[string]string{"Cache-Control": "private, max-age=60"},
+			Body:       "me " + strconv.Itoa(private),
+		}, nil
+	}))
+
+	invoke := func(path string, query, headers map[string]string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod:            http.MethodGet,
+			Path:                  path,
+			QueryStringParameters: query,
+			Headers:               headers,
+		})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "Middleware should")
+	{
+		desc(t, 4, "respond with cached responses without invoking the handler")
+		res := invoke("/prefix/reports", nil, nil)
+		a.Exactly("report 1", res.Body)
+		a.Exactly("MISS", res.Headers["X-Cache"])
+
+		res = invoke("/prefix/reports", nil, nil)
+		a.Exactly("report 1", res.Body)
+		a.Exactly("HIT", res.Headers["X-Cache"])
+
+		desc(t, 4, "cache requests with other query strings or varied headers separately")
+		res = invoke("/prefix/reports", map[string]string{"year": "2019"}, nil)
+		a.Exactly("report 2", res.Body)
+		res = invoke("/prefix/reports", nil, map[string]string{"accept-language": "fr"})
+		a.Exactly("report 3", res.Body)
+		res = invoke("/prefix/reports", nil, map[string]string{"accept-language": "fr"})
+		a.Exactly("report 3", res.Body)
+
+		desc(t, 4, "bypass the cache for requests marked no-cache")
+		res = invoke("/prefix/reports", nil, map[string]string{"Cache-Control": "no-cache"})
+		a.Exactly("report 4", res.Body)
+		res = invoke("/prefix/reports", nil, nil)
+		a.Exactly("report 4", res.Body)
+
+		desc(t, 4, "not cache responses marked private")
+		invoke("/prefix/me", nil, nil)
+		res = invoke("/prefix/me", nil, nil)
+		a.Exactly("me 2", res.Body)
+	}
+
+	desc(t, 2, "ttl method should")
+	{
+		c := cacher{cfg: Config{TTL: time.Minute}}
+		ttl := func(cc string) (time.Duration, bool) {
+			return c.ttl(events.APIGatewayProxyResponse{Headers: map[string]string{"cache-control": cc}})
+		}
+
+		desc(t, 4, "prefer the max-age of the response to the configured TTL")
+		d, ok := ttl("public, max-age=30, s-maxage=10")
+		a.True(ok)
+		a.Exactly(10*time.Second, d)
+		d, _ = ttl("max-age=30")
+		a.Exactly(30*time.Second, d)
+		d, _ = ttl("")
+		a.Exactly(time.Minute, d)
+
+		desc(t, 4, "report responses which must not be cached")
+		_, ok = ttl("no-store")
+		a.False(ok)
+		_, ok = ttl("max-age=0")
+		a.False(ok)
+	}
+
+	desc(t, 2, "MemoryStore type should")
+	{
+		s := NewMemoryStore(2)
+		ctx := context.Background()
+
+		desc(t, 4, "evict the responses closest to expiring when full")
+		_ = s.Set(ctx, "a", []byte("a"), time.Minute)
+		_ = s.Set(ctx, "b", []byte("b"), time.Hour)
+		_ = s.Set(ctx, "c", []byte("c"), time.Hour)
+		_, found, _ := s.Get(ctx, "a")
+		a.False(found)
+		v, found, _ := s.Get(ctx, "c")
+		a.True(found)
+		a.Exactly("c", string(v))
+
+		desc(t, 4, "not return expired responses")
+		_ = s.Set(ctx, "d", []byte("d"), -time.Second)
+		_, found, _ = s.Get(ctx, "d")
+		a.False(found)
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
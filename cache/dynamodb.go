@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/mitchell/lambdarouter/internal/dynamo"
+)
+
+// DynamoDBStore is a Store which holds responses in a DynamoDB table, so that they are shared by
+// every container of a function. The table must have a string partition key named pk. Each item
+// has a ttl attribute holding the time it expires, after which it can be deleted by enabling time
+// to live on the table; expired items are ignored either way.
+type DynamoDBStore struct {
+	table  string
+	client *dynamo.Client
+}
+
+// NewDynamoDBStore returns a store holding responses in table. Requests to DynamoDB are signed
+// with the credentials of the execution role of the function, in the region it runs in.
+func NewDynamoDBStore(table string) *DynamoDBStore {
+	return &DynamoDBStore{table: table, client: dynamo.FromEnv()}
+}
+
+// Get implements the Store interface for the DynamoDBStore type.
+func (s *DynamoDBStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	item, err := s.client.GetItem(ctx, s.table, dynamo.Item{"pk": dynamo.S(hashKey(key))})
+	if err != nil || item == nil {
+		return nil, false, err
+	}
+
+	if time.Now().Unix() >= item["ttl"].Int() {
+		return nil, false, nil
+	}
+
+	return item["response"].B, true, nil
+}
+
+// Set implements the Store interface for the DynamoDBStore type.
+func (s *DynamoDBStore) Set(ctx context.Context, key string, response []byte, ttl time.Duration) error {
+	return s.client.PutItem(ctx, s.table, dynamo.Item{
+		"pk":       dynamo.S(hashKey(key)),
+		"response": dynamo.B(response),
+		"ttl":      dynamo.N(time.Now().Add(ttl).Unix()),
+	}, "", nil, nil)
+}
+
+// hashKey shortens cache keys, which may include long query strings and header values, to fit
+// within the size limit of partition keys.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mitchell/lambdarouter/internal/dynamotest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamoDBStore(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a fake DynamoDB and")
+	srv := dynamotest.NewServer()
+	defer srv.Close()
+
+	s := NewDynamoDBStore("responses")
+	s.client = srv.Client()
+	ctx := context.Background()
+
+	desc(t, 2, "DynamoDBStore type should")
+	{
+		desc(t, 4, "return stored responses")
+		a.NoError(s.Set(ctx, "GET /reports", []byte(`{"statusCode": 200}`), time.Minute))
+		res, found, err := s.Get(ctx, "GET /reports")
+		a.NoError(err)
+		a.True(found)
+		a.Exactly(`{"statusCode": 200}`, string(res))
+
+		desc(t, 4, "not return missing or expired responses")
+		_, found, err = s.Get(ctx, "GET /other")
+		a.NoError(err)
+		a.False(found)
+
+		a.NoError(s.Set(ctx, "GET /old", []byte(`{}`), -time.Minute))
+		_, found, _ = s.Get(ctx, "GET /old")
+		a.False(found)
+	}
+}
@@ -0,0 +1,114 @@
+package lambdarouter
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+// WeightedHandler is one of the handlers a route created with Weighted chooses between.
+type WeightedHandler struct {
+	// Name identifies the handler, such as v1 or v2, and is what VariantFrom returns for the
+	// requests it handles.
+	Name string
+
+	// Weight is the share of requests the handler receives, relative to the weights of the other
+	// handlers. Handlers with a weight of zero receive none.
+	Weight int
+
+	Handler lambda.Handler
+}
+
+// HashKey returns the key a request is assigned a handler by, such as the ID of its user, so every
+// request with the same key is handled by the same handler. Requests for which it returns an empty
+// key are assigned a handler at random.
+type HashKey func(ctx context.Context, req events.APIGatewayProxyRequest) string
+
+// HashByHeader returns a HashKey which keys requests by the named header, regardless of the case of
+// its name.
+func HashByHeader(name string) HashKey {
+	return func(_ context.Context, req events.APIGatewayProxyRequest) string {
+		if values := headerValues(req, name); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+}
+
+// Weighted returns a handler which splits the requests of a route between handlers by their
+// weights, so a new implementation can be canaried on a share of the traffic of the route, as in:
+//
+//	r.Get("orders/{id}", lambdarouter.Weighted(lambdarouter.HashByHeader("X-User-Id"),
+//		lambdarouter.WeightedHandler{Name: "v1", Weight: 95, Handler: v1},
+//		lambdarouter.WeightedHandler{Name: "v2", Weight: 5, Handler: v2},
+//	))
+//
+// Requests are assigned a handler by the hash of their key, so a client keeps the same handler
+// for as long as the weights do not change. A nil key assigns every request at random. The name of
+// the handler of a request can be retrieved with VariantFrom, such as to tag its metrics.
+func Weighted(key HashKey, handlers ...WeightedHandler) lambda.Handler {
+	total := 0
+	for _, wh := range handlers {
+		if wh.Weight < 0 {
+			panic("weight of handler " + wh.Name + " is negative")
+		}
+		total += wh.Weight
+	}
+	if total == 0 {
+		panic("weighted handlers have no weight")
+	}
+
+	return weighted{key: key, handlers: handlers, total: total}
+}
+
+// VariantFrom returns the name of the handler chosen by Weighted for the current invocation, or an
+// empty string if none was.
+func VariantFrom(ctx context.Context) string {
+	name, _ := ctx.Value(variantKey).(string)
+	return name
+}
+
+type weighted struct {
+	key      HashKey
+	handlers []WeightedHandler
+	total    int
+}
+
+func (w weighted) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	var k string
+	if w.key != nil {
+		req, err := RequestFrom(ctx, payload)
+		if err != nil {
+			return nil, err
+		}
+		k = w.key(ctx, req)
+	}
+
+	var n int
+	if k == "" {
+		n = rand.Intn(w.total)
+	} else {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(k))
+		n = int(h.Sum32() % uint32(w.total))
+	}
+
+	wh := w.pick(n)
+
+	return wh.Handler.Invoke(context.WithValue(ctx, variantKey, wh.Name), payload)
+}
+
+// pick returns the handler whose share of the weights holds n.
+func (w weighted) pick(n int) WeightedHandler {
+	for _, wh := range w.handlers {
+		if n < wh.Weight {
+			return wh
+		}
+		n -= wh.Weight
+	}
+
+	return w.handlers[len(w.handlers)-1]
+}
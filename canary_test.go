@@ -0,0 +1,68 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeighted(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with a weighted route and")
+	version := func(name string) lambda.Handler {
+		return lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: name + " " + VariantFrom(ctx)}, nil
+		})
+	}
+
+	r := New("")
+	r.Get("orders", Weighted(HashByHeader("X-User-Id"),
+		WeightedHandler{Name: "v1", Weight: 90, Handler: version("one")},
+		WeightedHandler{Name: "v2", Weight: 10, Handler: version("two")},
+		WeightedHandler{Name: "v3", Weight: 0, Handler: version("three")},
+	))
+
+	invoke := func(user string) string {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodGet,
+			Path:       "/orders",
+			Headers:    map[string]string{"x-user-id": user},
+		})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res.Body
+	}
+
+	desc(t, 2, "Weighted function should")
+	{
+		desc(t, 4, "split requests between handlers by their weights")
+		counts := map[string]int{}
+		for i := 0; i < 1000; i++ {
+			counts[invoke(fmt.Sprintf("user-%d", i))]++
+		}
+		a.InDelta(900, counts["one v1"], 50)
+		a.InDelta(100, counts["two v2"], 50)
+		a.Zero(counts["three v3"])
+
+		desc(t, 4, "keep requests with the same key on the same handler")
+		first := invoke("user-42")
+		for i := 0; i < 10; i++ {
+			a.Exactly(first, invoke("user-42"))
+		}
+
+		desc(t, 4, "panic without any weight")
+		a.Panics(func() {
+			Weighted(nil, WeightedHandler{Name: "v1", Handler: version("one")})
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Package chiroutes registers the routes of a chi router on a lambdarouter.Router, easing the
+// migration of existing HTTP services into a Lambda function behind API Gateway.
+package chiroutes
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mitchell/lambdarouter"
+	"github.com/mitchell/lambdarouter/internal/pathprefix"
+)
+
+// Import walks the route tree of src and defines an equivalent route on r for every method and
+// pattern it finds. Each imported route invokes src itself through lambdarouter.WrapHTTP, so chi's
+// own middleware and URL parameters keep working unchanged. Routes are defined relative to the
+// current prefix of r, which is stripped from the path before the request reaches src, along with
+// the values of any path parameters it holds. An error is returned for the first route which cannot
+// be defined, such as one which conflicts with a route r already has.
+func Import(r *lambdarouter.Router, src chi.Router) error {
+	handler := lambdarouter.WrapHTTP(pathprefix.Strip(r.Prefix(), src))
+
+	return chi.Walk(src, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		return r.TryHandle(method, convertPattern(route), handler)
+	})
+}
+
+// convertPattern translates a chi route pattern into a lambdarouter path template. Regular
+// expressions are dropped from parameters and a trailing wildcard becomes a greedy parameter.
+func convertPattern(pattern string) string {
+	segs := strings.Split(pattern, "/")
+
+	for i, seg := range segs {
+		switch {
+		case seg == "*" && i == len(segs)-1:
+			segs[i] = "{proxy+}"
+		case strings.HasPrefix(seg, "{") && strings.Contains(seg, ":"):
+			segs[i] = seg[:strings.Index(seg, ":")] + "}"
+		}
+	}
+
+	return strings.Join(segs, "/")
+}
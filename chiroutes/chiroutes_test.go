@@ -0,0 +1,84 @@
+package chiroutes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-chi/chi/v5"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImport(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a chi router and")
+	src := chi.NewRouter()
+	src.Get("/users/{id:[0-9]+}", func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("user " + chi.URLParam(req, "id")))
+	})
+	src.Route("/files", func(r chi.Router) {
+		r.Post("/*", func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte("file " + chi.URLParam(req, "*")))
+		})
+	})
+
+	desc(t, 2, "Import function should")
+	{
+		r := lambdarouter.New("prefix")
+
+		desc(t, 4, "define every route of the chi router")
+		a.NoError(Import(&r, src))
+		a.Exactly([]lambdarouter.Route{
+			{Method: http.MethodGet, Path: "/prefix/users/{id}"},
+			{Method: http.MethodPost, Path: "/prefix/files/{proxy+}"},
+		}, r.Routes())
+
+		desc(t, 4, "route requests through the chi router")
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod:     http.MethodGet,
+			Path:           "/prefix/users/42",
+			PathParameters: map[string]string{"id": "42"},
+		})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("user 42", res.Body)
+
+		desc(t, 4, "strip group prefixes holding path parameters")
+		org := lambdarouter.New("prefix")
+		var importErr error
+		org.Group("orgs/{org}", func(r *lambdarouter.Router) {
+			importErr = Import(r, src)
+		})
+		a.NoError(importErr)
+
+		payload, _ = json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodGet,
+			Path:       "/prefix/orgs/acme/users/42",
+		})
+		resjson, err = org.Invoke(context.Background(), payload)
+		a.NoError(err)
+		a.NoError(json.Unmarshal(resjson, &res))
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("user 42", res.Body)
+
+		desc(t, 4, "return an error for routes which conflict with those of the router")
+		a.Error(Import(&r, src))
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
@@ -0,0 +1,161 @@
+package lambdarouter
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+// CloudFrontEvent is the event Lambda@Edge invokes functions with when a CloudFront distribution
+// receives a request or a response. It always holds a single record.
+type CloudFrontEvent struct {
+	Records []CloudFrontRecord `json:"Records"`
+}
+
+// CloudFrontRecord is a record of a CloudFrontEvent.
+type CloudFrontRecord struct {
+	CF struct {
+		Config   CloudFrontConfig    `json:"config"`
+		Request  CloudFrontRequest   `json:"request"`
+		Response *CloudFrontResponse `json:"response,omitempty"`
+	} `json:"cf"`
+}
+
+// CloudFrontConfig describes the distribution and the event of a CloudFrontRecord. Its EventType
+// is viewer-request, origin-request, origin-response, or viewer-response.
+type CloudFrontConfig struct {
+	DistributionDomainName string `json:"distributionDomainName"`
+	DistributionID         string `json:"distributionId"`
+	EventType              string `json:"eventType"`
+	RequestID              string `json:"requestId"`
+}
+
+// CloudFrontRequest is the request CloudFront received. Handlers of request events may return it,
+// changed or not, to have CloudFront carry on with it.
+type CloudFrontRequest struct {
+	ClientIP    string            `json:"clientIp"`
+	Method      string            `json:"method"`
+	URI         string            `json:"uri"`
+	Querystring string            `json:"querystring"`
+	Headers     CloudFrontHeaders `json:"headers"`
+	Body        *CloudFrontBody   `json:"body,omitempty"`
+}
+
+// CloudFrontBody is the body of a CloudFrontRequest, which is only included when the distribution
+// is configured to include it.
+type CloudFrontBody struct {
+	InputTruncated bool   `json:"inputTruncated"`
+	Action         string `json:"action"`
+	Encoding       string `json:"encoding"`
+	Data           string `json:"data"`
+}
+
+// CloudFrontResponse is a response generated at the edge, or the response of the origin in
+// response events.
+type CloudFrontResponse struct {
+	Status            string            `json:"status"`
+	StatusDescription string            `json:"statusDescription,omitempty"`
+	Headers           CloudFrontHeaders `json:"headers,omitempty"`
+	Body              string            `json:"body,omitempty"`
+	BodyEncoding      string            `json:"bodyEncoding,omitempty"`
+}
+
+// CloudFrontHeaders holds headers in the form CloudFront uses, keyed by their lowercase names.
+type CloudFrontHeaders map[string][]CloudFrontHeader
+
+// CloudFrontHeader is a value of a header, along with its name in its original case.
+type CloudFrontHeader struct {
+	Key   string `json:"key,omitempty"`
+	Value string `json:"value"`
+}
+
+// Get returns the first value of the named header, regardless of the case of its name.
+func (h CloudFrontHeaders) Get(name string) string {
+	if values := h[strings.ToLower(name)]; len(values) > 0 {
+		return values[0].Value
+	}
+
+	return ""
+}
+
+// Set replaces the values of the named header with value.
+func (h CloudFrontHeaders) Set(name, value string) {
+	h[strings.ToLower(name)] = []CloudFrontHeader{{Key: http.CanonicalHeaderKey(name), Value: value}}
+}
+
+// CloudFrontRespond returns a response generated at the edge with the given status, headers, and
+// body, to be returned by handlers of request events instead of forwarding the request.
+func CloudFrontRespond(status int, headers map[string]string, body string) CloudFrontResponse {
+	res := CloudFrontResponse{
+		Status:            strconv.Itoa(status),
+		StatusDescription: http.StatusText(status),
+		Headers:           CloudFrontHeaders{},
+		Body:              body,
+	}
+	for name, value := range headers {
+		res.Headers.Set(name, value)
+	}
+
+	return res
+}
+
+// CloudFrontRedirect returns a response generated at the edge which redirects to location with
+// the given status.
+func CloudFrontRedirect(status int, location string) CloudFrontResponse {
+	return CloudFrontRespond(status, map[string]string{"Location": location}, "")
+}
+
+type cloudFrontRoute struct {
+	method string
+	uri    string
+	h      lambda.Handler
+}
+
+// CloudFront adds a route for the events of Lambda@Edge, so edge functions can route requests as
+// other functions do. The method parameter is the method of the request, or empty to match any,
+// and the uri parameter is a pattern matched against its URI, in which * matches any run of
+// characters, and which matches any URI if empty. The handler parameter is invoked with the event,
+// which decodes into a CloudFrontEvent, and is wrapped by the middleware of the router, which
+// should not assume it handles HTTP requests of API Gateway. Handlers of request events return
+// either the request, to have CloudFront carry on with it, or a response such as one created by
+// CloudFrontRespond.
+//
+// Events are routed to the first route which matches them in the order they were defined. Events
+// which match no route are passed through, by responding with their response, if they have one, or
+// their request.
+func (r *Router) CloudFront(method, uri string, handler lambda.Handler) {
+	rt := cloudFrontRoute{method: strings.ToUpper(method), uri: uri, h: r.sourceHandler(handler)}
+
+	r.addSource(func(s *sourceRoutes) {
+		s.cloudFront = append(s.cloudFront[:len(s.cloudFront):len(s.cloudFront)], rt)
+	})
+}
+
+func (r Router) invokeCloudFront(ctx context.Context, routes []cloudFrontRoute, payload []byte) ([]byte, error) {
+	codec := r.jsonCodec()
+
+	var e CloudFrontEvent
+	if err := codec.Unmarshal(payload, &e); err != nil {
+		return nil, err
+	}
+
+	cf := e.Records[0].CF
+	for _, rt := range routes {
+		if (rt.method == "" || rt.method == cf.Request.Method) && matchPattern(rt.uri, cf.Request.URI) {
+			res, err := rt.h.Invoke(ctx, payload)
+			if err != nil {
+				r.logf("CloudFront %s %s: %v", cf.Request.Method, cf.Request.URI, err)
+			}
+			return res, err
+		}
+	}
+
+	if cf.Response != nil {
+		return codec.Marshal(cf.Response)
+	}
+
+	return codec.Marshal(cf.Request)
+}
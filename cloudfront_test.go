@@ -0,0 +1,66 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudFront(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with CloudFront routes and")
+	r := New("prefix")
+	r.CloudFront("GET", "/old/*", lambda.NewHandler(func(e CloudFrontEvent) (CloudFrontResponse, error) {
+		req := e.Records[0].CF.Request
+		return CloudFrontRedirect(http.StatusMovedPermanently, "/new/"+strings.TrimPrefix(req.URI, "/old/")), nil
+	}))
+	r.CloudFront("", "*/", lambda.NewHandler(func(e CloudFrontEvent) (CloudFrontRequest, error) {
+		req := e.Records[0].CF.Request
+		req.URI += "index.html"
+		req.Headers.Set("x-rewritten", "true")
+		return req, nil
+	}))
+
+	invoke := func(method, uri string) []byte {
+		var e CloudFrontEvent
+		e.Records = make([]CloudFrontRecord, 1)
+		e.Records[0].CF.Config.EventType = "viewer-request"
+		e.Records[0].CF.Request = CloudFrontRequest{
+			Method:  method,
+			URI:     uri,
+			Headers: CloudFrontHeaders{"host": {{Key: "Host", Value: "example.com"}}},
+		}
+		payload, _ := json.Marshal(e)
+
+		res, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+		return res
+	}
+
+	desc(t, 2, "CloudFront method should")
+	{
+		desc(t, 4, "route events by method and URI")
+		var res CloudFrontResponse
+		a.NoError(json.Unmarshal(invoke(http.MethodGet, "/old/page"), &res))
+		a.Exactly("301", res.Status)
+		a.Exactly("Moved Permanently", res.StatusDescription)
+		a.Exactly("/new/page", res.Headers.Get("Location"))
+		a.Exactly("Location", res.Headers["location"][0].Key)
+
+		var req CloudFrontRequest
+		a.NoError(json.Unmarshal(invoke(http.MethodGet, "/docs/"), &req))
+		a.Exactly("/docs/index.html", req.URI)
+		a.Exactly("true", req.Headers.Get("X-Rewritten"))
+
+		desc(t, 4, "pass unmatched requests through")
+		a.NoError(json.Unmarshal(invoke(http.MethodPost, "/old/page"), &req))
+		a.Exactly("/old/page", req.URI)
+		a.Exactly("example.com", req.Headers.Get("Host"))
+	}
+}
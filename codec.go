@@ -0,0 +1,60 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mitchell/lambdarouter/internal/jsoncodec"
+)
+
+// Codec encodes and decodes JSON. It is satisfied by the APIs of most JSON packages, such as
+// jsoniter's ConfigCompatibleWithStandardLibrary and sonic's ConfigStd, so the router can use a
+// faster codec than encoding/json, whose work dominates the cost of routing an invocation.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// StdCodec is the Codec of the encoding/json package, which routers use unless created with
+// WithCodec.
+var StdCodec Codec = stdCodec{}
+
+type stdCodec struct{}
+
+func (stdCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// WithCodec sets the codec the router decodes requests and encodes the responses it renders with,
+// in place of encoding/json. Handlers and middleware can use the same codec by retrieving it with
+// CodecFrom, as HandlerFunc, HandlerOf, and RequestFrom do. The codec must produce and accept the
+// JSON of encoding/json, including its handling of struct tags, as the types of the events package
+// rely on it.
+func WithCodec(c Codec) Option {
+	return func(r *Router) {
+		r.codec = c
+	}
+}
+
+// CodecFrom returns the codec of the router which is routing the current invocation, or StdCodec
+// if the invocation was not routed by a router created with WithCodec.
+func CodecFrom(ctx context.Context) Codec {
+	if c, ok := jsoncodec.FromContext(ctx); ok {
+		return c
+	}
+
+	return StdCodec
+}
+
+// jsonCodec returns the codec of the router.
+func (r Router) jsonCodec() Codec {
+	if r.codec == nil {
+		return StdCodec
+	}
+
+	return r.codec
+}
@@ -0,0 +1,120 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingCodec is a Codec which counts its calls, and records the types it decodes.
+type countingCodec struct {
+	marshals, unmarshals int
+	decoded              []string
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshals++
+	return json.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.unmarshals++
+	c.decoded = append(c.decoded, fmt.Sprintf("%T", v))
+	return json.Unmarshal(data, v)
+}
+
+func TestWithCodec(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a router with a codec and")
+	codec := &countingCodec{}
+	r := New("prefix", WithCodec(codec))
+	r.Get("hello/{name}", HandlerFunc(func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		a.Same(codec, CodecFrom(ctx))
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: "hello " + req.PathParameters["name"]}, nil
+	}))
+	r.Get("typed", HandlerOf(func(ctx context.Context, in struct{}) (map[string]int, error) {
+		return map[string]int{"n": 1}, nil
+	}))
+	r.Post("bound", HandlerOf(func(ctx context.Context, in struct{ Name string }) (string, error) {
+		return in.Name, nil
+	}))
+	r.Get("negotiated", Negotiate(func(ctx context.Context, req events.APIGatewayProxyRequest) (interface{}, error) {
+		return map[string]int{"n": 1}, nil
+	}, JSONRenderer))
+	r.Get("shared", lambda.NewHandler(handler), WithRouteCORS(CORSPolicy{AllowOrigins: []string{"*"}}))
+	r.SQS("", nil, lambda.NewHandler(func(msg events.SQSMessage) error {
+		return nil
+	}))
+
+	invoke := func(path string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 1, "WithCodec option should")
+	{
+		desc(t, 3, "decode requests and encode the responses of HandlerFuncs with the codec")
+		*codec = countingCodec{}
+		res := invoke("/prefix/hello/bob")
+		a.Exactly("hello bob", res.Body)
+		a.Exactly(1, codec.unmarshals)
+		a.Exactly(2, codec.marshals)
+
+		desc(t, 3, "encode the responses of typed handlers with the codec")
+		*codec = countingCodec{}
+		res = invoke("/prefix/typed")
+		a.Exactly(`{"n":1}`, res.Body)
+		a.Exactly("application/json", res.Headers["Content-Type"])
+		a.Exactly(2, codec.marshals)
+
+		desc(t, 3, "render the responses of the router with the codec")
+		*codec = countingCodec{}
+		res = invoke("/prefix/missing")
+		a.Exactly(http.StatusNotFound, res.StatusCode)
+		a.Exactly(1, codec.unmarshals)
+
+		desc(t, 3, "decode the bodies of typed handlers with the codec")
+		*codec = countingCodec{}
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodPost, Path: "/prefix/bound", Body: `{"Name":"bob"}`})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+		a.Contains(string(resjson), `\"bob\"`)
+		a.Contains(codec.decoded, "*struct { Name string }")
+
+		desc(t, 3, "render negotiated and CORS responses with the codec")
+		*codec = countingCodec{}
+		a.Exactly(`{"n":1}`, invoke("/prefix/negotiated").Body)
+		a.Exactly(2, codec.marshals)
+		*codec = countingCodec{}
+		payload, _ = json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/prefix/shared", Headers: map[string]string{"Origin": "https://a.example.com"}})
+		_, err = r.Invoke(context.Background(), payload)
+		a.NoError(err)
+		a.Exactly(2, codec.unmarshals)
+
+		desc(t, 3, "decode the events of other services with the codec")
+		*codec = countingCodec{}
+		_, err = r.Invoke(context.Background(), []byte(`{"Records":[{"eventSource":"aws:sqs","messageId":"1"}]}`))
+		a.NoError(err)
+		a.Len(codec.decoded, 3)
+		a.Contains(codec.decoded, "*lambdarouter.sourceProbe")
+		a.Contains(codec.decoded, "*events.SQSMessage")
+	}
+
+	desc(t, 1, "CodecFrom function should")
+	{
+		desc(t, 3, "return the codec of encoding/json outside a router")
+		a.Exactly(StdCodec, CodecFrom(context.Background()))
+	}
+}
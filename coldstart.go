@@ -0,0 +1,53 @@
+package lambdarouter
+
+import (
+	"context"
+	"sync"
+)
+
+// coldStart holds the hooks run before the first invocation of a router, and ensures they run
+// once.
+type coldStart struct {
+	once  sync.Once
+	hooks []func(ctx context.Context)
+}
+
+// OnColdStart adds a hook which runs once per container, before the first invocation of the router
+// is handled, such as to open connection pools or load configuration. Hooks run in the order they
+// were added, with the context of that invocation, and the invocation waits for them to finish.
+// Hooks must be added before the router is first invoked, as those added later never run.
+func (r *Router) OnColdStart(fn func(ctx context.Context)) {
+	if r.table == nil {
+		panic("router not initialized")
+	}
+
+	r.table.mu.Lock()
+	defer r.table.mu.Unlock()
+
+	r.table.coldStart.hooks = append(r.table.coldStart.hooks, fn)
+}
+
+// IsColdStart reports whether the current invocation is the first the router has handled in this
+// container, so handlers and middleware can tag the metrics and logs of cold starts.
+func IsColdStart(ctx context.Context) bool {
+	cold, _ := ctx.Value(coldStartKey).(bool)
+	return cold
+}
+
+// start runs the cold start hooks of the table if this is its first invocation, returning ctx
+// marked as a cold start if so.
+func (t *routeTable) start(ctx context.Context) context.Context {
+	t.coldStart.once.Do(func() {
+		ctx = context.WithValue(ctx, coldStartKey, true)
+
+		t.mu.RLock()
+		hooks := t.coldStart.hooks
+		t.mu.RUnlock()
+
+		for _, fn := range hooks {
+			fn(ctx)
+		}
+	})
+
+	return ctx
+}
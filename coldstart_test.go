@@ -0,0 +1,57 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnColdStart(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with cold start hooks and")
+	var calls []string
+	var cold []bool
+
+	r := New("")
+	r.OnColdStart(func(ctx context.Context) {
+		calls = append(calls, "first")
+		a.True(IsColdStart(ctx))
+	})
+	r.OnColdStart(func(ctx context.Context) {
+		calls = append(calls, "second")
+	})
+	r.Get("users", lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+		calls = append(calls, "handler")
+		cold = append(cold, IsColdStart(ctx))
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}))
+
+	invoke := func() {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/users"})
+		_, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+	}
+
+	desc(t, 2, "OnColdStart method should")
+	{
+		desc(t, 4, "run the hooks in order before the first invocation only")
+		invoke()
+		invoke()
+		a.Exactly([]string{"first", "second", "handler", "handler"}, calls)
+
+		desc(t, 4, "mark only the first invocation as a cold start")
+		a.Exactly([]bool{true, false}, cold)
+	}
+
+	desc(t, 2, "IsColdStart function should")
+	{
+		desc(t, 4, "report false for contexts not created by the router")
+		a.False(IsColdStart(context.Background()))
+	}
+}
@@ -0,0 +1,158 @@
+// Package compress provides middleware which compresses response bodies, keeping large payloads
+// within the 6 MB response limit of Lambda.
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/mitchell/lambdarouter/internal/header"
+)
+
+// Config configures the compression middleware.
+type Config struct {
+	// Level is the gzip compression level, from gzip.BestSpeed to gzip.BestCompression. If zero,
+	// gzip.DefaultCompression is used.
+	Level int
+
+	// MinSize is the size in bytes below which bodies are not compressed, since compressing them
+	// saves little. If zero, it is 1024.
+	MinSize int
+}
+
+// Middleware returns middleware which gzips the bodies of responses to requests whose
+// Accept-Encoding header accepts gzip. Compressed bodies are base64 encoded, with isBase64Encoded
+// set, so API Gateway passes them on as binary. The Content-Encoding header is set, and
+// Accept-Encoding is added to the Vary header. Responses which are empty, smaller than
+// cfg.MinSize, or already encoded are left as they are.
+func Middleware(cfg Config) lambdarouter.Middleware {
+	if cfg.Level == 0 {
+		cfg.Level = gzip.DefaultCompression
+	}
+	if cfg.MinSize == 0 {
+		cfg.MinSize = 1024
+	}
+
+	return func(next lambda.Handler) lambda.Handler {
+		return compressor{cfg: cfg, next: next}
+	}
+}
+
+type compressor struct {
+	cfg  Config
+	next lambda.Handler
+}
+
+func (c compressor) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	codec := lambdarouter.CodecFrom(ctx)
+
+	req, err := lambdarouter.RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resjson, err := c.next.Invoke(ctx, payload)
+	if err != nil || !acceptsGzip(header.Get(req, "Accept-Encoding")) {
+		return resjson, err
+	}
+
+	var res events.APIGatewayProxyResponse
+	if err := codec.Unmarshal(resjson, &res); err != nil {
+		return resjson, nil
+	}
+	if header.FromResponse(res, "Content-Encoding") != "" {
+		return resjson, nil
+	}
+
+	body := []byte(res.Body)
+	if res.IsBase64Encoded {
+		if body, err = base64.StdEncoding.DecodeString(res.Body); err != nil {
+			return resjson, nil
+		}
+	}
+	if len(body) < c.cfg.MinSize {
+		return resjson, nil
+	}
+
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, c.cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	if res.Headers == nil {
+		res.Headers = map[string]string{}
+	}
+	for name := range res.Headers {
+		if strings.EqualFold(name, "Content-Length") {
+			delete(res.Headers, name)
+		}
+	}
+	res.Headers["Content-Encoding"] = "gzip"
+	addVary(res.Headers, "Accept-Encoding")
+
+	res.Body = base64.StdEncoding.EncodeToString(buf.Bytes())
+	res.IsBase64Encoded = true
+
+	return codec.Marshal(res)
+}
+
+// acceptsGzip reports whether an Accept-Encoding header value accepts gzip, either by name or by
+// a wildcard, with a non-zero quality.
+func acceptsGzip(accept string) bool {
+	accepted := false
+
+	for _, coding := range strings.Split(accept, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(coding), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "gzip" && name != "*" {
+			continue
+		}
+
+		q := 1.0
+		if key, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(key) == "q" {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = parsed
+			}
+		}
+
+		if name == "gzip" {
+			return q > 0
+		}
+		accepted = q > 0
+	}
+
+	return accepted
+}
+
+// addVary adds name to the Vary header in headers, unless it is already listed.
+func addVary(headers map[string]string, name string) {
+	for key, value := range headers {
+		if !strings.EqualFold(key, "Vary") {
+			continue
+		}
+
+		for _, listed := range strings.Split(value, ",") {
+			if l := strings.TrimSpace(listed); strings.EqualFold(l, name) || l == "*" {
+				return
+			}
+		}
+		headers[key] = value + ", " + name
+		return
+	}
+
+	headers["Vary"] = name
+}
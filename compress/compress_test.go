@@ -0,0 +1,98 @@
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with compression and")
+	large := strings.Repeat(`{"id": 1}`, 1000)
+	r := lambdarouter.New("prefix")
+	r.Use(Middleware(Config{}))
+	r.Get("large", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusOK,
+			Headers:    map[string]string{"Content-Type": "application/json", "Vary": "Accept"},
+			Body:       large,
+		}, nil
+	}))
+	r.Get("small", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: "{}"}, nil
+	}))
+
+	invoke := func(path, accept string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodGet,
+			Path:       path,
+			Headers:    map[string]string{"Accept-Encoding": accept},
+		})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "Middleware should")
+	{
+		desc(t, 4, "gzip large bodies for clients which accept gzip")
+		res := invoke("/prefix/large", "br, gzip;q=0.8")
+		a.True(res.IsBase64Encoded)
+		a.Exactly("gzip", res.Headers["Content-Encoding"])
+		a.Exactly("Accept, Accept-Encoding", res.Headers["Vary"])
+
+		compressed, err := base64.StdEncoding.DecodeString(res.Body)
+		a.NoError(err)
+		a.True(len(compressed) < len(large))
+		zr, err := gzip.NewReader(bytes.NewReader(compressed))
+		a.NoError(err)
+		body, err := io.ReadAll(zr)
+		a.NoError(err)
+		a.Exactly(large, string(body))
+
+		desc(t, 4, "leave bodies as they are for other clients")
+		res = invoke("/prefix/large", "gzip;q=0, *")
+		a.False(res.IsBase64Encoded)
+		a.Exactly(large, res.Body)
+
+		desc(t, 4, "leave small bodies as they are")
+		res = invoke("/prefix/small", "gzip")
+		a.Exactly("{}", res.Body)
+		a.Exactly("", res.Headers["Content-Encoding"])
+	}
+
+	desc(t, 2, "acceptsGzip function should")
+	{
+		desc(t, 4, "honor wildcards and qualities")
+		a.True(acceptsGzip("*"))
+		a.True(acceptsGzip("deflate, GZIP"))
+		a.False(acceptsGzip("*;q=0"))
+		a.False(acceptsGzip("identity"))
+		a.False(acceptsGzip(""))
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
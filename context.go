@@ -0,0 +1,68 @@
+package lambdarouter
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type contextKey int
+
+const (
+	routedKey contextKey = iota
+	coldStartKey
+	fallbackKey
+	shadowKey
+	variantKey
+	tenantKey
+	reachedKey
+)
+
+// routed is the information the router places in the context of every invocation it routes.
+type routed struct {
+	req events.APIGatewayProxyRequest
+	rt  Route
+}
+
+// RequestFromContext returns the proxy request the router decoded to route the current
+// invocation. Handlers and middleware can use it to avoid decoding the payload a second time. The
+// second return value reports whether the context was created by the router.
+func RequestFromContext(ctx context.Context) (events.APIGatewayProxyRequest, bool) {
+	rd, ok := ctx.Value(routedKey).(*routed)
+	if !ok {
+		return events.APIGatewayProxyRequest{}, false
+	}
+
+	return rd.req, true
+}
+
+// RouteFromContext returns the route which matched the current invocation, whose Path is the
+// template the route was defined with rather than the path which was requested. The second return
+// value reports whether the context was created by the router.
+func RouteFromContext(ctx context.Context) (Route, bool) {
+	rd, ok := ctx.Value(routedKey).(*routed)
+	if !ok {
+		return Route{}, false
+	}
+
+	return rd.rt, true
+}
+
+// RequestFrom returns the proxy request of an invocation. When the invocation was routed by a
+// Router the request it already decoded is taken from ctx, otherwise payload is decoded with the
+// codec of the context. Handlers and middleware should prefer it to decoding the payload
+// themselves, so that the payload is only decoded once per invocation.
+func RequestFrom(ctx context.Context, payload []byte) (events.APIGatewayProxyRequest, error) {
+	if req, ok := RequestFromContext(ctx); ok {
+		return req, nil
+	}
+
+	var req events.APIGatewayProxyRequest
+	err := CodecFrom(ctx).Unmarshal(payload, &req)
+
+	return req, err
+}
+
+func withRequest(ctx context.Context, req events.APIGatewayProxyRequest, rt Route) context.Context {
+	return context.WithValue(ctx, routedKey, &routed{req: req, rt: rt})
+}
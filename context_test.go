@@ -0,0 +1,72 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router and")
+	r := New("prefix")
+
+	var (
+		req      events.APIGatewayProxyRequest
+		rt       Route
+		reqFound bool
+		rtFound  bool
+	)
+	r.Get("hello/{name}", lambda.NewHandler(func(ctx context.Context) error {
+		req, reqFound = RequestFromContext(ctx)
+		rt, rtFound = RouteFromContext(ctx)
+		return nil
+	}))
+
+	desc(t, 2, "RequestFromContext and RouteFromContext functions should")
+	{
+		desc(t, 4, "return the decoded request and matched route to handlers")
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod:     http.MethodGet,
+			Path:           "/prefix/hello/mitchell",
+			PathParameters: map[string]string{"name": "mitchell"},
+		})
+		_, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		a.True(reqFound)
+		a.Exactly("/prefix/hello/mitchell", req.Path)
+		a.True(rtFound)
+		a.Exactly(Route{Method: http.MethodGet, Path: "/prefix/hello/{name}"}, rt)
+
+		desc(t, 4, "report when the context was not created by the router")
+		_, reqFound = RequestFromContext(context.Background())
+		_, rtFound = RouteFromContext(context.Background())
+		a.False(reqFound)
+		a.False(rtFound)
+	}
+
+	desc(t, 2, "RequestFrom function should")
+	{
+		desc(t, 4, "take the request from the context without decoding the payload")
+		ctx := withRequest(context.Background(), events.APIGatewayProxyRequest{Path: "/routed"}, Route{})
+		req, err := RequestFrom(ctx, nil)
+		a.NoError(err)
+		a.Exactly("/routed", req.Path)
+
+		desc(t, 4, "decode the payload when the invocation was not routed")
+		req, err = RequestFrom(context.Background(), []byte(`{"path": "/decoded"}`))
+		a.NoError(err)
+		a.Exactly("/decoded", req.Path)
+
+		desc(t, 4, "return an error when the payload is malformed")
+		_, err = RequestFrom(context.Background(), nil)
+		a.Error(err)
+	}
+}
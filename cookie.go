@@ -0,0 +1,59 @@
+package lambdarouter
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Cookies parses the cookies sent with req in its Cookie headers. HTTP APIs send cookies in a
+// field of their own, which the router joins into a Cookie header, so Cookies works for either
+// payload format.
+func Cookies(req events.APIGatewayProxyRequest) []*http.Cookie {
+	hr := http.Request{Header: http.Header{"Cookie": headerValues(req, "Cookie")}}
+	return hr.Cookies()
+}
+
+// Cookie returns the named cookie sent with req, or http.ErrNoCookie if there is none.
+func Cookie(req events.APIGatewayProxyRequest, name string) (*http.Cookie, error) {
+	for _, c := range Cookies(req) {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+
+	return nil, http.ErrNoCookie
+}
+
+// SetCookie adds a Set-Cookie header for c to res. As a header map can only hold one Set-Cookie
+// header, cookies are added to the multi-value headers of the response, along with any Set-Cookie
+// header already in its headers, so responses of REST APIs can set several cookies. The router
+// moves them to the cookies field of responses in the version 2.0 payload format of HTTP APIs.
+// Invalid cookies are silently dropped, as they are by http.SetCookie.
+func SetCookie(res *events.APIGatewayProxyResponse, c *http.Cookie) {
+	v := c.String()
+	if v == "" {
+		return
+	}
+
+	if res.MultiValueHeaders == nil {
+		res.MultiValueHeaders = map[string][]string{}
+	}
+
+	for name, value := range res.Headers {
+		if strings.EqualFold(name, "Set-Cookie") {
+			res.MultiValueHeaders["Set-Cookie"] = append(res.MultiValueHeaders["Set-Cookie"], value)
+			delete(res.Headers, name)
+		}
+	}
+
+	for name, values := range res.MultiValueHeaders {
+		if name != "Set-Cookie" && strings.EqualFold(name, "Set-Cookie") {
+			res.MultiValueHeaders["Set-Cookie"] = append(res.MultiValueHeaders["Set-Cookie"], values...)
+			delete(res.MultiValueHeaders, name)
+		}
+	}
+
+	res.MultiValueHeaders["Set-Cookie"] = append(res.MultiValueHeaders["Set-Cookie"], v)
+}
@@ -0,0 +1,73 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCookies(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Cookies function should")
+	{
+		desc(t, 2, "parse every cookie of the Cookie headers")
+		cookies := Cookies(events.APIGatewayProxyRequest{
+			MultiValueHeaders: map[string][]string{"cookie": {"session=abc; theme=dark", "lang=en"}},
+		})
+		a.Len(cookies, 3)
+		a.Exactly("theme", cookies[1].Name)
+		a.Exactly("en", cookies[2].Value)
+	}
+
+	desc(t, 0, "Cookie function should")
+	{
+		req := events.APIGatewayProxyRequest{Headers: map[string]string{"Cookie": "session=abc"}}
+
+		desc(t, 2, "return the named cookie")
+		c, err := Cookie(req, "session")
+		a.NoError(err)
+		a.Exactly("abc", c.Value)
+
+		desc(t, 2, "return http.ErrNoCookie for missing cookies")
+		_, err = Cookie(req, "theme")
+		a.Exactly(http.ErrNoCookie, err)
+	}
+
+	desc(t, 0, "SetCookie function should")
+	{
+		desc(t, 2, "add cookies to the multi-value headers of the response")
+		res := events.APIGatewayProxyResponse{Headers: map[string]string{"set-cookie": "a=1"}}
+		SetCookie(&res, &http.Cookie{Name: "session", Value: "abc", HttpOnly: true})
+		SetCookie(&res, &http.Cookie{Name: "theme", Value: "dark"})
+		SetCookie(&res, &http.Cookie{Name: "bad name", Value: "x"})
+
+		a.Exactly([]string{"a=1", "session=abc; HttpOnly", "theme=dark"}, res.MultiValueHeaders["Set-Cookie"])
+		a.Empty(res.Headers)
+
+		desc(t, 2, "set cookies through the cookies field of version 2.0 responses")
+		r := New("prefix", WithPayloadFormat(PayloadFormatV2))
+		r.Get("login", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+			res := events.APIGatewayProxyResponse{StatusCode: http.StatusOK}
+			SetCookie(&res, &http.Cookie{Name: "session", Value: "abc"})
+			SetCookie(&res, &http.Cookie{Name: "theme", Value: "dark"})
+			return res, nil
+		}))
+
+		payload, _ := json.Marshal(events.APIGatewayV2HTTPRequest{
+			RawPath:        "/prefix/login",
+			RequestContext: events.APIGatewayV2HTTPRequestContext{HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{Method: http.MethodGet}},
+		})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var v2 events.APIGatewayV2HTTPResponse
+		a.NoError(json.Unmarshal(resjson, &v2))
+		a.Exactly([]string{"session=abc", "theme=dark"}, v2.Cookies)
+	}
+}
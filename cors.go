@@ -0,0 +1,239 @@
+package lambdarouter
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// CORSPolicy describes which cross-origin requests browsers should allow to a route.
+type CORSPolicy struct {
+	// AllowOrigins lists the origins which may make requests, such as "https://example.com". An
+	// origin of "*" allows any origin, and one of the form "https://*.example.com" allows any
+	// subdomain.
+	AllowOrigins []string
+
+	// AllowMethods lists the methods preflight requests may ask for. If empty, the methods of
+	// every route at the requested path are allowed.
+	AllowMethods []string
+
+	// AllowHeaders lists the request headers preflight requests may ask for. If empty, whatever
+	// headers they ask for are allowed.
+	AllowHeaders []string
+
+	// ExposeHeaders lists the response headers scripts may read, beyond those browsers always
+	// expose.
+	ExposeHeaders []string
+
+	// AllowCredentials allows requests to include cookies and authorization headers. The origin
+	// of requests is then echoed in place of "*".
+	AllowCredentials bool
+
+	// MaxAge is how long browsers may cache the result of a preflight request.
+	MaxAge time.Duration
+}
+
+// WithCORS sets the CORS policy of every route of the router. It can be overridden for groups of
+// routes with the CORS method, and for single routes with WithRouteCORS.
+//
+// When a route has a policy, its responses to requests from allowed origins carry the
+// Access-Control-Allow-Origin header and the other headers the policy calls for. Preflight requests
+// are answered by the router itself with a 204, according to the policy of the route matching the
+// path and the method they ask for, unless an OPTIONS route is defined at the path.
+func WithCORS(p CORSPolicy) Option {
+	return func(r *Router) {
+		r.cors = &p
+	}
+}
+
+// CORS allows you to define many routes with the same CORS policy, in place of the policy of the
+// router, as WithRouteCORS does for a single route. The fn parameter is a function in which the
+// routes, or mounted routers, should be defined.
+func (r *Router) CORS(p CORSPolicy, fn func(r *Router)) {
+	original := r.cors
+	r.cors = &p
+	fn(r)
+	r.cors = original
+}
+
+// WithRouteCORS sets the CORS policy of a single route, in place of the policy of the router or
+// group it is defined in, such as to let a public widget endpoint be called from any origin.
+func WithRouteCORS(p CORSPolicy) RouteOption {
+	return withCORS(&p)
+}
+
+func withCORS(p *CORSPolicy) RouteOption {
+	return func(e *event) {
+		if p != nil {
+			e.cors = p
+		}
+	}
+}
+
+// allowOrigin returns the value of the Access-Control-Allow-Origin header for a request from
+// origin, reporting false if the origin is not allowed.
+func (p *CORSPolicy) allowOrigin(origin string) (string, bool) {
+	if origin == "" {
+		return "", false
+	}
+
+	for _, allowed := range p.AllowOrigins {
+		switch {
+		case allowed == "*":
+			if p.AllowCredentials {
+				return origin, true
+			}
+			return "*", true
+		case strings.EqualFold(allowed, origin):
+			return origin, true
+		case strings.Contains(allowed, "://*."):
+			scheme, pattern, _ := strings.Cut(strings.ToLower(allowed), "://")
+			originScheme, host, _ := strings.Cut(strings.ToLower(origin), "://")
+			if originScheme == scheme && matchHost(pattern, host) {
+				return origin, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// headers returns the CORS headers common to preflight and actual responses to a request from
+// origin, reporting false if the origin is not allowed.
+func (p *CORSPolicy) headers(origin string) (map[string]string, bool) {
+	allowed, ok := p.allowOrigin(origin)
+	if !ok {
+		return nil, false
+	}
+
+	headers := map[string]string{"Access-Control-Allow-Origin": allowed}
+	if allowed != "*" {
+		headers["Vary"] = "Origin"
+	}
+	if p.AllowCredentials {
+		headers["Access-Control-Allow-Credentials"] = "true"
+	}
+
+	return headers, true
+}
+
+// addHeaders adds the CORS headers for req to the response encoded in payload with codec.
+func (p *CORSPolicy) addHeaders(codec Codec, req events.APIGatewayProxyRequest, payload []byte) ([]byte, error) {
+	headers, ok := p.headers(requestOrigin(req))
+	if !ok {
+		return payload, nil
+	}
+	if len(p.ExposeHeaders) > 0 {
+		headers["Access-Control-Expose-Headers"] = strings.Join(p.ExposeHeaders, ", ")
+	}
+
+	var res events.APIGatewayProxyResponse
+	if err := codec.Unmarshal(payload, &res); err != nil {
+		return nil, err
+	}
+
+	if res.Headers == nil {
+		res.Headers = map[string]string{}
+	}
+	for name, value := range headers {
+		if name == "Vary" {
+			addVary(res.Headers, value)
+			continue
+		}
+		res.Headers[name] = value
+	}
+
+	return codec.Marshal(res)
+}
+
+// isPreflight reports whether req is a CORS preflight request.
+func isPreflight(req events.APIGatewayProxyRequest) bool {
+	return req.HTTPMethod == http.MethodOptions && requestOrigin(req) != "" &&
+		len(headerValues(req, "Access-Control-Request-Method")) > 0
+}
+
+// preflight renders the response to a preflight request, according to the CORS policy of the route
+// it asks about. It reports false if that route has no policy, so the request is responded to as
+// any other.
+func (r Router) preflight(req events.APIGatewayProxyRequest) ([]byte, bool, error) {
+	method := strings.ToUpper(headerValues(req, "Access-Control-Request-Method")[0])
+
+	evs, _, found := r.lookup(method, req.Path, &req)
+	if !found {
+		return nil, false, nil
+	}
+
+	e, status := selectEvent(evs, req)
+	if status != 0 {
+		e = evs[0]
+	}
+	if e.cors == nil {
+		return nil, false, nil
+	}
+	p := e.cors
+
+	res := events.APIGatewayProxyResponse{
+		StatusCode: http.StatusNoContent,
+		Headers:    map[string]string{"Vary": "Origin, Access-Control-Request-Method, Access-Control-Request-Headers"},
+	}
+
+	headers, ok := p.headers(requestOrigin(req))
+	if !ok {
+		b, err := r.jsonCodec().Marshal(res)
+		return b, true, err
+	}
+	for name, value := range headers {
+		if name != "Vary" {
+			res.Headers[name] = value
+		}
+	}
+
+	methods := p.AllowMethods
+	if len(methods) == 0 {
+		methods = r.allowedMethods(req.Path)
+	}
+	res.Headers["Access-Control-Allow-Methods"] = strings.Join(methods, ", ")
+
+	if len(p.AllowHeaders) > 0 {
+		res.Headers["Access-Control-Allow-Headers"] = strings.Join(p.AllowHeaders, ", ")
+	} else if values := headerValues(req, "Access-Control-Request-Headers"); len(values) > 0 {
+		res.Headers["Access-Control-Allow-Headers"] = strings.Join(values, ", ")
+	}
+
+	if p.MaxAge > 0 {
+		res.Headers["Access-Control-Max-Age"] = strconv.Itoa(int(p.MaxAge / time.Second))
+	}
+
+	b, err := r.jsonCodec().Marshal(res)
+	return b, true, err
+}
+
+func requestOrigin(req events.APIGatewayProxyRequest) string {
+	if values := headerValues(req, "Origin"); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// addVary adds name to the Vary header in headers, unless it is already listed.
+func addVary(headers map[string]string, name string) {
+	for key, value := range headers {
+		if !strings.EqualFold(key, "Vary") {
+			continue
+		}
+
+		for _, listed := range strings.Split(value, ",") {
+			if l := strings.TrimSpace(listed); strings.EqualFold(l, name) || l == "*" {
+				return
+			}
+		}
+		headers[key] = value + ", " + name
+		return
+	}
+
+	headers["Vary"] = name
+}
@@ -0,0 +1,96 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with a CORS policy and")
+	r := New("prefix", WithCORS(CORSPolicy{
+		AllowOrigins:     []string{"https://app.example.com"},
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+	}))
+	handler := lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Headers: map[string]string{"Vary": "Accept"}}, nil
+	})
+	r.Get("users", handler)
+	r.Post("users", handler)
+	r.Get("widget", handler, WithRouteCORS(CORSPolicy{AllowOrigins: []string{"*"}, ExposeHeaders: []string{"X-Total"}}))
+	r.CORS(CORSPolicy{AllowOrigins: []string{"https://*.partner.com"}, AllowHeaders: []string{"Authorization"}}, func(r *Router) {
+		r.Get("partners", handler)
+	})
+
+	invoke := func(method, path string, headers map[string]string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: method, Path: path, Headers: headers})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "WithCORS option should")
+	{
+		desc(t, 4, "allow requests from allowed origins")
+		res := invoke(http.MethodGet, "/prefix/users", map[string]string{"Origin": "https://app.example.com"})
+		a.Exactly("https://app.example.com", res.Headers["Access-Control-Allow-Origin"])
+		a.Exactly("true", res.Headers["Access-Control-Allow-Credentials"])
+		a.Exactly("Accept, Origin", res.Headers["Vary"])
+
+		desc(t, 4, "not allow requests from other origins")
+		res = invoke(http.MethodGet, "/prefix/users", map[string]string{"Origin": "https://evil.com"})
+		a.NotContains(res.Headers, "Access-Control-Allow-Origin")
+
+		desc(t, 4, "answer preflight requests")
+		res = invoke(http.MethodOptions, "/prefix/users", map[string]string{
+			"Origin":                         "https://app.example.com",
+			"Access-Control-Request-Method":  "POST",
+			"Access-Control-Request-Headers": "content-type",
+		})
+		a.Exactly(http.StatusNoContent, res.StatusCode)
+		a.Exactly("https://app.example.com", res.Headers["Access-Control-Allow-Origin"])
+		a.Exactly("GET, POST", res.Headers["Access-Control-Allow-Methods"])
+		a.Exactly("content-type", res.Headers["Access-Control-Allow-Headers"])
+		a.Exactly("3600", res.Headers["Access-Control-Max-Age"])
+
+		desc(t, 4, "respond to other OPTIONS requests as usual")
+		res = invoke(http.MethodOptions, "/prefix/users", nil)
+		a.Exactly(http.StatusMethodNotAllowed, res.StatusCode)
+	}
+
+	desc(t, 2, "WithRouteCORS option should")
+	{
+		desc(t, 4, "override the policy of the router")
+		res := invoke(http.MethodGet, "/prefix/widget", map[string]string{"Origin": "https://blog.example.org"})
+		a.Exactly("*", res.Headers["Access-Control-Allow-Origin"])
+		a.Exactly("X-Total", res.Headers["Access-Control-Expose-Headers"])
+		a.Exactly("Accept", res.Headers["Vary"])
+	}
+
+	desc(t, 2, "CORS method should")
+	{
+		desc(t, 4, "override the policy of the router for its routes")
+		res := invoke(http.MethodGet, "/prefix/partners", map[string]string{"Origin": "https://api.partner.com"})
+		a.Exactly("https://api.partner.com", res.Headers["Access-Control-Allow-Origin"])
+		res = invoke(http.MethodGet, "/prefix/partners", map[string]string{"Origin": "https://app.example.com"})
+		a.NotContains(res.Headers, "Access-Control-Allow-Origin")
+
+		res = invoke(http.MethodOptions, "/prefix/partners", map[string]string{
+			"Origin":                        "https://api.partner.com",
+			"Access-Control-Request-Method": "GET",
+		})
+		a.Exactly("Authorization", res.Headers["Access-Control-Allow-Headers"])
+	}
+}
@@ -0,0 +1,188 @@
+// Package csrf provides middleware which protects browser-facing routes from cross-site request
+// forgery, using the double-submit cookie pattern.
+package csrf
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/mitchell/lambdarouter/internal/header"
+)
+
+type contextKey int
+
+const tokenKey contextKey = iota
+
+// Token returns the CSRF token of the current invocation, which pages should submit with their
+// forms in the field named by Config.FormField, or scripts in the header named by Config.Header.
+// It returns an empty string if the invocation was not handled by the CSRF middleware.
+func Token(ctx context.Context) string {
+	token, _ := ctx.Value(tokenKey).(string)
+	return token
+}
+
+// Config configures the CSRF middleware.
+type Config struct {
+	// CookieName is the name of the cookie holding the token. If empty, it is "csrf_token".
+	CookieName string
+
+	// Header is the name of the header requests may submit the token in. If empty, it is
+	// X-CSRF-Token.
+	Header string
+
+	// FormField is the name of the form field requests may submit the token in. If empty, it is
+	// "csrf_token".
+	FormField string
+
+	// Exempt lists the routes which are not protected, such as webhooks called by other services,
+	// in the "METHOD /path" form of Route.String, with paths as they were defined.
+	Exempt []string
+
+	// Insecure allows the cookie to be sent over plain HTTP, such as when running locally.
+	Insecure bool
+}
+
+// Middleware returns middleware which issues each client a random token in a cookie, and responds
+// with a 403 to requests of unsafe methods, such as POST and DELETE, which do not submit the same
+// token in a header or form field. As other sites can neither read the cookie nor set the header,
+// forged requests cannot carry the token. The token is placed in the context of the handler, from
+// which it can be retrieved with Token. The cookie is not HttpOnly, so scripts can read it.
+func Middleware(cfg Config) lambdarouter.Middleware {
+	if cfg.CookieName == "" {
+		cfg.CookieName = "csrf_token"
+	}
+	if cfg.Header == "" {
+		cfg.Header = "X-CSRF-Token"
+	}
+	if cfg.FormField == "" {
+		cfg.FormField = "csrf_token"
+	}
+
+	exempt := map[string]bool{}
+	for _, rt := range cfg.Exempt {
+		exempt[rt] = true
+	}
+
+	return func(next lambda.Handler) lambda.Handler {
+		return protector{cfg: cfg, exempt: exempt, next: next}
+	}
+}
+
+type protector struct {
+	cfg    Config
+	exempt map[string]bool
+	next   lambda.Handler
+}
+
+func (p protector) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	codec := lambdarouter.CodecFrom(ctx)
+
+	req, err := lambdarouter.RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if rt, ok := lambdarouter.RouteFromContext(ctx); ok && p.exempt[rt.String()] {
+		return p.next.Invoke(ctx, payload)
+	}
+
+	token := ""
+	if c, err := lambdarouter.Cookie(req, p.cfg.CookieName); err == nil {
+		token = c.Value
+	}
+
+	if !safe(req.HTTPMethod) {
+		submitted := p.submitted(req)
+		if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(submitted)) != 1 {
+			return nil, &lambdarouter.HTTPError{Status: http.StatusForbidden, Detail: "invalid CSRF token"}
+		}
+	}
+
+	issued := token == ""
+	if issued {
+		if token, err = newToken(); err != nil {
+			return nil, err
+		}
+	}
+
+	resjson, err := p.next.Invoke(context.WithValue(ctx, tokenKey, token), payload)
+	if err != nil || !issued {
+		return resjson, err
+	}
+
+	var res events.APIGatewayProxyResponse
+	if err := codec.Unmarshal(resjson, &res); err != nil {
+		return nil, err
+	}
+
+	lambdarouter.SetCookie(&res, &http.Cookie{
+		Name:     p.cfg.CookieName,
+		Value:    token,
+		Path:     "/",
+		Secure:   !p.cfg.Insecure,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	return codec.Marshal(res)
+}
+
+// submitted returns the token submitted by req, in its header or in a field of its form body.
+func (p protector) submitted(req events.APIGatewayProxyRequest) string {
+	if token := header.Get(req, p.cfg.Header); token != "" {
+		return token
+	}
+
+	contentType := strings.ToLower(header.Get(req, "Content-Type"))
+	switch {
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		body := req.Body
+		if req.IsBase64Encoded {
+			decoded, err := base64.StdEncoding.DecodeString(body)
+			if err != nil {
+				return ""
+			}
+			body = string(decoded)
+		}
+		form, err := url.ParseQuery(body)
+		if err != nil {
+			return ""
+		}
+		return form.Get(p.cfg.FormField)
+	case strings.HasPrefix(contentType, "multipart/form-data"):
+		form, err := lambdarouter.ParseMultipart(req, lambdarouter.MultipartLimits{})
+		if err != nil {
+			return ""
+		}
+		return form.Value(p.cfg.FormField)
+	}
+
+	return ""
+}
+
+// safe reports whether method is one which should not change state, and so need not be protected.
+func safe(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+
+	return false
+}
+
+// newToken returns a random token of 256 bits.
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,87 @@
+package csrf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with CSRF protection and")
+	r := lambdarouter.New("prefix")
+	r.Use(Middleware(Config{Exempt: []string{"POST /prefix/webhooks/{id}"}}))
+	handler := lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: Token(ctx)}, nil
+	})
+	r.Get("form", handler)
+	r.Post("form", handler)
+	r.Post("webhooks/{id}", handler)
+
+	invoke := func(method, path string, headers map[string]string, body string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod: method,
+			Path:       path,
+			Headers:    headers,
+			Body:       body,
+		})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "Middleware should")
+	{
+		desc(t, 4, "issue a token to clients without one")
+		res := invoke(http.MethodGet, "/prefix/form", nil, "")
+		token := res.Body
+		a.NotEmpty(token)
+		hr := http.Response{Header: http.Header{"Set-Cookie": res.MultiValueHeaders["Set-Cookie"]}}
+		a.Exactly(token, hr.Cookies()[0].Value)
+		a.False(hr.Cookies()[0].HttpOnly)
+
+		desc(t, 4, "keep the token of clients with one")
+		cookie := "csrf_token=" + token
+		res = invoke(http.MethodGet, "/prefix/form", map[string]string{"Cookie": cookie}, "")
+		a.Exactly(token, res.Body)
+		a.Empty(res.MultiValueHeaders)
+
+		desc(t, 4, "accept unsafe requests submitting the token in a header or form field")
+		res = invoke(http.MethodPost, "/prefix/form", map[string]string{"Cookie": cookie, "x-csrf-token": token}, "")
+		a.Exactly(http.StatusOK, res.StatusCode)
+		res = invoke(http.MethodPost, "/prefix/form", map[string]string{
+			"Cookie":       cookie,
+			"Content-Type": "application/x-www-form-urlencoded",
+		}, "name=a&csrf_token="+token)
+		a.Exactly(http.StatusOK, res.StatusCode)
+
+		desc(t, 4, "respond with a 403 to unsafe requests without the token")
+		res = invoke(http.MethodPost, "/prefix/form", map[string]string{"Cookie": cookie, "X-CSRF-Token": "forged"}, "")
+		a.Exactly(http.StatusForbidden, res.StatusCode)
+		res = invoke(http.MethodPost, "/prefix/form", map[string]string{"X-CSRF-Token": token}, "")
+		a.Exactly(http.StatusForbidden, res.StatusCode)
+
+		desc(t, 4, "not protect exempt routes")
+		res = invoke(http.MethodPost, "/prefix/webhooks/1", nil, "")
+		a.Exactly(http.StatusOK, res.StatusCode)
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
@@ -0,0 +1,173 @@
+package lambdarouter
+
+import (
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// WithDebug makes the router log how it matches each request: the keys its matcher considered, in
+// which the method is followed by the template with each parameter written as {} and each greedy
+// parameter as {+}, along with the template of the route which matched and the parameters taken
+// from the path. It explains why requests are routed as they are, such as a 404 caused by a
+// trailing segment or an unexpected prefix, and is too verbose for production. Requests which match
+// no route are also logged with the routes closest to them. Messages go to the
+// logger of the router, or to the standard logger if it has none.
+func WithDebug() Option {
+	return func(r *Router) {
+		r.debug = true
+	}
+}
+
+// traceMatch logs the outcome of the lookup of req in debug mode.
+func (r Router) traceMatch(req events.APIGatewayProxyRequest, events []event, params map[string]string, found bool) {
+	tried := "nothing"
+	if candidates := r.table.candidates(req.HTTPMethod, req.Path); len(candidates) > 0 {
+		tried = strings.Join(candidates, ", ")
+	}
+
+	if !found {
+		r.debugf("%s %s: tried %s; matched no route", req.HTTPMethod, req.Path, tried)
+
+		if closest := closestRoutes(r.Routes(), req.HTTPMethod, req.Path); len(closest) > 0 {
+			names := make([]string, len(closest))
+			for i, rt := range closest {
+				names[i] = rt.String()
+			}
+			r.debugf("%s %s: closest routes are %s", req.HTTPMethod, req.Path, strings.Join(names, ", "))
+		}
+		return
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=" + params[name]
+	}
+
+	r.debugf("%s %s: tried %s; matched %s with parameters [%s]",
+		req.HTTPMethod, req.Path, tried, events[0].rt.Path, strings.Join(pairs, " "))
+}
+
+// maxSuggestions is the number of routes suggested for a request which matches none.
+const maxSuggestions = 3
+
+// closestRoutes returns up to maxSuggestions of routes which are nearest to method and path, by
+// the number of segments which would have to be inserted, deleted, or replaced for the path to
+// match their templates, plus one if their method differs. Routes further than half the segments
+// of the path are not suggested, as they are unlikely to be what was meant.
+func closestRoutes(routes []Route, method, path string) []Route {
+	segs := splitSegments(path)
+	limit := (len(segs) + 1) / 2
+	if limit < 1 {
+		limit = 1
+	}
+
+	type suggestion struct {
+		rt       Route
+		distance int
+	}
+
+	var suggestions []suggestion
+	seen := map[string]bool{}
+
+	for _, rt := range routes {
+		if seen[rt.String()] {
+			continue
+		}
+		seen[rt.String()] = true
+
+		d := segmentDistance(splitSegments(rt.Path), segs)
+		if !strings.EqualFold(rt.Method, method) {
+			d++
+		}
+		if d <= limit {
+			suggestions = append(suggestions, suggestion{rt: Route{Method: rt.Method, Path: rt.Path}, distance: d})
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].distance < suggestions[j].distance
+	})
+
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+
+	closest := make([]Route, len(suggestions))
+	for i, s := range suggestions {
+		closest[i] = s.rt
+	}
+
+	return closest
+}
+
+// segmentDistance returns the edit distance between the segments of a template and of a path,
+// where a parameter matches any segment, a greedy parameter matches every remaining segment, and
+// static segments match regardless of case.
+func segmentDistance(template, path []string) int {
+	prev := make([]int, len(path)+1)
+	cur := make([]int, len(path)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i, tseg := range template {
+		greedy := isParam("/"+tseg) && strings.HasSuffix(tseg, "+}") || isWildcard("/"+tseg)
+		cur[0] = i + 1
+
+		for j, pseg := range path {
+			cost := 1
+			if isParam("/"+tseg) || strings.EqualFold(tseg, pseg) {
+				cost = 0
+			}
+
+			cur[j+1] = min3(prev[j]+cost, prev[j+1]+1, cur[j]+1)
+			if greedy && cur[j] < cur[j+1] {
+				cur[j+1] = cur[j]
+			}
+		}
+
+		prev, cur = cur, prev
+	}
+
+	return prev[len(path)]
+}
+
+// splitSegments returns the segments of a path, without their slashes.
+func splitSegments(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, "/")
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+
+	return a
+}
+
+// debugf logs a message of debug mode to the logger of the router, or the standard logger.
+func (r Router) debugf(format string, v ...interface{}) {
+	if r.logger != nil {
+		r.logger.Printf("debug: "+format, v...)
+		return
+	}
+
+	log.Printf("debug: "+format, v...)
+}
@@ -0,0 +1,77 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDebug(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router in debug mode and")
+	var logs logRecorder
+	r := New("prefix", WithDebug(), WithLogger(&logs))
+	r.Get("users/{id}", lambda.NewHandler(handler))
+	r.Get("users/{id}/files/{path+}", lambda.NewHandler(handler))
+
+	invoke := func(method, path string) {
+		logs = nil
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: method, Path: path})
+		_, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+	}
+
+	desc(t, 2, "WithDebug option should")
+	{
+		desc(t, 4, "log the keys considered, the matched template, and the parameters")
+		invoke(http.MethodGet, "/prefix/users/42/files/a/b")
+		a.Exactly(logRecorder{
+			"debug: GET /prefix/users/42/files/a/b: tried GET /prefix/..., GET /prefix/users/..., " +
+				"GET /prefix/users/42/..., GET /prefix/users/{}/..., GET /prefix/users/{}/files/..., " +
+				"GET /prefix/users/{}/files/a/..., GET /prefix/users/{}/files/{}/..., " +
+				"GET /prefix/users/{}/files/{+}; " +
+				"matched /prefix/users/{id}/files/{path+} with parameters [id=42 path=a/b]",
+		}, logs)
+
+		desc(t, 4, "log lookups which match no route")
+		invoke(http.MethodGet, "/prefix/user/42")
+		a.Exactly("debug: GET /prefix/user/42: tried GET /prefix/..., GET /prefix/user/..., "+
+			"GET /prefix/{}/..., GET /prefix/{+}, GET /{}/..., GET /{+}; matched no route", logs[0])
+
+		desc(t, 4, "log the routes closest to requests which match none")
+		a.Exactly("debug: GET /prefix/user/42: closest routes are GET /prefix/users/{id}, "+
+			"GET /prefix/users/{id}/files/{path+}", logs[1])
+
+		invoke(http.MethodPost, "/prefix/users/42")
+		a.Exactly("debug: POST /prefix/users/42: closest routes are GET /prefix/users/{id}", logs[1])
+
+		desc(t, 4, "not suggest routes far from the request")
+		invoke(http.MethodGet, "/elsewhere")
+		a.NotContains(logs[1], "closest routes")
+	}
+}
+
+func TestSegmentDistance(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "segmentDistance function should")
+	{
+		desc(t, 2, "match parameters to any segment")
+		a.Exactly(0, segmentDistance(splitSegments("/users/{id}"), splitSegments("/Users/42")))
+
+		desc(t, 2, "match greedy parameters to every remaining segment")
+		a.Exactly(0, segmentDistance(splitSegments("/files/{path+}"), splitSegments("/files/a/b/c")))
+		a.Exactly(1, segmentDistance(splitSegments("/files/{path+}"), splitSegments("/files")))
+
+		desc(t, 2, "count inserted, deleted, and replaced segments")
+		a.Exactly(1, segmentDistance(splitSegments("/users/{id}"), splitSegments("/user/42")))
+		a.Exactly(1, segmentDistance(splitSegments("/v1/users"), splitSegments("/users")))
+		a.Exactly(2, segmentDistance(splitSegments("/users"), splitSegments("/users/42/posts")))
+	}
+}
@@ -0,0 +1,51 @@
+package lambdarouter
+
+import "github.com/aws/aws-lambda-go/lambda"
+
+// EventClassifier recognises the payloads of a kind of event, such as the envelopes of internal
+// events or third party webhooks relayed by a queue.
+type EventClassifier interface {
+	// Classify reports whether payload is an event of the kind. It is called with the payload of
+	// every invocation of the router until a classifier recognises it, so it should be cheap.
+	Classify(payload []byte) bool
+}
+
+// EventClassifierFunc adapts a function to the EventClassifier interface.
+type EventClassifierFunc func(payload []byte) bool
+
+// Classify implements the EventClassifier interface for the EventClassifierFunc type.
+func (f EventClassifierFunc) Classify(payload []byte) bool {
+	return f(payload)
+}
+
+// Dispatcher handles the events an EventClassifier recognises, routing them further as it sees
+// fit.
+type Dispatcher interface {
+	EventClassifier
+	lambda.Handler
+}
+
+type customRoute struct {
+	c EventClassifier
+	h lambda.Handler
+}
+
+// Dispatch adds d to the router, so events of kinds the router has no routes for can be handled
+// by the same function as HTTP requests and the events of AWS services. Payloads are offered to
+// dispatchers in the order they were added, before they are routed as events of AWS services or
+// HTTP requests, and the first dispatcher which classifies a payload is invoked with it. Its
+// invocations are wrapped by the middleware of the router, which should not assume it handles
+// HTTP requests.
+func (r *Router) Dispatch(d Dispatcher) {
+	r.On(d, d)
+}
+
+// On adds a route for the events c recognises, which are handled by handler as those of a
+// dispatcher added by Dispatch.
+func (r *Router) On(c EventClassifier, handler lambda.Handler) {
+	rt := customRoute{c: c, h: r.sourceHandler(handler)}
+
+	r.addSource(func(s *sourceRoutes) {
+		s.custom = append(s.custom[:len(s.custom):len(s.custom)], rt)
+	})
+}
@@ -0,0 +1,68 @@
+package lambdarouter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+type envelopeDispatcher struct{}
+
+func (envelopeDispatcher) Classify(payload []byte) bool {
+	return bytes.Contains(payload, []byte(`"envelope"`))
+}
+
+func (envelopeDispatcher) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	var env struct {
+		Envelope struct {
+			Type string `json:"type"`
+		} `json:"envelope"`
+	}
+	if err := json.Unmarshal(payload, &env); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal("handled " + env.Envelope.Type)
+}
+
+func TestDispatch(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with dispatchers, SQS, and HTTP routes and")
+	r := New("prefix")
+	r.Dispatch(envelopeDispatcher{})
+	r.On(EventClassifierFunc(func(payload []byte) bool {
+		return bytes.Contains(payload, []byte("stripe"))
+	}), lambda.NewHandler(func() (string, error) {
+		return "webhook", nil
+	}))
+	r.SQS("", nil, lambda.NewHandler(func() error { return nil }))
+	r.Get("health", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}))
+
+	invoke := func(payload string) string {
+		res, err := r.Invoke(context.Background(), []byte(payload))
+		a.NoError(err)
+		return string(res)
+	}
+
+	desc(t, 2, "Dispatch method should")
+	{
+		desc(t, 4, "invoke the dispatcher which classifies the payload")
+		a.Exactly(`"handled order.placed"`, invoke(`{"envelope": {"type": "order.placed"}}`))
+
+		desc(t, 4, "offer payloads to dispatchers before routing them as events of AWS services")
+		a.Exactly(`"webhook"`, invoke(`{"Records": [{"eventSource": "aws:sqs", "body": "stripe"}]}`))
+		a.Exactly(`{"batchItemFailures":[]}`, invoke(`{"Records": [{"eventSource": "aws:sqs", "body": "{}"}]}`))
+
+		desc(t, 4, "still route HTTP requests")
+		a.Contains(invoke(`{"httpMethod": "GET", "path": "/prefix/health"}`), `"statusCode":200`)
+	}
+}
@@ -0,0 +1,183 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+type dynamoDBRoute struct {
+	table     string
+	eventName string
+	h         lambda.Handler
+}
+
+// DynamoDB adds a route for the records of DynamoDB streams. The table parameter is a pattern
+// matched against the name of the table of a stream, taken from the ARN of the stream, in which *
+// matches any run of characters, and which matches any table if empty. The eventName parameter is
+// the operation which changed the item, which is INSERT, MODIFY, or REMOVE, or empty to match any.
+// The handler parameter is invoked once for each record, which decodes into an
+// events.DynamoDBEventRecord, and is wrapped by the middleware of the router, which should not
+// assume it handles HTTP requests. DynamoDBHandlerOf creates handlers which receive the images of
+// the item decoded into a struct.
+//
+// Records are routed to the first route which matches them in the order they were defined. As the
+// records of a stream must be handled in order, the first record whose handler fails or which
+// matches no route is reported as a batch item failure and the rest of the batch is left unhandled,
+// so the stream is retried from that record. This requires the event source mapping to have
+// ReportBatchItemFailures enabled.
+func (r *Router) DynamoDB(table, eventName string, handler lambda.Handler) {
+	rt := dynamoDBRoute{table: table, eventName: strings.ToUpper(eventName), h: r.sourceHandler(handler)}
+
+	r.addSource(func(s *sourceRoutes) {
+		s.dynamoDB = append(s.dynamoDB[:len(s.dynamoDB):len(s.dynamoDB)], rt)
+	})
+}
+
+// DynamoDBHandlerOf returns a lambda.Handler for DynamoDB routes which decodes the images of the
+// item a record changed into Ts, and invokes fn with them along with the record. An image is
+// decoded as if the item were a JSON object, so the fields of T may be tagged as they would be for
+// encoding/json. The old or new image is nil if the record does not have it, such as the old image
+// of an INSERT or when the stream is not configured to hold it.
+func DynamoDBHandlerOf[T any](fn func(ctx context.Context, record events.DynamoDBEventRecord, oldImage, newImage *T) error) lambda.Handler {
+	return dynamoDBHandler[T]{fn: fn}
+}
+
+type dynamoDBHandler[T any] struct {
+	fn func(ctx context.Context, record events.DynamoDBEventRecord, oldImage, newImage *T) error
+}
+
+func (dh dynamoDBHandler[T]) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	codec := CodecFrom(ctx)
+
+	var record events.DynamoDBEventRecord
+	if err := codec.Unmarshal(payload, &record); err != nil {
+		return nil, err
+	}
+
+	oldImage, err := decodeImage[T](codec, record.Change.OldImage)
+	if err != nil {
+		return nil, fmt.Errorf("decoding old image of %s: %w", record.EventID, err)
+	}
+
+	newImage, err := decodeImage[T](codec, record.Change.NewImage)
+	if err != nil {
+		return nil, fmt.Errorf("decoding new image of %s: %w", record.EventID, err)
+	}
+
+	return nil, dh.fn(ctx, record, oldImage, newImage)
+}
+
+// decodeImage decodes the image of an item into a T, or returns nil if there is no image.
+func decodeImage[T any](codec Codec, image map[string]events.DynamoDBAttributeValue) (*T, error) {
+	if len(image) == 0 {
+		return nil, nil
+	}
+
+	b, err := codec.Marshal(plainValue(events.NewMapAttribute(image)))
+	if err != nil {
+		return nil, err
+	}
+
+	v := new(T)
+	if err := codec.Unmarshal(b, v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// plainValue returns the value of av as it would appear in a JSON document, keeping numbers as
+// json.Numbers so they are not rounded.
+func plainValue(av events.DynamoDBAttributeValue) interface{} {
+	switch av.DataType() {
+	case events.DataTypeBinary:
+		return av.Binary()
+	case events.DataTypeBoolean:
+		return av.Boolean()
+	case events.DataTypeBinarySet:
+		return av.BinarySet()
+	case events.DataTypeList:
+		list := make([]interface{}, len(av.List()))
+		for i, v := range av.List() {
+			list[i] = plainValue(v)
+		}
+		return list
+	case events.DataTypeMap:
+		m := make(map[string]interface{}, len(av.Map()))
+		for k, v := range av.Map() {
+			m[k] = plainValue(v)
+		}
+		return m
+	case events.DataTypeNumber:
+		return json.Number(av.Number())
+	case events.DataTypeNumberSet:
+		set := make([]json.Number, len(av.NumberSet()))
+		for i, n := range av.NumberSet() {
+			set[i] = json.Number(n)
+		}
+		return set
+	case events.DataTypeString:
+		return av.String()
+	case events.DataTypeStringSet:
+		return av.StringSet()
+	}
+
+	return nil
+}
+
+func (r Router) invokeDynamoDB(ctx context.Context, routes []dynamoDBRoute, payload []byte) ([]byte, error) {
+	codec := r.jsonCodec()
+
+	var batch struct {
+		Records []json.RawMessage
+	}
+	if err := codec.Unmarshal(payload, &batch); err != nil {
+		return nil, err
+	}
+
+	res := events.DynamoDBEventResponse{BatchItemFailures: []events.DynamoDBBatchItemFailure{}}
+
+	for _, raw := range batch.Records {
+		var record events.DynamoDBEventRecord
+		if err := codec.Unmarshal(raw, &record); err != nil {
+			return nil, err
+		}
+
+		if err := invokeDynamoDBRoute(ctx, routes, record, raw); err != nil {
+			r.logf("DynamoDB %s %s: %v", record.EventName, record.EventID, err)
+			res.BatchItemFailures = append(res.BatchItemFailures, events.DynamoDBBatchItemFailure{
+				ItemIdentifier: record.Change.SequenceNumber,
+			})
+			break
+		}
+	}
+
+	return codec.Marshal(res)
+}
+
+func invokeDynamoDBRoute(ctx context.Context, routes []dynamoDBRoute, record events.DynamoDBEventRecord, raw []byte) error {
+	table := streamTable(record.EventSourceArn)
+
+	for _, rt := range routes {
+		if matchPattern(rt.table, table) && (rt.eventName == "" || rt.eventName == record.EventName) {
+			_, err := rt.h.Invoke(ctx, raw)
+			return err
+		}
+	}
+
+	return fmt.Errorf("no route for %s of table %q", record.EventName, table)
+}
+
+// streamTable returns the name of the table of a stream from its ARN, which has the form
+// arn:aws:dynamodb:region:account:table/name/stream/label.
+func streamTable(arn string) string {
+	_, resource, _ := strings.Cut(arn, ":table/")
+	table, _, _ := strings.Cut(resource, "/")
+
+	return table
+}
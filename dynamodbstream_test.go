@@ -0,0 +1,98 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamoDB(t *testing.T) {
+	a := assert.New(t)
+
+	type order struct {
+		ID    string   `json:"id"`
+		Total float64  `json:"total"`
+		Tags  []string `json:"tags"`
+		Lines []struct {
+			SKU string `json:"sku"`
+		} `json:"lines"`
+	}
+
+	desc(t, 0, "Initialize Router with DynamoDB routes and")
+	var handled []string
+	r := New("prefix")
+	r.DynamoDB("orders", "MODIFY", DynamoDBHandlerOf(func(ctx context.Context, record events.DynamoDBEventRecord, oldImage, newImage *order) error {
+		if newImage.Total < 0 {
+			return errors.New("negative total")
+		}
+		handled = append(handled, "modify "+oldImage.ID+" "+newImage.Lines[0].SKU+" "+newImage.Tags[0])
+		return nil
+	}))
+	r.DynamoDB("orders", "", DynamoDBHandlerOf(func(ctx context.Context, record events.DynamoDBEventRecord, oldImage, newImage *order) error {
+		handled = append(handled, record.EventName)
+		a.Nil(oldImage)
+		return nil
+	}))
+	r.DynamoDB("audit-*", "insert", lambda.NewHandler(func(record events.DynamoDBEventRecord) error {
+		handled = append(handled, "audit "+record.Change.SequenceNumber)
+		return nil
+	}))
+
+	invoke := func(records ...events.DynamoDBEventRecord) events.DynamoDBEventResponse {
+		handled = nil
+		payload, _ := json.Marshal(events.DynamoDBEvent{Records: records})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.DynamoDBEventResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+	record := func(seq, table, eventName string, total string) events.DynamoDBEventRecord {
+		var rec events.DynamoDBEventRecord
+		rec.EventSource, rec.EventName = "aws:dynamodb", eventName
+		rec.EventSourceArn = "arn:aws:dynamodb:us-east-1:123456789012:table/" + table + "/stream/2024-01-01T00:00:00.000"
+		rec.Change.SequenceNumber = seq
+		rec.Change.NewImage = map[string]events.DynamoDBAttributeValue{
+			"id":    events.NewStringAttribute("o1"),
+			"total": events.NewNumberAttribute(total),
+			"tags":  events.NewStringSetAttribute([]string{"gift"}),
+			"lines": events.NewListAttribute([]events.DynamoDBAttributeValue{
+				events.NewMapAttribute(map[string]events.DynamoDBAttributeValue{"sku": events.NewStringAttribute("sku-1")}),
+			}),
+		}
+		if eventName == "MODIFY" {
+			rec.Change.OldImage = map[string]events.DynamoDBAttributeValue{"id": events.NewStringAttribute("o1")}
+		}
+		return rec
+	}
+
+	desc(t, 2, "DynamoDB method should")
+	{
+		desc(t, 4, "route records by table and event name, decoding their images")
+		res := invoke(
+			record("1", "orders", "MODIFY", "9.5"),
+			record("2", "orders", "INSERT", "9.5"),
+			record("3", "audit-log", "INSERT", "1"),
+		)
+		a.Empty(res.BatchItemFailures)
+		a.Exactly([]string{"modify o1 sku-1 gift", "INSERT", "audit 3"}, handled)
+
+		desc(t, 4, "report the first record which fails and leave the rest")
+		res = invoke(
+			record("1", "orders", "MODIFY", "9.5"),
+			record("2", "orders", "MODIFY", "-1"),
+			record("3", "orders", "INSERT", "9.5"),
+		)
+		a.Exactly([]events.DynamoDBBatchItemFailure{{ItemIdentifier: "2"}}, res.BatchItemFailures)
+		a.Exactly([]string{"modify o1 sku-1 gift"}, handled)
+
+		res = invoke(record("1", "users", "REMOVE", "0"))
+		a.Exactly([]events.DynamoDBBatchItemFailure{{ItemIdentifier: "1"}}, res.BatchItemFailures)
+	}
+}
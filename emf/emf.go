@@ -0,0 +1,156 @@
+// Package emf provides middleware which records metrics of each invocation of a route in the
+// CloudWatch Embedded Metric Format. Lambda sends what functions write to standard output to
+// CloudWatch Logs, which extracts metrics from records in this format, so metrics per route need
+// neither an agent nor calls to the CloudWatch API.
+package emf
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/mitchell/lambdarouter"
+)
+
+// Config configures the metrics middleware.
+type Config struct {
+	// Namespace is the CloudWatch namespace metrics are recorded in. If empty, it is lambdarouter.
+	Namespace string
+
+	// Output is where records are written. If nil, it is os.Stdout, whose contents Lambda sends to
+	// CloudWatch Logs.
+	Output io.Writer
+}
+
+// Middleware returns middleware which writes a record of the Latency, in milliseconds, the Count,
+// and the ColdStart of each invocation of a route, the last of which is 1 for the first invocation
+// the router handles and 0 for the rest. The metrics have the Method and Route dimensions, naming
+// the method and template of the route, such as GET and /users/{id}, and are also recorded with
+// the StatusClass dimension, such as 2xx or 5xx. Errors returned by the handler are counted as
+// 5xx, unless they are HTTPErrors. The status and request ID of the invocation are included in the
+// record as properties, which can be searched with CloudWatch Logs Insights.
+func Middleware(cfg Config) lambdarouter.Middleware {
+	if cfg.Namespace == "" {
+		cfg.Namespace = "lambdarouter"
+	}
+	if cfg.Output == nil {
+		cfg.Output = os.Stdout
+	}
+
+	w := &writer{out: cfg.Output}
+
+	return func(next lambda.Handler) lambda.Handler {
+		return recorder{namespace: cfg.Namespace, w: w, next: next}
+	}
+}
+
+type recorder struct {
+	namespace string
+	w         *writer
+	next      lambda.Handler
+}
+
+func (r recorder) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	start := time.Now()
+	res, err := r.next.Invoke(ctx, payload)
+	latency := time.Since(start)
+
+	rec := map[string]interface{}{
+		"Method":    "",
+		"Route":     "",
+		"Latency":   float64(latency) / float64(time.Millisecond),
+		"Count":     1,
+		"ColdStart": 0,
+	}
+
+	if lambdarouter.IsColdStart(ctx) {
+		rec["ColdStart"] = 1
+	}
+	if rt, ok := lambdarouter.RouteFromContext(ctx); ok {
+		rec["Method"], rec["Route"] = rt.Method, rt.Path
+	}
+	if lc, ok := lambdacontext.FromContext(ctx); ok {
+		rec["RequestId"] = lc.AwsRequestID
+	}
+
+	status := http.StatusInternalServerError
+	if err != nil {
+		var httpErr *lambdarouter.HTTPError
+		if errors.As(err, &httpErr) {
+			status = httpErr.Status
+		}
+	} else {
+		var out struct {
+			StatusCode int `json:"statusCode"`
+		}
+		if lambdarouter.CodecFrom(ctx).Unmarshal(res, &out) == nil && out.StatusCode != 0 {
+			status = out.StatusCode
+		} else {
+			status = http.StatusOK
+		}
+	}
+	rec["Status"] = status
+	rec["StatusClass"] = strconv.Itoa(status/100) + "xx"
+
+	rec["_aws"] = metadata{
+		Timestamp: start.UnixNano() / int64(time.Millisecond),
+		CloudWatchMetrics: []directive{{
+			Namespace:  r.namespace,
+			Dimensions: [][]string{{"Method", "Route"}, {"Method", "Route", "StatusClass"}},
+			Metrics: []metric{
+				{Name: "Latency", Unit: "Milliseconds"},
+				{Name: "Count", Unit: "Count"},
+				{Name: "ColdStart", Unit: "Count"},
+			},
+		}},
+	}
+
+	r.w.write(rec)
+
+	return res, err
+}
+
+// metadata is the _aws member of a record, which tells CloudWatch which of its members are metrics
+// and which are dimensions.
+type metadata struct {
+	Timestamp         int64       `json:"Timestamp"`
+	CloudWatchMetrics []directive `json:"CloudWatchMetrics"`
+}
+
+type directive struct {
+	Namespace  string     `json:"Namespace"`
+	Dimensions [][]string `json:"Dimensions"`
+	Metrics    []metric   `json:"Metrics"`
+}
+
+type metric struct {
+	Name string `json:"Name"`
+	Unit string `json:"Unit"`
+}
+
+// writer writes records to an output one line at a time, so those of concurrent invocations are
+// not interleaved.
+type writer struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func (w *writer) write(rec map[string]interface{}) {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, _ = w.out.Write(append(line, '\n'))
+}
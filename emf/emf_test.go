@@ -0,0 +1,75 @@
+package emf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with metrics middleware and")
+	var out bytes.Buffer
+	r := lambdarouter.New("")
+	r.Use(Middleware(Config{Namespace: "shop", Output: &out}))
+	r.Get("users/{id}", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}))
+	r.Post("users", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{}, &lambdarouter.HTTPError{Status: http.StatusConflict, Detail: "taken"}
+	}))
+
+	invoke := func(method, path string) map[string]interface{} {
+		out.Reset()
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: method, Path: path})
+		ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "req-1"})
+		_, _ = r.Invoke(ctx, payload)
+
+		var rec map[string]interface{}
+		a.NoError(json.Unmarshal(out.Bytes(), &rec))
+		return rec
+	}
+
+	desc(t, 2, "Middleware should")
+	{
+		desc(t, 4, "record the metrics of invocations by route")
+		rec := invoke(http.MethodGet, "/users/42")
+		a.Exactly("GET", rec["Method"])
+		a.Exactly("/users/{id}", rec["Route"])
+		a.Exactly("2xx", rec["StatusClass"])
+		a.Exactly(float64(200), rec["Status"])
+		a.Exactly(float64(1), rec["Count"])
+		a.Exactly(float64(1), rec["ColdStart"])
+		a.Exactly("req-1", rec["RequestId"])
+		a.Contains(rec, "Latency")
+
+		meta := rec["_aws"].(map[string]interface{})
+		directive := meta["CloudWatchMetrics"].([]interface{})[0].(map[string]interface{})
+		a.Exactly("shop", directive["Namespace"])
+		a.Len(directive["Metrics"], 3)
+
+		desc(t, 4, "record later invocations as warm, and the status of HTTP errors")
+		rec = invoke(http.MethodPost, "/users")
+		a.Exactly(float64(0), rec["ColdStart"])
+		a.Exactly("4xx", rec["StatusClass"])
+		a.Exactly(float64(409), rec["Status"])
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
@@ -0,0 +1,242 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// HTTPError is an error which should be responded to with a specific HTTP status code. When a
+// handler returns an HTTPError, the router responds with its status, detail, and headers instead
+// of failing the invocation.
+type HTTPError struct {
+	// Status is the HTTP status code of the response.
+	Status int
+
+	// Detail is a human-readable explanation specific to this occurrence of the error.
+	Detail string
+
+	// Type is an optional URI reference identifying the problem type, used when responding with
+	// problem details.
+	Type string
+
+	// Headers are additional headers to include in the response, such as Allow or Retry-After.
+	Headers map[string]string
+}
+
+// Error implements the error interface for the HTTPError type.
+func (e *HTTPError) Error() string {
+	if e.Detail == "" {
+		return http.StatusText(e.Status)
+	}
+
+	return http.StatusText(e.Status) + ": " + e.Detail
+}
+
+// Problem is an RFC 7807 problem details document, rendered with the application/problem+json
+// content type.
+type Problem struct {
+	Type     string
+	Title    string
+	Status   int
+	Detail   string
+	Instance string
+
+	// Extensions holds additional members of the problem document. They are written alongside the
+	// standard members, which take precedence over extensions of the same name.
+	Extensions map[string]interface{}
+}
+
+// MarshalJSON implements the json.Marshaler interface for the Problem type.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	doc := map[string]interface{}{}
+
+	for name, value := range p.Extensions {
+		doc[name] = value
+	}
+
+	doc["type"] = p.Type
+	doc["title"] = p.Title
+	doc["status"] = p.Status
+	if p.Detail != "" {
+		doc["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		doc["instance"] = p.Instance
+	}
+
+	return json.Marshal(doc)
+}
+
+// ProblemExtender is called with every problem document rendered by the router before it is
+// written, allowing custom fields to be added through its Extensions. The err parameter is the
+// error which caused the problem.
+type ProblemExtender func(ctx context.Context, p *Problem, err error)
+
+// ProblemDetails configures the router to render errors as application/problem+json documents,
+// as described by RFC 7807. This applies to unmatched routes (404), unsupported methods (405),
+// HTTPErrors returned by handlers, and any other error returned by a handler, which is rendered as
+// a 500 rather than failing the invocation. The extend parameter may be nil.
+func (r *Router) ProblemDetails(extend ProblemExtender) {
+	r.problems = true
+	r.extendProblem = extend
+
+	// Problem documents include the path of the request, so none of them can be cached.
+	r.unmatched = nil
+}
+
+// unmatchedResponses caches the serialized responses to requests which match no route, keyed by
+// the value of their Allow header, which is empty for 404s. These responses only depend on the
+// configuration of the router, so they are rendered once rather than on every miss: the 404 once
+// the options of the router have been applied, and the 405 of each set of methods as routes are
+// defined. Defining routes never invalidates them, as the response for a set of methods is the
+// same whichever paths have it.
+type unmatchedResponses struct {
+	cache sync.Map
+}
+
+// unmatchedError returns the error responded with to requests which match no route, whose path
+// matches routes of the allow methods.
+func unmatchedError(allow string) *HTTPError {
+	if allow == "" {
+		return &HTTPError{Status: http.StatusNotFound}
+	}
+
+	return &HTTPError{
+		Status:  http.StatusMethodNotAllowed,
+		Headers: map[string]string{"Allow": allow},
+	}
+}
+
+// unmatchedResponse returns the cached response to requests which match no route, whose path
+// matches routes of the allow methods, rendering it if it is not cached yet.
+func (r Router) unmatchedResponse(allow string) ([]byte, error) {
+	if res, ok := r.unmatched.cache.Load(allow); ok {
+		return res.([]byte), nil
+	}
+
+	res, err := r.errorResponse(context.Background(), events.APIGatewayProxyRequest{}, unmatchedError(allow))
+	if err == nil {
+		r.unmatched.cache.Store(allow, res)
+	}
+
+	return res, err
+}
+
+// prerenderUnmatched renders the responses to requests which match no route ahead of them: the 404,
+// and the 405 of requests to path, if it is not empty, with methods it has no route for.
+func (r Router) prerenderUnmatched(path string) {
+	if r.unmatched == nil {
+		return
+	}
+
+	allow := ""
+	if path != "" {
+		allow = strings.Join(r.allowedMethods(path), ", ")
+	}
+
+	_, _ = r.unmatchedResponse(allow)
+}
+
+// notMatched renders the response to a request which matches no route: a 405 listing the allowed
+// methods if the path matches routes of other methods, or a 404 otherwise. The not found handler
+// of the router is invoked in place of the 404, if it has one.
+func (r Router) notMatched(ctx context.Context, req events.APIGatewayProxyRequest, payload []byte) ([]byte, error) {
+	allow := strings.Join(r.allowedMethods(req.Path), ", ")
+	if allow == "" {
+		r.logf("no route for %s %s", req.HTTPMethod, req.Path)
+	} else {
+		r.logf("method %s not allowed for %s, only %s", req.HTTPMethod, req.Path, allow)
+	}
+
+	if allow == "" && r.unknownVersion(req) {
+		return r.errorResponse(ctx, req, &HTTPError{Status: http.StatusBadRequest, Detail: "unknown version"})
+	}
+
+	if allow == "" && r.notFound != nil {
+		res, err := r.notFound.Invoke(withRequest(ctx, req, Route{}), payload)
+		if err != nil {
+			return r.errorResponse(ctx, req, err)
+		}
+
+		return res, nil
+	}
+
+	if r.unmatched != nil {
+		return r.unmatchedResponse(allow)
+	}
+
+	return r.errorResponse(ctx, req, unmatchedError(allow))
+}
+
+// errorResponse renders the response for an error encountered while handling req. Errors which
+// are not HTTPErrors are only rendered when problem details are enabled; otherwise they are
+// returned so the invocation fails, as it would without the router.
+func (r Router) errorResponse(ctx context.Context, req events.APIGatewayProxyRequest, err error) ([]byte, error) {
+	var httpErr *HTTPError
+
+	if !errors.As(err, &httpErr) {
+		if !r.problems {
+			return nil, err
+		}
+		httpErr = &HTTPError{Status: http.StatusInternalServerError}
+	}
+
+	res := events.APIGatewayProxyResponse{StatusCode: httpErr.Status}
+	if len(httpErr.Headers) > 0 {
+		res.Headers = map[string]string{}
+		for name, value := range httpErr.Headers {
+			res.Headers[name] = value
+		}
+	}
+
+	if !r.problems {
+		res.Body = httpErr.Detail
+		if res.Body == "" {
+			res.Body = strings.ToLower(http.StatusText(httpErr.Status))
+		}
+
+		return r.jsonCodec().Marshal(res)
+	}
+
+	p := Problem{
+		Type:     httpErr.Type,
+		Title:    http.StatusText(httpErr.Status),
+		Status:   httpErr.Status,
+		Detail:   httpErr.Detail,
+		Instance: req.Path,
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if r.extendProblem != nil {
+		r.extendProblem(ctx, &p, err)
+	}
+
+	body, err := r.jsonCodec().Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Headers == nil {
+		res.Headers = map[string]string{}
+	}
+	res.Headers["Content-Type"] = "application/problem+json"
+	res.Body = string(body)
+
+	return r.jsonCodec().Marshal(res)
+}
+
+// allowedMethods returns the methods of every route which matches path, sorted.
+func (r Router) allowedMethods(path string) []string {
+	if r.table == nil {
+		return nil
+	}
+
+	return r.table.allowedMethods(path)
+}
@@ -0,0 +1,121 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrors(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router and")
+	r := New("prefix")
+	ctx := context.Background()
+
+	r.Get("teapot", lambda.NewHandler(func() error {
+		return &HTTPError{Status: http.StatusTeapot, Detail: "short and stout"}
+	}))
+	r.Get("broken", lambda.NewHandler(func() error {
+		return errors.New("broken")
+	}))
+
+	invoke := func(method, path string) (events.APIGatewayProxyResponse, error) {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: method, Path: path})
+
+		var res events.APIGatewayProxyResponse
+		resjson, err := r.Invoke(ctx, payload)
+		if err == nil {
+			a.NoError(json.Unmarshal(resjson, &res))
+		}
+		return res, err
+	}
+
+	desc(t, 2, "Invoke method should")
+	{
+		desc(t, 4, "respond with the status and detail of a returned HTTPError")
+		res, err := invoke(http.MethodGet, "/prefix/teapot")
+		a.NoError(err)
+		a.Exactly(http.StatusTeapot, res.StatusCode)
+		a.Exactly("short and stout", res.Body)
+
+		desc(t, 4, "respond with a 405 when the path only matches other methods")
+		res, err = invoke(http.MethodPost, "/prefix/teapot")
+		a.NoError(err)
+		a.Exactly(http.StatusMethodNotAllowed, res.StatusCode)
+		a.Exactly("GET", res.Headers["Allow"])
+
+		desc(t, 4, "cache the responses to requests which match no route")
+		_, cached := r.unmatched.cache.Load("GET")
+		a.True(cached)
+		notFound, _ := r.unmatched.cache.Load("")
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/prefix/nothing"})
+		resjson, err := r.Invoke(ctx, payload)
+		a.NoError(err)
+		a.Exactly(notFound, resjson)
+
+		desc(t, 4, "render the responses to requests which match no route before any request, with the codec of the router")
+		codec := &countingCodec{}
+		cr := New("prefix", WithCodec(codec))
+		_, cached = cr.unmatched.cache.Load("")
+		a.True(cached)
+		a.Exactly(1, codec.marshals)
+		cr.Get("teapot", lambda.NewHandler(func() error { return nil }))
+		cr.Put("teapot", lambda.NewHandler(func() error { return nil }))
+		_, cached = cr.unmatched.cache.Load("GET")
+		a.True(cached)
+		_, cached = cr.unmatched.cache.Load("GET, PUT")
+		a.True(cached)
+		*codec = countingCodec{}
+		payload, _ = json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodPost, Path: "/prefix/teapot"})
+		resjson, err = cr.Invoke(ctx, payload)
+		a.NoError(err)
+		a.Contains(string(resjson), `"Allow":"GET, PUT"`)
+		a.Exactly(0, codec.marshals)
+
+		desc(t, 4, "return any other error returned by the handler")
+		_, err = invoke(http.MethodGet, "/prefix/broken")
+		a.EqualError(err, "broken")
+	}
+
+	desc(t, 2, "ProblemDetails method should")
+	{
+		r.ProblemDetails(func(ctx context.Context, p *Problem, err error) {
+			p.Extensions = map[string]interface{}{"error": err.Error()}
+		})
+
+		desc(t, 4, "stop caching the responses to requests which match no route")
+		a.Nil(r.unmatched)
+
+		desc(t, 4, "render not found responses as problem details")
+		res, err := invoke(http.MethodGet, "/prefix/nothing")
+		a.NoError(err)
+		a.Exactly(http.StatusNotFound, res.StatusCode)
+		a.Exactly("application/problem+json", res.Headers["Content-Type"])
+		a.JSONEq(`{
+			"type": "about:blank",
+			"title": "Not Found",
+			"status": 404,
+			"instance": "/prefix/nothing",
+			"error": "Not Found"
+		}`, res.Body)
+
+		desc(t, 4, "render returned HTTPErrors as problem details")
+		res, err = invoke(http.MethodGet, "/prefix/teapot")
+		a.NoError(err)
+		a.Exactly(http.StatusTeapot, res.StatusCode)
+		a.Contains(res.Body, `"detail":"short and stout"`)
+
+		desc(t, 4, "render any other error as an internal server error")
+		res, err = invoke(http.MethodGet, "/prefix/broken")
+		a.NoError(err)
+		a.Exactly(http.StatusInternalServerError, res.StatusCode)
+		a.Contains(res.Body, `"error":"broken"`)
+	}
+}
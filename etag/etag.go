@@ -0,0 +1,173 @@
+// Package etag provides middleware which tags responses with entity tags and evaluates the
+// If-None-Match and If-Match preconditions of requests against them.
+package etag
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/mitchell/lambdarouter/internal/header"
+)
+
+// Config configures the entity tag middleware. Routes can be configured differently by passing
+// the middleware to lambdarouter.WithMiddleware.
+type Config struct {
+	// Weak makes generated tags weak, as in W/"...", for responses which are semantically
+	// equivalent but may not be byte for byte identical, such as those rendered from maps.
+	Weak bool
+
+	// Current returns the entity tag of the current representation of the resource a request
+	// targets, or an empty string if it does not exist. It lets the preconditions of PUT, PATCH,
+	// DELETE, and POST requests be evaluated before the handler changes the resource. If nil,
+	// preconditions of those requests are ignored.
+	Current func(ctx context.Context, req events.APIGatewayProxyRequest) (string, error)
+}
+
+// Middleware returns middleware which adds an ETag header to 200 responses which lack one,
+// generated from a hash of their body. GET and HEAD requests whose If-None-Match header matches
+// the tag are responded to with a 304 without a body, and those whose If-Match header does not
+// are responded to with a 412. Other requests are responded to with a 412, without invoking the
+// handler, when their preconditions fail against the tag returned by cfg.Current.
+func Middleware(cfg Config) lambdarouter.Middleware {
+	return func(next lambda.Handler) lambda.Handler {
+		return tagger{cfg: cfg, next: next}
+	}
+}
+
+type tagger struct {
+	cfg  Config
+	next lambda.Handler
+}
+
+func (t tagger) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	codec := lambdarouter.CodecFrom(ctx)
+
+	req, err := lambdarouter.RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	safe := req.HTTPMethod == http.MethodGet || req.HTTPMethod == http.MethodHead
+
+	if !safe {
+		if t.cfg.Current != nil {
+			current, err := t.cfg.Current(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			if preconditions(req, current, false) != 0 {
+				return nil, preconditionFailed()
+			}
+		}
+		return t.next.Invoke(ctx, payload)
+	}
+
+	resjson, err := t.next.Invoke(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var res events.APIGatewayProxyResponse
+	if err := codec.Unmarshal(resjson, &res); err != nil || res.StatusCode != http.StatusOK {
+		return resjson, nil
+	}
+
+	tag := header.FromResponse(res, "ETag")
+	if tag == "" {
+		tag = t.generate(res.Body)
+		if res.Headers == nil {
+			res.Headers = map[string]string{}
+		}
+		res.Headers["ETag"] = tag
+	}
+
+	switch preconditions(req, tag, true) {
+	case http.StatusNotModified:
+		return codec.Marshal(notModified(res))
+	case http.StatusPreconditionFailed:
+		return nil, preconditionFailed()
+	}
+
+	return codec.Marshal(res)
+}
+
+// preconditions evaluates the If-Match and If-None-Match headers of req against the entity tag
+// current, which is empty when the resource does not exist. It returns the status to respond with
+// when a precondition fails, which is a 304 when If-None-Match fails for a safe request, or zero
+// when they are satisfied. If-None-Match is compared weakly for safe requests and strongly
+// otherwise.
+func preconditions(req events.APIGatewayProxyRequest, current string, safe bool) int {
+	if ifMatch := header.Get(req, "If-Match"); ifMatch != "" {
+		if current == "" || !matches(ifMatch, current, false) {
+			return http.StatusPreconditionFailed
+		}
+	}
+
+	if ifNoneMatch := header.Get(req, "If-None-Match"); ifNoneMatch != "" && current != "" {
+		if matches(ifNoneMatch, current, safe) {
+			if safe {
+				return http.StatusNotModified
+			}
+			return http.StatusPreconditionFailed
+		}
+	}
+
+	return 0
+}
+
+func (t tagger) generate(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	tag := `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+
+	if t.cfg.Weak {
+		return "W/" + tag
+	}
+	return tag
+}
+
+// matches reports whether the list of entity tags in a precondition header matches tag. Weak
+// comparison ignores whether either tag is weak, while strong comparison never matches weak tags.
+func matches(list, tag string, weak bool) bool {
+	if strings.TrimSpace(list) == "*" {
+		return true
+	}
+
+	opaque := strings.TrimPrefix(tag, "W/")
+	if !weak && opaque != tag {
+		return false
+	}
+
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		trimmed := strings.TrimPrefix(candidate, "W/")
+
+		if trimmed == opaque && (weak || trimmed == candidate) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// notModified returns the 304 response for res, which keeps only the headers a 304 may carry.
+func notModified(res events.APIGatewayProxyResponse) events.APIGatewayProxyResponse {
+	headers := map[string]string{}
+	for name, value := range res.Headers {
+		switch http.CanonicalHeaderKey(name) {
+		case "Etag", "Cache-Control", "Content-Location", "Date", "Expires", "Vary":
+			headers[name] = value
+		}
+	}
+
+	return events.APIGatewayProxyResponse{StatusCode: http.StatusNotModified, Headers: headers}
+}
+
+func preconditionFailed() error {
+	return &lambdarouter.HTTPError{Status: http.StatusPreconditionFailed, Detail: "precondition failed"}
+}
@@ -0,0 +1,112 @@
+package etag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with tagged routes and")
+	document, updates := "v1", 0
+	current := func(context.Context, events.APIGatewayProxyRequest) (string, error) {
+		return tagger{}.generate(document), nil
+	}
+	r := lambdarouter.New("prefix")
+	r.Use(Middleware(Config{Current: current}))
+	r.Get("document", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusOK,
+			Headers:    map[string]string{"Cache-Control": "max-age=60", "Content-Type": "text/plain"},
+			Body:       document,
+		}, nil
+	}))
+	r.Put("document", lambda.NewHandler(func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		document = req.Body
+		updates++
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusNoContent}, nil
+	}))
+	r.Get("weak", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: "weak"}, nil
+	}), lambdarouter.WithMiddleware(Middleware(Config{Weak: true})))
+
+	invoke := func(method, path, body string, headers map[string]string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod: method,
+			Path:       path,
+			Headers:    headers,
+			Body:       body,
+		})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "Middleware should")
+	{
+		desc(t, 4, "tag responses with a hash of their body")
+		res := invoke(http.MethodGet, "/prefix/document", "", nil)
+		tag := res.Headers["ETag"]
+		a.Regexp(`^"[\w-]+"$`, tag)
+		a.Exactly("v1", res.Body)
+
+		res = invoke(http.MethodGet, "/prefix/weak", "", nil)
+		a.Regexp(`^W/"[\w-]+"$`, res.Headers["ETag"])
+
+		desc(t, 4, "respond with a 304 when If-None-Match matches")
+		res = invoke(http.MethodGet, "/prefix/document", "", map[string]string{"If-None-Match": `"other", W/` + tag})
+		a.Exactly(http.StatusNotModified, res.StatusCode)
+		a.Exactly("", res.Body)
+		a.Exactly(map[string]string{"ETag": tag, "Cache-Control": "max-age=60"}, res.Headers)
+
+		desc(t, 4, "respond with a 412 when If-Match does not match")
+		res = invoke(http.MethodGet, "/prefix/document", "", map[string]string{"If-Match": `"other"`})
+		a.Exactly(http.StatusPreconditionFailed, res.StatusCode)
+
+		desc(t, 4, "evaluate the preconditions of updates before invoking the handler")
+		res = invoke(http.MethodPut, "/prefix/document", "v2", map[string]string{"If-Match": `"other"`})
+		a.Exactly(http.StatusPreconditionFailed, res.StatusCode)
+		a.Exactly(0, updates)
+
+		res = invoke(http.MethodPut, "/prefix/document", "v2", map[string]string{"If-Match": tag})
+		a.Exactly(http.StatusNoContent, res.StatusCode)
+		a.Exactly(1, updates)
+
+		res = invoke(http.MethodPut, "/prefix/document", "v3", map[string]string{"If-None-Match": "*"})
+		a.Exactly(http.StatusPreconditionFailed, res.StatusCode)
+
+		desc(t, 4, "respond as usual once the tag changes")
+		res = invoke(http.MethodGet, "/prefix/document", "", map[string]string{"If-None-Match": tag})
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("v2", res.Body)
+	}
+
+	desc(t, 2, "matches function should")
+	{
+		desc(t, 4, "never match weak tags when comparing strongly")
+		a.True(matches(`W/"a"`, `"a"`, true))
+		a.False(matches(`W/"a"`, `"a"`, false))
+		a.False(matches(`"a"`, `W/"a"`, false))
+		a.True(matches(`"b", "a"`, `"a"`, false))
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
@@ -0,0 +1,40 @@
+package lambdarouter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+type eventBridgeRoute struct {
+	source     string
+	detailType string
+	h          lambda.Handler
+}
+
+// EventBridge adds a route for events delivered by EventBridge, such as those of schedules and
+// event buses, so one function can handle them as well as HTTP requests. The source and detailType
+// parameters are patterns matched against the source and detail-type of events, in which * matches
+// any run of characters, and which match any event if empty. The handler parameter is invoked with
+// the event, which decodes into an events.CloudWatchEvent, and is wrapped by the middleware of the
+// router, which should not assume it handles HTTP requests. Events are routed to the first route
+// which matches them in the order they were defined, and invocations with events which match no
+// route fail.
+func (r *Router) EventBridge(source, detailType string, handler lambda.Handler) {
+	rt := eventBridgeRoute{source: source, detailType: detailType, h: r.sourceHandler(handler)}
+
+	r.addSource(func(s *sourceRoutes) {
+		s.eventBridge = append(s.eventBridge[:len(s.eventBridge):len(s.eventBridge)], rt)
+	})
+}
+
+func (r Router) invokeEventBridge(ctx context.Context, routes []eventBridgeRoute, probe sourceProbe, payload []byte) ([]byte, error) {
+	for _, rt := range routes {
+		if matchPattern(rt.source, probe.Source) && matchPattern(rt.detailType, probe.DetailType) {
+			return rt.h.Invoke(ctx, payload)
+		}
+	}
+
+	return nil, fmt.Errorf("no route for EventBridge event %q from %q", probe.DetailType, probe.Source)
+}
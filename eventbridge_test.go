@@ -0,0 +1,96 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventBridge(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with EventBridge and HTTP routes and")
+	var seen []string
+	r := New("prefix")
+	r.Use(func(next lambda.Handler) lambda.Handler {
+		return lambda.NewHandler(func(ctx context.Context, payload json.RawMessage) (json.RawMessage, error) {
+			seen = append(seen, "middleware")
+			return next.Invoke(ctx, payload)
+		})
+	})
+	r.EventBridge("aws.events", "Scheduled Event", lambda.NewHandler(func(e events.CloudWatchEvent) (string, error) {
+		return "scheduled " + e.ID, nil
+	}))
+	r.EventBridge("com.example.*", "Order *", lambda.NewHandler(func(e events.CloudWatchEvent) (string, error) {
+		return e.DetailType + " " + string(e.Detail), nil
+	}))
+	r.EventBridge("", "", lambda.NewHandler(func(e events.CloudWatchEvent) (string, error) {
+		return "fallback", nil
+	}))
+	r.Get("health", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: "ok"}, nil
+	}))
+
+	invoke := func(v interface{}) (string, error) {
+		payload, _ := json.Marshal(v)
+		res, err := r.Invoke(context.Background(), payload)
+		return string(res), err
+	}
+
+	desc(t, 2, "EventBridge method should")
+	{
+		desc(t, 4, "route events by source and detail-type")
+		res, err := invoke(events.CloudWatchEvent{ID: "e1", Source: "aws.events", DetailType: "Scheduled Event"})
+		a.NoError(err)
+		a.Exactly(`"scheduled e1"`, res)
+
+		res, err = invoke(events.CloudWatchEvent{
+			Source:     "com.example.orders",
+			DetailType: "Order Placed",
+			Detail:     json.RawMessage(`{"id":7}`),
+		})
+		a.NoError(err)
+		a.Exactly(`"Order Placed {\"id\":7}"`, res)
+
+		desc(t, 4, "route unmatched events to routes with empty patterns")
+		res, err = invoke(events.CloudWatchEvent{Source: "com.example.users", DetailType: "User Created"})
+		a.NoError(err)
+		a.Exactly(`"fallback"`, res)
+
+		desc(t, 4, "wrap handlers by the middleware of the router")
+		a.Exactly([]string{"middleware", "middleware", "middleware"}, seen)
+
+		desc(t, 4, "still route HTTP requests")
+		resjson, err := invoke(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/prefix/health"})
+		a.NoError(err)
+		var httpRes events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal([]byte(resjson), &httpRes))
+		a.Exactly("ok", httpRes.Body)
+	}
+
+	desc(t, 2, "Invoke method should")
+	{
+		desc(t, 4, "fail for events which match no route")
+		r := New("prefix")
+		r.EventBridge("aws.events", "*", lambda.NewHandler(func() {}))
+		payload, _ := json.Marshal(events.CloudWatchEvent{Source: "aws.s3", DetailType: "Object Created"})
+		_, err := r.Invoke(context.Background(), payload)
+		a.EqualError(err, `no route for EventBridge event "Object Created" from "aws.s3"`)
+	}
+
+	desc(t, 2, "matchPattern function should")
+	{
+		desc(t, 4, "match wildcards anywhere in the pattern")
+		a.True(matchPattern("", "anything"))
+		a.True(matchPattern("a*c*e", "abcde"))
+		a.True(matchPattern("*.png", "logo.png"))
+		a.False(matchPattern("a*c*e", "abcd"))
+		a.False(matchPattern("ab*ba", "aba"))
+		a.False(matchPattern("exact", "exactly"))
+	}
+}
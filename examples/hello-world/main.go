@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/aws/aws-lambda-go/events"
@@ -11,7 +12,7 @@ import (
 var r = lambdarouter.New("hellosrv")
 
 func init() {
-	r.Post("hello", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+	r.Post("hello", lambdarouter.HandlerFunc(func(_ context.Context, _ events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 		return events.APIGatewayProxyResponse{
 			StatusCode: http.StatusCreated,
 			Body:       "hello world",
@@ -19,21 +20,21 @@ func init() {
 	}))
 
 	r.Group("hello", func(r *lambdarouter.Router) {
-		r.Get("{name}", lambda.NewHandler(func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		r.Get("{name}", lambdarouter.HandlerFunc(func(_ context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 			return events.APIGatewayProxyResponse{
 				StatusCode: http.StatusOK,
 				Body:       "hello " + req.PathParameters["name"],
 			}, nil
 		}))
 
-		r.Put("french", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		r.Put("french", lambdarouter.HandlerFunc(func(_ context.Context, _ events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 			return events.APIGatewayProxyResponse{
 				StatusCode: http.StatusOK,
 				Body:       "bonjour le monde",
 			}, nil
 		}))
 
-		r.Get("french/{prenom}", lambda.NewHandler(func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		r.Get("french/{prenom}", lambdarouter.HandlerFunc(func(_ context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 			return events.APIGatewayProxyResponse{
 				StatusCode: http.StatusOK,
 				Body:       "bonjour " + req.PathParameters["prenom"],
@@ -0,0 +1,68 @@
+package lambdarouter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+// WithFallback sets a handler invoked in place of the response of a route when its handler fails,
+// such as to respond with stale data from a cache or a degraded response. The handler fails when
+// it returns an error other than an HTTPError of a status below 500, including the 504 of a route
+// which outlasts the limit set by WithTimeout. Only requests which the middleware given with
+// WithMiddleware passed on to the handler fall back, so a request rejected by them, such as by
+// authentication which could not be completed, is never responded to with the fallback. The
+// fallback is invoked with the same payload, and the error of the handler can be retrieved with
+// FallbackError. Its response, or error, is that of the route, as seen by the middleware of the
+// router.
+func WithFallback(h lambda.Handler) RouteOption {
+	return func(e *event) {
+		e.fallback = h
+	}
+}
+
+// FallbackError returns the error of the handler a fallback is invoked in place of, or nil if the
+// current invocation is not of a fallback.
+func FallbackError(ctx context.Context) error {
+	err, _ := ctx.Value(fallbackKey).(error)
+	return err
+}
+
+// fallbackHandler invokes a fallback when its handler fails, once the middleware of the route have
+// passed the request on to the handler.
+type fallbackHandler struct {
+	next     lambda.Handler
+	fallback lambda.Handler
+}
+
+func (fh fallbackHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	reached := new(int32)
+	res, err := fh.next.Invoke(context.WithValue(ctx, reachedKey, reached), payload)
+	if err == nil || atomic.LoadInt32(reached) == 0 {
+		return res, err
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.Status < http.StatusInternalServerError {
+		return res, err
+	}
+
+	return fh.fallback.Invoke(context.WithValue(ctx, fallbackKey, err), payload)
+}
+
+// reachHandler records that the middleware of a route with a fallback passed the request on to
+// its handler. The flag is set atomically, as the handler may run in the goroutine of a time limit.
+type reachHandler struct {
+	next lambda.Handler
+}
+
+func (rh reachHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	if reached, ok := ctx.Value(reachedKey).(*int32); ok {
+		atomic.StoreInt32(reached, 1)
+	}
+
+	return rh.next.Invoke(ctx, payload)
+}
@@ -0,0 +1,94 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithFallback(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with routes with fallbacks and")
+	var cause error
+	fallback := lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+		cause = FallbackError(ctx)
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: "stale"}, nil
+	})
+
+	r := New("")
+	r.Get("broken", lambda.NewHandler(func() error {
+		return errors.New("broken")
+	}), WithFallback(fallback))
+	r.Get("slow", lambda.NewHandler(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}), WithTimeout(10*time.Millisecond), WithFallback(fallback))
+	r.Get("missing", lambda.NewHandler(func() error {
+		return &HTTPError{Status: http.StatusNotFound}
+	}), WithFallback(fallback))
+
+	var handled bool
+	unauthorized := func(next lambda.Handler) lambda.Handler {
+		return lambda.NewHandler(func() error {
+			return errors.New("keys unavailable")
+		})
+	}
+	slowAuth := func(next lambda.Handler) lambda.Handler {
+		return lambda.NewHandler(func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		})
+	}
+	protected := lambda.NewHandler(func() error {
+		handled = true
+		return errors.New("broken")
+	})
+	r.Get("private", protected, WithMiddleware(unauthorized), WithFallback(fallback))
+	r.Get("private/slow", protected, WithMiddleware(slowAuth), WithTimeout(10*time.Millisecond),
+		WithFallback(fallback))
+
+	invoke := func(path string) events.APIGatewayProxyResponse {
+		cause = nil
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "WithFallback option should")
+	{
+		desc(t, 4, "respond with the fallback when the handler fails")
+		a.Exactly("stale", invoke("/broken").Body)
+
+		desc(t, 4, "give the fallback the error of the handler")
+		a.EqualError(cause, "broken")
+
+		desc(t, 4, "respond with the fallback when the handler times out")
+		a.Exactly("stale", invoke("/slow").Body)
+		a.Exactly(&HTTPError{Status: http.StatusGatewayTimeout}, cause)
+
+		desc(t, 4, "not replace client errors")
+		a.Exactly(http.StatusNotFound, invoke("/missing").StatusCode)
+		a.Nil(cause)
+
+		desc(t, 4, "not replace failures of the middleware of the route")
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/private"})
+		_, err := r.Invoke(context.Background(), payload)
+		a.EqualError(err, "keys unavailable")
+		a.Nil(cause)
+		a.Exactly(http.StatusGatewayTimeout, invoke("/private/slow").StatusCode)
+		a.Nil(cause)
+		a.False(handled)
+	}
+}
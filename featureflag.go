@@ -0,0 +1,83 @@
+package lambdarouter
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// FlagProvider decides whether feature flags are enabled, for the routes gated by WithFeatureFlag.
+// Providers backed by a remote service, such as AppConfig or LaunchDarkly, should cache flags, as
+// they are consulted on every request to gated routes.
+type FlagProvider interface {
+	// FlagEnabled reports whether the named flag is enabled for req.
+	FlagEnabled(name string, req events.APIGatewayProxyRequest) bool
+}
+
+// FlagProviderFunc adapts a function to the FlagProvider interface, such as one evaluating flags
+// with the LaunchDarkly SDK for a context built from the request.
+type FlagProviderFunc func(name string, req events.APIGatewayProxyRequest) bool
+
+// FlagEnabled implements the FlagProvider interface for the FlagProviderFunc type.
+func (f FlagProviderFunc) FlagEnabled(name string, req events.APIGatewayProxyRequest) bool {
+	return f(name, req)
+}
+
+// EnvFlags returns a FlagProvider which takes flags from environment variables named by prefix
+// followed by the name of the flag, upper-cased and with every character other than a letter or
+// digit replaced by an underscore, so with the prefix FEATURE_ the flag new-checkout is enabled by
+// FEATURE_NEW_CHECKOUT=true. Values are parsed by strconv.ParseBool, and flags whose variable is
+// unset or invalid are disabled.
+func EnvFlags(prefix string) FlagProvider {
+	return FlagProviderFunc(func(name string, _ events.APIGatewayProxyRequest) bool {
+		enabled, _ := strconv.ParseBool(os.Getenv(prefix + envName(name)))
+		return enabled
+	})
+}
+
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, name)
+}
+
+// WithFlags sets the provider of the feature flags which gate routes. By default flags are taken
+// from environment variables with the prefix FEATURE_, as by EnvFlags.
+func WithFlags(p FlagProvider) Option {
+	return func(r *Router) {
+		r.table.flags = p
+	}
+}
+
+// WithFeatureFlag gates a route behind the named feature flag, so it can be deployed dark and
+// enabled without deploying again. While the flag is disabled the route does not exist: requests
+// are routed to another route of the same method and path, such as the implementation the gated
+// route replaces, or are responded to with a 404.
+func WithFeatureFlag(name string) RouteOption {
+	return withPredicates(predicate{
+		name:   "flag=" + name,
+		rank:   flagRank,
+		status: http.StatusNotFound,
+		bind: func(t *routeTable) func(req events.APIGatewayProxyRequest) bool {
+			return func(req events.APIGatewayProxyRequest) bool {
+				t.mu.RLock()
+				flags := t.flags
+				t.mu.RUnlock()
+
+				if flags == nil {
+					flags = defaultFlags
+				}
+				return flags.FlagEnabled(name, req)
+			}
+		},
+	})
+}
+
+var defaultFlags = EnvFlags("FEATURE_")
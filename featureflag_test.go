@@ -0,0 +1,61 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithFeatureFlag(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with feature flags and")
+	enabled := map[string]bool{}
+	r := New("", WithFlags(FlagProviderFunc(func(name string, _ events.APIGatewayProxyRequest) bool {
+		return enabled[name]
+	})))
+
+	respond := func(body string) lambda.Handler {
+		return lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: body}, nil
+		})
+	}
+	r.Get("checkout", respond("old"))
+	r.Get("checkout", respond("new"), WithFeatureFlag("new-checkout"))
+	r.Get("reports", respond("reports"), WithFeatureFlag("reports"))
+
+	invoke := func(path string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "WithFeatureFlag option should")
+	{
+		desc(t, 4, "hide routes whose flag is disabled")
+		a.Exactly("old", invoke("/checkout").Body)
+		a.Exactly(http.StatusNotFound, invoke("/reports").StatusCode)
+
+		desc(t, 4, "route to routes whose flag is enabled")
+		enabled["new-checkout"], enabled["reports"] = true, true
+		a.Exactly("new", invoke("/checkout").Body)
+		a.Exactly("reports", invoke("/reports").Body)
+	}
+
+	desc(t, 2, "EnvFlags function should")
+	{
+		desc(t, 4, "take flags from environment variables")
+		t.Setenv("FEATURE_NEW_CHECKOUT", "true")
+		a.True(EnvFlags("FEATURE_").FlagEnabled("new-checkout", events.APIGatewayProxyRequest{}))
+		a.False(EnvFlags("FEATURE_").FlagEnabled("reports", events.APIGatewayProxyRequest{}))
+	}
+}
@@ -1,9 +1,25 @@
 module github.com/mitchell/lambdarouter
 
+go 1.18
+
 require (
-	github.com/aws/aws-lambda-go v1.10.0
-	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/aws/aws-lambda-go v1.47.0
+	github.com/go-chi/chi/v5 v5.0.12
+	github.com/gorilla/mux v1.8.0
 	github.com/hashicorp/go-immutable-radix v1.0.0
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.0
+	github.com/stretchr/testify v1.8.2
+	go.opentelemetry.io/otel v1.14.0
+	go.opentelemetry.io/otel/sdk v1.14.0
+	go.opentelemetry.io/otel/trace v1.14.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/go-logr/logr v1.2.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
 	github.com/hashicorp/golang-lru v0.5.1 // indirect
-	github.com/stretchr/testify v1.3.0
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.5.0 // indirect
 )
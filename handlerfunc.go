@@ -0,0 +1,41 @@
+package lambdarouter
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// HandlerFunc adapts a function handling proxy requests to a lambda.Handler. Unlike the handlers
+// created by lambda.NewHandler, it needs no reflection to invoke the function, and takes the
+// request the router already decoded from the context rather than decoding the payload again.
+//
+//	r.Get("hello/{name}", lambdarouter.HandlerFunc(func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+//		return respond.Text(http.StatusOK, "hello "+req.PathParameters["name"]), nil
+//	}))
+type HandlerFunc func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error)
+
+// Invoke decodes the proxy request, calls f with it, and encodes its response with the codec of the
+// router. Errors returned by f are returned unchanged.
+func (f HandlerFunc) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := f(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return CodecFrom(ctx).Marshal(res)
+}
+
+// InvokeFunc adapts a function handling raw payloads to a lambda.Handler, such as the handlers of
+// events which are not HTTP requests, or handlers which encode their responses themselves.
+type InvokeFunc func(ctx context.Context, payload []byte) ([]byte, error)
+
+// Invoke calls f.
+func (f InvokeFunc) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	return f(ctx, payload)
+}
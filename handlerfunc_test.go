@@ -0,0 +1,71 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerFunc(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a router with adapted handlers and")
+	r := New("prefix")
+	r.Get("hello/{name}", HandlerFunc(func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		_, routed := RequestFromContext(ctx)
+		a.True(routed)
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: "hello " + req.PathParameters["name"]}, nil
+	}))
+	r.Get("fail", HandlerFunc(func(context.Context, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{}, errors.New("failed")
+	}))
+	r.Get("raw", InvokeFunc(func(_ context.Context, payload []byte) ([]byte, error) {
+		return []byte(`{"statusCode": 204}`), nil
+	}))
+
+	invoke := func(path string) (events.APIGatewayProxyResponse, error) {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path})
+		resjson, err := r.Invoke(context.Background(), payload)
+
+		var res events.APIGatewayProxyResponse
+		_ = json.Unmarshal(resjson, &res)
+		return res, err
+	}
+
+	desc(t, 1, "HandlerFunc type should")
+	{
+		desc(t, 3, "invoke the function with the routed request")
+		res, err := invoke("/prefix/hello/bob")
+		a.NoError(err)
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("hello bob", res.Body)
+
+		desc(t, 3, "return the errors of the function")
+		_, err = invoke("/prefix/fail")
+		a.EqualError(err, "failed")
+
+		desc(t, 3, "decode the payload when invoked without a router")
+		h := HandlerFunc(func(_ context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+			return events.APIGatewayProxyResponse{Body: req.Path}, nil
+		})
+		resjson, err := h.Invoke(context.Background(), []byte(`{"path": "/direct"}`))
+		a.NoError(err)
+		a.JSONEq(`{"statusCode": 0, "headers": null, "multiValueHeaders": null, "body": "/direct"}`, string(resjson))
+
+		desc(t, 3, "be named by its function in route listings")
+		a.Contains(r.String(), "TestHandlerFunc.func")
+	}
+
+	desc(t, 1, "InvokeFunc type should")
+	{
+		desc(t, 3, "invoke the function with the payload")
+		res, err := invoke("/prefix/raw")
+		a.NoError(err)
+		a.Exactly(http.StatusNoContent, res.StatusCode)
+	}
+}
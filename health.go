@@ -0,0 +1,162 @@
+package lambdarouter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/mitchell/lambdarouter/internal/dynamo"
+)
+
+// Check is a check of a dependency of the function, run by the route of WithHealthCheck.
+type Check struct {
+	// Name identifies the check in the response of the route.
+	Name string
+
+	// Run checks the dependency, returning an error if it is unhealthy.
+	Run func(ctx context.Context) error
+}
+
+// URLCheck returns a check which requests url with a GET, and fails unless the response has a 2xx
+// or 3xx status.
+func URLCheck(name, url string) Check {
+	return Check{Name: name, Run: func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		res.Body.Close()
+
+		if res.StatusCode >= 400 {
+			return fmt.Errorf("%s responded %s", url, res.Status)
+		}
+		return nil
+	}}
+}
+
+// DynamoDBCheck returns a check which describes a DynamoDB table, and fails unless it is ACTIVE or
+// UPDATING. Requests are signed with the credentials of the execution role of the function, which
+// must be allowed dynamodb:DescribeTable.
+func DynamoDBCheck(table string) Check {
+	client := dynamo.FromEnv()
+
+	return Check{Name: "dynamodb:" + table, Run: func(ctx context.Context) error {
+		status, err := client.DescribeTable(ctx, table)
+		if err != nil {
+			return err
+		}
+
+		if status != "ACTIVE" && status != "UPDATING" {
+			return fmt.Errorf("table %s is %s", table, status)
+		}
+		return nil
+	}}
+}
+
+// healthCheckTimeout is how long checks may run before they fail, short enough for the default
+// timeouts of the health checks of load balancers.
+const healthCheckTimeout = 3 * time.Second
+
+// WithHealthCheck adds a GET route at path, beneath the prefix of the router, for the health
+// checks of load balancers and Route 53. The route runs the checks concurrently, failing those
+// which take longer than three seconds, and responds with a 200 if they all pass and a 503
+// otherwise. The body of the response holds the outcome of each check:
+//
+//	{"status": "fail", "checks": {"orders": {"status": "fail", "durationMs": 12, "error": "..."}}}
+//
+// The route is added before any middleware is used, so that it is not subject to authentication.
+func WithHealthCheck(path string, checks ...Check) Option {
+	return func(r *Router) {
+		r.health = &healthCheck{path: path, checks: checks}
+	}
+}
+
+type healthCheck struct {
+	path   string
+	checks []Check
+}
+
+type checkResult struct {
+	Status     string  `json:"status"`
+	DurationMs float64 `json:"durationMs"`
+	Error      string  `json:"error,omitempty"`
+}
+
+func (hc *healthCheck) Invoke(ctx context.Context, _ []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	results := make([]checkResult, len(hc.checks))
+
+	var wg sync.WaitGroup
+	for i, c := range hc.checks {
+		wg.Add(1)
+		go func(i int, c Check) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	body := struct {
+		Status string                 `json:"status"`
+		Checks map[string]checkResult `json:"checks"`
+	}{Status: "pass", Checks: map[string]checkResult{}}
+
+	status := http.StatusOK
+	for i, c := range hc.checks {
+		body.Checks[c.Name] = results[i]
+		if results[i].Status != "pass" {
+			body.Status, status = "fail", http.StatusServiceUnavailable
+		}
+	}
+
+	codec := CodecFrom(ctx)
+
+	b, err := codec.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return codec.Marshal(events.APIGatewayProxyResponse{
+		StatusCode: status,
+		Headers:    map[string]string{"Content-Type": "application/json", "Cache-Control": "no-store"},
+		Body:       string(b),
+	})
+}
+
+// runCheck runs c, failing it if it panics or outlives ctx.
+func runCheck(ctx context.Context, c Check) checkResult {
+	start := time.Now()
+	done := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("panic: %v", p)
+			}
+		}()
+		done <- c.Run(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	res := checkResult{Status: "pass", DurationMs: float64(time.Since(start)) / float64(time.Millisecond)}
+	if err != nil {
+		res.Status, res.Error = "fail", err.Error()
+	}
+	return res
+}
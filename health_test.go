@@ -0,0 +1,69 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithHealthCheck(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a downstream service and")
+	downstream := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(downstream)
+	}))
+	defer srv.Close()
+
+	desc(t, 1, "Router with a health check and")
+	var dbErr error
+	r := New("prefix", WithHealthCheck("healthz",
+		URLCheck("payments", srv.URL),
+		Check{Name: "db", Run: func(ctx context.Context) error { return dbErr }},
+	))
+	r.Use(func(next lambda.Handler) lambda.Handler {
+		t.Error("middleware used after the health check should not wrap it")
+		return next
+	})
+
+	invoke := func() (events.APIGatewayProxyResponse, map[string]interface{}) {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/prefix/healthz"})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+
+		var body map[string]interface{}
+		a.NoError(json.Unmarshal([]byte(res.Body), &body))
+		return res, body
+	}
+
+	desc(t, 2, "WithHealthCheck option should")
+	{
+		desc(t, 4, "respond with a 200 when every check passes")
+		res, body := invoke()
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("pass", body["status"])
+		a.Exactly("pass", body["checks"].(map[string]interface{})["payments"].(map[string]interface{})["status"])
+
+		desc(t, 4, "respond with a 503 and the failures when a check fails")
+		dbErr = errors.New("connection refused")
+		downstream = http.StatusBadGateway
+		res, body = invoke()
+		a.Exactly(http.StatusServiceUnavailable, res.StatusCode)
+		a.Exactly("fail", body["status"])
+
+		checks := body["checks"].(map[string]interface{})
+		a.Exactly("connection refused", checks["db"].(map[string]interface{})["error"])
+		a.Exactly("fail", checks["payments"].(map[string]interface{})["status"])
+	}
+}
@@ -0,0 +1,96 @@
+package lambdarouter
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// RequestHook is run by a router before it routes each request.
+type RequestHook func(ctx context.Context, req *events.APIGatewayProxyRequest)
+
+// ResponseHook is run by a router after it handles each request. The res parameter is nil when
+// err is not, as the invocation fails without a response.
+type ResponseHook func(ctx context.Context, res *events.APIGatewayProxyResponse, err error)
+
+// lifecycleHooks holds the hooks run around every request a router handles.
+type lifecycleHooks struct {
+	request  []RequestHook
+	response []ResponseHook
+}
+
+// OnRequest adds a hook which runs before every request is routed, whether or not it matches a
+// route, for concerns which apply to the router as a whole rather than to its routes, such as
+// counting requests. Hooks run in the order they were added, and may change the request, which is
+// then routed as changed. Requests of the version 2.0 payload format are given to hooks once they
+// are translated into the version 1.0 format.
+func (r *Router) OnRequest(fn RequestHook) {
+	if r.table == nil {
+		panic("router not initialized")
+	}
+
+	r.table.mu.Lock()
+	defer r.table.mu.Unlock()
+
+	r.table.hooks.request = append(r.table.hooks.request, fn)
+}
+
+// OnResponse adds a hook which runs after every request is handled, whether or not it matched a
+// route, with the response of the router or the error the invocation fails with, such as to flush
+// buffered logs or record metrics. Hooks run in the order they were added, and may change the
+// response, which is then returned as changed.
+func (r *Router) OnResponse(fn ResponseHook) {
+	if r.table == nil {
+		panic("router not initialized")
+	}
+
+	r.table.mu.Lock()
+	defer r.table.mu.Unlock()
+
+	r.table.hooks.response = append(r.table.hooks.response, fn)
+}
+
+// lifecycleHooks returns the hooks of the router.
+func (r Router) lifecycleHooks() lifecycleHooks {
+	if r.table == nil {
+		return lifecycleHooks{}
+	}
+
+	r.table.mu.RLock()
+	defer r.table.mu.RUnlock()
+
+	return r.table.hooks
+}
+
+// runRequestHooks runs hooks on req, returning it as changed along with its encoding.
+func runRequestHooks(ctx context.Context, hooks []RequestHook, req events.APIGatewayProxyRequest) (events.APIGatewayProxyRequest, []byte, error) {
+	for _, fn := range hooks {
+		fn(ctx, &req)
+	}
+
+	payload, err := CodecFrom(ctx).Marshal(req)
+	return req, payload, err
+}
+
+// runResponseHooks runs hooks on the response encoded in payload, or on err, returning the
+// encoding of the response as changed.
+func runResponseHooks(ctx context.Context, hooks []ResponseHook, payload []byte, err error) ([]byte, error) {
+	if err != nil {
+		for _, fn := range hooks {
+			fn(ctx, nil, err)
+		}
+
+		return nil, err
+	}
+
+	var res events.APIGatewayProxyResponse
+	if err := CodecFrom(ctx).Unmarshal(payload, &res); err != nil {
+		return nil, err
+	}
+
+	for _, fn := range hooks {
+		fn(ctx, &res, nil)
+	}
+
+	return CodecFrom(ctx).Marshal(res)
+}
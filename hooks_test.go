@@ -0,0 +1,80 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifecycleHooks(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with lifecycle hooks and")
+	var calls []string
+	var failure error
+
+	r := New("")
+	r.OnRequest(func(ctx context.Context, req *events.APIGatewayProxyRequest) {
+		calls = append(calls, "request "+req.Path)
+		if req.Path == "/old" {
+			req.Path = "/users"
+		}
+	})
+	r.OnResponse(func(ctx context.Context, res *events.APIGatewayProxyResponse, err error) {
+		if err != nil {
+			failure = err
+			return
+		}
+		calls = append(calls, "response "+res.Body)
+		res.Headers = map[string]string{"X-Hooked": "yes"}
+	})
+	r.Get("users", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: "users"}, nil
+	}))
+	r.Get("broken", lambda.NewHandler(func() error {
+		return errors.New("broken")
+	}))
+
+	invoke := func(path string) (events.APIGatewayProxyResponse, error) {
+		calls = nil
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path})
+
+		var res events.APIGatewayProxyResponse
+		resjson, err := r.Invoke(context.Background(), payload)
+		if err == nil {
+			a.NoError(json.Unmarshal(resjson, &res))
+		}
+		return res, err
+	}
+
+	desc(t, 2, "OnRequest and OnResponse methods should")
+	{
+		desc(t, 4, "run hooks around matched requests")
+		res, err := invoke("/users")
+		a.NoError(err)
+		a.Exactly([]string{"request /users", "response users"}, calls)
+
+		desc(t, 4, "return the response as changed by the hooks")
+		a.Exactly("yes", res.Headers["X-Hooked"])
+
+		desc(t, 4, "route the request as changed by the hooks")
+		res, _ = invoke("/old")
+		a.Exactly("users", res.Body)
+
+		desc(t, 4, "run hooks around requests which match no route")
+		res, _ = invoke("/nothing")
+		a.Exactly(http.StatusNotFound, res.StatusCode)
+		a.Exactly([]string{"request /nothing", "response not found"}, calls)
+
+		desc(t, 4, "give hooks the error the invocation fails with")
+		_, err = invoke("/broken")
+		a.EqualError(err, "broken")
+		a.EqualError(failure, "broken")
+	}
+}
@@ -0,0 +1,143 @@
+package lambdarouter
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"unicode/utf8"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+// WrapHTTP adapts a standard http.Handler into a lambda.Handler, so existing net/http handlers
+// and frameworks can be mounted on individual routes. The proxy request is translated into an
+// *http.Request, and whatever the handler writes is captured and returned as the proxy response.
+func WrapHTTP(h http.Handler) lambda.Handler {
+	return httpHandler{h: h}
+}
+
+type httpHandler struct {
+	h http.Handler
+}
+
+func (hh httpHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := httpRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &responseWriter{header: http.Header{}}
+	hh.h.ServeHTTP(w, httpReq)
+
+	return CodecFrom(ctx).Marshal(w.response())
+}
+
+func httpRequest(ctx context.Context, req events.APIGatewayProxyRequest) (*http.Request, error) {
+	body := []byte(req.Body)
+	if req.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = decoded
+	}
+
+	query := url.Values{}
+	for name, value := range req.QueryStringParameters {
+		query.Set(name, value)
+	}
+	for name, values := range req.MultiValueQueryStringParameters {
+		query[name] = values
+	}
+
+	u := url.URL{Path: req.Path, RawQuery: query.Encode()}
+
+	httpReq, err := http.NewRequest(req.HTTPMethod, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range req.Headers {
+		httpReq.Header.Set(name, value)
+	}
+	for name, values := range req.MultiValueHeaders {
+		httpReq.Header[http.CanonicalHeaderKey(name)] = values
+	}
+
+	httpReq.Host = httpReq.Header.Get("Host")
+	httpReq.RemoteAddr = req.RequestContext.Identity.SourceIP
+	httpReq.RequestURI = u.RequestURI()
+
+	return httpReq.WithContext(ctx), nil
+}
+
+// responseWriter is a minimal http.ResponseWriter which buffers everything written to it.
+type responseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.body.Write(b)
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *responseWriter) response() events.APIGatewayProxyResponse {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if w.header.Get("Content-Type") == "" && w.body.Len() > 0 {
+		w.header.Set("Content-Type", http.DetectContentType(w.body.Bytes()))
+	}
+
+	res := events.APIGatewayProxyResponse{
+		StatusCode: w.status,
+		Headers:    map[string]string{},
+	}
+
+	// Headers with several values, such as Set-Cookie, can only be expressed through the
+	// multi-value headers of the response.
+	for name, values := range w.header {
+		if len(values) == 1 {
+			res.Headers[name] = values[0]
+			continue
+		}
+
+		if res.MultiValueHeaders == nil {
+			res.MultiValueHeaders = map[string][]string{}
+		}
+		res.MultiValueHeaders[name] = values
+	}
+
+	if utf8.Valid(w.body.Bytes()) {
+		res.Body = w.body.String()
+	} else {
+		res.Body = base64.StdEncoding.EncodeToString(w.body.Bytes())
+		res.IsBase64Encoded = true
+	}
+
+	return res
+}
@@ -0,0 +1,56 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapHTTP(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Wrap an http.Handler and")
+	h := WrapHTTP(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+
+		http.SetCookie(w, &http.Cookie{Name: "a", Value: "1"})
+		http.SetCookie(w, &http.Cookie{Name: "b", Value: "2"})
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(req.Method + " " + req.URL.Path + " " + req.URL.Query().Get("q") + " " +
+			req.Header.Get("X-Thing") + " " + string(body)))
+	}))
+
+	desc(t, 2, "Invoke method should")
+	{
+		desc(t, 4, "translate the proxy request and capture the response")
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod:            http.MethodPut,
+			Path:                  "/things/1",
+			QueryStringParameters: map[string]string{"q": "query"},
+			Headers:               map[string]string{"x-thing": "header"},
+			Body:                  "Ym9keQ==",
+			IsBase64Encoded:       true,
+		})
+
+		resjson, err := h.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+
+		a.Exactly(http.StatusAccepted, res.StatusCode)
+		a.Exactly("PUT /things/1 query header body", res.Body)
+		a.Exactly("text/plain", res.Headers["Content-Type"])
+		a.Exactly([]string{"a=1", "b=2"}, res.MultiValueHeaders["Set-Cookie"])
+
+		desc(t, 4, "return an error when the there is an issue with the incoming event")
+		_, err = h.Invoke(context.Background(), nil)
+		a.Error(err)
+	}
+}
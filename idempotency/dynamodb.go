@@ -0,0 +1,75 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/mitchell/lambdarouter/internal/dynamo"
+)
+
+// DynamoDBStore is a Store which holds records in a DynamoDB table, so that retries are
+// recognised by every container of a function. The table must have a string partition key named
+// pk. Each item has a ttl attribute holding the time it expires, after which it can be deleted by
+// enabling time to live on the table; expired items are ignored either way.
+type DynamoDBStore struct {
+	table  string
+	client *dynamo.Client
+}
+
+// NewDynamoDBStore returns a store holding records in table. Requests to DynamoDB are signed with
+// the credentials of the execution role of the function, in the region it runs in.
+func NewDynamoDBStore(table string) *DynamoDBStore {
+	return &DynamoDBStore{table: table, client: dynamo.FromEnv()}
+}
+
+// Begin implements the Store interface for the DynamoDBStore type. The key is claimed with a
+// conditional write, so only one of several concurrent requests acquires it.
+func (s *DynamoDBStore) Begin(ctx context.Context, key, fingerprint string, ttl time.Duration) (Record, bool, error) {
+	now := time.Now()
+
+	err := s.client.PutItem(ctx, s.table, dynamo.Item{
+		"pk":          dynamo.S(key),
+		"fingerprint": dynamo.S(fingerprint),
+		"ttl":         dynamo.N(now.Add(ttl).Unix()),
+	}, "attribute_not_exists(pk) OR #ttl < :now", map[string]string{"#ttl": "ttl"}, dynamo.Item{
+		":now": dynamo.N(now.Unix()),
+	})
+	if err == nil {
+		return Record{}, true, nil
+	}
+	if !dynamo.IsConditionFailed(err) {
+		return Record{}, false, err
+	}
+
+	item, err := s.client.GetItem(ctx, s.table, dynamo.Item{"pk": dynamo.S(key)})
+	if err != nil {
+		return Record{}, false, err
+	}
+
+	rec := Record{Fingerprint: item["fingerprint"].String()}
+	if v, ok := item["response"]; ok {
+		rec.Response = v.B
+	}
+
+	return rec, false, nil
+}
+
+// Complete implements the Store interface for the DynamoDBStore type.
+func (s *DynamoDBStore) Complete(ctx context.Context, key string, response []byte, ttl time.Duration) error {
+	item, err := s.client.GetItem(ctx, s.table, dynamo.Item{"pk": dynamo.S(key)})
+	if err != nil {
+		return err
+	}
+
+	return s.client.PutItem(ctx, s.table, dynamo.Item{
+		"pk":          dynamo.S(key),
+		"fingerprint": item["fingerprint"],
+		"response":    dynamo.B(response),
+		"ttl":         dynamo.N(time.Now().Add(ttl).Unix()),
+	}, "", nil, nil)
+}
+
+// Release implements the Store interface for the DynamoDBStore type.
+func (s *DynamoDBStore) Release(ctx context.Context, key string) error {
+	return s.client.DeleteItem(ctx, s.table, dynamo.Item{"pk": dynamo.S(key)})
+}
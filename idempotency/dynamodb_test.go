@@ -0,0 +1,54 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mitchell/lambdarouter/internal/dynamo"
+	"github.com/mitchell/lambdarouter/internal/dynamotest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamoDBStore(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a fake DynamoDB and")
+	srv := dynamotest.NewServer()
+	defer srv.Close()
+
+	s := NewDynamoDBStore("keys")
+	s.client = srv.Client()
+	ctx := context.Background()
+
+	desc(t, 2, "DynamoDBStore type should")
+	{
+		desc(t, 4, "claim keys once")
+		_, acquired, err := s.Begin(ctx, "POST /charges k1", "f", time.Minute)
+		a.NoError(err)
+		a.True(acquired)
+
+		rec, acquired, err := s.Begin(ctx, "POST /charges k1", "f", time.Minute)
+		a.NoError(err)
+		a.False(acquired)
+		a.Exactly(Record{Fingerprint: "f"}, rec)
+
+		desc(t, 4, "return the stored response of completed keys")
+		a.NoError(s.Complete(ctx, "POST /charges k1", []byte(`{"statusCode": 201}`), time.Hour))
+		rec, _, err = s.Begin(ctx, "POST /charges k1", "f", time.Minute)
+		a.NoError(err)
+		a.Exactly(`{"statusCode": 201}`, string(rec.Response))
+
+		desc(t, 4, "claim released and expired keys again")
+		a.NoError(s.Release(ctx, "POST /charges k1"))
+		_, acquired, _ = s.Begin(ctx, "POST /charges k1", "f", time.Minute)
+		a.True(acquired)
+
+		_ = s.client.PutItem(ctx, "keys", dynamo.Item{
+			"pk":  dynamo.S("POST /charges k2"),
+			"ttl": dynamo.N(time.Now().Add(-time.Minute).Unix()),
+		}, "", nil, nil)
+		_, acquired, _ = s.Begin(ctx, "POST /charges k2", "f", time.Minute)
+		a.True(acquired)
+	}
+}
@@ -0,0 +1,235 @@
+// Package idempotency provides middleware implementing the Idempotency-Key pattern: the first
+// response to a request carrying a key is stored, and replayed for retries of the request with the
+// same key, so clients can safely retry requests which create or charge something.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/mitchell/lambdarouter/internal/header"
+)
+
+// Record is the state of a key in a Store.
+type Record struct {
+	// Fingerprint identifies the request which claimed the key, so the key cannot be reused for a
+	// different request.
+	Fingerprint string
+
+	// Response is the response to the request, or nil while the request is in progress.
+	Response []byte
+}
+
+// Store holds the records of idempotency keys.
+type Store interface {
+	// Begin claims key for the request with the given fingerprint, for ttl. If the key is already
+	// claimed and has not expired, its record is returned and acquired is false.
+	Begin(ctx context.Context, key, fingerprint string, ttl time.Duration) (rec Record, acquired bool, err error)
+
+	// Complete stores the response to the request which claimed key, for ttl.
+	Complete(ctx context.Context, key string, response []byte, ttl time.Duration) error
+
+	// Release gives up the claim on key, so the request may be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// MemoryStore is a Store which holds records in the memory of the container. As retries may be
+// handled by other containers, it is only suitable for testing and functions with a reserved
+// concurrency of one.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]memoryRecord
+}
+
+type memoryRecord struct {
+	Record
+	expires time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: map[string]memoryRecord{}}
+}
+
+// Begin implements the Store interface for the MemoryStore type.
+func (s *MemoryStore) Begin(_ context.Context, key, fingerprint string, ttl time.Duration) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, rec := range s.records {
+		if now.After(rec.expires) {
+			delete(s.records, k)
+		}
+	}
+
+	if rec, ok := s.records[key]; ok {
+		return rec.Record, false, nil
+	}
+
+	s.records[key] = memoryRecord{Record: Record{Fingerprint: fingerprint}, expires: now.Add(ttl)}
+
+	return Record{}, true, nil
+}
+
+// Complete implements the Store interface for the MemoryStore type.
+func (s *MemoryStore) Complete(_ context.Context, key string, response []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec := s.records[key]
+	rec.Response, rec.expires = response, time.Now().Add(ttl)
+	s.records[key] = rec
+
+	return nil
+}
+
+// Release implements the Store interface for the MemoryStore type.
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+
+	return nil
+}
+
+// lockTimeout is how long a key is claimed for while its request is handled, which is the longest
+// a Lambda function can run. Keys claimed by invocations which time out or crash can then be
+// retried once it passes.
+const lockTimeout = 15 * time.Minute
+
+// Config configures the idempotency middleware.
+type Config struct {
+	// Store holds the records of keys.
+	Store Store
+
+	// TTL is how long responses are kept for replay. If zero, it is 24 hours.
+	TTL time.Duration
+
+	// Header is the name of the header carrying the key. If empty, it is Idempotency-Key.
+	Header string
+
+	// Required makes requests without a key be responded to with a 400, rather than handled
+	// without the protection of a key.
+	Required bool
+}
+
+// Middleware returns middleware which makes requests carrying an idempotency key safe to retry.
+// The first request with a key is handled as usual, and its response stored unless it is a server
+// error or the handler fails. Retries with the same key receive the stored response, with an
+// Idempotent-Replayed header, without invoking the handler. Keys are scoped to the method and path
+// of the request, and a retry whose body differs from that of the first request is responded to
+// with a 422. A retry made while the first request is still being handled is responded to with a
+// 409.
+func Middleware(cfg Config) lambdarouter.Middleware {
+	if cfg.TTL == 0 {
+		cfg.TTL = 24 * time.Hour
+	}
+	if cfg.Header == "" {
+		cfg.Header = "Idempotency-Key"
+	}
+
+	return func(next lambda.Handler) lambda.Handler {
+		return idempotent{cfg: cfg, next: next}
+	}
+}
+
+type idempotent struct {
+	cfg  Config
+	next lambda.Handler
+}
+
+func (i idempotent) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	codec := lambdarouter.CodecFrom(ctx)
+
+	req, err := lambdarouter.RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	key := header.Get(req, i.cfg.Header)
+	if key == "" {
+		if i.cfg.Required {
+			return nil, &lambdarouter.HTTPError{Status: http.StatusBadRequest, Detail: "missing " + i.cfg.Header + " header"}
+		}
+		return i.next.Invoke(ctx, payload)
+	}
+
+	key = req.HTTPMethod + " " + req.Path + " " + key
+	fingerprint := hash(req.Body)
+
+	rec, acquired, err := i.cfg.Store.Begin(ctx, key, fingerprint, lockTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if !acquired {
+		switch {
+		case rec.Fingerprint != fingerprint:
+			return nil, &lambdarouter.HTTPError{
+				Status: http.StatusUnprocessableEntity,
+				Detail: i.cfg.Header + " was used for a different request",
+			}
+		case rec.Response == nil:
+			return nil, &lambdarouter.HTTPError{
+				Status: http.StatusConflict,
+				Detail: "a request with this " + i.cfg.Header + " is in progress",
+			}
+		}
+
+		return replayed(codec, rec.Response)
+	}
+
+	res, err := i.next.Invoke(ctx, payload)
+	if err != nil || serverError(codec, res) {
+		if releaseErr := i.cfg.Store.Release(ctx, key); releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+		return res, err
+	}
+
+	// Handlers such as those created by lambda.NewHandler reuse the buffer of their responses, so
+	// the response is copied before it is stored.
+	stored := append([]byte(nil), res...)
+	if err := i.cfg.Store.Complete(ctx, key, stored, i.cfg.TTL); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// replayed marks a stored response as replayed.
+func replayed(codec lambdarouter.Codec, response []byte) ([]byte, error) {
+	var res events.APIGatewayProxyResponse
+	if err := codec.Unmarshal(response, &res); err != nil {
+		return nil, err
+	}
+
+	if res.Headers == nil {
+		res.Headers = map[string]string{}
+	}
+	res.Headers["Idempotent-Replayed"] = "true"
+
+	return codec.Marshal(res)
+}
+
+func serverError(codec lambdarouter.Codec, response []byte) bool {
+	var res struct {
+		StatusCode int `json:"statusCode"`
+	}
+
+	return codec.Unmarshal(response, &res) != nil || res.StatusCode >= http.StatusInternalServerError
+}
+
+func hash(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,105 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with idempotent routes and")
+	charges, failures := 0, 1
+	r := lambdarouter.New("prefix")
+	r.Use(Middleware(Config{Store: NewMemoryStore()}))
+	r.Post("charges", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		charges++
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusCreated, Body: "charge " + strconv.Itoa(charges)}, nil
+	}))
+	r.Post("flaky", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		if failures > 0 {
+			failures--
+			return events.APIGatewayProxyResponse{}, errors.New("flaky")
+		}
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusCreated, Body: "created"}, nil
+	}))
+
+	invoke := func(path, key, body string) (events.APIGatewayProxyResponse, error) {
+		req := events.APIGatewayProxyRequest{HTTPMethod: http.MethodPost, Path: path, Body: body}
+		if key != "" {
+			req.Headers = map[string]string{"idempotency-key": key}
+		}
+		payload, _ := json.Marshal(req)
+
+		var res events.APIGatewayProxyResponse
+		resjson, err := r.Invoke(context.Background(), payload)
+		if err == nil {
+			a.NoError(json.Unmarshal(resjson, &res))
+		}
+		return res, err
+	}
+
+	desc(t, 2, "Middleware should")
+	{
+		desc(t, 4, "handle the first request with a key")
+		res, err := invoke("/prefix/charges", "k1", "{}")
+		a.NoError(err)
+		a.Exactly("charge 1", res.Body)
+
+		desc(t, 4, "replay the response to retries without invoking the handler")
+		res, err = invoke("/prefix/charges", "k1", "{}")
+		a.NoError(err)
+		a.Exactly(http.StatusCreated, res.StatusCode)
+		a.Exactly("charge 1", res.Body)
+		a.Exactly("true", res.Headers["Idempotent-Replayed"])
+		a.Exactly(1, charges)
+
+		desc(t, 4, "handle requests with other keys or without a key")
+		res, _ = invoke("/prefix/charges", "k2", "{}")
+		a.Exactly("charge 2", res.Body)
+		res, _ = invoke("/prefix/charges", "", "{}")
+		a.Exactly("charge 3", res.Body)
+
+		desc(t, 4, "respond with a 422 when a key is reused for a different body")
+		res, _ = invoke("/prefix/charges", "k1", `{"amount": 2}`)
+		a.Exactly(http.StatusUnprocessableEntity, res.StatusCode)
+
+		desc(t, 4, "allow retries after the handler fails")
+		_, err = invoke("/prefix/flaky", "k1", "{}")
+		a.EqualError(err, "flaky")
+		res, err = invoke("/prefix/flaky", "k1", "{}")
+		a.NoError(err)
+		a.Exactly("created", res.Body)
+	}
+
+	desc(t, 2, "MemoryStore type should")
+	{
+		s := NewMemoryStore()
+		ctx := context.Background()
+
+		desc(t, 4, "report keys in progress")
+		_, acquired, _ := s.Begin(ctx, "k", "f", lockTimeout)
+		a.True(acquired)
+		rec, acquired, _ := s.Begin(ctx, "k", "f", lockTimeout)
+		a.False(acquired)
+		a.Nil(rec.Response)
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
@@ -0,0 +1,243 @@
+// Package dynamo is a minimal client for the DynamoDB JSON API, signed with AWS Signature Version
+// 4. It supports the few item operations the stores of the router need, so that using DynamoDB
+// does not require the AWS SDK.
+package dynamo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitchell/lambdarouter/internal/sigv4"
+)
+
+// Credentials are the AWS credentials requests are signed with.
+type Credentials = sigv4.Credentials
+
+// Client sends requests to the DynamoDB API.
+type Client struct {
+	// Region is the AWS region of the tables.
+	Region string
+
+	// Endpoint is the URL requests are sent to. If empty, the regional endpoint is used.
+	Endpoint string
+
+	// Credentials returns the credentials to sign requests with.
+	Credentials func() Credentials
+
+	// HTTPClient sends the requests. If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// FromEnv returns a client configured by the environment variables Lambda provides to functions:
+// AWS_REGION and the credentials of the execution role. AWS_ENDPOINT_URL_DYNAMODB overrides the
+// endpoint, for DynamoDB Local.
+func FromEnv() *Client {
+	return &Client{
+		Region:      os.Getenv("AWS_REGION"),
+		Endpoint:    os.Getenv("AWS_ENDPOINT_URL_DYNAMODB"),
+		Credentials: sigv4.EnvCredentials,
+	}
+}
+
+// Value is a DynamoDB attribute value. Only strings, numbers, and binary values are supported.
+type Value struct {
+	S *string `json:"S,omitempty"`
+	N *string `json:"N,omitempty"`
+	B []byte  `json:"B,omitempty"`
+}
+
+// Item is a DynamoDB item, keyed by attribute name.
+type Item map[string]Value
+
+// S returns a string value.
+func S(s string) Value {
+	return Value{S: &s}
+}
+
+// N returns a number value.
+func N(n int64) Value {
+	s := strconv.FormatInt(n, 10)
+	return Value{N: &s}
+}
+
+// B returns a binary value.
+func B(b []byte) Value {
+	if b == nil {
+		b = []byte{}
+	}
+	return Value{B: b}
+}
+
+// String returns the value of a string attribute, or an empty string.
+func (v Value) String() string {
+	if v.S == nil {
+		return ""
+	}
+	return *v.S
+}
+
+// Int returns the value of a number attribute, or zero.
+func (v Value) Int() int64 {
+	if v.N == nil {
+		return 0
+	}
+	n, _ := strconv.ParseInt(*v.N, 10, 64)
+	return n
+}
+
+// Error is an error returned by the DynamoDB API.
+type Error struct {
+	Type    string
+	Message string
+}
+
+// Error implements the error interface for the Error type.
+func (e *Error) Error() string {
+	return "dynamodb: " + e.Type + ": " + e.Message
+}
+
+// IsConditionFailed reports whether err is the error returned when the condition of a write is
+// not met.
+func IsConditionFailed(err error) bool {
+	var de *Error
+	return errors.As(err, &de) && de.Type == "ConditionalCheckFailedException"
+}
+
+// GetItem returns the item of table with the given key, or nil if there is none. Reads are
+// strongly consistent.
+func (c *Client) GetItem(ctx context.Context, table string, key Item) (Item, error) {
+	var out struct {
+		Item Item `json:"Item"`
+	}
+	err := c.do(ctx, "GetItem", map[string]interface{}{
+		"TableName":      table,
+		"Key":            key,
+		"ConsistentRead": true,
+	}, &out)
+
+	return out.Item, err
+}
+
+// PutItem writes item to table. If condition is not empty, the write only succeeds if it holds,
+// with the placeholders of names and values substituted.
+func (c *Client) PutItem(ctx context.Context, table string, item Item, condition string, names map[string]string, values Item) error {
+	in := map[string]interface{}{"TableName": table, "Item": item}
+	if condition != "" {
+		in["ConditionExpression"] = condition
+	}
+	if len(names) > 0 {
+		in["ExpressionAttributeNames"] = names
+	}
+	if len(values) > 0 {
+		in["ExpressionAttributeValues"] = values
+	}
+
+	return c.do(ctx, "PutItem", in, nil)
+}
+
+// Scan returns a page of the items of table, starting after the item with the key start, or at
+// the first item if start is nil. The key of the last item of the page is returned if there are
+// more items, and nil otherwise.
+func (c *Client) Scan(ctx context.Context, table string, start Item) ([]Item, Item, error) {
+	in := map[string]interface{}{"TableName": table}
+	if start != nil {
+		in["ExclusiveStartKey"] = start
+	}
+
+	var out struct {
+		Items            []Item `json:"Items"`
+		LastEvaluatedKey Item   `json:"LastEvaluatedKey"`
+	}
+	err := c.do(ctx, "Scan", in, &out)
+
+	return out.Items, out.LastEvaluatedKey, err
+}
+
+// DeleteItem removes the item of table with the given key.
+func (c *Client) DeleteItem(ctx context.Context, table string, key Item) error {
+	return c.do(ctx, "DeleteItem", map[string]interface{}{"TableName": table, "Key": key}, nil)
+}
+
+// DescribeTable returns the status of table, such as ACTIVE.
+func (c *Client) DescribeTable(ctx context.Context, table string) (string, error) {
+	var out struct {
+		Table struct {
+			TableStatus string `json:"TableStatus"`
+		} `json:"Table"`
+	}
+	err := c.do(ctx, "DescribeTable", map[string]interface{}{"TableName": table}, &out)
+
+	return out.Table.TableStatus, err
+}
+
+func (c *Client) do(ctx context.Context, operation string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = "https://dynamodb." + c.Region + ".amazonaws.com"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	req.Header.Set("X-Amz-Target", "DynamoDB_20120810."+operation)
+
+	var creds Credentials
+	if c.Credentials != nil {
+		creds = c.Credentials()
+	}
+	sigv4.Sign(req, body, creds, c.Region, "dynamodb", time.Now().UTC())
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("dynamodb: %w", err)
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("dynamodb: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		var e struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(resBody, &e)
+		if i := strings.LastIndexByte(e.Type, '#'); i >= 0 {
+			e.Type = e.Type[i+1:]
+		}
+		if e.Type == "" {
+			e.Type = res.Status
+		}
+
+		return &Error{Type: e.Type, Message: e.Message}
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.Unmarshal(resBody, out)
+}
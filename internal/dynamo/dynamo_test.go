@@ -0,0 +1,88 @@
+package dynamo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a fake DynamoDB endpoint and")
+	var target string
+	var request map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target = r.Header.Get("X-Amz-Target")
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &request)
+
+		switch target {
+		case "DynamoDB_20120810.GetItem":
+			_, _ = w.Write([]byte(`{"Item": {"pk": {"S": "a"}, "n": {"N": "42"}}}`))
+		case "DynamoDB_20120810.PutItem":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type": "com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException", "message": "failed"}`))
+		case "DynamoDB_20120810.DescribeTable":
+			_, _ = w.Write([]byte(`{"Table": {"TableName": "table", "TableStatus": "ACTIVE"}}`))
+		case "DynamoDB_20120810.Scan":
+			_, _ = w.Write([]byte(`{"Items": [{"pk": {"S": "b"}}], "LastEvaluatedKey": {"pk": {"S": "b"}}}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := &Client{Region: "us-east-1", Endpoint: srv.URL}
+	ctx := context.Background()
+
+	desc(t, 2, "GetItem method should")
+	{
+		desc(t, 4, "decode the item")
+		item, err := c.GetItem(ctx, "table", Item{"pk": S("a")})
+		a.NoError(err)
+		a.Exactly("DynamoDB_20120810.GetItem", target)
+		a.Exactly(true, request["ConsistentRead"])
+		a.Exactly("a", item["pk"].String())
+		a.Exactly(int64(42), item["n"].Int())
+	}
+
+	desc(t, 2, "PutItem method should")
+	{
+		desc(t, 4, "return errors of the API")
+		err := c.PutItem(ctx, "table", Item{"pk": S("a")}, "attribute_not_exists(pk)", nil, nil)
+		a.True(IsConditionFailed(err))
+		a.Exactly("attribute_not_exists(pk)", request["ConditionExpression"])
+	}
+
+	desc(t, 2, "Scan method should")
+	{
+		desc(t, 4, "continue from the start key and return the last key")
+		items, last, err := c.Scan(ctx, "table", Item{"pk": S("a")})
+		a.NoError(err)
+		a.Exactly(map[string]interface{}{"pk": map[string]interface{}{"S": "a"}}, request["ExclusiveStartKey"])
+		a.Len(items, 1)
+		a.Exactly("b", last["pk"].String())
+	}
+
+	desc(t, 2, "DescribeTable method should")
+	{
+		desc(t, 4, "return the status of the table")
+		status, err := c.DescribeTable(ctx, "table")
+		a.NoError(err)
+		a.Exactly("table", request["TableName"])
+		a.Exactly("ACTIVE", status)
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
@@ -0,0 +1,164 @@
+// Package dynamotest provides an in-memory fake of the DynamoDB API for tests of the stores backed
+// by DynamoDB.
+package dynamotest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mitchell/lambdarouter/internal/dynamo"
+)
+
+// Server is a fake DynamoDB endpoint holding items in memory. Items are keyed by their pk
+// attribute. Condition expressions made of terms of the form attribute_not_exists(name) and
+// name = :value or name < :value joined by OR are supported.
+type Server struct {
+	*httptest.Server
+
+	// ScanLimit is the most items a page of a scan holds. If zero, pages are unlimited.
+	ScanLimit int
+
+	mu     sync.Mutex
+	tables map[string]map[string]dynamo.Item
+}
+
+// NewServer starts a fake DynamoDB endpoint. It should be closed when the test is done.
+func NewServer() *Server {
+	s := &Server{tables: map[string]map[string]dynamo.Item{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+
+	return s
+}
+
+// Client returns a client of the server.
+func (s *Server) Client() *dynamo.Client {
+	return &dynamo.Client{Region: "local", Endpoint: s.URL}
+}
+
+// Items returns the items of table.
+func (s *Server) Items(table string) map[string]dynamo.Item {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := map[string]dynamo.Item{}
+	for pk, item := range s.tables[table] {
+		items[pk] = item
+	}
+
+	return items
+}
+
+type request struct {
+	TableName                 string            `json:"TableName"`
+	Key                       dynamo.Item       `json:"Key"`
+	Item                      dynamo.Item       `json:"Item"`
+	ExclusiveStartKey         dynamo.Item       `json:"ExclusiveStartKey"`
+	ConditionExpression       string            `json:"ConditionExpression"`
+	ExpressionAttributeNames  map[string]string `json:"ExpressionAttributeNames"`
+	ExpressionAttributeValues dynamo.Item       `json:"ExpressionAttributeValues"`
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	table := s.tables[req.TableName]
+	if table == nil {
+		table = map[string]dynamo.Item{}
+		s.tables[req.TableName] = table
+	}
+
+	switch strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "DynamoDB_20120810.") {
+	case "GetItem":
+		out := map[string]interface{}{}
+		if item, ok := table[req.Key["pk"].String()]; ok {
+			out["Item"] = item
+		}
+		_ = json.NewEncoder(w).Encode(out)
+
+	case "PutItem":
+		pk := req.Item["pk"].String()
+		if req.ConditionExpression != "" && !req.holds(table[pk]) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type": "com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException", "message": "The conditional request failed"}`))
+			return
+		}
+		table[pk] = req.Item
+		_, _ = w.Write([]byte(`{}`))
+
+	case "Scan":
+		pks := make([]string, 0, len(table))
+		for pk := range table {
+			if req.ExclusiveStartKey == nil || pk > req.ExclusiveStartKey["pk"].String() {
+				pks = append(pks, pk)
+			}
+		}
+		sort.Strings(pks)
+
+		out := map[string]interface{}{}
+		if s.ScanLimit > 0 && len(pks) > s.ScanLimit {
+			pks = pks[:s.ScanLimit]
+			out["LastEvaluatedKey"] = dynamo.Item{"pk": dynamo.S(pks[len(pks)-1])}
+		}
+
+		items := make([]dynamo.Item, len(pks))
+		for i, pk := range pks {
+			items[i] = table[pk]
+		}
+		out["Items"] = items
+		_ = json.NewEncoder(w).Encode(out)
+
+	case "DeleteItem":
+		delete(table, req.Key["pk"].String())
+		_, _ = w.Write([]byte(`{}`))
+
+	default:
+		http.Error(w, "unsupported operation", http.StatusBadRequest)
+	}
+}
+
+// holds evaluates the condition expression of the request against item, which is nil if absent.
+func (req request) holds(item dynamo.Item) bool {
+	name := func(n string) string {
+		if actual, ok := req.ExpressionAttributeNames[n]; ok {
+			return actual
+		}
+		return n
+	}
+
+	for _, term := range strings.Split(req.ConditionExpression, " OR ") {
+		term = strings.TrimSpace(term)
+
+		if strings.HasPrefix(term, "attribute_not_exists(") {
+			attr := name(strings.TrimSuffix(strings.TrimPrefix(term, "attribute_not_exists("), ")"))
+			if _, ok := item[attr]; !ok {
+				return true
+			}
+			continue
+		}
+
+		if parts := strings.SplitN(term, " = ", 2); len(parts) == 2 {
+			if v, ok := item[name(parts[0])]; ok && reflect.DeepEqual(v, req.ExpressionAttributeValues[parts[1]]) {
+				return true
+			}
+		}
+		if parts := strings.SplitN(term, " < ", 2); len(parts) == 2 {
+			if v, ok := item[name(parts[0])]; ok && v.Int() < req.ExpressionAttributeValues[parts[1]].Int() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,41 @@
+// Package header reads the headers of the proxy requests and responses of API Gateway, whose
+// names keep whatever case the client or handler gave them, for the router's packages.
+package header
+
+import (
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Get returns the first value of the named header of req, regardless of the case of its name.
+func Get(req events.APIGatewayProxyRequest, name string) string {
+	for key, values := range req.MultiValueHeaders {
+		if strings.EqualFold(key, name) && len(values) > 0 {
+			return values[0]
+		}
+	}
+	for key, value := range req.Headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// FromResponse returns the value of the named header of res, regardless of the case of its name.
+func FromResponse(res events.APIGatewayProxyResponse, name string) string {
+	for key, value := range res.Headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	for key, values := range res.MultiValueHeaders {
+		if strings.EqualFold(key, name) && len(values) > 0 {
+			return values[0]
+		}
+	}
+
+	return ""
+}
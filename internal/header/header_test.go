@@ -0,0 +1,49 @@
+package header
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeader(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Get function should")
+	{
+		desc(t, 2, "ignore the case of header names")
+		req := events.APIGatewayProxyRequest{Headers: map[string]string{"x-request-id": "42"}}
+		a.Exactly("42", Get(req, "X-Request-Id"))
+
+		desc(t, 2, "prefer the first of multiple values")
+		req.MultiValueHeaders = map[string][]string{"X-REQUEST-ID": {"1", "2"}}
+		a.Exactly("1", Get(req, "X-Request-Id"))
+
+		desc(t, 2, "return the empty string for missing headers")
+		a.Exactly("", Get(req, "Accept"))
+	}
+
+	desc(t, 0, "FromResponse function should")
+	{
+		desc(t, 2, "ignore the case of header names")
+		res := events.APIGatewayProxyResponse{Headers: map[string]string{"etag": `"a"`}}
+		a.Exactly(`"a"`, FromResponse(res, "ETag"))
+
+		desc(t, 2, "read multiple values")
+		res = events.APIGatewayProxyResponse{MultiValueHeaders: map[string][]string{"Vary": {"Accept", "Origin"}}}
+		a.Exactly("Accept", FromResponse(res, "vary"))
+
+		desc(t, 2, "return the empty string for missing headers")
+		a.Exactly("", FromResponse(res, "ETag"))
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
@@ -0,0 +1,37 @@
+// Package jsoncodec carries the JSON codec of a lambdarouter.Router in the context of the
+// invocations it routes, so packages which the router itself imports, such as respond, can encode
+// with it without importing the router.
+package jsoncodec
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Codec encodes and decodes JSON, as lambdarouter.Codec does.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx which carries c.
+func NewContext(ctx context.Context, c Codec) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext returns the codec carried by ctx, if any.
+func FromContext(ctx context.Context) (Codec, bool) {
+	c, ok := ctx.Value(contextKey{}).(Codec)
+	return c, ok
+}
+
+// Marshal encodes v with the codec carried by ctx, or with encoding/json if there is none.
+func Marshal(ctx context.Context, v interface{}) ([]byte, error) {
+	if c, ok := FromContext(ctx); ok {
+		return c.Marshal(v)
+	}
+
+	return json.Marshal(v)
+}
@@ -0,0 +1,76 @@
+// Package pathprefix strips the prefix of a lambdarouter.Router from the paths of requests before
+// they reach an imported http.Handler. Unlike http.StripPrefix, it understands prefixes holding
+// path parameters, such as those of groups like /orgs/{org}.
+package pathprefix
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Strip returns a handler which removes prefix, a path template whose parameters match any single
+// non-empty segment, from the path of each request and invokes h with the rest. Static segments
+// are compared regardless of case, as the router may match them so. Requests whose path does not
+// start with prefix are responded to with a 404, as by http.StripPrefix.
+func Strip(prefix string, h http.Handler) http.Handler {
+	segs := split(prefix)
+	if len(segs) == 0 {
+		return h
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		p, ok := strip(segs, req.URL.Path)
+		rp, rok := strip(segs, req.URL.RawPath)
+		if !ok || (req.URL.RawPath != "" && !rok) {
+			http.NotFound(w, req)
+			return
+		}
+
+		r2 := new(http.Request)
+		*r2 = *req
+		r2.URL = new(url.URL)
+		*r2.URL = *req.URL
+		r2.URL.Path = p
+		if req.URL.RawPath != "" {
+			r2.URL.RawPath = rp
+		}
+
+		h.ServeHTTP(w, r2)
+	})
+}
+
+// strip removes the segments of a prefix from path, reporting whether path starts with them.
+func strip(prefix []string, path string) (string, bool) {
+	segs := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(segs) < len(prefix) {
+		return "", false
+	}
+
+	for i, seg := range prefix {
+		if isParam(seg) {
+			if segs[i] == "" {
+				return "", false
+			}
+			continue
+		}
+		if !strings.EqualFold(seg, segs[i]) {
+			return "", false
+		}
+	}
+
+	return "/" + strings.Join(segs[len(prefix):], "/"), true
+}
+
+func split(prefix string) []string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return nil
+	}
+
+	return strings.Split(prefix, "/")
+}
+
+func isParam(seg string) bool {
+	return strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}")
+}
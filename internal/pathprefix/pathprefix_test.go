@@ -0,0 +1,58 @@
+package pathprefix
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrip(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a handler recording its path and")
+	var got string
+	h := http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		got = req.URL.Path
+	})
+
+	serve := func(prefix, path string) int {
+		got = ""
+		w := httptest.NewRecorder()
+		Strip(prefix, h).ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code
+	}
+
+	desc(t, 2, "Strip function should")
+	{
+		desc(t, 4, "strip static prefixes")
+		a.Exactly(http.StatusOK, serve("/api", "/api/users/42"))
+		a.Exactly("/users/42", got)
+
+		desc(t, 4, "strip prefixes holding path parameters")
+		a.Exactly(http.StatusOK, serve("/api/orgs/{org}", "/api/orgs/acme/users"))
+		a.Exactly("/users", got)
+
+		desc(t, 4, "compare static segments regardless of case")
+		a.Exactly(http.StatusOK, serve("/api", "/API/users"))
+		a.Exactly("/users", got)
+
+		desc(t, 4, "leave paths unchanged for an empty prefix")
+		a.Exactly(http.StatusOK, serve("/", "/users"))
+		a.Exactly("/users", got)
+
+		desc(t, 4, "respond with a 404 to paths outside the prefix")
+		a.Exactly(http.StatusNotFound, serve("/api/orgs/{org}", "/api/users"))
+		a.Exactly("", got)
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
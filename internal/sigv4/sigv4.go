@@ -0,0 +1,178 @@
+// Package sigv4 signs requests to AWS APIs with AWS Signature Version 4, so that the clients of
+// the router's packages do not require the AWS SDK.
+package sigv4
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Credentials are the AWS credentials requests are signed with.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// EnvCredentials returns the credentials of the execution role, which Lambda provides to functions
+// in environment variables.
+func EnvCredentials() Credentials {
+	return Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// Sign adds an AWS Signature Version 4 Authorization header to req, whose body is body, for the
+// given region and service.
+func Sign(req *http.Request, body []byte, creds Credentials, region, service string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	canonical, signedHeaders := canonicalRequest(req, body, service)
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hexSHA256([]byte(canonical))
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+creds.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+hex.EncodeToString(hmacSHA256(key, toSign)))
+}
+
+// canonicalRequest returns the canonical form of req which is signed, and the names of the headers
+// it signs.
+func canonicalRequest(req *http.Request, body []byte, service string) (string, string) {
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		// The signature of a request signed again is not itself signed.
+		if strings.EqualFold(name, "Authorization") {
+			continue
+		}
+
+		trimmed := make([]string, len(values))
+		for i, value := range values {
+			trimmed[i] = strings.Join(strings.Fields(value), " ")
+		}
+		headers[strings.ToLower(name)] = strings.Join(trimmed, ",")
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	return strings.Join([]string{
+		req.Method,
+		canonicalURI(req, service),
+		canonicalQuery(req.URL.RawQuery),
+		canonicalHeaders.String(),
+		strings.Join(names, ";"),
+		hexSHA256(body),
+	}, "\n"), strings.Join(names, ";")
+}
+
+// canonicalURI returns the canonical form of the path of req. The path of S3 requests is each of
+// its segments encoded once, which is also the path they are sent with. The paths of other
+// services are normalized and encoded a second time, as they are sent, so characters Go leaves
+// unencoded in paths, such as the @ and = of API Gateway connection URLs, are encoded once and
+// those it encodes are encoded twice.
+func canonicalURI(req *http.Request, service string) string {
+	if service == "s3" {
+		p := encodePath(req.URL.Path)
+		req.URL.RawPath = p
+		if p == "" {
+			return "/"
+		}
+		return p
+	}
+
+	p := req.URL.EscapedPath()
+	if p == "" {
+		return "/"
+	}
+
+	clean := path.Clean(p)
+	if strings.HasSuffix(p, "/") && clean != "/" {
+		clean += "/"
+	}
+
+	return encodePath(clean)
+}
+
+// canonicalQuery returns the parameters of query encoded and sorted by name and then value.
+func canonicalQuery(query string) string {
+	values, _ := url.ParseQuery(query)
+
+	params := make([]string, 0, len(values))
+	for name, vs := range values {
+		for _, v := range vs {
+			params = append(params, encode(name)+"="+encode(v))
+		}
+	}
+	sort.Strings(params)
+
+	return strings.Join(params, "&")
+}
+
+// encodePath encodes each segment of p, leaving the slashes between them.
+func encodePath(p string) string {
+	segs := strings.Split(p, "/")
+	for i, seg := range segs {
+		segs[i] = encode(seg)
+	}
+
+	return strings.Join(segs, "/")
+}
+
+// encode percent-encodes every byte of s other than the unreserved characters of RFC 3986, with
+// upper case hexadecimal digits, as Signature Version 4 requires.
+func encode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+
+	return b.String()
+}
+
+func hexSHA256(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
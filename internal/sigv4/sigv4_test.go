@@ -0,0 +1,96 @@
+package sigv4
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	a := assert.New(t)
+
+	creds := Credentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+	sign := func(method, url string) (string, string) {
+		req, _ := http.NewRequest(method, url, nil)
+		Sign(req, nil, creds, "us-east-1", "service", now)
+
+		canonical, _ := canonicalRequest(req, nil, "service")
+		return req.Header.Get("Authorization"), canonical
+	}
+
+	desc(t, 0, "Sign function should")
+	{
+		desc(t, 2, "match the get-vanilla example of the Signature Version 4 test suite")
+		vanilla, _ := sign(http.MethodGet, "https://example.amazonaws.com/")
+		a.Exactly("AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+			"SignedHeaders=host;x-amz-date, "+
+			"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31", vanilla)
+
+		desc(t, 2, "match the examples of the test suite with query parameters and reserved characters")
+		for url, signature := range map[string]string{
+			// get-vanilla-query-order-key-case
+			"/?Param2=value2&Param1=value1": "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+			// get-vanilla-utf8-query
+			"/?ሴ=bar": "2cdec8eed098649ff3a119c94853b13c643bcf08f8b0a1d91e12c9027818dd04",
+			// get-unreserved
+			"/-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz": "07ef7494c76fa4850883e2b006601f940f8a34d404d0cfa977f52a65bbf5f24f",
+			// get-vanilla-query-unreserved
+			"/?-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz=-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz": "9c3e54bfcdf0b19771a7f523ee5669cdf59bc7cc0884027167c21bb143a40197",
+		} {
+			auth, _ := sign(http.MethodGet, "https://example.amazonaws.com"+url)
+			a.True(strings.HasSuffix(auth, "Signature="+signature), url)
+		}
+
+		desc(t, 2, "normalize relative paths, as in the get-relative-relative example")
+		relative, canonical := sign(http.MethodGet, "https://example.amazonaws.com/example1/example2/../..")
+		a.Exactly(vanilla, relative)
+		a.Exactly("GET\n/\n\nhost:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\nhost;x-amz-date\n"+
+			"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", canonical)
+
+		desc(t, 2, "sort query parameters by name and value, as in the get-vanilla-query-order-value example")
+		_, canonical = sign(http.MethodGet, "https://example.amazonaws.com/?Param1=value2&Param1=Value1")
+		a.Contains(canonical, "GET\n/\nParam1=Value1&Param1=value2\n")
+
+		desc(t, 2, "leave unreserved characters unencoded, as in the get-vanilla-query-unreserved example")
+		unreserved := "-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+		_, canonical = sign(http.MethodGet, "https://example.amazonaws.com/"+unreserved+"?"+unreserved+"="+unreserved)
+		a.Contains(canonical, "GET\n/"+unreserved+"\n"+unreserved+"="+unreserved+"\n")
+
+		desc(t, 2, "encode reserved characters of the query, as in the get-vanilla-utf8-query example")
+		_, canonical = sign(http.MethodGet, "https://example.amazonaws.com/?ሴ=bar")
+		a.Contains(canonical, "GET\n/\n%E1%88%B4=bar\n")
+
+		_, canonical = sign(http.MethodGet, "https://example.amazonaws.com/?a=b%2Fc+d&empty=&%40=%3D")
+		a.Contains(canonical, "GET\n/\n%40=%3D&a=b%2Fc%20d&empty=\n")
+
+		desc(t, 2, "encode the reserved characters of API Gateway connection URLs")
+		_, canonical = sign(http.MethodPost, "https://example.amazonaws.com/prod/@connections/L0SM9cOFvHcCIhw=")
+		a.Contains(canonical, "POST\n/prod/%40connections/L0SM9cOFvHcCIhw%3D\n")
+
+		desc(t, 2, "encode the path of services other than S3 twice")
+		_, canonical = sign(http.MethodGet, "https://example.amazonaws.com/example space/ሴ")
+		a.Contains(canonical, "GET\n/example%2520space/%25E1%2588%25B4\n")
+
+		req, _ := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/example space/ሴ", nil)
+		Sign(req, nil, creds, "us-east-1", "s3", now)
+		canonical, _ = canonicalRequest(req, nil, "s3")
+		a.Contains(canonical, "GET\n/example%20space/%E1%88%B4\n")
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
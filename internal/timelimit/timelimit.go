@@ -0,0 +1,40 @@
+// Package timelimit invokes handlers within the time limit of their context, for the router's
+// WithTimeout option and its timeout package.
+package timelimit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+type result struct {
+	res []byte
+	err error
+}
+
+// Invoke invokes h with ctx and payload, returning its result, or timedOut without waiting for h
+// once ctx is done. The handler keeps running until it returns, so it should stop once ctx is
+// cancelled. A panic of h is returned as an error, as it would otherwise crash the function: the
+// router only recovers from the panics of its own goroutine.
+func Invoke(ctx context.Context, h lambda.Handler, payload []byte, timedOut error) ([]byte, error) {
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- result{err: fmt.Errorf("panic: %v", p)}
+			}
+		}()
+
+		res, err := h.Invoke(ctx, payload)
+		done <- result{res: res, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.res, r.err
+	case <-ctx.Done():
+		return nil, timedOut
+	}
+}
@@ -0,0 +1,50 @@
+package timelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvoke(t *testing.T) {
+	a := assert.New(t)
+	timedOut := errors.New("timed out")
+
+	invoke := func(h interface{}) ([]byte, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		return Invoke(ctx, lambda.NewHandler(h), []byte(`{}`), timedOut)
+	}
+
+	desc(t, 0, "Invoke function should")
+	{
+		desc(t, 2, "return the result of handlers which finish in time")
+		res, err := invoke(func() (string, error) { return "done", nil })
+		a.NoError(err)
+		a.Exactly(`"done"`, string(res))
+
+		desc(t, 2, "return the given error without waiting for slow handlers")
+		release := make(chan struct{})
+		_, err = invoke(func() { <-release })
+		a.Exactly(timedOut, err)
+		close(release)
+
+		desc(t, 2, "return the panics of handlers as errors")
+		_, err = invoke(func() { panic("broken") })
+		a.EqualError(err, "panic: broken")
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
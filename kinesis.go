@@ -0,0 +1,108 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+type kinesisRoute struct {
+	stream       string
+	partitionKey string
+	h            lambda.Handler
+}
+
+// Kinesis adds a route for the records of Kinesis data streams. The stream parameter is a pattern
+// matched against the name of the stream, taken from its ARN, and the partitionKey parameter one
+// matched against the partition key of a record. In both * matches any run of characters, and an
+// empty pattern matches anything. The handler parameter is invoked once for each record, which
+// decodes into an events.KinesisEventRecord whose Data holds the record's data already decoded
+// from base64, and is wrapped by the middleware of the router, which should not assume it handles
+// HTTP requests. KinesisHandlerOf creates handlers which receive the data decoded from JSON.
+//
+// Records are routed to the first route which matches them in the order they were defined. As the
+// records of a shard must be handled in order, the first record whose handler fails or which
+// matches no route is reported as a batch item failure and the rest of the batch is left unhandled,
+// so the stream is retried from that record. This requires the event source mapping to have
+// ReportBatchItemFailures enabled.
+func (r *Router) Kinesis(stream, partitionKey string, handler lambda.Handler) {
+	rt := kinesisRoute{stream: stream, partitionKey: partitionKey, h: r.sourceHandler(handler)}
+
+	r.addSource(func(s *sourceRoutes) {
+		s.kinesis = append(s.kinesis[:len(s.kinesis):len(s.kinesis)], rt)
+	})
+}
+
+// KinesisHandlerOf returns a lambda.Handler for Kinesis routes which decodes the data of a record
+// from JSON into a T, and invokes fn with it along with the record. Data which cannot be decoded
+// fails the record.
+func KinesisHandlerOf[T any](fn func(ctx context.Context, record events.KinesisEventRecord, data T) error) lambda.Handler {
+	return kinesisHandler[T]{fn: fn}
+}
+
+type kinesisHandler[T any] struct {
+	fn func(ctx context.Context, record events.KinesisEventRecord, data T) error
+}
+
+func (kh kinesisHandler[T]) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	codec := CodecFrom(ctx)
+
+	var record events.KinesisEventRecord
+	if err := codec.Unmarshal(payload, &record); err != nil {
+		return nil, err
+	}
+
+	var data T
+	if err := codec.Unmarshal(record.Kinesis.Data, &data); err != nil {
+		return nil, fmt.Errorf("decoding data of %s: %w", record.EventID, err)
+	}
+
+	return nil, kh.fn(ctx, record, data)
+}
+
+func (r Router) invokeKinesis(ctx context.Context, routes []kinesisRoute, payload []byte) ([]byte, error) {
+	codec := r.jsonCodec()
+
+	var batch struct {
+		Records []json.RawMessage
+	}
+	if err := codec.Unmarshal(payload, &batch); err != nil {
+		return nil, err
+	}
+
+	res := events.KinesisEventResponse{BatchItemFailures: []events.KinesisBatchItemFailure{}}
+
+	for _, raw := range batch.Records {
+		var record events.KinesisEventRecord
+		if err := codec.Unmarshal(raw, &record); err != nil {
+			return nil, err
+		}
+
+		if err := invokeKinesisRoute(ctx, routes, record, raw); err != nil {
+			r.logf("Kinesis record %s: %v", record.EventID, err)
+			res.BatchItemFailures = append(res.BatchItemFailures, events.KinesisBatchItemFailure{
+				ItemIdentifier: record.Kinesis.SequenceNumber,
+			})
+			break
+		}
+	}
+
+	return codec.Marshal(res)
+}
+
+func invokeKinesisRoute(ctx context.Context, routes []kinesisRoute, record events.KinesisEventRecord, raw []byte) error {
+	_, stream, _ := strings.Cut(record.EventSourceArn, ":stream/")
+
+	for _, rt := range routes {
+		if matchPattern(rt.stream, stream) && matchPattern(rt.partitionKey, record.Kinesis.PartitionKey) {
+			_, err := rt.h.Invoke(ctx, raw)
+			return err
+		}
+	}
+
+	return fmt.Errorf("no route for record of stream %q with partition key %q", stream, record.Kinesis.PartitionKey)
+}
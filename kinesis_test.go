@@ -0,0 +1,73 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKinesis(t *testing.T) {
+	a := assert.New(t)
+
+	type click struct {
+		Page string `json:"page"`
+	}
+
+	desc(t, 0, "Initialize Router with Kinesis routes and")
+	var handled []string
+	r := New("prefix")
+	r.Kinesis("clicks", "user-*", KinesisHandlerOf(func(ctx context.Context, record events.KinesisEventRecord, c click) error {
+		if c.Page == "" {
+			return errors.New("missing page")
+		}
+		handled = append(handled, record.Kinesis.PartitionKey+" "+c.Page)
+		return nil
+	}))
+	r.Kinesis("clicks", "", lambda.NewHandler(func(record events.KinesisEventRecord) error {
+		handled = append(handled, "raw "+string(record.Kinesis.Data))
+		return nil
+	}))
+
+	invoke := func(payload string) events.KinesisEventResponse {
+		handled = nil
+		resjson, err := r.Invoke(context.Background(), []byte(payload))
+		a.NoError(err)
+
+		var res events.KinesisEventResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+	record := func(seq, stream, key, data string) string {
+		b, _ := json.Marshal(events.KinesisEventRecord{
+			EventSource:    "aws:kinesis",
+			EventSourceArn: "arn:aws:kinesis:us-east-1:123456789012:stream/" + stream,
+			Kinesis:        events.KinesisRecord{SequenceNumber: seq, PartitionKey: key, Data: []byte(data)},
+		})
+		return string(b)
+	}
+
+	desc(t, 2, "Kinesis method should")
+	{
+		desc(t, 4, "route records by stream and partition key, decoding their data")
+		res := invoke(`{"Records": [` +
+			record("1", "clicks", "user-1", `{"page": "/home"}`) + `,` +
+			record("2", "clicks", "anonymous", "plain") + `]}`)
+		a.Empty(res.BatchItemFailures)
+		a.Exactly([]string{"user-1 /home", "raw plain"}, handled)
+
+		desc(t, 4, "report the first record which fails and leave the rest")
+		res = invoke(`{"Records": [` +
+			record("1", "clicks", "user-1", `{}`) + `,` +
+			record("2", "clicks", "user-2", `{"page": "/about"}`) + `]}`)
+		a.Exactly([]events.KinesisBatchItemFailure{{ItemIdentifier: "1"}}, res.BatchItemFailures)
+		a.Empty(handled)
+
+		res = invoke(`{"Records": [` + record("1", "orders", "user-1", `{}`) + `]}`)
+		a.Exactly([]events.KinesisBatchItemFailure{{ItemIdentifier: "1"}}, res.BatchItemFailures)
+	}
+}
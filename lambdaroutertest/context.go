@@ -0,0 +1,127 @@
+package lambdaroutertest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// The values of the requestContext of requests, unless they are changed.
+const (
+	AccountID = "123456789012"
+	APIID     = "lambdaroutertest"
+	Stage     = "test"
+	SourceIP  = "192.0.2.1"
+	UserAgent = "lambdaroutertest"
+)
+
+func newRequestContext(method string) events.APIGatewayProxyRequestContext {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	return events.APIGatewayProxyRequestContext{
+		AccountID:        AccountID,
+		APIID:            APIID,
+		Stage:            Stage,
+		RequestID:        hex.EncodeToString(id),
+		HTTPMethod:       method,
+		Protocol:         "HTTP/1.1",
+		RequestTime:      time.Now().UTC().Format("02/Jan/2006:15:04:05 -0700"),
+		RequestTimeEpoch: time.Now().UnixNano() / int64(time.Millisecond),
+		DomainName:       APIID + ".execute-api.us-east-1.amazonaws.com",
+		Identity: events.APIGatewayRequestIdentity{
+			SourceIP:  SourceIP,
+			UserAgent: UserAgent,
+		},
+	}
+}
+
+// WithStage sets the stage the request was made to, which is also the first segment of its
+// requestContext path.
+func (r *Request) WithStage(stage string) *Request {
+	r.event.RequestContext.Stage = stage
+	r.event.RequestContext.Path = "/" + stage + r.event.Path
+
+	return r
+}
+
+// WithStageVariable sets the named stage variable.
+func (r *Request) WithStageVariable(name, value string) *Request {
+	if r.event.StageVariables == nil {
+		r.event.StageVariables = map[string]string{}
+	}
+
+	r.event.StageVariables[name] = value
+
+	return r
+}
+
+// WithSourceIP sets the address of the client the request came from.
+func (r *Request) WithSourceIP(ip string) *Request {
+	r.event.RequestContext.Identity.SourceIP = ip
+	return r
+}
+
+// WithUserAgent sets the User-Agent header of the request, and the user agent of its identity.
+func (r *Request) WithUserAgent(ua string) *Request {
+	r.event.RequestContext.Identity.UserAgent = ua
+	return r.WithHeader("User-Agent", ua)
+}
+
+// WithIdentity sets the identity of the caller, in place of the source IP and user agent the
+// request has by default.
+func (r *Request) WithIdentity(identity events.APIGatewayRequestIdentity) *Request {
+	r.event.RequestContext.Identity = identity
+	return r
+}
+
+// WithCognitoClaims adds claims verified by a Cognito user pool authorizer, which API Gateway
+// places in requestContext.authorizer.claims. Claims such as sub, email, and cognito:username are
+// strings, as API Gateway passes them.
+//
+//	lambdaroutertest.Get("/orders").WithCognitoClaims(map[string]string{"sub": "42", "email": "a@example.com"})
+func (r *Request) WithCognitoClaims(claims map[string]string) *Request {
+	authorizer := r.authorizer()
+
+	c, ok := authorizer["claims"].(map[string]interface{})
+	if !ok {
+		c = map[string]interface{}{}
+		authorizer["claims"] = c
+	}
+	for name, value := range claims {
+		c[name] = value
+	}
+
+	return r
+}
+
+// WithCognitoGroups sets the cognito:groups claim of a Cognito user pool authorizer, in the
+// bracketed form API Gateway passes it in, such as "[admin users]".
+func (r *Request) WithCognitoGroups(groups ...string) *Request {
+	return r.WithCognitoClaims(map[string]string{"cognito:groups": "[" + strings.Join(groups, " ") + "]"})
+}
+
+// WithAuthorizerContext sets the principal and adds the context returned by a custom Lambda
+// authorizer, which API Gateway places in requestContext.authorizer. Values of the context of a
+// REST API authorizer are passed as strings, so they are given as strings here as well.
+func (r *Request) WithAuthorizerContext(principalID string, context map[string]string) *Request {
+	authorizer := r.authorizer()
+
+	authorizer["principalId"] = principalID
+	for key, value := range context {
+		authorizer[key] = value
+	}
+
+	return r
+}
+
+func (r *Request) authorizer() map[string]interface{} {
+	if r.event.RequestContext.Authorizer == nil {
+		r.event.RequestContext.Authorizer = map[string]interface{}{}
+	}
+
+	return r.event.RequestContext.Authorizer
+}
@@ -0,0 +1,60 @@
+package lambdaroutertest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/mitchell/lambdarouter/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestContext(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a router with a route capturing its request and")
+	var got events.APIGatewayProxyRequest
+	r := lambdarouter.New("prefix")
+	capture := lambda.NewHandler(func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		got = req
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	})
+	r.Get("orders", capture)
+	r.Get("admin", capture, lambdarouter.WithMiddleware(auth.RequireGroup("admin")))
+
+	desc(t, 1, "Requests should")
+	{
+		desc(t, 3, "have the requestContext of an unauthenticated request by default")
+		Get("/prefix/orders").Invoke(t, r)
+		a.Exactly(Stage, got.RequestContext.Stage)
+		a.Exactly("/test/prefix/orders", got.RequestContext.Path)
+		a.Exactly(SourceIP, got.RequestContext.Identity.SourceIP)
+		a.NotEmpty(got.RequestContext.RequestID)
+		a.Empty(got.RequestContext.Authorizer)
+
+		desc(t, 3, "set the stage and identity")
+		Get("/prefix/orders").WithStage("prod").WithSourceIP("203.0.113.7").WithUserAgent("curl").Invoke(t, r)
+		a.Exactly("prod", got.RequestContext.Stage)
+		a.Exactly("/prod/prefix/orders", got.RequestContext.Path)
+		a.Exactly("203.0.113.7", got.RequestContext.Identity.SourceIP)
+		a.Exactly("curl", got.RequestContext.Identity.UserAgent)
+		a.Exactly("curl", got.Headers["User-Agent"])
+
+		desc(t, 3, "hold Cognito claims where the auth package finds them")
+		Get("/prefix/orders").WithCognitoClaims(map[string]string{"sub": "42"}).WithCognitoGroups("admin", "users").Invoke(t, r)
+		claims, ok := auth.CognitoClaimsFrom(got)
+		a.True(ok)
+		a.Exactly("42", claims.String("sub"))
+		a.Exactly([]string{"admin", "users"}, claims.Groups())
+
+		desc(t, 3, "pass Cognito groups to middleware")
+		a.Exactly(http.StatusOK, Get("/prefix/admin").WithCognitoGroups("admin").Invoke(t, r).StatusCode)
+		a.Exactly(http.StatusForbidden, Get("/prefix/admin").WithCognitoGroups("users").Invoke(t, r).StatusCode)
+
+		desc(t, 3, "hold the context of a custom authorizer")
+		Get("/prefix/orders").WithAuthorizerContext("user|42", map[string]string{"tenant": "acme"}).Invoke(t, r)
+		a.Exactly(map[string]interface{}{"principalId": "user|42", "tenant": "acme"}, got.RequestContext.Authorizer)
+	}
+}
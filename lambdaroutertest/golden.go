@@ -0,0 +1,145 @@
+package lambdaroutertest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+// UpdateEnv is the environment variable which, when set to true, makes Golden write the responses
+// of its events to their golden files rather than compare them.
+const UpdateEnv = "LAMBDAROUTERTEST_UPDATE"
+
+// Golden replays a directory of recorded events through a handler, such as a router, and compares
+// its responses with golden files, so the behaviour of routes can be locked in before handlers are
+// refactored or the router is upgraded. Each event is a file named name.json, holding the event
+// as API Gateway sent it, whose response is held in name.golden.json beside it.
+//
+//	lambdaroutertest.Golden{Dir: "testdata/contract", IgnoreHeaders: []string{"Date"}}.Run(t, r)
+//
+// Running the test with LAMBDAROUTERTEST_UPDATE=true writes the golden files instead, which should
+// be reviewed before they are committed.
+type Golden struct {
+	// Dir is the directory holding the events and golden files.
+	Dir string
+
+	// IgnoreHeaders are headers left out of the comparison, such as those holding dates or request
+	// IDs, which differ between runs. Their names are matched regardless of case.
+	IgnoreHeaders []string
+
+	// Update makes Run write the golden files instead of comparing them. It is also set by the
+	// LAMBDAROUTERTEST_UPDATE environment variable.
+	Update bool
+}
+
+// Run invokes h with each event of the directory, as a subtest named by its file, and fails the
+// subtest if the response differs from its golden file. A handler returning an error is recorded
+// as {"error": message} in place of a response.
+func (g Golden) Run(t *testing.T, h lambda.Handler) {
+	t.Helper()
+
+	update := g.Update || strings.EqualFold(os.Getenv(UpdateEnv), "true")
+
+	paths, err := filepath.Glob(filepath.Join(g.Dir, "*.json"))
+	if err != nil {
+		t.Fatalf("lambdaroutertest: listing events: %v", err)
+	}
+
+	var replayed int
+	for _, path := range paths {
+		if strings.HasSuffix(path, ".golden.json") {
+			continue
+		}
+		replayed++
+
+		path := path
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		golden := strings.TrimSuffix(path, ".json") + ".golden.json"
+
+		t.Run(name, func(t *testing.T) {
+			event, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("lambdaroutertest: reading event: %v", err)
+			}
+
+			got, err := g.record(h, event)
+			if err != nil {
+				t.Fatalf("lambdaroutertest: decoding response: %v", err)
+			}
+
+			if update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatalf("lambdaroutertest: writing golden file: %v", err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(golden)
+			if os.IsNotExist(err) {
+				t.Fatalf("lambdaroutertest: %s does not exist; run with %s=true to create it", golden, UpdateEnv)
+			}
+			if err != nil {
+				t.Fatalf("lambdaroutertest: reading golden file: %v", err)
+			}
+
+			if !bytes.Equal(bytes.TrimSpace(want), bytes.TrimSpace(got)) {
+				t.Errorf("response differs from %s\nwant:\n%s\ngot:\n%s", golden, want, got)
+			}
+		})
+	}
+
+	if replayed == 0 {
+		t.Fatalf("lambdaroutertest: %s holds no events", g.Dir)
+	}
+}
+
+// record invokes h with event and returns its response, or error, as indented JSON with the
+// ignored headers removed.
+func (g Golden) record(h lambda.Handler, event []byte) ([]byte, error) {
+	payload, err := h.Invoke(context.Background(), event)
+	if err != nil {
+		return marshalGolden(map[string]string{"error": err.Error()})
+	}
+
+	var res events.APIGatewayProxyResponse
+	if err := json.Unmarshal(payload, &res); err != nil {
+		return nil, err
+	}
+
+	for _, name := range g.IgnoreHeaders {
+		for key := range res.Headers {
+			if strings.EqualFold(key, name) {
+				delete(res.Headers, key)
+			}
+		}
+		for key := range res.MultiValueHeaders {
+			if strings.EqualFold(key, name) {
+				delete(res.MultiValueHeaders, key)
+			}
+		}
+	}
+	if len(res.Headers) == 0 {
+		res.Headers = nil
+	}
+	if len(res.MultiValueHeaders) == 0 {
+		res.MultiValueHeaders = nil
+	}
+
+	return marshalGolden(res)
+}
+
+func marshalGolden(v interface{}) ([]byte, error) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
@@ -0,0 +1,49 @@
+package lambdaroutertest
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGolden(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a router and")
+	r := lambdarouter.New("prefix")
+	r.Get("hello/{name}", lambda.NewHandler(func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusOK,
+			Headers:    map[string]string{"Content-Type": "text/plain", "Date": time.Now().Format(http.TimeFormat)},
+			Body:       "hello " + req.PathParameters["name"],
+		}, nil
+	}))
+	r.Post("fail", lambda.NewHandler(func() error { return errors.New("failed") }))
+
+	desc(t, 1, "Golden type should")
+	{
+		desc(t, 3, "match the recorded responses, ignoring the given headers")
+		Golden{Dir: "testdata/golden", IgnoreHeaders: []string{"date"}}.Run(t, r)
+
+		desc(t, 3, "write the golden files when updating")
+		dir := t.TempDir()
+		event, err := os.ReadFile("testdata/golden/hello.json")
+		a.NoError(err)
+		a.NoError(os.WriteFile(filepath.Join(dir, "hello.json"), event, 0o644))
+
+		Golden{Dir: dir, IgnoreHeaders: []string{"Date"}, Update: true}.Run(t, r)
+		got, err := os.ReadFile(filepath.Join(dir, "hello.golden.json"))
+		a.NoError(err)
+		want, err := os.ReadFile("testdata/golden/hello.golden.json")
+		a.NoError(err)
+		a.Exactly(string(want), string(got))
+	}
+}
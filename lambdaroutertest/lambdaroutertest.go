@@ -0,0 +1,223 @@
+// Package lambdaroutertest provides utilities for testing routers and handlers: a builder of API
+// Gateway proxy requests, and a recording of the response they are given, so tests need not
+// marshal proxy events by hand.
+//
+//	res := lambdaroutertest.Get("/prefix/hello/bob").WithHeader("Accept", "application/json").Invoke(t, r)
+//	if res.StatusCode != http.StatusOK { ... }
+package lambdaroutertest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+// Request builds an API Gateway proxy request. Its methods change and return the request, so
+// calls can be chained.
+type Request struct {
+	event events.APIGatewayProxyRequest
+	ctx   context.Context
+	err   error
+}
+
+// NewRequest returns a request with the given method and path, which may include a query string.
+// Its requestContext is filled in as API Gateway would fill it in for an unauthenticated request
+// to the test stage, which the WithStage, WithSourceIP, and authorizer methods change.
+func NewRequest(method, path string) *Request {
+	r := &Request{ctx: context.Background()}
+	r.event.HTTPMethod = method
+	r.event.Path = path
+	r.event.RequestContext = newRequestContext(method)
+
+	if u, err := url.Parse(path); err == nil && u.RawQuery != "" {
+		r.event.Path = u.Path
+		for name, values := range u.Query() {
+			for _, value := range values {
+				r.WithQuery(name, value)
+			}
+		}
+	}
+	r.event.RequestContext.Path = "/" + Stage + r.event.Path
+
+	return r
+}
+
+// Get returns a GET request for path.
+func Get(path string) *Request { return NewRequest(http.MethodGet, path) }
+
+// Post returns a POST request for path.
+func Post(path string) *Request { return NewRequest(http.MethodPost, path) }
+
+// Put returns a PUT request for path.
+func Put(path string) *Request { return NewRequest(http.MethodPut, path) }
+
+// Patch returns a PATCH request for path.
+func Patch(path string) *Request { return NewRequest(http.MethodPatch, path) }
+
+// Delete returns a DELETE request for path.
+func Delete(path string) *Request { return NewRequest(http.MethodDelete, path) }
+
+// WithHeader adds a value of the named header, which is kept in both the Headers and the
+// MultiValueHeaders of the request, as API Gateway does.
+func (r *Request) WithHeader(name, value string) *Request {
+	if r.event.Headers == nil {
+		r.event.Headers = map[string]string{}
+		r.event.MultiValueHeaders = map[string][]string{}
+	}
+
+	r.event.Headers[name] = value
+	r.event.MultiValueHeaders[name] = append(r.event.MultiValueHeaders[name], value)
+
+	return r
+}
+
+// WithQuery adds a value of the named query string parameter.
+func (r *Request) WithQuery(name, value string) *Request {
+	if r.event.QueryStringParameters == nil {
+		r.event.QueryStringParameters = map[string]string{}
+		r.event.MultiValueQueryStringParameters = map[string][]string{}
+	}
+
+	r.event.QueryStringParameters[name] = value
+	r.event.MultiValueQueryStringParameters[name] = append(r.event.MultiValueQueryStringParameters[name], value)
+
+	return r
+}
+
+// WithPathParameter sets the named path parameter, for handlers invoked without a router, which
+// would otherwise set the parameters of the route.
+func (r *Request) WithPathParameter(name, value string) *Request {
+	if r.event.PathParameters == nil {
+		r.event.PathParameters = map[string]string{}
+	}
+
+	r.event.PathParameters[name] = value
+
+	return r
+}
+
+// WithBody sets the body of the request.
+func (r *Request) WithBody(body string) *Request {
+	r.event.Body, r.event.IsBase64Encoded = body, false
+	return r
+}
+
+// WithBinaryBody sets the body of the request to b, base64 encoded as API Gateway encodes binary
+// media types.
+func (r *Request) WithBinaryBody(b []byte) *Request {
+	r.event.Body, r.event.IsBase64Encoded = base64.StdEncoding.EncodeToString(b), true
+	return r
+}
+
+// WithJSONBody sets the body of the request to the JSON encoding of v, and its Content-Type to
+// application/json. An error encoding v fails the test the request is invoked in.
+func (r *Request) WithJSONBody(v interface{}) *Request {
+	b, err := json.Marshal(v)
+	if err != nil {
+		r.err = err
+		return r
+	}
+
+	return r.WithHeader("Content-Type", "application/json").WithBody(string(b))
+}
+
+// WithContext sets the context the request is invoked with, such as one holding a
+// lambdacontext.LambdaContext.
+func (r *Request) WithContext(ctx context.Context) *Request {
+	r.ctx = ctx
+	return r
+}
+
+// Event returns the proxy request which has been built.
+func (r *Request) Event() events.APIGatewayProxyRequest {
+	return r.event
+}
+
+// Invoke invokes h, such as a router, with the request, and records its response. An error
+// building the request fails the test, while errors returned by h are recorded in the response.
+func (r *Request) Invoke(t testing.TB, h lambda.Handler) *Response {
+	t.Helper()
+
+	if r.err != nil {
+		t.Fatalf("lambdaroutertest: building request: %v", r.err)
+	}
+
+	payload, err := json.Marshal(r.event)
+	if err != nil {
+		t.Fatalf("lambdaroutertest: encoding request: %v", err)
+	}
+
+	out, err := h.Invoke(r.ctx, payload)
+	if err != nil {
+		return &Response{Err: err, Header: http.Header{}}
+	}
+
+	res, err := NewResponse(out)
+	if err != nil {
+		t.Fatalf("lambdaroutertest: decoding response: %v", err)
+	}
+
+	return res
+}
+
+// Response is the recording of the response to a request.
+type Response struct {
+	// StatusCode is the status of the response.
+	StatusCode int
+
+	// Header holds both the Headers and the MultiValueHeaders of the response, with canonical
+	// names.
+	Header http.Header
+
+	// Body is the body of the response, decoded if it was base64 encoded.
+	Body []byte
+
+	// Event is the proxy response as the handler returned it.
+	Event events.APIGatewayProxyResponse
+
+	// Err is the error the handler returned, if any, in which case the rest of the response is
+	// empty.
+	Err error
+}
+
+// NewResponse records the encoded proxy response payload.
+func NewResponse(payload []byte) (*Response, error) {
+	var event events.APIGatewayProxyResponse
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+
+	res := &Response{StatusCode: event.StatusCode, Header: http.Header{}, Body: []byte(event.Body), Event: event}
+
+	for name, values := range event.MultiValueHeaders {
+		for _, value := range values {
+			res.Header.Add(name, value)
+		}
+	}
+	for name, value := range event.Headers {
+		if res.Header.Get(name) == "" {
+			res.Header.Set(name, value)
+		}
+	}
+
+	if event.IsBase64Encoded {
+		body, err := base64.StdEncoding.DecodeString(event.Body)
+		if err != nil {
+			return nil, err
+		}
+		res.Body = body
+	}
+
+	return res, nil
+}
+
+// DecodeJSON decodes the body of the response, as JSON, into v.
+func (r *Response) DecodeJSON(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
@@ -0,0 +1,84 @@
+package lambdaroutertest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequest(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a router with a route echoing its request and")
+	var got events.APIGatewayProxyRequest
+	r := lambdarouter.New("prefix")
+	echo := lambda.NewHandler(func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		got = req
+		body, _ := json.Marshal(map[string]string{"name": req.PathParameters["name"], "body": req.Body})
+		return events.APIGatewayProxyResponse{
+			StatusCode:        http.StatusCreated,
+			Headers:           map[string]string{"content-type": "application/json"},
+			MultiValueHeaders: map[string][]string{"Set-Cookie": {"a=1", "b=2"}},
+			Body:              base64.StdEncoding.EncodeToString(body),
+			IsBase64Encoded:   true,
+		}, nil
+	})
+	r.Get("hello/{name}", echo)
+	r.Post("hello/{name}", echo)
+	r.Get("fail", lambda.NewHandler(func() error { return fmt.Errorf("failed") }))
+
+	desc(t, 1, "Request builders should")
+	{
+		desc(t, 3, "build the request with its headers and query")
+		res := Get("/prefix/hello/bob?page=2").WithHeader("Accept", "text/plain").WithQuery("tag", "a").Invoke(t, r)
+		a.NoError(res.Err)
+		a.Exactly(http.MethodGet, got.HTTPMethod)
+		a.Exactly("/prefix/hello/bob", got.Path)
+		a.Exactly("text/plain", got.Headers["Accept"])
+		a.Exactly([]string{"text/plain"}, got.MultiValueHeaders["Accept"])
+		a.Exactly(map[string]string{"page": "2", "tag": "a"}, got.QueryStringParameters)
+
+		desc(t, 3, "encode JSON bodies")
+		res = Post("/prefix/hello/bob").WithJSONBody(map[string]int{"n": 1}).Invoke(t, r)
+		a.NoError(res.Err)
+		a.Exactly(`{"n":1}`, got.Body)
+		a.Exactly("application/json", got.Headers["Content-Type"])
+
+		desc(t, 3, "set path parameters of handlers invoked directly")
+		res = Get("/hello/alice").WithPathParameter("name", "alice").Invoke(t, echo)
+		a.Exactly("alice", got.PathParameters["name"])
+	}
+
+	desc(t, 1, "Response should")
+	{
+		desc(t, 3, "hold the status, headers, and decoded body")
+		res := Get("/prefix/hello/bob").Invoke(t, r)
+		a.Exactly(http.StatusCreated, res.StatusCode)
+		a.Exactly("application/json", res.Header.Get("Content-Type"))
+		a.Exactly([]string{"a=1", "b=2"}, res.Header.Values("Set-Cookie"))
+
+		var body map[string]string
+		a.NoError(res.DecodeJSON(&body))
+		a.Exactly("bob", body["name"])
+
+		desc(t, 3, "hold the errors of handlers")
+		res = Get("/prefix/fail").WithContext(context.Background()).Invoke(t, r)
+		a.EqualError(res.Err, "failed")
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
@@ -0,0 +1,154 @@
+package lambdarouter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// RouteInfo describes a route along with its handler, for listings of the routes of a router.
+type RouteInfo struct {
+	Route
+
+	// Handler names the handler of the route, as given by WithHandlerName, or else the name of
+	// its function or type.
+	Handler string
+}
+
+// WithHandlerName names the handler of a route in the listings of RouteInfos, PrintRoutes, and
+// WithRouteListing. Handlers created by lambda.NewHandler should be named, as their type names
+// nothing of the function they wrap.
+func WithHandlerName(name string) RouteOption {
+	return func(e *event) {
+		e.name = name
+	}
+}
+
+// handlerName returns the name of the handler of e.
+func (e event) handlerName() string {
+	if e.name != "" {
+		return e.name
+	}
+	if e.base == nil {
+		return ""
+	}
+
+	if v := reflect.ValueOf(e.base); v.Kind() == reflect.Func {
+		if fn := runtime.FuncForPC(v.Pointer()); fn != nil {
+			return fn.Name()
+		}
+	}
+
+	return strings.TrimPrefix(fmt.Sprintf("%T", e.base), "*")
+}
+
+// RouteInfos returns every route defined on the router along with its handler, ordered by method
+// and then path.
+func (r Router) RouteInfos() []RouteInfo {
+	if r.table == nil {
+		return nil
+	}
+
+	var infos []RouteInfo
+	for _, e := range r.table.events() {
+		infos = append(infos, RouteInfo{Route: e.rt, Handler: e.handlerName()})
+	}
+
+	return infos
+}
+
+// PrintRoutes writes a table of every route defined on the router to w, with the method,
+// template, handler, and conditions of each, such as for a command line flag which prints the
+// routes a function serves without deploying it.
+func (r Router) PrintRoutes(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "METHOD\tPATH\tHANDLER\tCONDITIONS")
+	for _, info := range r.RouteInfos() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.Method, info.Path, info.Handler, strings.Join(info.Conditions, " "))
+	}
+
+	return tw.Flush()
+}
+
+// String returns the table of routes written by PrintRoutes.
+func (r Router) String() string {
+	var b strings.Builder
+	_ = r.PrintRoutes(&b)
+
+	return b.String()
+}
+
+// routeListingEnv is the environment variable which enables the route listing of routers created
+// WithRouteListing, when it is not guarded by route options.
+const routeListingEnv = "LAMBDAROUTER_ROUTE_LISTING"
+
+// WithRouteListing adds a GET route at __routes, beneath the prefix of the router, which responds
+// with the RouteInfos of the router as JSON, so operators can verify what a deployed function
+// serves. The listing reveals the whole API, so it must be guarded: the opts parameter should
+// guard it with authentication, such as by WithMiddleware. Without opts, the route only responds
+// while the LAMBDAROUTER_ROUTE_LISTING environment variable is true, and with a 404 otherwise.
+//
+// The route is added before any middleware is used, as is that of WithHealthCheck.
+func WithRouteListing(opts ...RouteOption) Option {
+	return func(r *Router) {
+		r.listing = &routeListing{opts: opts}
+	}
+}
+
+type routeListing struct {
+	opts []RouteOption
+}
+
+// routeLister is the handler of the route listing of a router.
+type routeLister struct {
+	r       Router
+	guarded bool
+}
+
+type listedRoute struct {
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Handler    string   `json:"handler"`
+	Conditions []string `json:"conditions,omitempty"`
+}
+
+func (rl routeLister) Invoke(ctx context.Context, _ []byte) ([]byte, error) {
+	if enabled, _ := strconv.ParseBool(os.Getenv(routeListingEnv)); !rl.guarded && !enabled {
+		return nil, &HTTPError{Status: http.StatusNotFound}
+	}
+
+	routes := []listedRoute{}
+	for _, info := range rl.r.RouteInfos() {
+		routes = append(routes, listedRoute{
+			Method:     info.Method,
+			Path:       info.Path,
+			Handler:    info.Handler,
+			Conditions: info.Conditions,
+		})
+	}
+
+	codec := CodecFrom(ctx)
+
+	body, err := codec.Marshal(struct {
+		Routes []listedRoute `json:"routes"`
+	}{routes})
+	if err != nil {
+		return nil, err
+	}
+
+	return codec.Marshal(events.APIGatewayProxyResponse{
+		StatusCode: http.StatusOK,
+		Headers:    map[string]string{"Content-Type": "application/json", "Cache-Control": "no-store"},
+		Body:       string(body),
+	})
+}
@@ -0,0 +1,85 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+type namedHandler struct{}
+
+func (namedHandler) Invoke(context.Context, []byte) ([]byte, error) { return nil, nil }
+
+func TestRouteListing(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with a route listing and")
+	r := New("prefix", WithRouteListing())
+	r.Get("users/{id}", lambda.NewHandler(handler), WithHandlerName("getUser"))
+	r.Post("users", namedHandler{}, WithHost("api.example.com"))
+
+	invoke := func() events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/prefix/__routes"})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "RouteInfos method should")
+	{
+		desc(t, 4, "name handlers by their given name or their type")
+		a.Exactly([]RouteInfo{
+			{Route: Route{Method: "GET", Path: "/prefix/__routes"}, Handler: "lambdarouter.routeLister"},
+			{Route: Route{Method: "GET", Path: "/prefix/users/{id}"}, Handler: "getUser"},
+			{
+				Route:   Route{Method: "POST", Path: "/prefix/users", Conditions: []string{"host=api.example.com"}},
+				Handler: "lambdarouter.namedHandler",
+			},
+		}, r.RouteInfos())
+	}
+
+	desc(t, 2, "String method should")
+	{
+		desc(t, 4, "print the routes as a table")
+		a.Exactly(""+
+			"METHOD  PATH                HANDLER                    CONDITIONS\n"+
+			"GET     /prefix/__routes    lambdarouter.routeLister   \n"+
+			"GET     /prefix/users/{id}  getUser                    \n"+
+			"POST    /prefix/users       lambdarouter.namedHandler  host=api.example.com\n",
+			r.String())
+	}
+
+	desc(t, 2, "WithRouteListing option should")
+	{
+		desc(t, 4, "respond with a 404 unless enabled by the environment")
+		a.Exactly(http.StatusNotFound, invoke().StatusCode)
+
+		desc(t, 4, "list the routes when enabled")
+		t.Setenv("LAMBDAROUTER_ROUTE_LISTING", "true")
+		res := invoke()
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.JSONEq(`{"routes": [
+			{"method": "GET", "path": "/prefix/__routes", "handler": "lambdarouter.routeLister"},
+			{"method": "GET", "path": "/prefix/users/{id}", "handler": "getUser"},
+			{"method": "POST", "path": "/prefix/users", "handler": "lambdarouter.namedHandler", "conditions": ["host=api.example.com"]}
+		]}`, res.Body)
+
+		desc(t, 4, "be guarded by its route options instead of the environment when given")
+		t.Setenv("LAMBDAROUTER_ROUTE_LISTING", "")
+		guarded := New("", WithRouteListing(WithMiddleware(func(lambda.Handler) lambda.Handler {
+			return lambda.NewHandler(func() error { return &HTTPError{Status: http.StatusUnauthorized} })
+		})))
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/__routes"})
+		resjson, err := guarded.Invoke(context.Background(), payload)
+		a.NoError(err)
+		a.Contains(string(resjson), `"statusCode":401`)
+	}
+}
@@ -0,0 +1,266 @@
+package lambdarouter
+
+import (
+	"fmt"
+	"strings"
+
+	iradix "github.com/hashicorp/go-immutable-radix"
+)
+
+// The radix matcher stores routes in its events tree under their method followed by their
+// normalized template, in which every parameter segment is replaced by a marker. Every proper
+// prefix of a normalized template which ends at a segment boundary is stored in the prefixes tree,
+// so lookups can abandon a branch of the search as soon as no route could match it.
+const (
+	paramMarker  = "/{}"
+	greedyMarker = "/{+}"
+)
+
+// lookupBufSize is the size of the key buffer used by lookups. Keys which do not fit in it are
+// still matched, at the cost of an allocation.
+const lookupBufSize = 256
+
+// normalize replaces the parameters of a template with markers, so templates which only differ
+// by the names of their parameters share a key.
+func normalize(template string) string {
+	var b strings.Builder
+
+	for template != "" {
+		var seg string
+		seg, template = nextSegment(template)
+
+		switch {
+		case !isParam(seg):
+			b.WriteString(seg)
+		case strings.HasSuffix(seg, "+}"):
+			b.WriteString(greedyMarker)
+		default:
+			b.WriteString(paramMarker)
+		}
+	}
+
+	if b.Len() == 0 {
+		return "/"
+	}
+
+	return b.String()
+}
+
+// radixMatcher is the default Matcher. Routes are stored in an immutable radix tree under their
+// method followed by their normalized template.
+type radixMatcher struct {
+	events   *iradix.Tree
+	prefixes *iradix.Tree
+}
+
+type radixRoute struct {
+	template string
+	value    interface{}
+}
+
+// NewRadixMatcher returns the Matcher used by routers unless another is configured. It matches
+// static segments in preference to parameters, and parameters in preference to greedy parameters,
+// and does not allocate while matching routes without parameters.
+func NewRadixMatcher() Matcher {
+	return &radixMatcher{events: iradix.New(), prefixes: iradix.New()}
+}
+
+// Insert implements the Matcher interface for the radixMatcher type.
+func (m *radixMatcher) Insert(method, template string, value interface{}) error {
+	key := method + normalize(template)
+
+	if _, exists := m.events.Get([]byte(key)); exists {
+		return fmt.Errorf("a route matching the same paths as '%s' already exists", method+template)
+	}
+
+	m.events, _, _ = m.events.Insert([]byte(key), radixRoute{template: template, value: value})
+	m.prefixes = insertPrefixes(m.prefixes, key)
+
+	return nil
+}
+
+// Lookup implements the Matcher interface for the radixMatcher type.
+func (m *radixMatcher) Lookup(method, path string) (interface{}, map[string]string, bool) {
+	rt, found := m.match(method, path)
+	if !found {
+		return nil, nil, false
+	}
+
+	return rt.value, templateParams(rt.template, path), true
+}
+
+// Remove implements the RemovableMatcher interface for the radixMatcher type. The trees are
+// replaced rather than modified, so lookups in progress are unaffected.
+func (m *radixMatcher) Remove(method, template string) bool {
+	events, _, removed := m.events.Delete([]byte(method + normalize(template)))
+	if !removed {
+		return false
+	}
+
+	prefixes := iradix.New()
+	events.Root().Walk(func(key []byte, _ interface{}) bool {
+		prefixes = insertPrefixes(prefixes, string(key))
+		return false
+	})
+
+	m.events, m.prefixes = events, prefixes
+
+	return true
+}
+
+// insertPrefixes adds every proper prefix of the normalized key which ends at a segment boundary
+// to the prefixes tree.
+func insertPrefixes(prefixes *iradix.Tree, key string) *iradix.Tree {
+	for i := strings.IndexByte(key, '/') + 1; i < len(key); i++ {
+		if key[i] == '/' {
+			prefixes, _, _ = prefixes.Insert([]byte(key[:i]), struct{}{})
+		}
+	}
+
+	return prefixes
+}
+
+// match finds the route which matches method and path. It does not allocate unless the key
+// outgrows the lookup buffer.
+func (m *radixMatcher) match(method, path string) (radixRoute, bool) {
+	return m.matchKeys(method, path, nil)
+}
+
+// candidates returns the keys a lookup of method and path considers, in the order it considers
+// them, with the method separated from the normalized template by a space.
+func (m *radixMatcher) candidates(method, path string) []string {
+	var tried []string
+	m.matchKeys(method, path, &tried)
+
+	for i, key := range tried {
+		tried[i] = key[:len(method)] + " " + key[len(method):]
+	}
+
+	return tried
+}
+
+// matchKeys is match, which records the keys it considers in tried unless it is nil.
+func (m *radixMatcher) matchKeys(method, path string, tried *[]string) (radixRoute, bool) {
+	if path == "" || path[0] != '/' {
+		return radixRoute{}, false
+	}
+
+	var buf [lookupBufSize]byte
+	key := append(buf[:0], method...)
+
+	path = strings.TrimSuffix(path, "/")
+	if path == "" {
+		return m.get(append(key, '/'), tried)
+	}
+
+	return m.search(key, path, tried)
+}
+
+// search matches the remainder of a path against the routes whose keys begin with key.
+func (m *radixMatcher) search(key []byte, path string, tried *[]string) (radixRoute, bool) {
+	seg, rest := nextSegment(path)
+	n := len(key)
+
+	if rt, ok := m.try(append(key, seg...), rest, tried); ok {
+		return rt, true
+	}
+
+	if len(seg) > 1 {
+		if rt, ok := m.try(append(key[:n], paramMarker...), rest, tried); ok {
+			return rt, true
+		}
+		if rt, ok := m.get(append(key[:n], greedyMarker...), tried); ok {
+			return rt, true
+		}
+	}
+
+	return radixRoute{}, false
+}
+
+// try continues a search with key if any route could still match it.
+func (m *radixMatcher) try(key []byte, rest string, tried *[]string) (radixRoute, bool) {
+	if rest == "" {
+		return m.get(key, tried)
+	}
+
+	if tried != nil {
+		*tried = append(*tried, string(key)+"/...")
+	}
+	if _, ok := m.prefixes.Get(key); !ok {
+		return radixRoute{}, false
+	}
+
+	return m.search(key, rest, tried)
+}
+
+func (m *radixMatcher) get(key []byte, tried *[]string) (radixRoute, bool) {
+	if tried != nil {
+		*tried = append(*tried, string(key))
+	}
+
+	i, found := m.events.Get(key)
+	if !found {
+		return radixRoute{}, false
+	}
+
+	return i.(radixRoute), true
+}
+
+// templateParams returns the values of the parameters of template within path, which must match
+// it. It returns nil if the template has no parameters.
+func templateParams(template, path string) map[string]string {
+	var params map[string]string
+
+	for template != "" && path != "" {
+		var tseg, pseg string
+		tseg, template = nextSegment(template)
+		pseg, path = nextSegment(path)
+
+		if !isParam(tseg) {
+			continue
+		}
+		if params == nil {
+			params = map[string]string{}
+		}
+
+		name := tseg[2 : len(tseg)-1]
+		if strings.HasSuffix(name, "+") {
+			params[name[:len(name)-1]] = pseg[1:] + path
+			break
+		}
+
+		params[name] = pseg[1:]
+	}
+
+	return params
+}
+
+// sameParams reports whether two sets of path parameters are equal.
+func sameParams(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for name, value := range a {
+		if other, ok := b[name]; !ok || other != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// nextSegment splits a path into its first segment, including the leading slash, and the rest.
+func nextSegment(path string) (string, string) {
+	i := strings.IndexByte(path[1:], '/')
+	if i < 0 {
+		return path, ""
+	}
+
+	return path[:i+1], path[i+1:]
+}
+
+// isParam reports whether a segment, including its leading slash, is a parameter.
+func isParam(seg string) bool {
+	return len(seg) > 2 && seg[1] == '{' && seg[len(seg)-1] == '}'
+}
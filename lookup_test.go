@@ -0,0 +1,123 @@
+package lambdarouter
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router and")
+	r := New("prefix")
+	handler := lambda.NewHandler(handler)
+
+	r.Get("users/{id}", handler)
+	r.Get("users/me", handler)
+	r.Get("users/{id}/orders/{orderID}", handler)
+	r.Get("files/{path+}", handler)
+	r.Get("files/readme", handler)
+	r.Get("/", handler)
+
+	template := func(method, path string) string {
+		events, _, found := r.lookup(method, path, nil)
+		if !found {
+			return ""
+		}
+		return events[0].rt.Path
+	}
+
+	desc(t, 2, "lookup method should")
+	{
+		desc(t, 4, "match static and parameter segments")
+		a.Exactly("/prefix/users/{id}", template(http.MethodGet, "/prefix/users/42"))
+		a.Exactly("/prefix/users/{id}/orders/{orderID}", template(http.MethodGet, "/prefix/users/42/orders/7"))
+
+		desc(t, 4, "prefer static segments to parameters")
+		a.Exactly("/prefix/users/me", template(http.MethodGet, "/prefix/users/me"))
+		a.Exactly("/prefix/files/readme", template(http.MethodGet, "/prefix/files/readme"))
+
+		desc(t, 4, "match the remainder of the path with greedy parameters")
+		a.Exactly("/prefix/files/{path+}", template(http.MethodGet, "/prefix/files/a/b/c"))
+
+		desc(t, 4, "match the root of the prefix and ignore trailing slashes")
+		a.Exactly("/prefix", template(http.MethodGet, "/prefix/"))
+		a.Exactly("/prefix/users/{id}", template(http.MethodGet, "/prefix/users/42/"))
+
+		desc(t, 4, "not match unknown paths and methods")
+		a.Empty(template(http.MethodGet, "/prefix/users/42/orders"))
+		a.Empty(template(http.MethodGet, "/prefix/users//orders/7"))
+		a.Empty(template(http.MethodPost, "/prefix/users/42"))
+		a.Empty(template(http.MethodGet, "prefix/users/42"))
+	}
+
+	desc(t, 2, "Lookup method of the radix matcher should")
+	{
+		desc(t, 4, "return the values of path parameters")
+		_, params, found := r.table.matcher.Lookup(http.MethodGet, "/prefix/users/42/orders/7")
+		a.True(found)
+		a.Exactly(map[string]string{"id": "42", "orderID": "7"}, params)
+
+		desc(t, 4, "return nil parameters for static routes")
+		_, params, found = r.table.matcher.Lookup(http.MethodGet, "/prefix/users/me")
+		a.True(found)
+		a.Nil(params)
+
+		desc(t, 4, "return an error when inserting a template which only differs by parameter names")
+		a.Error(r.table.matcher.Insert(http.MethodGet, "/prefix/users/{name}", nil))
+	}
+
+	desc(t, 2, "templateParams function should")
+	{
+		desc(t, 4, "extract the values of parameters")
+		a.Exactly(map[string]string{"id": "42", "orderID": "7"},
+			templateParams("/prefix/users/{id}/orders/{orderID}", "/prefix/users/42/orders/7"))
+
+		desc(t, 4, "extract the remainder of the path for greedy parameters")
+		a.Exactly(map[string]string{"path": "a/b/c"}, templateParams("/files/{path+}", "/files/a/b/c"))
+
+		desc(t, 4, "return nil for templates without parameters")
+		a.Nil(templateParams("/users/me", "/users/me"))
+	}
+
+	desc(t, 2, "addEvent method should")
+	{
+		desc(t, 4, "panic when a template only differs by parameter names")
+		a.Panics(func() {
+			r.Get("users/{name}", handler)
+		})
+	}
+}
+
+func BenchmarkLookup(b *testing.B) {
+	m := NewRadixMatcher().(*radixMatcher)
+
+	for _, template := range []string{
+		"/prefix/users",
+		"/prefix/users/{id}",
+		"/prefix/users/{id}/orders/{orderID}",
+		"/prefix/users/{id}/orders/{orderID}/items",
+		"/prefix/files/{path+}",
+	} {
+		m.Insert(http.MethodGet, template, nil)
+	}
+	m.Insert(http.MethodPost, "/prefix/users/{id}/orders", nil)
+
+	for _, bm := range []struct{ name, path string }{
+		{"Static", "/prefix/users"},
+		{"Params", "/prefix/users/42/orders/7"},
+		{"Greedy", "/prefix/files/a/b/c"},
+		{"NotFound", "/prefix/users/42/invoices/7"},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				m.match(http.MethodGet, bm.path)
+			}
+		})
+	}
+}
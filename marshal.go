@@ -0,0 +1,119 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// ResponseMarshaler renders the response of a router, given the proxy response of the handler of
+// the route, or the response the router rendered itself. Setting one on a router enforces the
+// conventions of its responses, such as an envelope around every body or headers every response
+// must have, in one place rather than in every handler.
+type ResponseMarshaler func(ctx context.Context, res events.APIGatewayProxyResponse) (events.APIGatewayProxyResponse, error)
+
+// WithResponseMarshaler sets the marshaler every response of the router is given to, whether it was
+// returned by a handler created with HandlerOf, HandlerFunc, or any other, or rendered by the router
+// itself, such as a redirect, a static file, or an error. The marshaler runs before the default
+// headers of the router are added and the OnResponse hooks run, with a context from which the
+// request can be retrieved with RequestFromContext and the route which matched it, or an empty
+// route if none did, with RouteFromContext. If the marshaler fails the invocation fails with its
+// error.
+func WithResponseMarshaler(m ResponseMarshaler) Option {
+	return func(r *Router) {
+		r.marshaler = m
+	}
+}
+
+// JSONResponse renders v, encoded with the codec of the router, as the body of a response of type
+// application/json, as HandlerOf renders the values its handlers return.
+func JSONResponse(ctx context.Context, status int, v interface{}) (events.APIGatewayProxyResponse, error) {
+	body, err := CodecFrom(ctx).Marshal(v)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: status,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       string(body),
+	}, nil
+}
+
+// Envelope returns a ResponseMarshaler which wraps the JSON body of every response in a JSON object,
+// as its data member, beside the meta member returned by meta, which is omitted if meta is nil or
+// returns nil.
+//
+//	{"data": {"id": "42"}, "meta": {"requestId": "c6af9ac6"}}
+//
+// Responses with a 4xx or 5xx status are left unwrapped, as they are errors rather than data, as
+// are responses without a JSON body, such as redirects and static files.
+func Envelope(meta func(ctx context.Context, status int) interface{}) ResponseMarshaler {
+	return func(ctx context.Context, res events.APIGatewayProxyResponse) (events.APIGatewayProxyResponse, error) {
+		if res.StatusCode >= http.StatusBadRequest || res.Body == "" || res.IsBase64Encoded || !hasJSONBody(res) {
+			return res, nil
+		}
+
+		env := envelope{Data: json.RawMessage(res.Body)}
+		if meta != nil {
+			env.Meta = meta(ctx, res.StatusCode)
+		}
+
+		body, err := CodecFrom(ctx).Marshal(env)
+		if err != nil {
+			return res, err
+		}
+
+		res.Body = string(body)
+		return res, nil
+	}
+}
+
+type envelope struct {
+	Data interface{} `json:"data"`
+	Meta interface{} `json:"meta,omitempty"`
+}
+
+// hasJSONBody reports whether the Content-Type of res is application/json, or another JSON media
+// type such as application/hal+json.
+func hasJSONBody(res events.APIGatewayProxyResponse) bool {
+	var contentType string
+	for name, value := range res.Headers {
+		if strings.EqualFold(name, "Content-Type") {
+			contentType = value
+		}
+	}
+	for name, values := range res.MultiValueHeaders {
+		if strings.EqualFold(name, "Content-Type") && len(values) > 0 {
+			contentType = values[0]
+		}
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// marshalResponse gives the response encoded in payload to the response marshaler of the router.
+func (r Router) marshalResponse(ctx context.Context, payload []byte) ([]byte, error) {
+	codec := r.jsonCodec()
+
+	var res events.APIGatewayProxyResponse
+	if err := codec.Unmarshal(payload, &res); err != nil {
+		return nil, err
+	}
+
+	res, err := r.marshaler(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return codec.Marshal(res)
+}
@@ -0,0 +1,120 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type created struct {
+	ID string `json:"id"`
+}
+
+func (created) StatusCode() int { return http.StatusCreated }
+
+func TestWithResponseMarshaler(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize routers with an envelope, a header, and a failing marshaler and")
+	envelope := New("prefix", WithResponseMarshaler(Envelope(func(ctx context.Context, status int) interface{} {
+		rt, _ := RouteFromContext(ctx)
+		return map[string]string{"route": rt.Path}
+	})))
+	header := New("prefix", WithResponseMarshaler(func(ctx context.Context, res events.APIGatewayProxyResponse) (events.APIGatewayProxyResponse, error) {
+		if res.Headers == nil {
+			res.Headers = map[string]string{}
+		}
+		res.Headers["X-Marshaled"] = "true"
+		return res, nil
+	}))
+	failing := New("prefix", WithResponseMarshaler(func(context.Context, events.APIGatewayProxyResponse) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{}, errors.New("failed")
+	}))
+	plain := New("prefix")
+
+	for _, r := range []*Router{&envelope, &header, &failing, &plain} {
+		r.Post("items", HandlerOf(func(ctx context.Context, in struct{}) (created, error) {
+			return created{ID: "42"}, nil
+		}))
+		r.Get("items", HandlerFunc(func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+			return events.APIGatewayProxyResponse{
+				StatusCode: http.StatusOK,
+				Headers:    map[string]string{"content-type": "application/json; charset=utf-8"},
+				Body:       `[{"id": "42"}]`,
+			}, nil
+		}))
+		r.Redirect("old", "/prefix/items", http.StatusMovedPermanently)
+	}
+
+	invoke := func(r Router, method, path string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: method, Path: path})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 1, "WithResponseMarshaler option should")
+	{
+		desc(t, 3, "give the responses of typed handlers to the marshaler")
+		res := invoke(envelope, http.MethodPost, "/prefix/items")
+		a.Exactly(http.StatusCreated, res.StatusCode)
+		a.Exactly("application/json", res.Headers["Content-Type"])
+		a.JSONEq(`{"data": {"id": "42"}, "meta": {"route": "/prefix/items"}}`, res.Body)
+
+		desc(t, 3, "give the responses of every other handler to the marshaler")
+		res = invoke(envelope, http.MethodGet, "/prefix/items")
+		a.JSONEq(`{"data": [{"id": "42"}], "meta": {"route": "/prefix/items"}}`, res.Body)
+		a.Exactly("true", invoke(header, http.MethodGet, "/prefix/old").Headers["X-Marshaled"])
+
+		desc(t, 3, "give the responses the router renders itself to the marshaler")
+		res = invoke(header, http.MethodGet, "/prefix/missing")
+		a.Exactly(http.StatusNotFound, res.StatusCode)
+		a.Exactly("true", res.Headers["X-Marshaled"])
+
+		desc(t, 3, "fail the invocation when the marshaler fails")
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodPost, Path: "/prefix/items"})
+		_, err := failing.Invoke(context.Background(), payload)
+		a.EqualError(err, "failed")
+
+		desc(t, 3, "render values as plain JSON by default")
+		res = invoke(plain, http.MethodPost, "/prefix/items")
+		a.Exactly(http.StatusCreated, res.StatusCode)
+		a.JSONEq(`{"id": "42"}`, res.Body)
+	}
+
+	desc(t, 1, "Envelope function should")
+	{
+		ctx := context.Background()
+
+		desc(t, 3, "leave the bodies of errors unwrapped")
+		res, err := JSONResponse(ctx, http.StatusConflict, map[string]string{"error": "conflict"})
+		a.NoError(err)
+		res, err = Envelope(nil)(ctx, res)
+		a.NoError(err)
+		a.JSONEq(`{"error": "conflict"}`, res.Body)
+
+		desc(t, 3, "leave bodies which are not JSON unwrapped")
+		res, err = Envelope(nil)(ctx, events.APIGatewayProxyResponse{
+			StatusCode: http.StatusOK,
+			Headers:    map[string]string{"Content-Type": "text/html"},
+			Body:       "<p>hi</p>",
+		})
+		a.NoError(err)
+		a.Exactly("<p>hi</p>", res.Body)
+
+		desc(t, 3, "omit a nil meta")
+		res, err = JSONResponse(ctx, http.StatusOK, []int{1})
+		a.NoError(err)
+		res, err = Envelope(nil)(ctx, res)
+		a.NoError(err)
+		a.JSONEq(`{"data": [1]}`, res.Body)
+	}
+}
@@ -0,0 +1,134 @@
+package lambdarouter
+
+import (
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Matcher stores the routes of a router and finds the route which matches each request. The
+// router uses the matcher returned by NewRadixMatcher unless another is configured with
+// UseMatcher, allowing matchers tuned for particular route shapes to be used without changing the
+// router.
+type Matcher interface {
+	// Insert adds a route to the matcher. The template parameter is the path of the route, in which
+	// parameters are written as {name} and greedy parameters as {name+}. The value parameter is
+	// returned by Lookup when the route matches. It returns an error if the matcher already has a
+	// route for the method which matches the same paths, or cannot represent the template.
+	Insert(method, template string, value interface{}) error
+
+	// Lookup returns the value of the route which matches method and path, along with the values
+	// of its path parameters keyed by name. The params return value should be nil if the route has
+	// no parameters. The found return value reports whether any route matched.
+	Lookup(method, path string) (value interface{}, params map[string]string, found bool)
+}
+
+// RemovableMatcher is implemented by matchers which support removing routes, as required by the
+// Remove and Replace methods of Router.
+type RemovableMatcher interface {
+	Matcher
+
+	// Remove deletes the route with the given method and template, reporting whether it existed.
+	Remove(method, template string) bool
+}
+
+// candidateLister is implemented by matchers which can list the keys a lookup considers, which the
+// router logs in debug mode.
+type candidateLister interface {
+	candidates(method, path string) []string
+}
+
+// UseMatcher replaces the matcher the router stores its routes in. It must be called before any
+// routes are defined, and panics otherwise.
+func (r *Router) UseMatcher(m Matcher) {
+	if r.table == nil {
+		panic("router not initialized")
+	}
+	if err := r.table.setMatcher(r.adaptMatcher(m)); err != nil {
+		panic(err.Error())
+	}
+}
+
+// adaptMatcher adapts a matcher to the options of the router.
+func (r Router) adaptMatcher(m Matcher) Matcher {
+	if r.caseInsensitive {
+		return caseInsensitiveMatcher{m}
+	}
+
+	return m
+}
+
+// caseInsensitiveMatcher adapts a matcher to match the static segments of templates regardless of
+// case. Templates and paths are lower-cased before they reach the underlying matcher, and the
+// values of path parameters are then taken from the original path.
+type caseInsensitiveMatcher struct {
+	m Matcher
+}
+
+type caseInsensitiveRoute struct {
+	template string
+	value    interface{}
+}
+
+// Insert implements the Matcher interface for the caseInsensitiveMatcher type.
+func (ci caseInsensitiveMatcher) Insert(method, template string, value interface{}) error {
+	return ci.m.Insert(method, lowerStatic(template), caseInsensitiveRoute{template: template, value: value})
+}
+
+// Remove implements the RemovableMatcher interface for the caseInsensitiveMatcher type. It fails
+// if the underlying matcher does not support removing routes.
+func (ci caseInsensitiveMatcher) Remove(method, template string) bool {
+	rm, ok := ci.m.(RemovableMatcher)
+
+	return ok && rm.Remove(method, lowerStatic(template))
+}
+
+// Lookup implements the Matcher interface for the caseInsensitiveMatcher type.
+func (ci caseInsensitiveMatcher) Lookup(method, path string) (interface{}, map[string]string, bool) {
+	v, _, found := ci.m.Lookup(method, strings.ToLower(path))
+	if !found {
+		return nil, nil, false
+	}
+
+	rt := v.(caseInsensitiveRoute)
+
+	return rt.value, templateParams(rt.template, path), true
+}
+
+// candidates implements the candidateLister interface for the caseInsensitiveMatcher type, if the
+// underlying matcher does.
+func (ci caseInsensitiveMatcher) candidates(method, path string) []string {
+	if cl, ok := ci.m.(candidateLister); ok {
+		return cl.candidates(method, strings.ToLower(path))
+	}
+
+	return nil
+}
+
+// lowerStatic lower-cases the static segments of a template, leaving its parameters intact.
+func lowerStatic(template string) string {
+	var b strings.Builder
+
+	for template != "" {
+		var seg string
+		seg, template = nextSegment(template)
+
+		if !isParam(seg) {
+			seg = strings.ToLower(seg)
+		}
+		b.WriteString(seg)
+	}
+
+	return b.String()
+}
+
+// lookup finds the events of the routes which match method and path, along with the values of its
+// path parameters. Routes with a priority are only preferred if their predicates match req, unless
+// it is nil.
+func (r Router) lookup(method, path string, req *events.APIGatewayProxyRequest) ([]event, map[string]string, bool) {
+	if r.table == nil {
+		return nil, nil, false
+	}
+
+	return r.table.lookup(method, path, req)
+}
@@ -0,0 +1,73 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+// exactMatcher is a Matcher which only matches paths identical to the template of a route.
+type exactMatcher map[string]interface{}
+
+func (m exactMatcher) Insert(method, template string, value interface{}) error {
+	if strings.Contains(template, "+}") {
+		return errors.New("greedy parameters are not supported")
+	}
+	if _, exists := m[method+template]; exists {
+		return errors.New("route exists")
+	}
+
+	m[method+template] = value
+	return nil
+}
+
+func (m exactMatcher) Lookup(method, path string) (interface{}, map[string]string, bool) {
+	value, found := m[method+path]
+	return value, nil, found
+}
+
+func TestMatcher(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router and")
+	r := New("prefix")
+	r.UseMatcher(exactMatcher{})
+
+	r.Get("users/{id}", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}))
+
+	invoke := func(path string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path})
+		res, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var proxyRes events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(res, &proxyRes))
+		return proxyRes
+	}
+
+	desc(t, 2, "UseMatcher method should")
+	{
+		desc(t, 4, "route requests with the given matcher")
+		a.Exactly(http.StatusOK, invoke("/prefix/users/{id}").StatusCode)
+		a.Exactly(http.StatusNotFound, invoke("/prefix/users/42").StatusCode)
+
+		desc(t, 4, "panic when routes have already been defined")
+		a.Panics(func() {
+			r.UseMatcher(NewRadixMatcher())
+		})
+
+		desc(t, 4, "panic when the matcher rejects a route")
+		a.Panics(func() {
+			r.Get("files/{path+}", lambda.NewHandler(handler))
+		})
+	}
+}
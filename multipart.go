@@ -0,0 +1,147 @@
+package lambdarouter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Form holds the fields and files of a multipart/form-data request body.
+type Form struct {
+	// Fields maps the name of each field to its values, in the order they were sent.
+	Fields map[string][]string
+
+	// Files maps the name of each file field to its files, in the order they were sent.
+	Files map[string][]File
+}
+
+// Value returns the first value of the named field, or an empty string if there is none.
+func (f *Form) Value(name string) string {
+	if values := f.Fields[name]; len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// File returns the first file of the named field. The second return value reports whether there
+// is one.
+func (f *Form) File(name string) (File, bool) {
+	if files := f.Files[name]; len(files) > 0 {
+		return files[0], true
+	}
+
+	return File{}, false
+}
+
+// File is a file uploaded in a multipart/form-data request body.
+type File struct {
+	// Filename is the name the client gave the file, which must not be trusted as a path.
+	Filename string
+
+	// Header holds the headers of the part the file was sent in, such as its Content-Type.
+	Header textproto.MIMEHeader
+
+	// Content holds the bytes of the file.
+	Content []byte
+}
+
+// ContentType returns the media type the client gave the file.
+func (f File) ContentType() string {
+	return f.Header.Get("Content-Type")
+}
+
+// MultipartLimits bounds the parts of a multipart/form-data body ParseMultipart will accept. Zero
+// values leave the corresponding size unlimited, though bodies are always bounded by the 6 MB
+// request limit of Lambda.
+type MultipartLimits struct {
+	// MaxFileSize is the largest size in bytes of any one file.
+	MaxFileSize int64
+
+	// MaxFieldSize is the largest size in bytes of the value of any one field.
+	MaxFieldSize int64
+
+	// MaxParts is the most parts, fields and files together, the body may have.
+	MaxParts int
+}
+
+// ParseMultipart parses the multipart/form-data body of req, which may be base64 encoded, as API
+// Gateway does for binary media types. It returns an *HTTPError with a 415 if req is not a
+// multipart/form-data request, a 400 if its body is malformed, or a 413 if a part exceeds limits,
+// so handlers can return it as it is.
+func ParseMultipart(req events.APIGatewayProxyRequest, limits MultipartLimits) (*Form, error) {
+	contentType := ""
+	if values := headerValues(req, "Content-Type"); len(values) > 0 {
+		contentType = values[0]
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "multipart/form-data" {
+		return nil, &HTTPError{Status: http.StatusUnsupportedMediaType, Detail: "expected multipart/form-data"}
+	}
+	if params["boundary"] == "" {
+		return nil, &HTTPError{Status: http.StatusBadRequest, Detail: "missing multipart boundary"}
+	}
+
+	body := []byte(req.Body)
+	if req.IsBase64Encoded {
+		if body, err = base64.StdEncoding.DecodeString(req.Body); err != nil {
+			return nil, &HTTPError{Status: http.StatusBadRequest, Detail: "body is not valid base64"}
+		}
+	}
+
+	form := &Form{Fields: map[string][]string{}, Files: map[string][]File{}}
+	mr := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+
+	for parts := 0; ; parts++ {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return form, nil
+		}
+		if err != nil {
+			return nil, &HTTPError{Status: http.StatusBadRequest, Detail: "malformed multipart body"}
+		}
+
+		if limits.MaxParts > 0 && parts >= limits.MaxParts {
+			return nil, &HTTPError{
+				Status: http.StatusRequestEntityTooLarge,
+				Detail: "body has more than " + strconv.Itoa(limits.MaxParts) + " parts",
+			}
+		}
+
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+
+		limit := limits.MaxFieldSize
+		if part.FileName() != "" {
+			limit = limits.MaxFileSize
+		}
+
+		content, err := readAll(part, limit)
+		if errors.Is(err, errTooLarge) {
+			return nil, &HTTPError{
+				Status: http.StatusRequestEntityTooLarge,
+				Detail: name + " exceeds " + strconv.FormatInt(limit, 10) + " bytes",
+			}
+		}
+		if err != nil {
+			return nil, &HTTPError{Status: http.StatusBadRequest, Detail: "malformed multipart body"}
+		}
+
+		if filename := part.FileName(); filename != "" {
+			form.Files[name] = append(form.Files[name], File{Filename: filename, Header: part.Header, Content: content})
+			continue
+		}
+		form.Fields[name] = append(form.Fields[name], string(content))
+	}
+}
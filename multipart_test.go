@@ -0,0 +1,74 @@
+package lambdarouter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMultipart(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a multipart body and")
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	_ = mw.WriteField("title", "holiday")
+	_ = mw.WriteField("tag", "beach")
+	_ = mw.WriteField("tag", "sun")
+	fw, _ := mw.CreateFormFile("photo", "beach.png")
+	_, _ = fw.Write([]byte{0x89, 'P', 'N', 'G'})
+	_ = mw.Close()
+
+	req := events.APIGatewayProxyRequest{
+		Headers:         map[string]string{"content-type": mw.FormDataContentType()},
+		Body:            base64.StdEncoding.EncodeToString(buf.Bytes()),
+		IsBase64Encoded: true,
+	}
+	status := func(err error) int {
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr.Status
+		}
+		return 0
+	}
+
+	desc(t, 2, "ParseMultipart function should")
+	{
+		desc(t, 4, "expose the fields and files of base64 encoded bodies")
+		form, err := ParseMultipart(req, MultipartLimits{})
+		a.NoError(err)
+		a.Exactly("holiday", form.Value("title"))
+		a.Exactly([]string{"beach", "sun"}, form.Fields["tag"])
+
+		photo, ok := form.File("photo")
+		a.True(ok)
+		a.Exactly("beach.png", photo.Filename)
+		a.Exactly("application/octet-stream", photo.ContentType())
+		a.Exactly([]byte{0x89, 'P', 'N', 'G'}, photo.Content)
+
+		desc(t, 4, "parse bodies which are not base64 encoded")
+		plain := req
+		plain.Body, plain.IsBase64Encoded = buf.String(), false
+		form, err = ParseMultipart(plain, MultipartLimits{})
+		a.NoError(err)
+		a.Exactly("holiday", form.Value("title"))
+
+		desc(t, 4, "respond with a 413 when a part exceeds the limits")
+		_, err = ParseMultipart(req, MultipartLimits{MaxFileSize: 3})
+		a.Exactly(http.StatusRequestEntityTooLarge, status(err))
+		_, err = ParseMultipart(req, MultipartLimits{MaxFieldSize: 4})
+		a.Exactly(http.StatusRequestEntityTooLarge, status(err))
+		_, err = ParseMultipart(req, MultipartLimits{MaxParts: 3})
+		a.Exactly(http.StatusRequestEntityTooLarge, status(err))
+
+		desc(t, 4, "respond with a 415 to other media types")
+		_, err = ParseMultipart(events.APIGatewayProxyRequest{Body: "{}"}, MultipartLimits{})
+		a.Exactly(http.StatusUnsupportedMediaType, status(err))
+	}
+}
@@ -0,0 +1,76 @@
+// Package muxroutes registers the routes of a gorilla/mux router on a lambdarouter.Router, easing
+// the migration of existing HTTP services into a Lambda function behind API Gateway.
+package muxroutes
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/mitchell/lambdarouter"
+	"github.com/mitchell/lambdarouter/internal/pathprefix"
+)
+
+// methods are the methods a route is imported for when it does not restrict them itself.
+var methods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
+// Import walks the routes of src and defines an equivalent route on r for every method and path
+// template it finds. Each imported route invokes src itself through lambdarouter.WrapHTTP, so
+// mux's own middleware and variables keep working unchanged. Routes are defined relative to the
+// current prefix of r, which is stripped from the path before the request reaches src, along with
+// the values of any path parameters it holds. An error is returned for the first route which cannot
+// be defined, such as one which conflicts with a route r already has.
+func Import(r *lambdarouter.Router, src *mux.Router) error {
+	handler := lambdarouter.WrapHTTP(pathprefix.Strip(r.Prefix(), src))
+
+	return src.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		if route.GetHandler() == nil {
+			return nil
+		}
+
+		tpl, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+
+		// Routes defined with PathPrefix have no end anchor and match everything below them.
+		if re, err := route.GetPathRegexp(); err == nil && !strings.HasSuffix(re, "$") {
+			tpl = strings.TrimSuffix(tpl, "/") + "/{proxy+}"
+		}
+
+		ms, err := route.GetMethods()
+		if err != nil {
+			ms = methods
+		}
+
+		for _, method := range ms {
+			if err := r.TryHandle(method, convertTemplate(tpl), handler); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// convertTemplate translates a mux path template into a lambdarouter path template by dropping
+// the regular expressions from its variables.
+func convertTemplate(tpl string) string {
+	segs := strings.Split(tpl, "/")
+
+	for i, seg := range segs {
+		if strings.HasPrefix(seg, "{") && strings.Contains(seg, ":") {
+			segs[i] = seg[:strings.Index(seg, ":")] + "}"
+		}
+	}
+
+	return strings.Join(segs, "/")
+}
@@ -0,0 +1,81 @@
+package muxroutes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/gorilla/mux"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImport(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a mux router and")
+	src := mux.NewRouter()
+	src.HandleFunc("/users/{id:[0-9]+}", func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("user " + mux.Vars(req)["id"]))
+	}).Methods(http.MethodGet, http.MethodPut)
+	src.PathPrefix("/static/").Handler(http.NotFoundHandler()).Methods(http.MethodGet)
+
+	desc(t, 2, "Import function should")
+	{
+		r := lambdarouter.New("prefix")
+
+		desc(t, 4, "define every route of the mux router")
+		a.NoError(Import(&r, src))
+		a.Exactly([]lambdarouter.Route{
+			{Method: http.MethodGet, Path: "/prefix/static/{proxy+}"},
+			{Method: http.MethodGet, Path: "/prefix/users/{id}"},
+			{Method: http.MethodPut, Path: "/prefix/users/{id}"},
+		}, r.Routes())
+
+		desc(t, 4, "route requests through the mux router")
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod:     http.MethodPut,
+			Path:           "/prefix/users/42",
+			PathParameters: map[string]string{"id": "42"},
+		})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("user 42", res.Body)
+
+		desc(t, 4, "strip group prefixes holding path parameters")
+		org := lambdarouter.New("prefix")
+		var importErr error
+		org.Group("orgs/{org}", func(r *lambdarouter.Router) {
+			importErr = Import(r, src)
+		})
+		a.NoError(importErr)
+
+		payload, _ = json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodPut,
+			Path:       "/prefix/orgs/acme/users/42",
+		})
+		resjson, err = org.Invoke(context.Background(), payload)
+		a.NoError(err)
+		a.NoError(json.Unmarshal(resjson, &res))
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("user 42", res.Body)
+
+		desc(t, 4, "return an error for routes which conflict with those of the router")
+		a.Error(Import(&r, src))
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
@@ -0,0 +1,166 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+// Renderer encodes response values as one media type.
+type Renderer struct {
+	// MediaType is the media type the renderer produces, and the Content-Type of its responses.
+	MediaType string
+
+	// Render encodes v as the body of a response. Renderers without one encode v with the codec of
+	// the router.
+	Render func(v interface{}) ([]byte, error)
+}
+
+// JSONRenderer renders values as application/json, with the codec of the router.
+var JSONRenderer = Renderer{MediaType: "application/json"}
+
+// XMLRenderer renders values as application/xml.
+var XMLRenderer = Renderer{MediaType: "application/xml", Render: xml.Marshal}
+
+// Negotiate returns a lambda.Handler which invokes fn, and renders the value it returns with the
+// renderer whose media type the client prefers, according to the Accept header of the request.
+// Ties, and requests without an Accept header, are resolved in favour of the earliest renderer.
+// When none of the renderers are acceptable, the handler returns an HTTPError with a 406 status.
+// The status of the response is 200, unless the value implements StatusCoder. Errors returned by
+// fn are returned by the handler unchanged.
+func Negotiate(fn func(ctx context.Context, req events.APIGatewayProxyRequest) (interface{}, error), renderers ...Renderer) lambda.Handler {
+	return negotiatedHandler{fn: fn, renderers: renderers}
+}
+
+type negotiatedHandler struct {
+	fn        func(ctx context.Context, req events.APIGatewayProxyRequest) (interface{}, error)
+	renderers []Renderer
+}
+
+func (nh negotiatedHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	offers := make([]string, len(nh.renderers))
+	for i, rn := range nh.renderers {
+		offers[i] = rn.MediaType
+	}
+
+	i, ok := negotiate(strings.Join(headerValues(req, "Accept"), ","), offers)
+	if !ok {
+		return nil, &HTTPError{
+			Status: http.StatusNotAcceptable,
+			Detail: "acceptable media types are " + strings.Join(offers, ", "),
+		}
+	}
+
+	out, err := nh.fn(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	codec := CodecFrom(ctx)
+	render := nh.renderers[i].Render
+	if render == nil {
+		render = codec.Marshal
+	}
+
+	body, err := render(out)
+	if err != nil {
+		return nil, err
+	}
+
+	res := events.APIGatewayProxyResponse{
+		StatusCode: http.StatusOK,
+		Headers: map[string]string{
+			"Content-Type": nh.renderers[i].MediaType,
+			"Vary":         "Accept",
+		},
+		Body: string(body),
+	}
+	if sc, ok := out.(StatusCoder); ok {
+		res.StatusCode = sc.StatusCode()
+	}
+	if !utf8.Valid(body) {
+		res.Body = base64.StdEncoding.EncodeToString(body)
+		res.IsBase64Encoded = true
+	}
+
+	return codec.Marshal(res)
+}
+
+// negotiate returns the index of the media type in offers which the accept header value prefers.
+// Each offer takes the quality of the most specific media range matching it, and ties are won by
+// the earliest offer. It reports false if no offer has a quality above zero.
+func negotiate(accept string, offers []string) (int, bool) {
+	if len(offers) == 0 {
+		return 0, false
+	}
+	if strings.TrimSpace(accept) == "" {
+		return 0, true
+	}
+
+	type mediaRange struct {
+		mt string
+		q  float64
+	}
+
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		ranges = append(ranges, mediaRange{mt: mt, q: q})
+	}
+
+	best, bestQ := 0, 0.0
+	for i, offer := range offers {
+		offer = strings.ToLower(offer)
+		if mt, _, err := mime.ParseMediaType(offer); err == nil {
+			offer = mt
+		}
+
+		q, specificity := 0.0, -1
+		for _, rg := range ranges {
+			if !matchMediaType(rg.mt, offer) {
+				continue
+			}
+
+			s := 2
+			switch {
+			case rg.mt == "*/*":
+				s = 0
+			case strings.HasSuffix(rg.mt, "/*"):
+				s = 1
+			}
+			if s > specificity {
+				q, specificity = rg.q, s
+			}
+		}
+
+		if q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+
+	return best, bestQ > 0
+}
@@ -0,0 +1,64 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type greeting struct {
+	Text string `json:"text" xml:"text"`
+}
+
+func TestNegotiate(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router and")
+	r := New("prefix")
+	ctx := context.Background()
+
+	r.Get("greeting", Negotiate(func(ctx context.Context, req events.APIGatewayProxyRequest) (interface{}, error) {
+		return greeting{Text: "hello"}, nil
+	}, JSONRenderer, XMLRenderer))
+
+	invoke := func(accept string) events.APIGatewayProxyResponse {
+		req := events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/prefix/greeting"}
+		if accept != "" {
+			req.Headers = map[string]string{"Accept": accept}
+		}
+		payload, _ := json.Marshal(req)
+		resjson, err := r.Invoke(ctx, payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "Negotiate handler should")
+	{
+		desc(t, 4, "render with the first renderer when there is no Accept header")
+		res := invoke("")
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("application/json", res.Headers["Content-Type"])
+		a.Exactly("Accept", res.Headers["Vary"])
+		a.JSONEq(`{"text": "hello"}`, res.Body)
+
+		desc(t, 4, "render with the renderer the client prefers")
+		res = invoke("application/json;q=0.5, application/xml")
+		a.Exactly("application/xml", res.Headers["Content-Type"])
+		a.Exactly("<greeting><text>hello</text></greeting>", res.Body)
+
+		desc(t, 4, "use the quality of the most specific matching range")
+		res = invoke("*/*;q=0.1, application/*;q=0.9, application/json;q=0.2")
+		a.Exactly("application/xml", res.Headers["Content-Type"])
+
+		desc(t, 4, "respond with a 406 when no renderer is acceptable")
+		res = invoke("text/html, application/json;q=0")
+		a.Exactly(http.StatusNotAcceptable, res.StatusCode)
+	}
+}
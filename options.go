@@ -0,0 +1,139 @@
+package lambdarouter
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+// Option configures a router as it is created by New.
+type Option func(r *Router)
+
+// Logger receives the diagnostics of the router: errors returned by handlers, panics it recovers
+// from, requests which match no route, and payloads it cannot decode. It is satisfied by
+// *log.Logger, and a *slog.Logger can be used with WithSlog.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// WithNotFound sets the handler invoked for requests which match no route, in place of the
+// router's own 404 response. Requests whose path matches routes of other methods are still
+// responded to with a 405. The handler can retrieve the request with RequestFrom, as any other.
+func WithNotFound(h lambda.Handler) Option {
+	return func(r *Router) {
+		r.notFound = h
+	}
+}
+
+// WithCaseInsensitive makes the router match the static segments of route paths regardless of
+// case, so /Users/42 matches the route /users/{id}. The values of path parameters keep the case of
+// the request.
+func WithCaseInsensitive() Option {
+	return func(r *Router) {
+		r.caseInsensitive = true
+	}
+}
+
+// WithLogger sets the logger the router reports its diagnostics to. Nothing is logged by default.
+func WithLogger(l Logger) Option {
+	return func(r *Router) {
+		r.logger = l
+	}
+}
+
+// WithPayloadFormat sets the version of the API Gateway payload format the router is invoked
+// with. Version 1.0, used by REST APIs, is the default. HTTP APIs may be configured to use version
+// 2.0, in which case requests are translated into the version 1.0 format before they reach
+// handlers, and their responses are translated back.
+func WithPayloadFormat(f PayloadFormat) Option {
+	return func(r *Router) {
+		r.payloadFormat = f
+	}
+}
+
+// WithMethodOverride makes the router honour the X-HTTP-Method-Override header of POST requests,
+// routing them by the method it names, so clients behind proxies which block PUT, PATCH, and
+// DELETE requests can still reach those routes. Only those methods may be named by the header,
+// which is ignored otherwise. Handlers see the overriding method as the HTTPMethod of the request,
+// while the HTTPMethod of its RequestContext remains POST.
+func WithMethodOverride() Option {
+	return func(r *Router) {
+		r.methodOverride = true
+	}
+}
+
+// requestMethod returns the method req should be routed by, which is the method named by its
+// X-HTTP-Method-Override header if the router honours it.
+func (r Router) requestMethod(req events.APIGatewayProxyRequest) string {
+	if !r.methodOverride || req.HTTPMethod != http.MethodPost {
+		return req.HTTPMethod
+	}
+
+	values := headerValues(req, "X-HTTP-Method-Override")
+	if len(values) == 0 {
+		return req.HTTPMethod
+	}
+
+	switch method := strings.ToUpper(strings.TrimSpace(values[0])); method {
+	case http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return method
+	}
+
+	return req.HTTPMethod
+}
+
+// WithDefaultHeaders sets headers added to every response of the router, such as an X-Service
+// header naming the service or a default Cache-Control, so they need not be repeated in every
+// handler. Headers set by handlers take precedence, regardless of the case of their names. The
+// headers are also added to the responses the router renders itself, such as a 404.
+func WithDefaultHeaders(headers map[string]string) Option {
+	return func(r *Router) {
+		if r.defaultHeaders == nil {
+			r.defaultHeaders = map[string]string{}
+		}
+		for name, value := range headers {
+			r.defaultHeaders[name] = value
+		}
+	}
+}
+
+// addDefaultHeaders adds the default headers of the router to the response encoded in payload,
+// except those the response already has.
+func (r Router) addDefaultHeaders(payload []byte) ([]byte, error) {
+	var res events.APIGatewayProxyResponse
+	if err := r.jsonCodec().Unmarshal(payload, &res); err != nil {
+		return nil, err
+	}
+
+	if res.Headers == nil {
+		res.Headers = map[string]string{}
+	}
+
+	for name, value := range r.defaultHeaders {
+		if !hasHeader(res.Headers, name) && !hasHeader(res.MultiValueHeaders, name) {
+			res.Headers[name] = value
+		}
+	}
+
+	return r.jsonCodec().Marshal(res)
+}
+
+// hasHeader reports whether headers holds the named header, regardless of the case of its name.
+func hasHeader[V any](headers map[string]V, name string) bool {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// logf reports a message to the logger of the router, if it has one.
+func (r Router) logf(format string, v ...interface{}) {
+	if r.logger != nil {
+		r.logger.Printf(format, v...)
+	}
+}
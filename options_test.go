@@ -0,0 +1,178 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+type logRecorder []string
+
+func (l *logRecorder) Printf(format string, v ...interface{}) {
+	*l = append(*l, fmt.Sprintf(format, v...))
+}
+
+func TestOptions(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router and")
+	var logs logRecorder
+	r := New("prefix",
+		WithCaseInsensitive(),
+		WithLogger(&logs),
+		WithNotFound(lambda.NewHandler(func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusNotFound, Body: "no " + req.Path}, nil
+		})),
+	)
+
+	r.Get("Users/{ID}", lambda.NewHandler(func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: req.PathParameters["ID"]}, nil
+	}))
+	r.Get("broken", lambda.NewHandler(func() error {
+		return errors.New("broken")
+	}))
+	r.Get("panicking", lambda.NewHandler(func() error {
+		panic("oops")
+	}))
+
+	invoke := func(method, path string) (events.APIGatewayProxyResponse, error) {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: method, Path: path})
+
+		var res events.APIGatewayProxyResponse
+		resjson, err := r.Invoke(context.Background(), payload)
+		if err == nil {
+			a.NoError(json.Unmarshal(resjson, &res))
+		}
+		return res, err
+	}
+
+	desc(t, 2, "WithCaseInsensitive option should")
+	{
+		desc(t, 4, "match static segments regardless of case")
+		res, err := invoke(http.MethodGet, "/PREFIX/users/AbC")
+		a.NoError(err)
+		a.Exactly(http.StatusOK, res.StatusCode)
+
+		desc(t, 4, "keep the case of path parameters")
+		a.Exactly("AbC", res.Body)
+	}
+
+	desc(t, 2, "WithNotFound option should")
+	{
+		desc(t, 4, "invoke the handler for requests which match no route")
+		res, err := invoke(http.MethodGet, "/prefix/nothing")
+		a.NoError(err)
+		a.Exactly(http.StatusNotFound, res.StatusCode)
+		a.Exactly("no /prefix/nothing", res.Body)
+
+		desc(t, 4, "not replace method not allowed responses")
+		res, err = invoke(http.MethodPost, "/prefix/users/abc")
+		a.NoError(err)
+		a.Exactly(http.StatusMethodNotAllowed, res.StatusCode)
+	}
+
+	desc(t, 2, "WithLogger option should")
+	{
+		desc(t, 4, "log requests which match no route")
+		a.Exactly(logRecorder{
+			"no route for GET /prefix/nothing",
+			"method POST not allowed for /prefix/users/abc, only GET",
+		}, logs)
+
+		desc(t, 4, "log the errors returned by handlers")
+		logs = nil
+		_, err := invoke(http.MethodGet, "/prefix/broken")
+		a.EqualError(err, "broken")
+		a.Exactly(logRecorder{"GET /prefix/broken: broken"}, logs)
+
+		desc(t, 4, "log recovered panics of handlers with their stack")
+		logs = nil
+		_, err = invoke(http.MethodGet, "/prefix/panicking")
+		a.EqualError(err, "panic: oops")
+		a.Len(logs, 1)
+		a.Contains(logs[0], "GET /prefix/panicking: panic: oops\n")
+		a.Contains(logs[0], "runtime/debug.Stack")
+
+		desc(t, 4, "log malformed payloads")
+		logs = nil
+		_, err = r.Invoke(context.Background(), []byte(`{"path": 42}`))
+		a.Error(err)
+		a.Len(logs, 1)
+		a.Contains(logs[0], "malformed request: ")
+	}
+
+	desc(t, 0, "Initialize Router with method override and")
+	r = New("prefix", WithMethodOverride())
+	r.Delete("users/{id}", lambda.NewHandler(func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: req.HTTPMethod + " " + req.PathParameters["id"]}, nil
+	}))
+
+	override := func(method, header string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod: method,
+			Path:       "/prefix/users/42",
+			Headers:    map[string]string{"X-HTTP-Method-Override": header},
+		})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "WithMethodOverride option should")
+	{
+		desc(t, 4, "route POST requests by the method of the header")
+		res := override(http.MethodPost, "delete")
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("DELETE 42", res.Body)
+
+		desc(t, 4, "ignore the header on other methods")
+		res = override(http.MethodGet, "DELETE")
+		a.Exactly(http.StatusMethodNotAllowed, res.StatusCode)
+
+		desc(t, 4, "ignore methods other than PUT, PATCH, and DELETE")
+		res = override(http.MethodPost, "GET")
+		a.Exactly(http.StatusMethodNotAllowed, res.StatusCode)
+	}
+	desc(t, 2, "WithDefaultHeaders option should")
+	{
+		dr := New("prefix", WithDefaultHeaders(map[string]string{
+			"X-Service":     "hellosrv",
+			"Cache-Control": "no-cache",
+		}))
+		dr.Get("cached", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+			return events.APIGatewayProxyResponse{
+				StatusCode: http.StatusOK,
+				Headers:    map[string]string{"cache-control": "max-age=60"},
+			}, nil
+		}))
+
+		invoke := func(path string) events.APIGatewayProxyResponse {
+			payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path})
+			resjson, err := dr.Invoke(context.Background(), payload)
+			a.NoError(err)
+
+			var res events.APIGatewayProxyResponse
+			a.NoError(json.Unmarshal(resjson, &res))
+			return res
+		}
+
+		desc(t, 4, "merge the headers with those of handlers")
+		res := invoke("/prefix/cached")
+		a.Exactly(map[string]string{"X-Service": "hellosrv", "cache-control": "max-age=60"}, res.Headers)
+
+		desc(t, 4, "add the headers to responses rendered by the router")
+		res = invoke("/prefix/missing")
+		a.Exactly(http.StatusNotFound, res.StatusCode)
+		a.Exactly("hellosrv", res.Headers["X-Service"])
+	}
+}
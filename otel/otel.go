@@ -0,0 +1,166 @@
+// Package otel provides middleware which traces the invocations of routes with OpenTelemetry,
+// creating a server span for each with the semantic attributes of HTTP servers. Spans are created
+// with the tracer provider of the application, such as one exporting to the collector of the
+// OpenTelemetry Lambda extension, and continue the traces of the requests they handle.
+package otel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/mitchell/lambdarouter"
+	global "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/mitchell/lambdarouter/otel"
+
+// Config configures the OpenTelemetry middleware.
+type Config struct {
+	// TracerProvider creates the tracer spans are started with. If nil, the global tracer provider
+	// is used.
+	TracerProvider trace.TracerProvider
+
+	// Propagator extracts the trace context of requests from their headers. If nil, the global
+	// propagator is used, which should be set to propagation.TraceContext or a composite including
+	// it.
+	Propagator propagation.TextMapPropagator
+}
+
+// Middleware returns middleware which starts a server span for each invocation of a route, as a
+// child of the span whose context the request carries in its headers, if any. The span is named by
+// the method and template of the route, such as GET /users/{id}, and carries the http.method,
+// http.route, http.target, http.status_code, user_agent.original, and http.client_ip attributes,
+// along with faas.invocation_id. Errors returned by the handler are recorded on the span, and they
+// and responses with a 5xx status set its status to an error. The context of the span is passed to
+// the handler, so spans it starts are children of it.
+func Middleware(cfg Config) lambdarouter.Middleware {
+	return func(next lambda.Handler) lambda.Handler {
+		return tracer{cfg: cfg, next: next}
+	}
+}
+
+type tracer struct {
+	cfg  Config
+	next lambda.Handler
+}
+
+func (t tracer) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := lambdarouter.RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, propagator := t.cfg.TracerProvider, t.cfg.Propagator
+	if provider == nil {
+		provider = global.GetTracerProvider()
+	}
+	if propagator == nil {
+		propagator = global.GetTextMapPropagator()
+	}
+
+	ctx = propagator.Extract(ctx, headerCarrier{req: &req})
+
+	name := req.HTTPMethod
+	attrs := []attribute.KeyValue{
+		attribute.String("http.method", req.HTTPMethod),
+		attribute.String("http.target", req.Path),
+	}
+	if rt, ok := lambdarouter.RouteFromContext(ctx); ok {
+		name = rt.Method + " " + rt.Path
+		attrs = append(attrs, attribute.String("http.route", rt.Path))
+	}
+	if ua := (headerCarrier{req: &req}).Get("User-Agent"); ua != "" {
+		attrs = append(attrs, attribute.String("user_agent.original", ua))
+	}
+	if ip := req.RequestContext.Identity.SourceIP; ip != "" {
+		attrs = append(attrs, attribute.String("http.client_ip", ip))
+	}
+	if lc, ok := lambdacontext.FromContext(ctx); ok {
+		attrs = append(attrs, attribute.String("faas.invocation_id", lc.AwsRequestID))
+	}
+
+	ctx, span := provider.Tracer(instrumentationName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	res, err := t.next.Invoke(ctx, payload)
+
+	status := 0
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		var httpErr *lambdarouter.HTTPError
+		if errors.As(err, &httpErr) {
+			status = httpErr.Status
+		}
+	} else {
+		status = responseStatus(lambdarouter.CodecFrom(ctx), res)
+	}
+
+	if status != 0 {
+		span.SetAttributes(attribute.Int("http.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+
+	return res, err
+}
+
+// headerCarrier adapts the headers of a request to the propagation.TextMapCarrier interface,
+// regardless of the case of their names. Setting headers is not supported, as the middleware only
+// extracts trace contexts.
+type headerCarrier struct {
+	req *events.APIGatewayProxyRequest
+}
+
+func (c headerCarrier) Get(key string) string {
+	for name, values := range c.req.MultiValueHeaders {
+		if strings.EqualFold(name, key) && len(values) > 0 {
+			return values[0]
+		}
+	}
+	for name, value := range c.req.Headers {
+		if strings.EqualFold(name, key) {
+			return value
+		}
+	}
+
+	return ""
+}
+
+func (c headerCarrier) Set(string, string) {}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.req.Headers)+len(c.req.MultiValueHeaders))
+	for name := range c.req.Headers {
+		keys = append(keys, name)
+	}
+	for name := range c.req.MultiValueHeaders {
+		keys = append(keys, name)
+	}
+
+	return keys
+}
+
+// responseStatus returns the status code of the encoded proxy response, or zero if it is not one.
+func responseStatus(codec lambdarouter.Codec, response []byte) int {
+	var res struct {
+		StatusCode int `json:"statusCode"`
+	}
+	_ = codec.Unmarshal(response, &res)
+
+	return res.StatusCode
+}
@@ -0,0 +1,96 @@
+package otel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with OpenTelemetry middleware and")
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	var handlerSpan trace.SpanContext
+	r := lambdarouter.New("prefix")
+	r.Use(Middleware(Config{TracerProvider: provider, Propagator: propagation.TraceContext{}}))
+	r.Get("users/{id}", lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+		handlerSpan = trace.SpanContextFromContext(ctx)
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}))
+	r.Get("broken", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{}, errors.New("broken")
+	}))
+
+	invoke := func(path string, headers map[string]string) {
+		req := events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path, Headers: headers}
+		req.RequestContext.Identity.SourceIP = "203.0.113.7"
+		payload, _ := json.Marshal(req)
+		_, _ = r.Invoke(context.Background(), payload)
+	}
+	attrs := func(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+		m := map[attribute.Key]attribute.Value{}
+		for _, kv := range span.Attributes() {
+			m[kv.Key] = kv.Value
+		}
+		return m
+	}
+
+	desc(t, 2, "Middleware should")
+	{
+		desc(t, 4, "start a server span named by the route, continuing the trace of the request")
+		invoke("/prefix/users/42", map[string]string{
+			"Traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			"User-Agent":  "test",
+		})
+		spans := recorder.Ended()
+		a.Len(spans, 1)
+		span := spans[0]
+		a.Exactly("GET /prefix/users/{id}", span.Name())
+		a.Exactly(trace.SpanKindServer, span.SpanKind())
+		a.Exactly("4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+		a.Exactly("00f067aa0ba902b7", span.Parent().SpanID().String())
+		a.Exactly(span.SpanContext().SpanID(), handlerSpan.SpanID())
+
+		m := attrs(span)
+		a.Exactly("GET", m["http.method"].AsString())
+		a.Exactly("/prefix/users/{id}", m["http.route"].AsString())
+		a.Exactly("/prefix/users/42", m["http.target"].AsString())
+		a.Exactly(int64(200), m["http.status_code"].AsInt64())
+		a.Exactly("test", m["user_agent.original"].AsString())
+		a.Exactly("203.0.113.7", m["http.client_ip"].AsString())
+		a.Exactly(codes.Unset, span.Status().Code)
+
+		desc(t, 4, "record errors of the handler")
+		invoke("/prefix/broken", nil)
+		spans = recorder.Ended()
+		a.Len(spans, 2)
+		a.Exactly(codes.Error, spans[1].Status().Code)
+		a.Len(spans[1].Events(), 1)
+		a.False(spans[1].Parent().IsValid())
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
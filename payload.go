@@ -0,0 +1,141 @@
+package lambdarouter
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// PayloadFormat is a version of the payload format API Gateway invokes Lambda functions with.
+type PayloadFormat string
+
+// The payload formats supported by the router, named after the payloadFormatVersion setting of an
+// API Gateway integration.
+const (
+	PayloadFormatV1 PayloadFormat = "1.0"
+	PayloadFormatV2 PayloadFormat = "2.0"
+)
+
+// invokeV2 routes an invocation made with the version 2.0 payload format, translating the request
+// into the version 1.0 format handlers expect and the response back.
+func (r Router) invokeV2(ctx context.Context, payload []byte) ([]byte, error) {
+	var v2 events.APIGatewayV2HTTPRequest
+
+	if err := r.jsonCodec().Unmarshal(payload, &v2); err != nil {
+		r.logf("malformed request: %v", err)
+		return nil, err
+	}
+
+	req := proxyRequestV2(v2)
+
+	payload, err := r.jsonCodec().Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.route(ctx, req, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return responseV2(r.jsonCodec(), res)
+}
+
+// proxyRequestV2 translates a version 2.0 request into the version 1.0 format.
+func proxyRequestV2(v2 events.APIGatewayV2HTTPRequest) events.APIGatewayProxyRequest {
+	req := events.APIGatewayProxyRequest{
+		HTTPMethod:            v2.RequestContext.HTTP.Method,
+		Path:                  v2.RawPath,
+		Headers:               map[string]string{},
+		QueryStringParameters: v2.QueryStringParameters,
+		PathParameters:        v2.PathParameters,
+		StageVariables:        v2.StageVariables,
+		Body:                  v2.Body,
+		IsBase64Encoded:       v2.IsBase64Encoded,
+		RequestContext: events.APIGatewayProxyRequestContext{
+			AccountID:        v2.RequestContext.AccountID,
+			RequestID:        v2.RequestContext.RequestID,
+			Stage:            v2.RequestContext.Stage,
+			DomainName:       v2.RequestContext.DomainName,
+			APIID:            v2.RequestContext.APIID,
+			HTTPMethod:       v2.RequestContext.HTTP.Method,
+			Path:             v2.RequestContext.HTTP.Path,
+			RequestTime:      v2.RequestContext.Time,
+			RequestTimeEpoch: v2.RequestContext.TimeEpoch,
+			Identity: events.APIGatewayRequestIdentity{
+				SourceIP:  v2.RequestContext.HTTP.SourceIP,
+				UserAgent: v2.RequestContext.HTTP.UserAgent,
+			},
+		},
+	}
+
+	// Route keys have the form "METHOD /path", except for the $default route.
+	if i := strings.IndexByte(v2.RouteKey, ' '); i >= 0 {
+		req.Resource = v2.RouteKey[i+1:]
+		req.RequestContext.ResourcePath = req.Resource
+	}
+
+	for name, value := range v2.Headers {
+		req.Headers[name] = value
+	}
+	if len(v2.Cookies) > 0 {
+		req.Headers["cookie"] = strings.Join(v2.Cookies, "; ")
+	}
+
+	// Repeated query parameters are joined with commas, so their values are recovered from the
+	// raw query string instead.
+	if query, err := url.ParseQuery(v2.RawQueryString); err == nil && len(query) > 0 {
+		req.MultiValueQueryStringParameters = query
+	}
+
+	if auth := v2.RequestContext.Authorizer; auth != nil {
+		req.RequestContext.Authorizer = map[string]interface{}{}
+		for name, value := range auth.Lambda {
+			req.RequestContext.Authorizer[name] = value
+		}
+		if auth.JWT != nil {
+			req.RequestContext.Authorizer["claims"] = auth.JWT.Claims
+			req.RequestContext.Authorizer["scopes"] = auth.JWT.Scopes
+		}
+	}
+
+	return req
+}
+
+// responseV2 translates a version 1.0 response into the version 2.0 format, which has no
+// multi-value headers. Cookies are returned through their own field, and the values of any other
+// header are joined with commas.
+func responseV2(codec Codec, payload []byte) ([]byte, error) {
+	var res events.APIGatewayProxyResponse
+
+	if err := codec.Unmarshal(payload, &res); err != nil {
+		return nil, err
+	}
+
+	v2 := events.APIGatewayV2HTTPResponse{
+		StatusCode:      res.StatusCode,
+		Headers:         map[string]string{},
+		Body:            res.Body,
+		IsBase64Encoded: res.IsBase64Encoded,
+	}
+
+	for name, value := range res.Headers {
+		if strings.EqualFold(name, "Set-Cookie") {
+			v2.Cookies = append(v2.Cookies, value)
+			continue
+		}
+		v2.Headers[name] = value
+	}
+
+	for name, values := range res.MultiValueHeaders {
+		if strings.EqualFold(name, "Set-Cookie") {
+			v2.Cookies = append(v2.Cookies, values...)
+			continue
+		}
+		v2.Headers[name] = strings.Join(values, ",")
+	}
+
+	return codec.Marshal(v2)
+}
@@ -0,0 +1,68 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayloadFormat(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router and")
+	r := New("prefix", WithPayloadFormat(PayloadFormatV2))
+
+	var got events.APIGatewayProxyRequest
+	r.Get("hello/{name}", lambda.NewHandler(func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		got = req
+		return events.APIGatewayProxyResponse{
+			StatusCode:        http.StatusOK,
+			Headers:           map[string]string{"Content-Type": "text/plain"},
+			MultiValueHeaders: map[string][]string{"Set-Cookie": {"a=1", "b=2"}},
+			Body:              "hello " + req.PathParameters["name"],
+		}, nil
+	}))
+
+	desc(t, 2, "Invoke method should")
+	{
+		payload, _ := json.Marshal(events.APIGatewayV2HTTPRequest{
+			Version:        "2.0",
+			RouteKey:       "$default",
+			RawPath:        "/prefix/hello/mitchell",
+			RawQueryString: "tag=a&tag=b",
+			Cookies:        []string{"session=abc"},
+			Headers:        map[string]string{"accept": "text/plain"},
+			RequestContext: events.APIGatewayV2HTTPRequestContext{
+				HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+					Method:   http.MethodGet,
+					Path:     "/prefix/hello/mitchell",
+					SourceIP: "127.0.0.1",
+				},
+			},
+		})
+
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		desc(t, 4, "translate version 2.0 requests for handlers")
+		a.Exactly(http.MethodGet, got.HTTPMethod)
+		a.Exactly("/prefix/hello/mitchell", got.Path)
+		a.Exactly(map[string]string{"name": "mitchell"}, got.PathParameters)
+		a.Exactly([]string{"a", "b"}, got.MultiValueQueryStringParameters["tag"])
+		a.Exactly("session=abc", got.Headers["cookie"])
+		a.Exactly("127.0.0.1", got.RequestContext.Identity.SourceIP)
+
+		desc(t, 4, "translate responses into the version 2.0 format")
+		var res events.APIGatewayV2HTTPResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("hello mitchell", res.Body)
+		a.Exactly("text/plain", res.Headers["Content-Type"])
+		a.Exactly([]string{"a=1", "b=2"}, res.Cookies)
+	}
+}
@@ -0,0 +1,258 @@
+package lambdarouter
+
+import (
+	"mime"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// predicate is a condition on requests which a route requires in addition to its method and path.
+// Several routes may share a method and path as long as their predicates differ.
+type predicate struct {
+	// name describes the condition, as in "host=api.example.com". It distinguishes routes which
+	// share a method and path, and is listed in the Conditions of their Route.
+	name string
+
+	// rank orders the predicates of a route, so that the predicate which fails first determines
+	// the status of the response when no route matches.
+	rank int
+
+	// status is the status of the response when no route matches a request because of this
+	// predicate.
+	status int
+
+	match func(req events.APIGatewayProxyRequest) bool
+
+	// bind, if set, returns match for the table of the router the route is added to, for
+	// predicates which depend on the configuration of the router, such as its feature flags.
+	bind func(t *routeTable) func(req events.APIGatewayProxyRequest) bool
+}
+
+// The ranks of the kinds of predicates.
+const (
+	flagRank = iota
+	hostRank
+	tenantRank
+	versionRank
+	headerRank
+	queryRank
+	contentTypeRank
+)
+
+// sortPredicates orders predicates by rank and then name.
+func sortPredicates(ps []predicate) {
+	sort.SliceStable(ps, func(i, j int) bool {
+		if ps[i].rank != ps[j].rank {
+			return ps[i].rank < ps[j].rank
+		}
+		return ps[i].name < ps[j].name
+	})
+}
+
+// selectEvent returns the first of events whose predicates all match req, as events are ordered
+// from most to least specific. If none match, the status of the first predicate the least
+// specific event failed is returned instead.
+func selectEvent(events []event, req events.APIGatewayProxyRequest) (event, int) {
+	status := http.StatusNotFound
+
+	for _, e := range events {
+		failed := false
+
+		for _, p := range e.predicates {
+			if !p.match(req) {
+				failed, status = true, p.status
+				break
+			}
+		}
+
+		if !failed {
+			return e, 0
+		}
+	}
+
+	return event{}, status
+}
+
+// bindPredicates sets the match of the predicates which depend on the router the route is added
+// to, whose table is t.
+func bindPredicates(t *routeTable, ps []predicate) {
+	for i, p := range ps {
+		if p.bind != nil {
+			ps[i].match = p.bind(t)
+		}
+	}
+}
+
+// withPredicates returns a route option which adds predicates to a route.
+func withPredicates(ps ...predicate) RouteOption {
+	return func(e *event) {
+		e.predicates = append(e.predicates, ps...)
+	}
+}
+
+// WithHost restricts a route to requests for the given host, so routes of the same method and path
+// can be handled differently per custom domain. The host is taken from the domain name of the
+// request context, or the Host header when it is absent, and compared regardless of case and port.
+// A host beginning with "*." matches any subdomain of the rest of it.
+func WithHost(host string) RouteOption {
+	return withPredicates(hostPredicate(host))
+}
+
+// Host allows you to define many routes restricted to the same host, as WithHost does for a single
+// route. The fn parameter is a function in which the routes, or mounted routers, should be
+// defined.
+func (r *Router) Host(host string, fn func(r *Router)) {
+	original := r.predicates
+	r.predicates = append(r.predicates[:len(r.predicates):len(r.predicates)], hostPredicate(host))
+	fn(r)
+	r.predicates = original
+}
+
+func hostPredicate(host string) predicate {
+	host = strings.ToLower(host)
+
+	return predicate{
+		name:   "host=" + host,
+		rank:   hostRank,
+		status: http.StatusNotFound,
+		match: func(req events.APIGatewayProxyRequest) bool {
+			return matchHost(host, requestHost(req))
+		},
+	}
+}
+
+// requestHost returns the lower-case host a request was made to, without any port.
+func requestHost(req events.APIGatewayProxyRequest) string {
+	host := req.RequestContext.DomainName
+	if host == "" {
+		if values := headerValues(req, "Host"); len(values) > 0 {
+			host = values[0]
+		}
+	}
+
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	return strings.ToLower(host)
+}
+
+func matchHost(pattern, host string) bool {
+	if strings.HasPrefix(pattern, "*.") {
+		return strings.HasSuffix(host, pattern[1:]) && len(host) > len(pattern)-1
+	}
+
+	return host == pattern
+}
+
+// WithHeader restricts a route to requests with a header of the given name and value, so routes of
+// the same method and path can be chosen between by header, such as an API version. Header names
+// are compared regardless of case, and values exactly. If value is empty, the header only needs to
+// be present. Routes with more conditions are preferred to routes with fewer.
+func WithHeader(name, value string) RouteOption {
+	name = http.CanonicalHeaderKey(name)
+
+	p := predicate{
+		name:   "header:" + name + "=" + value,
+		rank:   headerRank,
+		status: http.StatusNotFound,
+		match: func(req events.APIGatewayProxyRequest) bool {
+			values := headerValues(req, name)
+			if value == "" {
+				return len(values) > 0
+			}
+
+			for _, v := range values {
+				if v == value {
+					return true
+				}
+			}
+			return false
+		},
+	}
+	if value == "" {
+		p.name = "header:" + name
+	}
+
+	return withPredicates(p)
+}
+
+// WithQuery restricts a route to requests with a query string parameter of the given name and
+// value, so that actions multiplexed over one path, as some webhook providers do, can be routed
+// to separate handlers. Names and values are compared exactly. If value is empty, the parameter
+// only needs to be present. Routes with more conditions are preferred to routes with fewer.
+func WithQuery(name, value string) RouteOption {
+	p := predicate{
+		name:   "query:" + name + "=" + value,
+		rank:   queryRank,
+		status: http.StatusNotFound,
+		match: func(req events.APIGatewayProxyRequest) bool {
+			values := queryValues(req, name)
+			if value == "" {
+				return len(values) > 0
+			}
+
+			for _, v := range values {
+				if v == value {
+					return true
+				}
+			}
+			return false
+		},
+	}
+	if value == "" {
+		p.name = "query:" + name
+	}
+
+	return withPredicates(p)
+}
+
+// WithContentType restricts a route to requests whose body has one of the given media types, so
+// routes of the same method and path can consume different formats, such as JSON and form data.
+// Parameters of the Content-Type header, such as the charset, are ignored, and a media type of
+// the form "type/*" matches any subtype. When a request matches the path of such routes but none
+// of their media types, the router responds with a 415.
+func WithContentType(mediaTypes ...string) RouteOption {
+	mediaTypes = append([]string(nil), mediaTypes...)
+	for i, mt := range mediaTypes {
+		mediaTypes[i] = strings.ToLower(mt)
+	}
+
+	return withPredicates(predicate{
+		name:   "content-type=" + strings.Join(mediaTypes, ","),
+		rank:   contentTypeRank,
+		status: http.StatusUnsupportedMediaType,
+		match: func(req events.APIGatewayProxyRequest) bool {
+			values := headerValues(req, "Content-Type")
+			if len(values) == 0 {
+				return false
+			}
+
+			mt, _, err := mime.ParseMediaType(values[0])
+			if err != nil {
+				return false
+			}
+
+			for _, accepted := range mediaTypes {
+				if matchMediaType(accepted, mt) {
+					return true
+				}
+			}
+			return false
+		},
+	})
+}
+
+// matchMediaType reports whether the media type mt matches pattern, which may be of the form
+// "type/*" or "*/*".
+func matchMediaType(pattern, mt string) bool {
+	if pattern == "*/*" || pattern == mt {
+		return true
+	}
+
+	return strings.HasSuffix(pattern, "/*") && strings.HasPrefix(mt, pattern[:len(pattern)-1])
+}
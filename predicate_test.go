@@ -0,0 +1,208 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPredicates(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router and")
+	r := New("prefix")
+	ctx := context.Background()
+
+	respond := func(body string) lambda.Handler {
+		return lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: body}, nil
+		})
+	}
+	invoke := func(req events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+		if req.HTTPMethod == "" {
+			req.HTTPMethod = http.MethodGet
+		}
+		payload, _ := json.Marshal(req)
+		resjson, err := r.Invoke(ctx, payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	admin := New("")
+	admin.Get("users", respond("admin users"))
+
+	r.Get("users", respond("api users"), WithHost("api.example.com"))
+	r.Get("users", respond("any users"), WithHost("*.example.com"))
+	r.Host("admin.example.com", func(r *Router) {
+		r.Mount("admin", admin)
+	})
+
+	r.Get("reports", respond("latest reports"))
+	r.Get("reports", respond("2023 reports"), WithHeader("x-api-version", "2023-06-01"))
+	r.Get("reports", respond("beta reports"), WithHeader("X-Beta", ""))
+
+	desc(t, 2, "WithHeader option should")
+	{
+		desc(t, 4, "choose between routes by the value of a header")
+		res := invoke(events.APIGatewayProxyRequest{
+			Path:    "/prefix/reports",
+			Headers: map[string]string{"X-Api-Version": "2023-06-01"},
+		})
+		a.Exactly("2023 reports", res.Body)
+
+		res = invoke(events.APIGatewayProxyRequest{
+			Path:              "/prefix/reports",
+			MultiValueHeaders: map[string][]string{"x-beta": {"1"}},
+		})
+		a.Exactly("beta reports", res.Body)
+
+		desc(t, 4, "fall back to routes without the header")
+		res = invoke(events.APIGatewayProxyRequest{
+			Path:    "/prefix/reports",
+			Headers: map[string]string{"X-Api-Version": "2022-01-01"},
+		})
+		a.Exactly("latest reports", res.Body)
+	}
+
+	r.Post("webhook", respond("resend"), WithQuery("action", "resend"))
+	r.Post("webhook", respond("cancel"), WithQuery("action", "cancel"))
+	r.Post("webhook", respond("test"), WithQuery("test", ""))
+
+	desc(t, 2, "WithQuery option should")
+	{
+		desc(t, 4, "choose between routes by the value of a query parameter")
+		res := invoke(events.APIGatewayProxyRequest{
+			HTTPMethod:            http.MethodPost,
+			Path:                  "/prefix/webhook",
+			QueryStringParameters: map[string]string{"action": "resend"},
+		})
+		a.Exactly("resend", res.Body)
+
+		res = invoke(events.APIGatewayProxyRequest{
+			HTTPMethod:                      http.MethodPost,
+			Path:                            "/prefix/webhook",
+			MultiValueQueryStringParameters: map[string][]string{"action": {"cancel"}},
+		})
+		a.Exactly("cancel", res.Body)
+
+		desc(t, 4, "match parameters which are only required to be present")
+		res = invoke(events.APIGatewayProxyRequest{
+			HTTPMethod:            http.MethodPost,
+			Path:                  "/prefix/webhook",
+			QueryStringParameters: map[string]string{"test": ""},
+		})
+		a.Exactly("test", res.Body)
+
+		desc(t, 4, "respond with a 404 when no parameter matches")
+		res = invoke(events.APIGatewayProxyRequest{
+			HTTPMethod:            http.MethodPost,
+			Path:                  "/prefix/webhook",
+			QueryStringParameters: map[string]string{"action": "delete"},
+		})
+		a.Exactly(http.StatusNotFound, res.StatusCode)
+	}
+
+	r.Post("orders", respond("json order"), WithContentType("application/json"))
+	r.Post("orders", respond("form order"), WithContentType("application/x-www-form-urlencoded", "multipart/*"))
+
+	desc(t, 2, "WithContentType option should")
+	{
+		desc(t, 4, "choose between routes by the media type of the body")
+		res := invoke(events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodPost,
+			Path:       "/prefix/orders",
+			Headers:    map[string]string{"content-type": "application/json; charset=utf-8"},
+		})
+		a.Exactly("json order", res.Body)
+
+		res = invoke(events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodPost,
+			Path:       "/prefix/orders",
+			Headers:    map[string]string{"Content-Type": "multipart/form-data; boundary=x"},
+		})
+		a.Exactly("form order", res.Body)
+
+		desc(t, 4, "respond with a 415 when no media type matches")
+		res = invoke(events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodPost,
+			Path:       "/prefix/orders",
+			Headers:    map[string]string{"Content-Type": "text/plain"},
+		})
+		a.Exactly(http.StatusUnsupportedMediaType, res.StatusCode)
+
+		res = invoke(events.APIGatewayProxyRequest{HTTPMethod: http.MethodPost, Path: "/prefix/orders"})
+		a.Exactly(http.StatusUnsupportedMediaType, res.StatusCode)
+	}
+
+	desc(t, 2, "WithHost option and Host method should")
+	{
+		desc(t, 4, "match routes by the domain name of the request")
+		res := invoke(events.APIGatewayProxyRequest{
+			Path:           "/prefix/users",
+			RequestContext: events.APIGatewayProxyRequestContext{DomainName: "api.example.com"},
+		})
+		a.Exactly("api users", res.Body)
+
+		desc(t, 4, "match routes by the Host header when there is no domain name")
+		res = invoke(events.APIGatewayProxyRequest{
+			Path:    "/prefix/users",
+			Headers: map[string]string{"host": "API.example.com:443"},
+		})
+		a.Exactly("api users", res.Body)
+
+		desc(t, 4, "match subdomains with wildcards")
+		res = invoke(events.APIGatewayProxyRequest{
+			Path:    "/prefix/users",
+			Headers: map[string]string{"Host": "www.example.com"},
+		})
+		a.Exactly("any users", res.Body)
+
+		desc(t, 4, "restrict mounted routers to the host")
+		res = invoke(events.APIGatewayProxyRequest{
+			Path:    "/prefix/admin/users",
+			Headers: map[string]string{"Host": "admin.example.com"},
+		})
+		a.Exactly("admin users", res.Body)
+
+		desc(t, 4, "respond with a 404 when no host matches")
+		res = invoke(events.APIGatewayProxyRequest{
+			Path:    "/prefix/admin/users",
+			Headers: map[string]string{"Host": "api.example.com"},
+		})
+		a.Exactly(http.StatusNotFound, res.StatusCode)
+		res = invoke(events.APIGatewayProxyRequest{
+			Path:    "/prefix/users",
+			Headers: map[string]string{"Host": "example.com"},
+		})
+		a.Exactly(http.StatusNotFound, res.StatusCode)
+
+		desc(t, 4, "describe the host in the conditions of routes")
+		a.Exactly([]Route{
+			{Method: "GET", Path: "/prefix/admin/users", Conditions: []string{"host=admin.example.com"}},
+			{Method: "GET", Path: "/prefix/reports"},
+			{Method: "GET", Path: "/prefix/reports", Conditions: []string{"header:X-Api-Version=2023-06-01"}},
+			{Method: "GET", Path: "/prefix/reports", Conditions: []string{"header:X-Beta"}},
+			{Method: "GET", Path: "/prefix/users", Conditions: []string{"host=*.example.com"}},
+			{Method: "GET", Path: "/prefix/users", Conditions: []string{"host=api.example.com"}},
+			{Method: "POST", Path: "/prefix/orders", Conditions: []string{"content-type=application/json"}},
+			{Method: "POST", Path: "/prefix/orders",
+				Conditions: []string{"content-type=application/x-www-form-urlencoded,multipart/*"}},
+			{Method: "POST", Path: "/prefix/webhook", Conditions: []string{"query:action=cancel"}},
+			{Method: "POST", Path: "/prefix/webhook", Conditions: []string{"query:action=resend"}},
+			{Method: "POST", Path: "/prefix/webhook", Conditions: []string{"query:test"}},
+		}, r.Routes())
+
+		desc(t, 4, "panic when a route with the same host already exists")
+		a.Panics(func() {
+			r.Get("users", respond("again"), WithHost("API.example.com"))
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package lambdarouter
+
+import (
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// WithPriority gives a route a priority, for routes whose templates overlap, such as /{org}/repos
+// and /users/{id} for the path /users/repos. Routes are otherwise matched segment by segment,
+// preferring a static segment to a parameter and a parameter to a greedy or wildcard parameter, so
+// /users/{id} would be matched as its first segment is static. A route given a priority is matched
+// in preference to every route without one whose template also matches the path, and to those
+// with a lower priority, whatever their segments, as long as its predicates, such as those of
+// WithHost, match the request.
+func WithPriority(n int) RouteOption {
+	return func(e *event) {
+		e.priority, e.prioritized = n, true
+	}
+}
+
+// prioritize records the priority of e, which was added to g, in the table.
+func (t *routeTable) prioritize(g *eventGroup, e event) {
+	if !e.prioritized {
+		return
+	}
+
+	if !g.prioritized {
+		g.prioritized, g.priority = true, e.priority
+		t.prioritized = append(t.prioritized[:len(t.prioritized):len(t.prioritized)], g)
+	} else if e.priority > g.priority {
+		g.priority = e.priority
+	}
+}
+
+// unprioritize removes g from the groups with a priority, if it is among them.
+func (t *routeTable) unprioritize(g *eventGroup) {
+	prioritized := make([]*eventGroup, 0, len(t.prioritized))
+	for _, pg := range t.prioritized {
+		if pg != g {
+			prioritized = append(prioritized, pg)
+		}
+	}
+
+	t.prioritized = prioritized
+}
+
+// prioritizedMatches returns the matches of the groups of routes with a priority other than g whose
+// templates match method and path, in the order they were given a priority.
+func (t *routeTable) prioritizedMatches(method, path string, g *eventGroup) []groupMatch {
+	_, fold := t.matcher.(caseInsensitiveMatcher)
+
+	var matches []groupMatch
+	for _, pg := range t.prioritized {
+		if pg == g || pg.events[0].rt.Method != method {
+			continue
+		}
+
+		for _, template := range matcherTemplates(pg.events[0].rt.Path) {
+			if matchesTemplate(template, path, fold) {
+				matches = append(matches, pg.match(templateParams(template, path)))
+			}
+		}
+	}
+
+	return matches
+}
+
+// prefer returns the match among prioritized which should be routed to in place of m, the match the
+// matcher found, or m if there is none. A group is only preferred if one of its routes has
+// predicates which match req, unless it is nil, so a request for another host, say, is still given
+// the route the matcher found. It is called once the table is unlocked, as predicates may lock it.
+func prefer(m groupMatch, prioritized []groupMatch, req *events.APIGatewayProxyRequest) groupMatch {
+	for _, pm := range prioritized {
+		if pm.group == m.group || m.prioritized && pm.priority <= m.priority {
+			continue
+		}
+
+		if req != nil {
+			preq := *req
+			preq.PathParameters = pm.params
+			if _, status := selectEvent(pm.events, preq); status != 0 {
+				continue
+			}
+		}
+
+		m = pm
+	}
+
+	return m
+}
+
+// matchesTemplate reports whether path matches template, as the default matcher would match it,
+// comparing static segments regardless of case if fold is set.
+func matchesTemplate(template, path string, fold bool) bool {
+	path = strings.TrimSuffix(path, "/")
+	if template == "/" {
+		return path == ""
+	}
+
+	for template != "" {
+		if path == "" {
+			return false
+		}
+
+		var tseg, pseg string
+		tseg, template = nextSegment(template)
+		pseg, path = nextSegment(path)
+
+		switch {
+		case isParam(tseg) && strings.HasSuffix(tseg, "+}"):
+			return len(pseg) > 1
+		case isParam(tseg):
+			if len(pseg) < 2 {
+				return false
+			}
+		case tseg == pseg, fold && strings.EqualFold(tseg, pseg):
+		default:
+			return false
+		}
+	}
+
+	return path == ""
+}
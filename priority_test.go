@@ -0,0 +1,137 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithPriority(t *testing.T) {
+	a := assert.New(t)
+
+	respond := func(body string) lambda.Handler {
+		return lambda.NewHandler(func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+			params, _ := json.Marshal(req.PathParameters)
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: body + " " + string(params)}, nil
+		})
+	}
+
+	invoke := func(r Router, path string) string {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res.Body
+	}
+
+	desc(t, 0, "Initialize Router with overlapping routes and")
+	r := New("")
+	r.Get("files/{path...}", respond("wildcard"))
+	r.Get("{org}/repos", respond("repos"))
+	r.Get("users/{id}", respond("user"))
+	r.Get("users/me", respond("me"))
+
+	desc(t, 2, "Router should")
+	{
+		desc(t, 4, "prefer static segments to parameters, whatever the order of definition")
+		a.Exactly(`me null`, invoke(r, "/users/me"))
+		a.Exactly(`user {"id":"repos"}`, invoke(r, "/users/repos"))
+
+		desc(t, 4, "prefer parameters to wildcards")
+		r.Get("files/{name}", respond("file"))
+		a.Exactly(`file {"name":"a.txt"}`, invoke(r, "/files/a.txt"))
+		a.Exactly(`wildcard {"path":"a/b.txt"}`, invoke(r, "/files/a/b.txt"))
+	}
+
+	desc(t, 0, "Initialize Router with prioritized routes and")
+	r = New("", WithCaseInsensitive())
+	r.Get("users/{id}", respond("user"))
+	r.Get("{org}/repos", respond("repos"), WithPriority(1))
+	r.Get("{org}/{repo}", respond("repo"), WithPriority(2))
+	r.Get("users/me", respond("me"))
+
+	desc(t, 2, "WithPriority option should")
+	{
+		desc(t, 4, "prefer routes with the highest priority whose template matches")
+		a.Exactly(`repo {"org":"Users","repo":"repos"}`, invoke(r, "/Users/repos"))
+
+		desc(t, 4, "prefer routes with a priority to routes without one")
+		a.Exactly(`repo {"org":"users","repo":"me"}`, invoke(r, "/users/me"))
+		a.Exactly(`repo {"org":"users","repo":"42"}`, invoke(r, "/users/42/"))
+
+		desc(t, 4, "stop preferring removed routes")
+		a.NoError(r.Remove(http.MethodGet, "{org}/{repo}"))
+		a.Exactly(`repos {"org":"users"}`, invoke(r, "/users/repos"))
+		a.Exactly(`me null`, invoke(r, "/users/me"))
+	}
+
+	desc(t, 0, "Initialize Router with prioritized routes with predicates and")
+	r = New("")
+	r.Get("users/{id}", respond("user"))
+	r.Get("{org}/repos", respond("repos"), WithPriority(1), WithHost("git.example.com"))
+
+	invokeHost := func(host, path string) string {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodGet,
+			Path:       path,
+			Headers:    map[string]string{"Host": host},
+		})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res.Body
+	}
+
+	desc(t, 2, "WithPriority option should")
+	{
+		desc(t, 4, "prefer routes whose predicates match the request")
+		a.Exactly(`repos {"org":"users"}`, invokeHost("git.example.com", "/users/repos"))
+
+		desc(t, 4, "not prefer routes whose predicates do not match the request")
+		a.Exactly(`user {"id":"repos"}`, invokeHost("api.example.com", "/users/repos"))
+
+		desc(t, 4, "evaluate predicates without holding the lock of the routes")
+		defined := 0
+		flags := FlagProviderFunc(func(name string, req events.APIGatewayProxyRequest) bool {
+			// Defining a route waits for every lock of the routes to be released.
+			defined++
+			a.NoError(r.TryGet(fmt.Sprintf("defined/%d", defined), respond("defined")))
+			return true
+		})
+		r = New("", WithFlags(flags), WithTenancy(TenantFromHeader("X-Tenant")))
+		r.Get("users/{id}", respond("user"))
+		r.Get("{org}/repos", respond("repos"), WithPriority(1), WithFeatureFlag("x"), WithTenants("acme"))
+
+		done := make(chan string)
+		go func() {
+			payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodGet,
+				Path:       "/users/repos",
+				Headers:    map[string]string{"X-Tenant": "acme"},
+			})
+			resjson, _ := r.Invoke(context.Background(), payload)
+
+			var res events.APIGatewayProxyResponse
+			_ = json.Unmarshal(resjson, &res)
+			done <- res.Body
+		}()
+
+		select {
+		case body := <-done:
+			a.Exactly(`repos {"org":"users"}`, body)
+		case <-time.After(time.Second):
+			a.Fail("routing deadlocked")
+		}
+	}
+}
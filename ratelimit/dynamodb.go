@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mitchell/lambdarouter/internal/dynamo"
+)
+
+// dynamoAttempts is how many times a bucket is read and written before giving up, when other
+// containers write it at the same time.
+const dynamoAttempts = 3
+
+// DynamoDBStore is a Store which holds buckets in a DynamoDB table, so that a limit applies across
+// every container of a function. The table must have a string partition key named pk. Each item
+// has a ttl attribute holding the time its bucket will be full again, after which it can be
+// deleted by enabling time to live on the table.
+type DynamoDBStore struct {
+	table  string
+	client *dynamo.Client
+}
+
+// NewDynamoDBStore returns a store holding buckets in table. Requests to DynamoDB are signed with
+// the credentials of the execution role of the function, in the region it runs in.
+func NewDynamoDBStore(table string) *DynamoDBStore {
+	return &DynamoDBStore{table: table, client: dynamo.FromEnv()}
+}
+
+// Take implements the Store interface for the DynamoDBStore type. Tokens are counted in
+// thousandths, and buckets are written conditionally on not having changed since they were read.
+func (s *DynamoDBStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (bool, time.Duration, error) {
+	for attempt := 0; attempt < dynamoAttempts; attempt++ {
+		item, err := s.client.GetItem(ctx, s.table, dynamo.Item{"pk": dynamo.S(key)})
+		if err != nil {
+			return false, 0, err
+		}
+
+		tokens, updated := limit.capacity(), now
+		if item != nil {
+			tokens = float64(item["tokens"].Int()) / 1000
+			updated = time.UnixMilli(item["updated"].Int())
+		}
+		tokens = limit.refill(tokens, updated, now)
+
+		if tokens < 1 {
+			return false, limit.wait(tokens), nil
+		}
+		tokens--
+
+		full := now.Add(limit.untilFull(tokens))
+		next := dynamo.Item{
+			"pk":      dynamo.S(key),
+			"tokens":  dynamo.N(int64(tokens * 1000)),
+			"updated": dynamo.N(now.UnixMilli()),
+			"ttl":     dynamo.N(full.Unix() + 1),
+		}
+
+		if item == nil {
+			err = s.client.PutItem(ctx, s.table, next, "attribute_not_exists(pk)", nil, nil)
+		} else {
+			err = s.client.PutItem(ctx, s.table, next, "#updated = :updated",
+				map[string]string{"#updated": "updated"}, dynamo.Item{":updated": item["updated"]})
+		}
+		if err == nil {
+			return true, 0, nil
+		}
+		if !dynamo.IsConditionFailed(err) {
+			return false, 0, err
+		}
+	}
+
+	return false, 0, errors.New("ratelimit: bucket " + key + " is contended")
+}
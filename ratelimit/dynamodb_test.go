@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mitchell/lambdarouter/internal/dynamotest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamoDBStore(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a fake DynamoDB and")
+	srv := dynamotest.NewServer()
+	defer srv.Close()
+
+	s := NewDynamoDBStore("buckets")
+	s.client = srv.Client()
+
+	ctx := context.Background()
+	limit := Limit{Requests: 2, Per: time.Minute}
+	now := time.Now().Truncate(time.Millisecond)
+
+	desc(t, 2, "Take method should")
+	{
+		desc(t, 4, "take tokens from the bucket in the table")
+		for i := 0; i < 2; i++ {
+			allowed, _, err := s.Take(ctx, "ip:192.0.2.1", limit, now)
+			a.NoError(err)
+			a.True(allowed)
+		}
+
+		item := srv.Items("buckets")["ip:192.0.2.1"]
+		a.Exactly(int64(0), item["tokens"].Int())
+		a.Exactly(now.Add(time.Minute).Unix()+1, item["ttl"].Int())
+
+		desc(t, 4, "refuse requests when the bucket is empty")
+		allowed, retryAfter, err := s.Take(ctx, "ip:192.0.2.1", limit, now)
+		a.NoError(err)
+		a.False(allowed)
+		a.Exactly(30*time.Second, retryAfter)
+
+		desc(t, 4, "refill the bucket over time")
+		allowed, _, err = s.Take(ctx, "ip:192.0.2.1", limit, now.Add(30*time.Second))
+		a.NoError(err)
+		a.True(allowed)
+	}
+}
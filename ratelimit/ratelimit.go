@@ -0,0 +1,222 @@
+// Package ratelimit provides middleware which limits the rate of requests to routes with token
+// buckets, keyed by API key, client address, or any other property of the request, and responds to
+// requests over the limit with a 429.
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+)
+
+// Limit is the rate a token bucket is refilled at, and how many tokens it holds. Each request
+// takes one token from the bucket of its key, so a key may make Burst requests at once, and
+// Requests requests every Per on average.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+
+	// Burst is the capacity of the bucket. If zero, it is Requests.
+	Burst int
+}
+
+func (l Limit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.Requests)
+}
+
+// refill returns the tokens a bucket holding tokens at updated holds at now.
+func (l Limit) refill(tokens float64, updated, now time.Time) float64 {
+	if elapsed := now.Sub(updated); elapsed > 0 && l.Per > 0 {
+		tokens += float64(l.Requests) * float64(elapsed) / float64(l.Per)
+	}
+
+	return math.Min(tokens, l.capacity())
+}
+
+// wait returns how long a bucket holding tokens takes to hold a whole token.
+func (l Limit) wait(tokens float64) time.Duration {
+	if l.Requests <= 0 {
+		return l.Per
+	}
+
+	return time.Duration((1 - tokens) * float64(l.Per) / float64(l.Requests))
+}
+
+// untilFull returns how long a bucket holding tokens takes to fill.
+func (l Limit) untilFull(tokens float64) time.Duration {
+	if l.Requests <= 0 {
+		return l.Per
+	}
+
+	return time.Duration((l.capacity() - tokens) * float64(l.Per) / float64(l.Requests))
+}
+
+// Store holds the token buckets of a limiter.
+type Store interface {
+	// Take removes a token from the bucket of key, which is refilled according to limit. It
+	// reports whether a token was available, and if not, how long until one will be.
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (ok bool, retryAfter time.Duration, err error)
+}
+
+// MemoryStore is a Store which holds buckets in the memory of the container, so each concurrent
+// execution environment of a function limits requests separately.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]bucket
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]bucket{}}
+}
+
+// Take implements the Store interface for the MemoryStore type.
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = bucket{tokens: limit.capacity(), updated: now}
+	}
+	b.tokens, b.updated = limit.refill(b.tokens, b.updated, now), now
+
+	if b.tokens < 1 {
+		s.buckets[key] = b
+		return false, limit.wait(b.tokens), nil
+	}
+
+	b.tokens--
+	s.buckets[key] = b
+
+	// Full buckets hold nothing worth remembering, and forgetting them bounds the size of the
+	// store by the number of recently active keys.
+	for k, other := range s.buckets {
+		if limit.refill(other.tokens, other.updated, now) >= limit.capacity() {
+			delete(s.buckets, k)
+		}
+		break
+	}
+
+	return true, 0, nil
+}
+
+// KeyFunc returns the key of the bucket a request takes tokens from. Requests for which it
+// reports false are not limited.
+type KeyFunc func(ctx context.Context, req events.APIGatewayProxyRequest) (string, bool)
+
+// ByIP keys requests by the source IP of their request context.
+func ByIP(_ context.Context, req events.APIGatewayProxyRequest) (string, bool) {
+	ip := req.RequestContext.Identity.SourceIP
+	return "ip:" + ip, ip != ""
+}
+
+// ByAPIKey keys requests by their API key, taken from the x-api-key header or the identity of
+// their request context. Keys are hashed, so they are not stored in plain text.
+func ByAPIKey(_ context.Context, req events.APIGatewayProxyRequest) (string, bool) {
+	key := req.RequestContext.Identity.APIKey
+	for name, value := range req.Headers {
+		if strings.EqualFold(name, "X-Api-Key") {
+			key = value
+		}
+	}
+	if key == "" {
+		return "", false
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	return "key:" + hex.EncodeToString(sum[:]), true
+}
+
+// Config configures the rate limiting middleware.
+type Config struct {
+	// Limit is the rate requests of each key are limited to.
+	Limit Limit
+
+	// Key returns the key of each request. If nil, ByIP is used.
+	Key KeyFunc
+
+	// Store holds the buckets. If nil, a MemoryStore is used.
+	Store Store
+
+	// Fallback holds the buckets when Store returns an error, such as when DynamoDB is throttling
+	// or unavailable, so requests are still limited per container rather than failing. If nil, a
+	// MemoryStore is used.
+	Fallback Store
+}
+
+// Middleware returns middleware which limits the rate of requests to routes as configured by cfg.
+// Requests over the limit are responded to with a 429 whose Retry-After header gives the number of
+// seconds until the request would be allowed, and the handler is not invoked.
+func Middleware(cfg Config) lambdarouter.Middleware {
+	if cfg.Key == nil {
+		cfg.Key = ByIP
+	}
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryStore()
+	}
+	if cfg.Fallback == nil {
+		cfg.Fallback = NewMemoryStore()
+	}
+
+	return func(next lambda.Handler) lambda.Handler {
+		return limiter{cfg: cfg, next: next}
+	}
+}
+
+type limiter struct {
+	cfg  Config
+	next lambda.Handler
+}
+
+func (l limiter) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := lambdarouter.RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := l.cfg.Key(ctx, req)
+	if !ok {
+		return l.next.Invoke(ctx, payload)
+	}
+
+	now := time.Now()
+	allowed, retryAfter, err := l.cfg.Store.Take(ctx, key, l.cfg.Limit, now)
+	if err != nil {
+		if allowed, retryAfter, err = l.cfg.Fallback.Take(ctx, key, l.cfg.Limit, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if !allowed {
+		seconds := int(math.Ceil(retryAfter.Seconds()))
+		if seconds < 1 {
+			seconds = 1
+		}
+
+		return nil, &lambdarouter.HTTPError{
+			Status:  http.StatusTooManyRequests,
+			Headers: map[string]string{"Retry-After": strconv.Itoa(seconds)},
+		}
+	}
+
+	return l.next.Invoke(ctx, payload)
+}
@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Limit, time.Time) (bool, time.Duration, error) {
+	return false, 0, errors.New("unavailable")
+}
+
+func TestMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with rate limits and")
+	ok := lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	})
+
+	r := lambdarouter.New("prefix")
+	r.Get("ip", ok, lambdarouter.WithMiddleware(Middleware(Config{Limit: Limit{Requests: 2, Per: time.Hour}})))
+	r.Get("key", ok, lambdarouter.WithMiddleware(Middleware(Config{
+		Limit: Limit{Requests: 1, Per: time.Minute},
+		Key:   ByAPIKey,
+		Store: failingStore{},
+	})))
+
+	invoke := func(path, ip, key string) events.APIGatewayProxyResponse {
+		req := events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path}
+		req.RequestContext.Identity.SourceIP = ip
+		req.RequestContext.Identity.APIKey = key
+		payload, _ := json.Marshal(req)
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "Middleware should")
+	{
+		desc(t, 4, "allow requests within the limit")
+		a.Exactly(http.StatusOK, invoke("/prefix/ip", "192.0.2.1", "").StatusCode)
+		a.Exactly(http.StatusOK, invoke("/prefix/ip", "192.0.2.1", "").StatusCode)
+
+		desc(t, 4, "respond with a 429 and Retry-After over the limit")
+		res := invoke("/prefix/ip", "192.0.2.1", "")
+		a.Exactly(http.StatusTooManyRequests, res.StatusCode)
+		a.Exactly("1800", res.Headers["Retry-After"])
+
+		desc(t, 4, "limit each key separately")
+		a.Exactly(http.StatusOK, invoke("/prefix/ip", "192.0.2.2", "").StatusCode)
+
+		desc(t, 4, "fall back to memory when the store fails")
+		a.Exactly(http.StatusOK, invoke("/prefix/key", "", "k1").StatusCode)
+		a.Exactly(http.StatusTooManyRequests, invoke("/prefix/key", "", "k1").StatusCode)
+
+		desc(t, 4, "not limit requests without a key")
+		a.Exactly(http.StatusOK, invoke("/prefix/key", "", "").StatusCode)
+		a.Exactly(http.StatusOK, invoke("/prefix/key", "", "").StatusCode)
+	}
+
+	desc(t, 2, "MemoryStore type should")
+	{
+		s := NewMemoryStore()
+		limit := Limit{Requests: 1, Per: time.Second, Burst: 2}
+		now := time.Now()
+
+		desc(t, 4, "allow bursts up to the capacity of the bucket")
+		for i := 0; i < 2; i++ {
+			allowed, _, err := s.Take(context.Background(), "k", limit, now)
+			a.NoError(err)
+			a.True(allowed)
+		}
+		allowed, retryAfter, _ := s.Take(context.Background(), "k", limit, now)
+		a.False(allowed)
+		a.Exactly(time.Second, retryAfter)
+
+		desc(t, 4, "refill buckets over time")
+		allowed, _, _ = s.Take(context.Background(), "k", limit, now.Add(time.Second))
+		a.True(allowed)
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
@@ -0,0 +1,72 @@
+package lambdarouter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/mitchell/lambdarouter/respond"
+)
+
+// Redirect defines a route which redirects requests for the path from to the path to, with the
+// given status, so path migrations and vanity URLs can be expressed in the router. Both paths are
+// relative to the prefix of the router, as they are for Get, unless to is an absolute URL such as
+// "https://example.com/docs". Parameters of from, such as {id}, are substituted into to wherever
+// it names them, and the query string of the request is kept.
+//
+// Redirects with a 301, 302, or 303 are defined for GET requests. As clients repeat the method of
+// the request when redirected with a 307 or 308, those are defined for POST, PUT, PATCH, and
+// DELETE requests as well. Redirect panics if status is not one of these.
+func (r *Router) Redirect(from, to string, status int, opts ...RouteOption) {
+	methods := []string{http.MethodGet}
+
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther:
+	case http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		methods = append(methods, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete)
+	default:
+		panic(fmt.Sprintf("invalid redirect status %d", status))
+	}
+
+	if !strings.Contains(to, "://") {
+		to = r.prefix + strings.TrimPrefix(to, "/")
+	}
+
+	h := HandlerFunc(func(_ context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return respond.Redirect(status, redirectLocation(to, req)), nil
+	})
+
+	for _, method := range methods {
+		r.Handle(method, from, h, opts...)
+	}
+}
+
+// redirectLocation returns the location req is redirected to by a redirect to target.
+func redirectLocation(target string, req events.APIGatewayProxyRequest) string {
+	for name, value := range req.PathParameters {
+		target = strings.ReplaceAll(target, "{"+name+"}", value)
+		target = strings.ReplaceAll(target, "{"+name+"+}", value)
+		target = strings.ReplaceAll(target, "{"+name+"...}", value)
+	}
+
+	query := url.Values(req.MultiValueQueryStringParameters)
+	if len(query) == 0 && len(req.QueryStringParameters) > 0 {
+		query = url.Values{}
+		for name, value := range req.QueryStringParameters {
+			query.Set(name, value)
+		}
+	}
+	if len(query) == 0 {
+		return target
+	}
+
+	sep := "?"
+	if strings.Contains(target, "?") {
+		sep = "&"
+	}
+
+	return target + sep + query.Encode()
+}
@@ -0,0 +1,55 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedirect(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with redirects and")
+	r := New("prefix")
+	r.Redirect("old/users/{id}", "users/{id}", http.StatusMovedPermanently)
+	r.Redirect("docs", "https://docs.example.com/?ref=api", http.StatusFound)
+	r.Redirect("v1/orders", "v2/orders", http.StatusPermanentRedirect)
+
+	invoke := func(method, path string, query map[string]string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: method, Path: path, QueryStringParameters: query})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "Redirect method should")
+	{
+		desc(t, 4, "redirect to the target, substituting path parameters")
+		res := invoke(http.MethodGet, "/prefix/old/users/42", nil)
+		a.Exactly(http.StatusMovedPermanently, res.StatusCode)
+		a.Exactly("/prefix/users/42", res.Headers["Location"])
+
+		desc(t, 4, "keep the query string")
+		res = invoke(http.MethodGet, "/prefix/docs", map[string]string{"page": "2"})
+		a.Exactly(http.StatusFound, res.StatusCode)
+		a.Exactly("https://docs.example.com/?ref=api&page=2", res.Headers["Location"])
+
+		desc(t, 4, "define method preserving redirects for every method")
+		res = invoke(http.MethodPost, "/prefix/v1/orders", nil)
+		a.Exactly(http.StatusPermanentRedirect, res.StatusCode)
+		a.Exactly("/prefix/v2/orders", res.Headers["Location"])
+
+		res = invoke(http.MethodPost, "/prefix/old/users/42", nil)
+		a.Exactly(http.StatusMethodNotAllowed, res.StatusCode)
+
+		desc(t, 4, "panic on statuses which are not redirects")
+		a.Panics(func() { r.Redirect("a", "b", http.StatusOK) })
+	}
+}
@@ -0,0 +1,184 @@
+// Package requestid provides middleware which gives every request an ID and a correlation ID,
+// taken from its headers or generated, so that the logs of middleware, handlers, and the services
+// they call can be tied to each other and to the request ID of API Gateway.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/mitchell/lambdarouter/internal/header"
+)
+
+// Config configures the request ID middleware.
+type Config struct {
+	// Header is the header carrying the ID of the request. If empty, it is X-Request-Id.
+	Header string
+
+	// CorrelationHeader is the header carrying the ID which correlates the request with those made
+	// on behalf of the same operation, such as by the service which called this one. If empty, it
+	// is X-Correlation-Id.
+	CorrelationHeader string
+
+	// Generate returns new IDs. If nil, IDs are random version 4 UUIDs.
+	Generate func() string
+}
+
+type contextKey int
+
+const idsKey contextKey = iota
+
+type ids struct {
+	request, correlation string
+}
+
+// maxLength is the length of the longest ID taken from a request. Longer IDs, and those holding
+// characters other than printable ASCII, are replaced, so that clients cannot forge lines of logs.
+const maxLength = 128
+
+// Middleware returns middleware which gives each request of the routes it wraps a request ID and a
+// correlation ID, retrieved by RequestID and CorrelationID, and sets both headers of the response,
+// including those rendered from HTTPErrors, to them. The request ID is taken from the request
+// header, or is the ID API Gateway gave the request, or is generated. The correlation ID is taken
+// from its header, or is the request ID, so a request which starts an operation names it.
+func Middleware(cfg Config) lambdarouter.Middleware {
+	if cfg.Header == "" {
+		cfg.Header = "X-Request-Id"
+	}
+	if cfg.CorrelationHeader == "" {
+		cfg.CorrelationHeader = "X-Correlation-Id"
+	}
+	if cfg.Generate == nil {
+		cfg.Generate = newUUID
+	}
+
+	return func(next lambda.Handler) lambda.Handler {
+		return identifier{cfg: cfg, next: next}
+	}
+}
+
+type identifier struct {
+	cfg  Config
+	next lambda.Handler
+}
+
+func (i identifier) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	codec := lambdarouter.CodecFrom(ctx)
+
+	req, err := lambdarouter.RequestFrom(ctx, payload)
+	if err != nil {
+		return i.next.Invoke(ctx, payload)
+	}
+
+	id := idHeader(req, i.cfg.Header)
+	if id == "" {
+		id = req.RequestContext.RequestID
+	}
+	if id == "" {
+		id = i.cfg.Generate()
+	}
+
+	correlation := idHeader(req, i.cfg.CorrelationHeader)
+	if correlation == "" {
+		correlation = id
+	}
+
+	resjson, err := i.next.Invoke(context.WithValue(ctx, idsKey, ids{request: id, correlation: correlation}), payload)
+
+	headers := map[string]string{i.cfg.Header: id, i.cfg.CorrelationHeader: correlation}
+
+	// HTTPErrors are rendered by the router once they reach it, so the headers are added to the
+	// error rather than to a response.
+	var httpErr *lambdarouter.HTTPError
+	if errors.As(err, &httpErr) {
+		errHeaders := map[string]string{}
+		for name, value := range httpErr.Headers {
+			errHeaders[name] = value
+		}
+		setHeaders(errHeaders, nil, headers)
+
+		withHeaders := *httpErr
+		withHeaders.Headers = errHeaders
+		return nil, &withHeaders
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var res events.APIGatewayProxyResponse
+	if err := codec.Unmarshal(resjson, &res); err != nil {
+		return resjson, nil
+	}
+
+	if res.Headers == nil {
+		res.Headers = map[string]string{}
+	}
+	setHeaders(res.Headers, res.MultiValueHeaders, headers)
+
+	return codec.Marshal(res)
+}
+
+// RequestID returns the ID of the current request, or the empty string if it was not given one by
+// Middleware.
+func RequestID(ctx context.Context) string {
+	v, _ := ctx.Value(idsKey).(ids)
+	return v.request
+}
+
+// CorrelationID returns the correlation ID of the current request, or the empty string if it was
+// not given one by Middleware. Handlers should send it with the requests they make to other
+// services.
+func CorrelationID(ctx context.Context) string {
+	v, _ := ctx.Value(idsKey).(ids)
+	return v.correlation
+}
+
+// idHeader returns the first value of the named header of req, regardless of the case of its
+// name, or the empty string if it is not a valid ID.
+func idHeader(req events.APIGatewayProxyRequest, name string) string {
+	value := strings.TrimSpace(header.Get(req, name))
+	if len(value) > maxLength {
+		return ""
+	}
+	for _, c := range value {
+		if c < ' ' || c > '~' {
+			return ""
+		}
+	}
+
+	return value
+}
+
+// setHeaders sets the headers of dst, replacing those in dst or multi regardless of the case of
+// their names.
+func setHeaders(dst map[string]string, multi map[string][]string, headers map[string]string) {
+	for name, value := range headers {
+		for key := range dst {
+			if strings.EqualFold(key, name) {
+				delete(dst, key)
+			}
+		}
+		for key := range multi {
+			if strings.EqualFold(key, name) {
+				delete(multi, key)
+			}
+		}
+		dst[name] = value
+	}
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	s := hex.EncodeToString(b)
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
@@ -0,0 +1,94 @@
+package requestid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with request IDs and")
+	var seen [2]string
+	r := lambdarouter.New("prefix")
+	r.Use(Middleware(Config{}))
+	r.Get("api", lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+		seen = [2]string{RequestID(ctx), CorrelationID(ctx)}
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusOK,
+			Headers:    map[string]string{"x-request-id": "stale"},
+		}, nil
+	}))
+	r.Get("teapot", lambda.NewHandler(func() error {
+		return &lambdarouter.HTTPError{Status: http.StatusTeapot}
+	}))
+
+	invoke := func(req events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+		req.HTTPMethod = http.MethodGet
+		payload, _ := json.Marshal(req)
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "Middleware should")
+	{
+		desc(t, 4, "take the IDs from the headers of the request and echo them")
+		res := invoke(events.APIGatewayProxyRequest{
+			Path:    "/prefix/api",
+			Headers: map[string]string{"x-request-id": "abc", "X-Correlation-ID": "op-1"},
+		})
+		a.Exactly([2]string{"abc", "op-1"}, seen)
+		a.Exactly(map[string]string{"X-Request-Id": "abc", "X-Correlation-Id": "op-1"}, res.Headers)
+
+		desc(t, 4, "use the request ID of API Gateway and correlate with it")
+		req := events.APIGatewayProxyRequest{Path: "/prefix/api"}
+		req.RequestContext.RequestID = "apigw-1"
+		invoke(req)
+		a.Exactly([2]string{"apigw-1", "apigw-1"}, seen)
+
+		desc(t, 4, "generate IDs for requests without one, and replace invalid ones")
+		invoke(events.APIGatewayProxyRequest{
+			Path:    "/prefix/api",
+			Headers: map[string]string{"X-Request-Id": "forged\nline"},
+		})
+		a.Regexp(regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), seen[0])
+		a.Exactly(seen[0], seen[1])
+
+		desc(t, 4, "add the headers to error responses")
+		res = invoke(events.APIGatewayProxyRequest{
+			Path:    "/prefix/teapot",
+			Headers: map[string]string{"X-Request-Id": "abc"},
+		})
+		a.Exactly(http.StatusTeapot, res.StatusCode)
+		a.Exactly("abc", res.Headers["X-Request-Id"])
+		a.Exactly("abc", res.Headers["X-Correlation-Id"])
+	}
+
+	desc(t, 2, "RequestID and CorrelationID functions should")
+	{
+		desc(t, 4, "return the empty string outside of the middleware")
+		a.Exactly("", RequestID(context.Background()))
+		a.Exactly("", CorrelationID(context.Background()))
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
@@ -0,0 +1,87 @@
+// Package respond provides helpers which build correctly formed API Gateway proxy responses, so
+// handlers do not need to construct them field by field.
+package respond
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/mitchell/lambdarouter/internal/jsoncodec"
+)
+
+// JSON returns a response with the given status code whose body is v encoded as JSON. If v cannot
+// be encoded an internal server error response is returned instead.
+func JSON(status int, v interface{}) events.APIGatewayProxyResponse {
+	return JSONContext(context.Background(), status, v)
+}
+
+// JSONContext is JSON, encoding v with the codec of the router routing the invocation of ctx
+// rather than encoding/json.
+func JSONContext(ctx context.Context, status int, v interface{}) events.APIGatewayProxyResponse {
+	body, err := jsoncodec.Marshal(ctx, v)
+	if err != nil {
+		return ErrorContext(ctx, http.StatusInternalServerError, err)
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: status,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       string(body),
+	}
+}
+
+// Text returns a response with the given status code whose body is the plain text s.
+func Text(status int, s string) events.APIGatewayProxyResponse {
+	return events.APIGatewayProxyResponse{
+		StatusCode: status,
+		Headers:    map[string]string{"Content-Type": "text/plain; charset=utf-8"},
+		Body:       s,
+	}
+}
+
+// Binary returns a response with the given status code and content type whose body is b. The body
+// is base64 encoded, as API Gateway requires for binary media types.
+func Binary(status int, contentType string, b []byte) events.APIGatewayProxyResponse {
+	return events.APIGatewayProxyResponse{
+		StatusCode:      status,
+		Headers:         map[string]string{"Content-Type": contentType},
+		Body:            base64.StdEncoding.EncodeToString(b),
+		IsBase64Encoded: true,
+	}
+}
+
+// NoContent returns an empty response with a 204 status code.
+func NoContent() events.APIGatewayProxyResponse {
+	return events.APIGatewayProxyResponse{
+		StatusCode: http.StatusNoContent,
+	}
+}
+
+// Redirect returns a response with the given status code, which should be one of the 3xx codes,
+// redirecting the client to location.
+func Redirect(status int, location string) events.APIGatewayProxyResponse {
+	return events.APIGatewayProxyResponse{
+		StatusCode: status,
+		Headers:    map[string]string{"Location": location},
+	}
+}
+
+// Error returns a response with the given status code whose body is a JSON object describing err,
+// in the form {"message": "..."}.
+func Error(status int, err error) events.APIGatewayProxyResponse {
+	return ErrorContext(context.Background(), status, err)
+}
+
+// ErrorContext is Error, encoding the body with the codec of the router routing the invocation of
+// ctx rather than encoding/json.
+func ErrorContext(ctx context.Context, status int, err error) events.APIGatewayProxyResponse {
+	body, _ := jsoncodec.Marshal(ctx, map[string]string{"message": err.Error()})
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: status,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       string(body),
+	}
+}
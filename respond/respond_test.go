@@ -0,0 +1,101 @@
+package respond
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/mitchell/lambdarouter/internal/jsoncodec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRespond(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "JSON function should")
+	{
+		desc(t, 2, "encode the value as the body")
+		res := JSON(http.StatusOK, map[string]int{"a": 1})
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("application/json", res.Headers["Content-Type"])
+		a.Exactly(`{"a":1}`, res.Body)
+
+		desc(t, 2, "return an internal server error when the value cannot be encoded")
+		res = JSON(http.StatusOK, math.Inf(1))
+		a.Exactly(http.StatusInternalServerError, res.StatusCode)
+	}
+
+	desc(t, 0, "Text function should")
+	{
+		desc(t, 2, "set a plain text content type")
+		res := Text(http.StatusAccepted, "hello")
+		a.Exactly(http.StatusAccepted, res.StatusCode)
+		a.Exactly("text/plain; charset=utf-8", res.Headers["Content-Type"])
+		a.Exactly("hello", res.Body)
+	}
+
+	desc(t, 0, "Binary function should")
+	{
+		desc(t, 2, "base64 encode the body")
+		res := Binary(http.StatusOK, "image/png", []byte{0x89, 'P', 'N', 'G'})
+		a.True(res.IsBase64Encoded)
+		a.Exactly(base64.StdEncoding.EncodeToString([]byte{0x89, 'P', 'N', 'G'}), res.Body)
+	}
+
+	desc(t, 0, "NoContent function should")
+	{
+		desc(t, 2, "return an empty 204 response")
+		a.Exactly(http.StatusNoContent, NoContent().StatusCode)
+		a.Empty(NoContent().Body)
+	}
+
+	desc(t, 0, "Redirect function should")
+	{
+		desc(t, 2, "return a response with a Location header")
+		res := Redirect(http.StatusFound, "/new")
+		a.Exactly(http.StatusFound, res.StatusCode)
+		a.Exactly("/new", res.Headers["Location"])
+	}
+
+	desc(t, 0, "Error function should")
+	{
+		desc(t, 2, "describe the error in the body")
+		res := Error(http.StatusBadRequest, errors.New("bad"))
+		a.Exactly(http.StatusBadRequest, res.StatusCode)
+		a.Exactly(`{"message":"bad"}`, res.Body)
+	}
+
+	desc(t, 0, "JSONContext and ErrorContext functions should")
+	{
+		desc(t, 2, "encode the body with the codec of the context")
+		ctx := jsoncodec.NewContext(context.Background(), upperCodec{})
+		a.Exactly(`{"A":1}`, JSONContext(ctx, http.StatusOK, map[string]int{"a": 1}).Body)
+		a.Exactly(`{"MESSAGE":"BAD"}`, ErrorContext(ctx, http.StatusBadRequest, errors.New("bad")).Body)
+	}
+}
+
+// upperCodec is a codec which encodes JSON in upper case.
+type upperCodec struct{}
+
+func (upperCodec) Marshal(v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	return bytes.ToUpper(b), err
+}
+
+func (upperCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
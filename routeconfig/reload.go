@@ -0,0 +1,180 @@
+package routeconfig
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mitchell/lambdarouter"
+)
+
+// Source provides the current version of a config document.
+type Source interface {
+	Fetch(ctx context.Context) ([]byte, error)
+}
+
+// SourceFunc adapts a function into a Source. It allows documents to be read from anywhere, such
+// as an S3 object:
+//
+//	src := routeconfig.SourceFunc(func(ctx context.Context) ([]byte, error) {
+//		out, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: &bucket, Key: &key})
+//		if err != nil {
+//			return nil, err
+//		}
+//		defer out.Body.Close()
+//
+//		return io.ReadAll(out.Body)
+//	})
+type SourceFunc func(ctx context.Context) ([]byte, error)
+
+// Fetch implements the Source interface for the SourceFunc type.
+func (f SourceFunc) Fetch(ctx context.Context) ([]byte, error) {
+	return f(ctx)
+}
+
+// AppConfig returns a Source which reads a configuration profile of AWS AppConfig through the
+// AppConfig Lambda extension, which must be added to the function as a layer. The extension caches
+// and polls the configuration itself, so fetching it is cheap.
+func AppConfig(application, environment, profile string) Source {
+	port := os.Getenv("AWS_APPCONFIG_EXTENSION_HTTP_PORT")
+	if port == "" {
+		port = "2772"
+	}
+
+	return appConfigSource{url: fmt.Sprintf("http://localhost:%s/applications/%s/environments/%s/configurations/%s",
+		port, url.PathEscape(application), url.PathEscape(environment), url.PathEscape(profile))}
+}
+
+type appConfigSource struct {
+	url string
+}
+
+func (s appConfigSource) Fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("appconfig extension responded with %s", res.Status)
+	}
+
+	return io.ReadAll(res.Body)
+}
+
+// ControlEvent is the payload which makes a Reloader reload its document immediately when the
+// function is invoked with it directly, such as by a deployment pipeline or an EventBridge rule.
+type ControlEvent struct {
+	ReloadRoutes bool `json:"reloadRoutes"`
+}
+
+// Reloader keeps the routes of a router in line with a config document which may change while the
+// function is running. It is invoked in place of the router, and reloads the document when it is
+// older than the interval or when it receives a ControlEvent. The routes of the router are only
+// swapped when the document changes, and if the new document cannot be applied the previous routes
+// are kept.
+type Reloader struct {
+	// Interval is the time after which the document is reloaded. Reloads happen on invocations
+	// rather than in the background, as Lambda freezes functions between invocations. If zero, the
+	// document is only reloaded by control events.
+	Interval time.Duration
+
+	// Setup is called with every router built from a new version of the document, before its routes
+	// are defined, so middleware and routes defined in code can be added to it.
+	Setup func(r *lambdarouter.Router)
+
+	// OnError is called with any error encountered while reloading on an invocation, which does not
+	// otherwise fail the invocation.
+	OnError func(err error)
+
+	router lambdarouter.Router
+	src    Source
+	reg    HandlerRegistry
+
+	mu      sync.Mutex
+	doc     []byte
+	fetched time.Time
+}
+
+// NewReloader returns a Reloader which defines the routes of the document provided by src on r,
+// looking handlers up in reg. The document is first loaded by Reload or the first invocation.
+func NewReloader(r lambdarouter.Router, src Source, reg HandlerRegistry) *Reloader {
+	return &Reloader{router: r, src: src, reg: reg}
+}
+
+// Reload fetches the document and, if it changed, replaces the routes of the router with those
+// it describes.
+func (rl *Reloader) Reload(ctx context.Context) error {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	return rl.reload(ctx)
+}
+
+func (rl *Reloader) reload(ctx context.Context) error {
+	doc, err := rl.src.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	rl.fetched = time.Now()
+	if rl.doc != nil && bytes.Equal(doc, rl.doc) {
+		return nil
+	}
+
+	cfg, err := Parse(doc)
+	if err != nil {
+		return err
+	}
+
+	next := lambdarouter.New(rl.router.Prefix())
+	if rl.Setup != nil {
+		rl.Setup(&next)
+	}
+	if err := Apply(&next, cfg, rl.reg); err != nil {
+		return err
+	}
+
+	if err := rl.router.SwapRoutes(next); err != nil {
+		return err
+	}
+
+	rl.doc = doc
+	return nil
+}
+
+// Invoke implements the lambda.Handler interface for the Reloader type.
+func (rl *Reloader) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	var control ControlEvent
+	if bytes.Contains(payload, []byte(`"reloadRoutes"`)) &&
+		json.Unmarshal(payload, &control) == nil && control.ReloadRoutes {
+		if err := rl.Reload(ctx); err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(control)
+	}
+
+	rl.mu.Lock()
+	if rl.fetched.IsZero() || (rl.Interval > 0 && time.Since(rl.fetched) >= rl.Interval) {
+		if err := rl.reload(ctx); err != nil && rl.OnError != nil {
+			rl.OnError(err)
+		}
+	}
+	rl.mu.Unlock()
+
+	return rl.router.Invoke(ctx, payload)
+}
@@ -0,0 +1,105 @@
+package routeconfig
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloader(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router and")
+	r := lambdarouter.New("prefix")
+	reg := HandlerRegistry{
+		"ok": lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+		}),
+	}
+
+	doc := `routes: [{route: GET /v1, handler: ok}]`
+	fetches := 0
+	rl := NewReloader(r, SourceFunc(func(ctx context.Context) ([]byte, error) {
+		fetches++
+		return []byte(doc), nil
+	}), reg)
+	rl.Setup = func(r *lambdarouter.Router) {
+		r.Get("health", reg["ok"])
+	}
+
+	invoke := func(path string) int {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path})
+		resjson, err := rl.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res.StatusCode
+	}
+
+	desc(t, 2, "Invoke method should")
+	{
+		desc(t, 4, "load the document on the first invocation")
+		a.Exactly(http.StatusOK, invoke("/prefix/v1"))
+		a.Exactly(http.StatusOK, invoke("/prefix/health"))
+
+		desc(t, 4, "not reload the document without an interval")
+		doc = `routes: [{route: GET /v2, handler: ok}]`
+		a.Exactly(http.StatusOK, invoke("/prefix/v1"))
+		a.Exactly(1, fetches)
+
+		desc(t, 4, "reload the document on a control event")
+		_, err := rl.Invoke(context.Background(), []byte(`{"reloadRoutes": true}`))
+		a.NoError(err)
+		a.Exactly(http.StatusNotFound, invoke("/prefix/v1"))
+		a.Exactly(http.StatusOK, invoke("/prefix/v2"))
+		a.Exactly(http.StatusOK, invoke("/prefix/health"))
+	}
+
+	desc(t, 2, "Reload method should")
+	{
+		desc(t, 4, "keep the previous routes when the document cannot be applied")
+		doc = `routes: [{route: GET /v3, handler: missing}]`
+		a.Error(rl.Reload(context.Background()))
+		a.Exactly(http.StatusOK, invoke("/prefix/v2"))
+	}
+}
+
+func TestAppConfig(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize AppConfig extension and")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/applications/app/environments/prod/configurations/routes" {
+			http.NotFound(w, req)
+			return
+		}
+		w.Write([]byte("routes: []"))
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	os.Setenv("AWS_APPCONFIG_EXTENSION_HTTP_PORT", u.Port())
+	defer os.Unsetenv("AWS_APPCONFIG_EXTENSION_HTTP_PORT")
+
+	desc(t, 2, "Fetch method should")
+	{
+		desc(t, 4, "read the configuration profile from the extension")
+		doc, err := AppConfig("app", "prod", "routes").Fetch(context.Background())
+		a.NoError(err)
+		a.Exactly("routes: []", string(doc))
+
+		desc(t, 4, "return an error when the profile does not exist")
+		_, err = AppConfig("app", "prod", "missing").Fetch(context.Background())
+		a.Error(err)
+	}
+}
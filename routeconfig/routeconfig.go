@@ -0,0 +1,194 @@
+// Package routeconfig defines routes from a declarative YAML or JSON document, which maps each
+// route to a handler registered by name. Routes can then be rearranged, redirected, or deprecated
+// by changing the document rather than the code of the function.
+package routeconfig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"gopkg.in/yaml.v3"
+)
+
+// HandlerRegistry maps the names used by a config document to the handlers they refer to.
+type HandlerRegistry map[string]lambda.Handler
+
+// Config is a document describing the routes of a router.
+type Config struct {
+	Routes []Route `json:"routes" yaml:"routes"`
+}
+
+// Route describes a single route of a Config. Exactly one of Handler and Redirect must be set.
+type Route struct {
+	// Route is the method and path of the route, in the "METHOD /path" form of API Gateway route
+	// keys. The path is relative to the prefix of the router the config is applied to.
+	Route string `json:"route" yaml:"route"`
+
+	// Handler is the name of the handler of the route in the HandlerRegistry.
+	Handler string `json:"handler,omitempty" yaml:"handler,omitempty"`
+
+	// Redirect is the location requests to the route are redirected to. Parameters of the route
+	// may be referred to in it, as in /v2/users/{id}.
+	Redirect string `json:"redirect,omitempty" yaml:"redirect,omitempty"`
+
+	// Status is the status code of redirects, which defaults to 308 Permanent Redirect.
+	Status int `json:"status,omitempty" yaml:"status,omitempty"`
+
+	// Deprecation and Sunset are the times the route was deprecated and will be removed. When set,
+	// they are announced to clients with the Deprecation and Sunset response headers.
+	Deprecation *time.Time `json:"deprecation,omitempty" yaml:"deprecation,omitempty"`
+	Sunset      *time.Time `json:"sunset,omitempty" yaml:"sunset,omitempty"`
+}
+
+// Parse decodes a config document. As YAML is a superset of JSON, the document may be written in
+// either. Unknown fields are reported as errors, so typos do not silently drop routes.
+func Parse(doc []byte) (Config, error) {
+	var cfg Config
+
+	dec := yaml.NewDecoder(bytes.NewReader(doc))
+	dec.KnownFields(true)
+
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// Apply defines every route of cfg on r, looking handlers up by name in reg. Every route which
+// cannot be defined is reported in a *lambdarouter.RegistrationError, and the rest are still
+// defined.
+func Apply(r *lambdarouter.Router, cfg Config, reg HandlerRegistry) error {
+	var errs []error
+
+	for _, rc := range cfg.Routes {
+		if err := apply(r, rc, reg); err != nil {
+			errs = append(errs, fmt.Errorf("route '%s': %w", rc.Route, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return &lambdarouter.RegistrationError{Errors: errs}
+	}
+
+	return nil
+}
+
+func apply(r *lambdarouter.Router, rc Route, reg HandlerRegistry) error {
+	method, path, ok := strings.Cut(rc.Route, " ")
+	if !ok {
+		return errors.New(`route must have the form "METHOD /path"`)
+	}
+
+	var h lambda.Handler
+	switch {
+	case rc.Handler != "" && rc.Redirect != "":
+		return errors.New("route has both a handler and a redirect")
+	case rc.Handler != "":
+		if h, ok = reg[rc.Handler]; !ok {
+			return fmt.Errorf("handler %q is not registered", rc.Handler)
+		}
+	case rc.Redirect != "":
+		status := rc.Status
+		if status == 0 {
+			status = http.StatusPermanentRedirect
+		}
+		h = redirect{location: rc.Redirect, status: status}
+	default:
+		return errors.New("route has neither a handler nor a redirect")
+	}
+
+	var opts []lambdarouter.RouteOption
+	if headers := deprecationHeaders(rc); len(headers) > 0 {
+		opts = append(opts, lambdarouter.WithMiddleware(addHeaders(headers)))
+	}
+
+	return r.TryHandle(method, path, h, opts...)
+}
+
+// redirect is the handler of routes which redirect to another location.
+type redirect struct {
+	location string
+	status   int
+}
+
+func (rd redirect) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	codec := lambdarouter.CodecFrom(ctx)
+
+	req, err := lambdarouter.RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	location := rd.location
+	for name, value := range req.PathParameters {
+		location = strings.ReplaceAll(location, "{"+name+"}", value)
+		location = strings.ReplaceAll(location, "{"+name+"+}", value)
+		location = strings.ReplaceAll(location, "{"+name+"...}", value)
+	}
+
+	return codec.Marshal(events.APIGatewayProxyResponse{
+		StatusCode: rd.status,
+		Headers:    map[string]string{"Location": location},
+	})
+}
+
+// deprecationHeaders returns the headers which announce the deprecation of a route, as described
+// by RFC 9745 and RFC 8594.
+func deprecationHeaders(rc Route) map[string]string {
+	headers := map[string]string{}
+
+	if rc.Deprecation != nil {
+		headers["Deprecation"] = "@" + strconv.FormatInt(rc.Deprecation.Unix(), 10)
+	}
+	if rc.Sunset != nil {
+		headers["Sunset"] = rc.Sunset.UTC().Format(http.TimeFormat)
+	}
+
+	return headers
+}
+
+// addHeaders returns middleware which adds headers to every response of a handler.
+func addHeaders(headers map[string]string) lambdarouter.Middleware {
+	return func(next lambda.Handler) lambda.Handler {
+		return headerAdder{next: next, headers: headers}
+	}
+}
+
+type headerAdder struct {
+	next    lambda.Handler
+	headers map[string]string
+}
+
+func (ha headerAdder) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	codec := lambdarouter.CodecFrom(ctx)
+
+	resjson, err := ha.next.Invoke(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var res events.APIGatewayProxyResponse
+	if err := codec.Unmarshal(resjson, &res); err != nil {
+		return nil, err
+	}
+
+	if res.Headers == nil {
+		res.Headers = map[string]string{}
+	}
+	for name, value := range ha.headers {
+		res.Headers[name] = value
+	}
+
+	return codec.Marshal(res)
+}
@@ -0,0 +1,98 @@
+package routeconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+const doc = `
+routes:
+  - route: GET /users/{id}
+    handler: getUser
+  - route: GET /legacy/users/{id}
+    redirect: /prefix/users/{id}
+    deprecation: 2026-01-01T00:00:00Z
+    sunset: 2027-01-01T00:00:00Z
+`
+
+func TestApply(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router and")
+	r := lambdarouter.New("prefix")
+	reg := HandlerRegistry{
+		"getUser": lambda.NewHandler(func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: req.PathParameters["id"]}, nil
+		}),
+	}
+
+	invoke := func(path string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "Parse function should")
+	{
+		desc(t, 4, "decode YAML and JSON documents")
+		cfg, err := Parse([]byte(doc))
+		a.NoError(err)
+		a.Len(cfg.Routes, 2)
+
+		cfg, err = Parse([]byte(`{"routes": [{"route": "GET /users", "handler": "listUsers"}]}`))
+		a.NoError(err)
+		a.Exactly([]Route{{Route: "GET /users", Handler: "listUsers"}}, cfg.Routes)
+
+		desc(t, 4, "return an error for unknown fields")
+		_, err = Parse([]byte(`{"routes": [{"route": "GET /users", "handle": "listUsers"}]}`))
+		a.Error(err)
+	}
+
+	desc(t, 2, "Apply function should")
+	{
+		cfg, _ := Parse([]byte(doc))
+		a.NoError(Apply(&r, cfg, reg))
+
+		desc(t, 4, "define routes with registered handlers")
+		res := invoke("/prefix/users/42")
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("42", res.Body)
+
+		desc(t, 4, "define redirects which announce their deprecation")
+		res = invoke("/prefix/legacy/users/42")
+		a.Exactly(http.StatusPermanentRedirect, res.StatusCode)
+		a.Exactly("/prefix/users/42", res.Headers["Location"])
+		a.Exactly("@1767225600", res.Headers["Deprecation"])
+		a.Exactly("Fri, 01 Jan 2027 00:00:00 GMT", res.Headers["Sunset"])
+
+		desc(t, 4, "report every route which cannot be defined")
+		err := Apply(&r, Config{Routes: []Route{
+			{Route: "GET /users/{id}", Handler: "getUser"},
+			{Route: "GET /orders", Handler: "listOrders"},
+			{Route: "/orders", Handler: "getUser"},
+		}}, reg)
+		a.IsType(&lambdarouter.RegistrationError{}, err)
+		a.Len(err.(*lambdarouter.RegistrationError).Errors, 3)
+		a.Contains(err.Error(), `route 'GET /orders': handler "listOrders" is not registered`)
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
@@ -2,106 +2,431 @@ package lambdarouter
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"runtime/debug"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
-	iradix "github.com/hashicorp/go-immutable-radix"
+	"github.com/mitchell/lambdarouter/internal/jsoncodec"
 )
 
 // Router holds the defined routes for use upon invocation.
+//
+// Routes may be defined concurrently, including while the router is being invoked, as every copy of
+// a router shares its routes. Group and Use change the router they are called on, so they must not
+// be called concurrently with each other or with the definition of routes on the same router.
 type Router struct {
-	events *iradix.Tree
-	prefix string
+	table      *routeTable
+	prefix     string
+	middleware []Middleware
+	predicates []predicate
+
+	notFound        lambda.Handler
+	caseInsensitive bool
+	logger          Logger
+	debug           bool
+	warmup          *warmup
+	payloadFormat   PayloadFormat
+	versionHeader   string
+	methodOverride  bool
+	defaultVersion  string
+	decodeBodies    bool
+	maxBodySize     int64
+	defaultHeaders  map[string]string
+	cors            *CORSPolicy
+	rpcField        string
+	health          *healthCheck
+	listing         *routeListing
+	codec           Codec
+	marshaler       ResponseMarshaler
+
+	problems      bool
+	extendProblem ProblemExtender
+	unmatched     *unmatchedResponses
 }
 
-// New initializes an empty router. The prefix parameter may be of any length.
-func New(prefix string) Router {
-	if len(prefix) > 0 {
-		if prefix[0] != '/' {
-			prefix = "/" + prefix
-		}
-		if prefix[len(prefix)-1] != '/' {
-			prefix += "/"
-		}
+// New initializes an empty router. The prefix parameter may be of any length, and is given leading
+// and trailing slashes if it lacks them, so an empty prefix roots the routes of the router at /.
+// The opts parameter configures the behaviour of the router.
+func New(prefix string, opts ...Option) Router {
+	if len(prefix) == 0 || prefix[0] != '/' {
+		prefix = "/" + prefix
+	}
+	if prefix[len(prefix)-1] != '/' {
+		prefix += "/"
 	}
 
-	return Router{
-		events: iradix.New(),
-		prefix: prefix,
+	r := Router{
+		table:         newRouteTable(),
+		prefix:        prefix,
+		payloadFormat: PayloadFormatV1,
+		unmatched:     &unmatchedResponses{},
 	}
+	for _, opt := range opts {
+		opt(&r)
+	}
+	r.prerenderUnmatched("")
+
+	r.table.matcher = r.adaptMatcher(NewRadixMatcher())
+
+	if r.health != nil {
+		r.Get(r.health.path, r.health)
+	}
+	if r.listing != nil {
+		r.Get("__routes", routeLister{r: r, guarded: len(r.listing.opts) > 0}, r.listing.opts...)
+	}
+
+	return r
 }
 
 // Get adds a new GET method route to the router. The path parameter is the route path you wish to
 // define. The handler parameter is a lambda.Handler to invoke if an incoming path matches the
-// route.
-func (r *Router) Get(path string, handler lambda.Handler) {
-	r.addEvent(prepPath(http.MethodGet, r.prefix, path), event{h: handler})
+// route. The opts parameter configures the route, for example by adding middleware to it.
+//
+// The last segment of the path may be an optional parameter, as in reports/{year}/{month?}, in
+// which case the route matches paths both with and without the segment. Handlers tell them apart
+// by whether the parameter is among the PathParameters of the request.
+//
+// The last segment may instead be a wildcard parameter, as in files/{path...}, which captures the
+// rest of the path as a single parameter, such as a/b.txt for /files/a/b.txt. Unlike the greedy
+// parameters of API Gateway, such as {proxy+}, it also matches when nothing remains, as for
+// /files, in which case the parameter is empty.
+//
+// When the templates of several routes match a path, they are compared segment by segment from
+// the start of the path, and a static segment is preferred to a parameter, and a parameter to a
+// greedy or wildcard parameter, so /users/me is matched in preference to /users/{id}, whatever
+// the order the routes were defined in. WithPriority overrides these rules for a route.
+func (r *Router) Get(path string, handler lambda.Handler, opts ...RouteOption) {
+	mustAdd(r.TryHandle(http.MethodGet, path, handler, opts...))
 }
 
 // Post adds a new POST method route to the router. The path parameter is the route path you wish to
 // define. The handler parameter is a lambda.Handler to invoke if an incoming path matches the
-// route.
-func (r *Router) Post(path string, handler lambda.Handler) {
-	r.addEvent(prepPath(http.MethodPost, r.prefix, path), event{h: handler})
+// route. The opts parameter configures the route.
+func (r *Router) Post(path string, handler lambda.Handler, opts ...RouteOption) {
+	mustAdd(r.TryHandle(http.MethodPost, path, handler, opts...))
 }
 
 // Put adds a new PUT method route to the router. The path parameter is the route path you wish to
 // define. The handler parameter is a lambda.Handler to invoke if an incoming path matches the
-// route.
-func (r *Router) Put(path string, handler lambda.Handler) {
-	r.addEvent(prepPath(http.MethodPut, r.prefix, path), event{h: handler})
+// route. The opts parameter configures the route.
+func (r *Router) Put(path string, handler lambda.Handler, opts ...RouteOption) {
+	mustAdd(r.TryHandle(http.MethodPut, path, handler, opts...))
 }
 
 // Patch adds a new PATCH method route to the router. The path parameter is the route path you wish
 // to define. The handler parameter is a lambda.Handler to invoke if an incoming path matches the
-// route.
-func (r *Router) Patch(path string, handler lambda.Handler) {
-	r.addEvent(prepPath(http.MethodPatch, r.prefix, path), event{h: handler})
+// route. The opts parameter configures the route.
+func (r *Router) Patch(path string, handler lambda.Handler, opts ...RouteOption) {
+	mustAdd(r.TryHandle(http.MethodPatch, path, handler, opts...))
 }
 
 // Delete adds a new DELETE method route to the router. The path parameter is the route path you
 // wish to define. The handler parameter is a lambda.Handler to invoke if an incoming path matches
-// the route.
-func (r *Router) Delete(path string, handler lambda.Handler) {
-	r.addEvent(prepPath(http.MethodDelete, r.prefix, path), event{h: handler})
+// the route. The opts parameter configures the route.
+func (r *Router) Delete(path string, handler lambda.Handler, opts ...RouteOption) {
+	mustAdd(r.TryHandle(http.MethodDelete, path, handler, opts...))
+}
+
+// Handle adds a new route with the given method to the router. It allows routes to be defined for
+// methods which have no dedicated function, such as HEAD or OPTIONS. The method parameter is the
+// HTTP method of the route, the path, handler, and opts parameters behave the same as they do for
+// Get.
+func (r *Router) Handle(method, path string, handler lambda.Handler, opts ...RouteOption) {
+	mustAdd(r.TryHandle(method, path, handler, opts...))
+}
+
+// TryGet behaves like Get, but returns an error instead of panicking if the route cannot be added.
+func (r *Router) TryGet(path string, handler lambda.Handler, opts ...RouteOption) error {
+	return r.TryHandle(http.MethodGet, path, handler, opts...)
+}
+
+// TryPost behaves like Post, but returns an error instead of panicking if the route cannot be
+// added.
+func (r *Router) TryPost(path string, handler lambda.Handler, opts ...RouteOption) error {
+	return r.TryHandle(http.MethodPost, path, handler, opts...)
+}
+
+// TryPut behaves like Put, but returns an error instead of panicking if the route cannot be added.
+func (r *Router) TryPut(path string, handler lambda.Handler, opts ...RouteOption) error {
+	return r.TryHandle(http.MethodPut, path, handler, opts...)
+}
+
+// TryPatch behaves like Patch, but returns an error instead of panicking if the route cannot be
+// added.
+func (r *Router) TryPatch(path string, handler lambda.Handler, opts ...RouteOption) error {
+	return r.TryHandle(http.MethodPatch, path, handler, opts...)
+}
+
+// TryDelete behaves like Delete, but returns an error instead of panicking if the route cannot be
+// added.
+func (r *Router) TryDelete(path string, handler lambda.Handler, opts ...RouteOption) error {
+	return r.TryHandle(http.MethodDelete, path, handler, opts...)
+}
+
+// TryHandle behaves like Handle, but returns an error instead of panicking if the route cannot be
+// added, such as when the path is empty or the route already exists. The error is also recorded,
+// so routers built from dynamic configuration can add every route and then report every problem
+// at once with Validate. Errors of routes which conflict with another name the file and line each
+// was defined at, so the definitions can be found however far apart they are.
+func (r *Router) TryHandle(method, path string, handler lambda.Handler, opts ...RouteOption) error {
+	key, err := prepPath(strings.ToUpper(method), r.prefix, path)
+	if err == nil {
+		err = validateParams(key)
+	}
+	if err == nil {
+		err = r.addEvent(key, handler, opts)
+	}
+
+	if err != nil && r.table != nil {
+		r.table.recordError(err)
+	}
+
+	return err
+}
+
+// Validate returns a *RegistrationError listing every route which could not be added to the
+// router with the Try methods, or nil if every route was added.
+func (r Router) Validate() error {
+	if r.table == nil {
+		return nil
+	}
+
+	errs := r.table.errors()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &RegistrationError{Errors: errs}
 }
 
-// Invoke implements the lambda.Handler interface for the Router type.
+// RegistrationError is returned by Validate when routes could not be added to a router.
+type RegistrationError struct {
+	Errors []error
+}
+
+// Error implements the error interface for the RegistrationError type.
+func (e *RegistrationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%d route(s) could not be added: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// mustAdd panics with the error returned while adding a route, if any.
+func mustAdd(err error) {
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+// Prefix returns the prefix which is currently applied to routes defined on the router, including
+// the prefixes of any groups it is used within.
+func (r Router) Prefix() string {
+	return r.prefix
+}
+
+// Invoke implements the lambda.Handler interface for the Router type. Payloads recognised by a
+// dispatcher added by Dispatch, or which are events of services the router has routes for, such as
+// those defined by EventBridge, are routed to those, and every other payload is routed as an HTTP
+// request. The hooks added by OnColdStart run before the first payload is routed, and warmup events
+// are responded to before any payload is routed if the router was created WithWarmup.
 func (r Router) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	ctx = r.invocationContext(ctx)
+
+	if r.warm(payload) {
+		return r.jsonCodec().Marshal(events.APIGatewayProxyResponse{StatusCode: http.StatusOK})
+	}
+
+	if res, routed, err := r.invokeSource(ctx, payload); routed {
+		return res, err
+	}
+
+	if limit, tooLarge := r.payloadTooLarge(payload); tooLarge {
+		res, err := r.rejectPayload(ctx, limit)
+		if err != nil || r.payloadFormat != PayloadFormatV2 {
+			return res, err
+		}
+		return responseV2(r.jsonCodec(), res)
+	}
+
+	if r.payloadFormat == PayloadFormatV2 {
+		return r.invokeV2(ctx, payload)
+	}
+
 	var req events.APIGatewayProxyRequest
 
-	if err := json.Unmarshal(payload, &req); err != nil {
+	if err := r.jsonCodec().Unmarshal(payload, &req); err != nil {
+		r.logf("malformed request: %v", err)
 		return nil, err
 	}
 
-	path := req.Path
+	return r.route(ctx, req, payload)
+}
+
+// invocationContext returns the context an invocation is routed with, which runs the hooks added by
+// OnColdStart before the first invocation and carries the codec of the router. Every entry point of
+// the router routes invocations with it.
+func (r Router) invocationContext(ctx context.Context) context.Context {
+	if r.table != nil {
+		ctx = r.table.start(ctx)
+	}
+	if r.codec != nil {
+		ctx = jsoncodec.NewContext(ctx, r.codec)
+	}
+
+	return ctx
+}
 
-	for param, value := range req.PathParameters {
-		path = strings.Replace(path, value, "{"+param+"}", -1)
+// route invokes the handler of the route which matches req, of which payload is the encoding, gives
+// its response to the response marshaler of the router, and adds the default headers of the router
+// to it. The lifecycle hooks of the router run around it.
+func (r Router) route(ctx context.Context, req events.APIGatewayProxyRequest, payload []byte) ([]byte, error) {
+	hooks := r.lifecycleHooks()
+
+	if len(hooks.request) > 0 {
+		var err error
+		if req, payload, err = runRequestHooks(ctx, hooks.request, req); err != nil {
+			return nil, err
+		}
 	}
 
-	i, found := r.events.Get([]byte(req.HTTPMethod + path))
+	var matched Route
+	res, err := r.dispatch(ctx, req, payload, &matched)
+	res, err = r.finishResponse(ctx, req, matched, res, err)
+
+	if len(hooks.response) > 0 {
+		return runResponseHooks(ctx, hooks.response, res, err)
+	}
+
+	return res, err
+}
+
+// finishResponse gives res, the response to req, which matched the route rt, to the response
+// marshaler of the router, and adds the default headers of the router to it, unless err is set.
+func (r Router) finishResponse(ctx context.Context, req events.APIGatewayProxyRequest, rt Route, res []byte, err error) ([]byte, error) {
+	if err == nil && r.marshaler != nil {
+		res, err = r.marshalResponse(withRequest(ctx, req, rt), res)
+	}
+	if err == nil && len(r.defaultHeaders) > 0 {
+		res, err = r.addDefaultHeaders(res)
+	}
+
+	return res, err
+}
+
+// dispatch invokes the handler of the route which matches req, which it sets matched to, or renders
+// the response to a request which matches none.
+func (r Router) dispatch(ctx context.Context, req events.APIGatewayProxyRequest, payload []byte, matched *Route) ([]byte, error) {
+	if method := r.requestMethod(req); method != req.HTTPMethod {
+		req.HTTPMethod = method
+
+		var err error
+		if payload, err = r.jsonCodec().Marshal(req); err != nil {
+			return nil, err
+		}
+	}
+
+	events, params, found := r.lookup(req.HTTPMethod, req.Path, &req)
+	if r.debug {
+		r.traceMatch(req, events, params, found)
+	}
 
 	if !found {
-		return json.Marshal(events.APIGatewayProxyResponse{
-			StatusCode: http.StatusNotFound,
-			Body:       "not found",
-		})
+		if isPreflight(req) {
+			if res, ok, err := r.preflight(req); ok {
+				return res, err
+			}
+		}
+		return r.notMatched(ctx, req, payload)
+	}
+
+	changed := false
+
+	// Handlers are given the parameters of the matched template, which differ from those of API
+	// Gateway when it routes to the function with a greedy path such as /{proxy+}. Predicates see
+	// them too, as the tenant of a request may be one of them.
+	if !sameParams(params, req.PathParameters) {
+		req.PathParameters = params
+		changed = true
+	}
+
+	e, status := selectEvent(events, req)
+	if status != 0 {
+		return r.errorResponse(ctx, req, &HTTPError{Status: status})
+	}
+	*matched = e.rt
+
+	if limit := r.bodyLimit(e); limit > 0 && bodySize(req) > limit {
+		return r.errorResponse(ctx, req, bodyTooLarge(limit))
+	}
+
+	if r.decodeBodies {
+		decoded, err := decodeBody(&req, r.bodyLimit(e))
+		if err != nil {
+			return r.errorResponse(ctx, req, err)
+		}
+		changed = changed || decoded
+	}
+
+	if changed {
+		var err error
+		if payload, err = r.jsonCodec().Marshal(req); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := r.invokeRoute(r.withTenant(withRequest(ctx, req, e.rt), req), e, payload)
+	if err != nil {
+		res, err = r.errorResponse(ctx, req, err)
 	}
 
-	e := i.(event)
-	return e.h.Invoke(ctx, payload)
+	if err != nil || e.cors == nil {
+		return res, err
+	}
+
+	return e.cors.addHeaders(r.jsonCodec(), req, res)
+}
+
+// invokeRoute invokes the handler of e, logging the error it returns. A panic of the handler is
+// recovered and logged with its stack, and returned as an error, so it is responded to as any other
+// error rather than crashing the function.
+func (r Router) invokeRoute(ctx context.Context, e event, payload []byte) (res []byte, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			res, err = nil, fmt.Errorf("panic: %v", p)
+			r.logf("%s: %v\n%s", e.rt, err, debug.Stack())
+		}
+	}()
+
+	res, err = e.h.Invoke(ctx, payload)
+	if err != nil {
+		r.logf("%s: %v", e.rt, err)
+	}
+
+	return res, err
 }
 
 // Group allows you to define many routes with the same prefix. The prefix parameter will be applied
 // to all routes defined in the function. The fn parameter is a function in which the grouped
 // routes should be defined.
+//
+// The prefix may hold path parameters, as in Group("users/{userID}", fn), whose values are given
+// to the handlers of every route in the group along with those of the route itself. It may not
+// hold a greedy parameter, which would leave nothing for the paths of the routes to match.
 func (r *Router) Group(prefix string, fn func(r *Router)) {
-	validatePathPart(prefix)
+	if err := validatePathPart(prefix); err != nil {
+		panic(err.Error())
+	}
+	if err := validateGroupPrefix(prefix); err != nil {
+		panic(err.Error())
+	}
 
 	if prefix[0] == '/' {
 		prefix = prefix[1:]
@@ -110,45 +435,365 @@ func (r *Router) Group(prefix string, fn func(r *Router)) {
 		prefix += "/"
 	}
 
-	original := r.prefix
+	original, middleware, predicates := r.prefix, r.middleware, r.predicates
 	r.prefix += prefix
 	fn(r)
-	r.prefix = original
+	r.prefix, r.middleware, r.predicates = original, middleware, predicates
+}
+
+// Mount defines every route of sub on the router, beneath prefix, so routers built separately, such
+// as one per package, can be composed into one. The routes keep the handlers and middleware they
+// were defined with on sub, and are wrapped by the middleware of the router as any other route.
+// Options of sub, such as its not found handler, do not apply to the mounted routes. The prefix
+// parameter may be "/" to mount the routes at the prefix of the router itself.
+func (r *Router) Mount(prefix string, sub Router) {
+	if sub.table == nil {
+		panic("mounted router not initialized")
+	}
+
+	events := sub.table.events()
+	define := func(r *Router) {
+		for _, e := range events {
+			opts := []RouteOption{withPredicates(e.predicates...), WithRouteMaxBodySize(e.maxBodySize), withCORS(e.cors),
+				WithHandlerName(e.handlerName()), withSite(e.site)}
+			if e.prioritized {
+				opts = append(opts, WithPriority(e.priority))
+			}
+			r.Handle(e.rt.Method, e.rt.Path, e.h, opts...)
+		}
+	}
+
+	if strings.Trim(prefix, "/") == "" {
+		define(r)
+		return
+	}
+
+	r.Group(prefix, define)
+}
+
+// Remove deletes the routes with the given method and path from the router, whatever their
+// conditions, so they can be withdrawn while the function is running, such as by a kill switch. The
+// path parameter is relative to the prefix of the router, as it is for Get. It returns an error if
+// the route does not exist or the matcher of the router does not implement RemovableMatcher.
+func (r *Router) Remove(method, path string) error {
+	if r.table == nil {
+		return errors.New("router not initialized")
+	}
+
+	key, err := prepPath(strings.ToUpper(method), r.prefix, path)
+	if err != nil {
+		return err
+	}
+
+	return r.table.remove(key)
+}
+
+// Replace swaps the handler of the route with the given method and path, and no conditions, for
+// handler, keeping the middleware the route was defined with. Invocations already in progress
+// complete with the previous handler. It returns an error if the route does not exist.
+func (r *Router) Replace(method, path string, handler lambda.Handler) error {
+	if r.table == nil {
+		return errors.New("router not initialized")
+	}
+
+	key, err := prepPath(strings.ToUpper(method), r.prefix, path)
+	if err != nil {
+		return err
+	}
+
+	return r.table.replace(key, handler)
+}
+
+// SwapRoutes replaces every route of the router with the routes of next in a single step, so no
+// invocation sees a mix of the two. It allows a router to be rebuilt from scratch at runtime, such
+// as from a new version of a config document. Every copy of the router is affected, while next
+// should not be used to define further routes afterwards. The options of the router are kept.
+func (r Router) SwapRoutes(next Router) error {
+	if r.table == nil || next.table == nil {
+		return errors.New("router not initialized")
+	}
+
+	r.table.swap(next.table)
+
+	return nil
+}
+
+// Merge combines the routes of routers into a new router, such as routers contributed by several
+// libraries to one function. The new router takes its prefix and options from the first router,
+// but always matches routes with the default matcher. The routes of events other than HTTP
+// requests, the OnColdStart, OnRequest and OnResponse hooks, and the versions of every router are
+// combined in the order of the routers, while the feature flag provider and tenant resolvers are
+// taken from the first router which sets them. The routes of every router are still gated by the
+// flags and tenants of their own router. Every route or RPC action which conflicts with one of an
+// earlier router is reported in a *RegistrationError, and the router holding the rest of the routes
+// is returned along with it.
+func Merge(routers ...Router) (Router, error) {
+	if len(routers) == 0 {
+		return New(""), nil
+	}
+
+	merged := routers[0]
+	if merged.table == nil {
+		return Router{}, errors.New("router not initialized")
+	}
+
+	merged.table = newRouteTable()
+	merged.table.matcher = merged.adaptMatcher(NewRadixMatcher())
+	merged.middleware, merged.predicates = nil, nil
+	if merged.unmatched != nil {
+		merged.unmatched = &unmatchedResponses{}
+		merged.prerenderUnmatched("")
+	}
+
+	for i, r := range routers {
+		if r.table == nil {
+			merged.table.recordError(fmt.Errorf("router %d not initialized", i))
+			continue
+		}
+
+		for _, e := range r.table.events() {
+			if err := merged.table.add(e); err != nil {
+				merged.table.recordError(fmt.Errorf("router %d: %w", i, err))
+				continue
+			}
+			merged.prerenderUnmatched(e.rt.Path)
+		}
+
+		for _, err := range merged.table.mergeState(r.table) {
+			merged.table.recordError(fmt.Errorf("router %d: %w", i, err))
+		}
+	}
+
+	return merged, merged.Validate()
+}
+
+// Use adds middleware to every route defined on the router after it is called. When called within
+// a Group, only the routes of that group are affected. Middleware run in the order they are added,
+// before any middleware added to a route with WithMiddleware.
+func (r *Router) Use(mw ...Middleware) {
+	r.middleware = append(r.middleware[:len(r.middleware):len(r.middleware)], mw...)
+}
+
+// Middleware wraps a handler with additional behaviour, such as authentication or logging. The
+// handler it returns is invoked in place of next, and is responsible for invoking next itself.
+type Middleware func(next lambda.Handler) lambda.Handler
+
+// RouteOption configures a single route as it is defined on a router.
+type RouteOption func(e *event)
+
+// WithMiddleware adds middleware to a single route. They run after any middleware added to the
+// router with Use, in the order they are given.
+func WithMiddleware(mw ...Middleware) RouteOption {
+	return func(e *event) {
+		e.middleware = append(e.middleware, mw...)
+	}
+}
+
+// Routes returns every route defined on the router, ordered by method and then path.
+func (r Router) Routes() []Route {
+	if r.table == nil {
+		return nil
+	}
+
+	return r.table.list()
+}
+
+// Route describes a single route defined on a Router.
+type Route struct {
+	Method string
+	Path   string
+
+	// Conditions describes the conditions the route places on requests in addition to its method
+	// and path, such as the host they are made to. It is nil for routes without any.
+	Conditions []string
+}
+
+// String returns the route in the "METHOD /path" form used by API Gateway route keys.
+func (rt Route) String() string {
+	return rt.Method + " " + rt.Path
 }
 
 type event struct {
-	h lambda.Handler
+	h           lambda.Handler
+	rt          Route
+	middleware  []Middleware
+	predicates  []predicate
+	maxBodySize int64
+	cors        *CORSPolicy
+	timeout     time.Duration
+	fallback    lambda.Handler
+	shadow      *shadow
+
+	// priority is the priority of the route, if prioritized is set.
+	priority    int
+	prioritized bool
+
+	// site is the file and line the route was defined at.
+	site string
+
+	// base is the handler of the route before it is wrapped, and name names it in listings if set.
+	base lambda.Handler
+	name string
+
+	// routerMiddleware is the number of the middleware which were added to the router, rather than
+	// to the route, and come first.
+	routerMiddleware int
 }
 
-func (r *Router) addEvent(key string, e event) {
-	if r.events == nil {
-		panic("router not initialized")
+func (r *Router) addEvent(key string, handler lambda.Handler, opts []RouteOption) error {
+	if r.table == nil {
+		return errors.New("router not initialized")
 	}
 
-	routes, _, overwrite := r.events.Insert([]byte(key), e)
+	e := event{
+		rt:               parseKey(key),
+		middleware:       r.middleware[:len(r.middleware):len(r.middleware)],
+		predicates:       r.predicates[:len(r.predicates):len(r.predicates)],
+		cors:             r.cors,
+		routerMiddleware: len(r.middleware),
+	}
+	for _, opt := range opts {
+		opt(&e)
+	}
+	if e.site == "" {
+		e.site = definitionSite()
+	}
 
-	if overwrite {
-		panic(fmt.Sprintf("event '%s' already exists", key))
+	sortPredicates(e.predicates)
+	bindPredicates(r.table, e.predicates)
+	for _, p := range e.predicates {
+		e.rt.Conditions = append(e.rt.Conditions, p.name)
 	}
 
-	r.events = routes
+	e.wrap(handler)
+
+	if err := r.table.add(e); err != nil {
+		return err
+	}
+	r.prerenderUnmatched(e.rt.Path)
+
+	return nil
 }
 
-func prepPath(method, prefix, path string) string {
-	validatePathPart(path)
+// key returns the key the event is stored under, which distinguishes it from every other route.
+func (e event) key() string {
+	key := e.rt.Method + e.rt.Path
+	for _, c := range e.rt.Conditions {
+		key += " " + c
+	}
+
+	return key
+}
+
+// wrap sets the handler of the event to handler, wrapped by the middleware of the event. The time
+// limit, fallback, and shadow of the route apply within the middleware of the router, and the
+// fallback only to requests which the middleware of the route pass on to the handler.
+func (e *event) wrap(handler lambda.Handler) {
+	e.base, e.h = handler, handler
+	if e.fallback != nil {
+		e.h = reachHandler{next: handler}
+	}
+	for i := len(e.middleware) - 1; i >= e.routerMiddleware; i-- {
+		e.h = e.middleware[i](e.h)
+	}
+	if e.timeout > 0 {
+		e.h = timeoutHandler{d: e.timeout, next: e.h}
+	}
+	if e.fallback != nil {
+		e.h = fallbackHandler{next: e.h, fallback: e.fallback}
+	}
+	if e.shadow != nil {
+		e.h = shadowHandler{next: e.h, shadow: e.shadow}
+	}
+	for i := e.routerMiddleware - 1; i >= 0; i-- {
+		e.h = e.middleware[i](e.h)
+	}
+}
+
+func prepPath(method, prefix, path string) (string, error) {
+	if err := validatePathPart(path); err != nil {
+		return "", err
+	}
 
 	if path[0] == '/' {
 		path = path[1:]
 	}
+	if len(path) == 0 {
+		// The path "/" refers to the prefix itself.
+		if len(prefix) > 1 {
+			prefix = prefix[:len(prefix)-1]
+		}
+		return method + prefix, nil
+	}
 	if path[len(path)-1] == '/' {
 		path = path[:len(path)-1]
 	}
 
-	return method + prefix + path
+	return method + prefix + path, nil
 }
 
-func validatePathPart(part string) {
+func parseKey(key string) Route {
+	i := strings.IndexByte(key, '/')
+
+	return Route{Method: key[:i], Path: key[i:]}
+}
+
+func validatePathPart(part string) error {
 	if len(part) == 0 {
-		panic("path was empty")
+		return errors.New("path was empty")
+	}
+
+	return nil
+}
+
+// validateGroupPrefix returns an error if the prefix of a group holds a greedy or wildcard
+// parameter.
+func validateGroupPrefix(prefix string) error {
+	for rest := "/" + strings.Trim(prefix, "/"); rest != ""; {
+		var seg string
+		seg, rest = nextSegment(rest)
+
+		if isParam(seg) && strings.HasSuffix(seg, "+}") || isWildcard(seg) {
+			return fmt.Errorf("group prefix '%s' may not hold the greedy parameter '%s'", prefix, seg[1:])
+		}
+	}
+
+	return nil
+}
+
+// validateParams returns an error if the template of the route with the given key names a path
+// parameter more than once, such as when a route of a group reuses the name of a parameter of the
+// group, as only one of their values could be given to the handler.
+func validateParams(key string) error {
+	i := strings.IndexByte(key, '/')
+	if i < 0 {
+		return nil
 	}
+	seen := map[string]bool{}
+
+	for rest := key[i:]; rest != ""; {
+		var seg string
+		seg, rest = nextSegment(rest)
+		if !isParam(seg) {
+			continue
+		}
+
+		if strings.HasSuffix(seg, "+?}") {
+			return fmt.Errorf("route '%s' has the greedy path parameter '%s', which cannot be optional", parseKey(key), seg[1:])
+		}
+		if isWildcard(seg) && rest != "" {
+			return fmt.Errorf("route '%s' has the wildcard path parameter '%s' before its last segment", parseKey(key), seg[1:])
+		}
+		if strings.HasSuffix(seg, "?}") && rest != "" {
+			return fmt.Errorf("route '%s' has the optional path parameter '%s' before its last segment", parseKey(key), seg[1:])
+		}
+
+		name := strings.TrimSuffix(strings.TrimRight(seg[2:len(seg)-1], "+?"), "...")
+		if seen[name] {
+			return fmt.Errorf("route '%s' names the path parameter '%s' more than once", parseKey(key), name)
+		}
+		seen[name] = true
+	}
+
+	return nil
 }
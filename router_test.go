@@ -46,6 +46,47 @@ func TestRouter(t *testing.T) {
 		})
 	}
 
+	desc(t, 2, "New function should")
+	{
+		desc(t, 4, "root the routes of an empty prefix at /")
+		root := New("")
+		a.Exactly("/", root.Prefix())
+		root.Get("thing", handler)
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/thing"})
+		_, err := root.Invoke(ctx, payload)
+		a.NoError(err)
+		a.Exactly([]Route{{Method: http.MethodGet, Path: "/thing"}}, root.Routes())
+	}
+
+	desc(t, 2, "TryGet|TryHandle and Validate methods should")
+	{
+		r2 := New("try")
+
+		desc(t, 4, "insert a new route succesfully")
+		a.NoError(r2.TryGet("thing/{id}", handler))
+		a.NoError(r2.TryHandle("options", "thing", handler))
+		a.NoError(r2.Validate())
+
+		desc(t, 4, "return an error when inserting the same route")
+		a.Regexp(`^event 'GET/try/thing/\{id\}' already exists `+
+			`\(first defined at .*router_test\.go:\d+, again at .*router_test\.go:\d+\)$`,
+			r2.TryGet("thing/{id}", handler).Error())
+
+		desc(t, 4, "return an error when given an empty path")
+		a.EqualError(r2.TryPost("", handler), "path was empty")
+
+		desc(t, 4, "return an error when router is uninitalized")
+		var r3 Router
+		a.EqualError(r3.TryPatch("thing", handler), "router not initialized")
+
+		desc(t, 4, "report every route which could not be inserted")
+		err := r2.Validate()
+		a.IsType(&RegistrationError{}, err)
+		a.Len(err.(*RegistrationError).Errors, 2)
+		a.Regexp(`^2 route\(s\) could not be added: `+
+			`event 'GET/try/thing/\{id\}' already exists \(.*\); path was empty$`, err.Error())
+	}
+
 	desc(t, 2, "PrefixGroup method should")
 	{
 		desc(t, 4, "insert routes with the specified prefix succesfully")
@@ -54,6 +95,210 @@ func TestRouter(t *testing.T) {
 		})
 	}
 
+	desc(t, 2, "Group method should")
+	{
+		desc(t, 4, "give the parameters of its prefix to the handlers of its routes")
+		var params map[string]string
+		gr := New("")
+		gr.Group("users/{userID}", func(r *Router) {
+			r.Get("orders/{orderID}", lambda.NewHandler(func(req events.APIGatewayProxyRequest) error {
+				params = req.PathParameters
+				return nil
+			}))
+		})
+		ejson, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/users/42/orders/7"})
+		_, err := gr.Invoke(context.Background(), ejson)
+		a.NoError(err)
+		a.Exactly(map[string]string{"userID": "42", "orderID": "7"}, params)
+
+		desc(t, 4, "reject routes which reuse the names of its parameters")
+		gr.Group("users/{userID}", func(r *Router) {
+			err = r.TryGet("friends/{userID}", lambda.NewHandler(handler))
+		})
+		a.EqualError(err, "route 'GET /users/{userID}/friends/{userID}' names the path parameter 'userID' more than once")
+
+		desc(t, 4, "panic if its prefix holds a greedy parameter")
+		a.PanicsWithValue("group prefix 'files/{path+}' may not hold the greedy parameter '{path+}'", func() {
+			gr.Group("files/{path+}", func(r *Router) {})
+		})
+	}
+
+	desc(t, 2, "Mount method should")
+	{
+		billing := New("")
+		billing.Get("/", handler)
+		billing.Get("invoices/{id}", handler)
+
+		r2 := New("api")
+		r2.Mount("billing", billing)
+		r2.Mount("/", billing)
+
+		desc(t, 4, "insert every route of the sub-router beneath the prefix")
+		a.Exactly([]Route{
+			{Method: "GET", Path: "/api"},
+			{Method: "GET", Path: "/api/billing"},
+			{Method: "GET", Path: "/api/billing/invoices/{id}"},
+			{Method: "GET", Path: "/api/invoices/{id}"},
+		}, r2.Routes())
+
+		desc(t, 4, "panic when a mounted route already exists")
+		a.Panics(func() {
+			r2.Mount("billing", billing)
+		})
+	}
+
+	desc(t, 2, "Remove and Replace methods should")
+	{
+		status := func(code int) lambda.Handler {
+			return lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+				return events.APIGatewayProxyResponse{StatusCode: code}, nil
+			})
+		}
+		invoke := func(r Router, method, path string) int {
+			payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: method, Path: path})
+			resjson, err := r.Invoke(ctx, payload)
+			a.NoError(err)
+
+			var res events.APIGatewayProxyResponse
+			a.NoError(json.Unmarshal(resjson, &res))
+			return res.StatusCode
+		}
+
+		r2 := New("flags")
+		r2.Get("beta/{id}", status(http.StatusOK))
+		r2.Post("beta/{id}", status(http.StatusCreated))
+
+		desc(t, 4, "swap the handler of a route")
+		a.NoError(r2.Replace("get", "beta/{id}", status(http.StatusAccepted)))
+		a.Exactly(http.StatusAccepted, invoke(r2, http.MethodGet, "/flags/beta/1"))
+
+		desc(t, 4, "remove a route")
+		a.NoError(r2.Remove(http.MethodGet, "beta/{id}"))
+		a.Exactly(http.StatusMethodNotAllowed, invoke(r2, http.MethodGet, "/flags/beta/1"))
+		a.NoError(r2.Remove(http.MethodPost, "/beta/{id}/"))
+		a.Exactly(http.StatusNotFound, invoke(r2, http.MethodPost, "/flags/beta/1"))
+		a.Empty(r2.Routes())
+
+		desc(t, 4, "return an error when the route does not exist")
+		a.EqualError(r2.Remove(http.MethodGet, "beta/{id}"), "event 'GET/flags/beta/{id}' does not exist")
+		a.Error(r2.Replace(http.MethodGet, "beta/{id}", handler))
+
+		desc(t, 4, "return an error when the matcher cannot remove routes")
+		r3 := New("flags")
+		r3.UseMatcher(exactMatcher{})
+		r3.Get("beta", handler)
+		a.EqualError(r3.Remove(http.MethodGet, "beta"),
+			"matcher lambdarouter.exactMatcher does not support removing routes")
+	}
+
+	desc(t, 2, "SwapRoutes method should")
+	{
+		r2, next := New("swap"), New("swap")
+		r2.Get("old", handler)
+		next.Get("new", handler)
+		copied := r2
+
+		desc(t, 4, "replace every route of every copy of the router")
+		a.NoError(r2.SwapRoutes(next))
+		a.Exactly([]Route{{Method: "GET", Path: "/swap/new"}}, copied.Routes())
+
+		desc(t, 4, "return an error when either router is uninitialized")
+		a.Error(r2.SwapRoutes(Router{}))
+	}
+
+	desc(t, 2, "Merge function should")
+	{
+		users, orders := New("users"), New("orders")
+		users.Get("{id}", handler)
+		orders.Get("{id}", handler)
+		orders.Post("/", handler)
+
+		desc(t, 4, "combine the routes of every router")
+		merged, err := Merge(users, orders)
+		a.NoError(err)
+		a.Exactly("/users/", merged.Prefix())
+		a.Exactly([]Route{
+			{Method: "GET", Path: "/orders/{id}"},
+			{Method: "GET", Path: "/users/{id}"},
+			{Method: "POST", Path: "/orders"},
+		}, merged.Routes())
+
+		desc(t, 4, "report every conflicting route")
+		merged, err = Merge(users, orders, orders)
+		a.Regexp(`^2 route\(s\) could not be added: `+
+			`router 2: event 'GET/orders/\{id\}' already exists \(.*\); `+
+			`router 2: event 'POST/orders' already exists \(.*\)$`, err.Error())
+		a.Len(merged.Routes(), 3)
+
+		desc(t, 4, "combine the hooks and the routes of other events of every router")
+		var calls []string
+		hooks, actions := New("hooks"), New("actions", WithTenancy(TenantFromHeader("X-Tenant")))
+		hooks.OnColdStart(func(ctx context.Context) { calls = append(calls, "cold start") })
+		hooks.OnRequest(func(ctx context.Context, req *events.APIGatewayProxyRequest) { calls = append(calls, "request") })
+		actions.OnResponse(func(ctx context.Context, res *events.APIGatewayProxyResponse, err error) {
+			calls = append(calls, "response")
+		})
+		actions.Get("tenant", HandlerFunc(func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+			tenant, _ := TenantFrom(ctx)
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: tenant.ID}, nil
+		}))
+		actions.RPC("ping", lambda.NewHandler(func() (string, error) { return "pong", nil }))
+		merged, err = Merge(hooks, actions)
+		a.NoError(err)
+
+		res, err := merged.Invoke(context.Background(), []byte(`{"action": "ping"}`))
+		a.NoError(err)
+		a.Exactly(`"pong"`, string(res))
+
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodGet,
+			Path:       "/actions/tenant",
+			Headers:    map[string]string{"X-Tenant": "acme"},
+		})
+		res, err = merged.Invoke(context.Background(), payload)
+		a.NoError(err)
+		a.Contains(string(res), `"body":"acme"`)
+		a.Exactly([]string{"cold start", "request", "response"}, calls)
+
+		desc(t, 4, "report every conflicting RPC action")
+		_, err = Merge(hooks, actions, actions)
+		a.Regexp(`router 2: action 'ping' already exists`, err.Error())
+	}
+
+	desc(t, 2, "Use method and WithMiddleware option should")
+	{
+		var calls []string
+		mw := func(name string) Middleware {
+			return func(next lambda.Handler) lambda.Handler {
+				return lambda.NewHandler(func(ctx context.Context, payload json.RawMessage) (json.RawMessage, error) {
+					calls = append(calls, name)
+					return next.Invoke(ctx, payload)
+				})
+			}
+		}
+
+		r2 := New("mw")
+		r2.Use(mw("router"))
+		r2.Group("group", func(r *Router) {
+			r.Use(mw("group"))
+			r.Get("thing", handler, WithMiddleware(mw("route")))
+		})
+		r2.Get("other", handler)
+
+		desc(t, 4, "run middleware in the order they were added")
+		ejson, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/mw/group/thing"})
+		_, err := r2.Invoke(ctx, ejson)
+		a.NoError(err)
+		a.Exactly([]string{"router", "group", "route"}, calls)
+
+		desc(t, 4, "only apply group middleware to routes within the group")
+		calls = nil
+		ejson, _ = json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/mw/other"})
+		_, err = r2.Invoke(ctx, ejson)
+		a.NoError(err)
+		a.Exactly([]string{"router"}, calls)
+	}
+
 	desc(t, 2, "Invoke method should")
 	{
 		e := events.APIGatewayProxyRequest{
@@ -0,0 +1,75 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+// WithRPCField sets the field of direct invocation payloads which names the action routed by RPC.
+// It is action by default.
+func WithRPCField(name string) Option {
+	return func(r *Router) {
+		r.rpcField = name
+	}
+}
+
+// RPC adds a route for direct invocations of the function, such as those of other services made
+// with the Lambda Invoke API, whose payloads are JSON objects naming action in their action field,
+// as in {"action": "getUser", "id": "42"}. The handler parameter is invoked with the whole
+// payload, and its response is returned to the caller as is. It is wrapped by the middleware of
+// the router, which should not assume it handles HTTP requests. Invocations naming actions which
+// have no route fail. RPC panics if a route for action already exists.
+func (r *Router) RPC(action string, handler lambda.Handler) {
+	h := r.sourceHandler(handler)
+
+	r.addSource(func(s *sourceRoutes) {
+		if _, exists := s.rpc[action]; exists {
+			panic(fmt.Sprintf("action '%s' already exists", action))
+		}
+
+		rpc := make(map[string]lambda.Handler, len(s.rpc)+1)
+		for name, h := range s.rpc {
+			rpc[name] = h
+		}
+		rpc[action] = h
+		s.rpc = rpc
+	})
+}
+
+// rpcFieldName returns the field of direct invocation payloads which names their action.
+func (r Router) rpcFieldName() string {
+	if r.rpcField == "" {
+		return "action"
+	}
+
+	return r.rpcField
+}
+
+// invokeRPC routes payload if it names an action in the RPC field of the router, which is reported
+// by the second return value.
+func (r Router) invokeRPC(ctx context.Context, routes map[string]lambda.Handler, payload []byte) ([]byte, bool, error) {
+	codec := r.jsonCodec()
+
+	field := r.rpcFieldName()
+
+	var fields map[string]json.RawMessage
+	if err := codec.Unmarshal(payload, &fields); err != nil {
+		return nil, false, nil
+	}
+
+	var action string
+	if err := codec.Unmarshal(fields[field], &action); err != nil || action == "" {
+		return nil, false, nil
+	}
+
+	h, ok := routes[action]
+	if !ok {
+		return nil, true, fmt.Errorf("no route for action %q", action)
+	}
+
+	res, err := h.Invoke(ctx, payload)
+	return res, true, err
+}
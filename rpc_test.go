@@ -0,0 +1,69 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPC(t *testing.T) {
+	a := assert.New(t)
+
+	type getUser struct {
+		ID string `json:"id"`
+	}
+	type user struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	desc(t, 0, "Initialize Router with RPC and HTTP routes and")
+	r := New("prefix")
+	r.RPC("getUser", lambda.NewHandler(func(in getUser) (user, error) {
+		return user{ID: in.ID, Name: "Ada"}, nil
+	}))
+	r.Get("health", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}))
+
+	desc(t, 2, "RPC method should")
+	{
+		desc(t, 4, "route direct invocations by their action")
+		res, err := r.Invoke(context.Background(), []byte(`{"action": "getUser", "id": "42"}`))
+		a.NoError(err)
+		a.JSONEq(`{"id": "42", "name": "Ada"}`, string(res))
+
+		desc(t, 4, "fail for actions which have no route")
+		_, err = r.Invoke(context.Background(), []byte(`{"action": "deleteUser"}`))
+		a.EqualError(err, `no route for action "deleteUser"`)
+
+		desc(t, 4, "recover the panics of handlers")
+		r.RPC("crash", lambda.NewHandler(func() error { panic("crashed") }))
+		_, err = r.Invoke(context.Background(), []byte(`{"action": "crash"}`))
+		a.EqualError(err, "panic: crashed")
+
+		desc(t, 4, "still route HTTP requests")
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/prefix/health"})
+		res, err = r.Invoke(context.Background(), payload)
+		a.NoError(err)
+		a.Contains(string(res), `"statusCode":200`)
+
+		desc(t, 4, "panic on duplicate actions")
+		a.Panics(func() { r.RPC("getUser", lambda.NewHandler(func() {})) })
+	}
+
+	desc(t, 2, "WithRPCField function should")
+	{
+		desc(t, 4, "route by the given field")
+		r := New("prefix", WithRPCField("method"))
+		r.RPC("ping", lambda.NewHandler(func() (string, error) { return "pong", nil }))
+		res, err := r.Invoke(context.Background(), []byte(`{"method": "ping"}`))
+		a.NoError(err)
+		a.Exactly(`"pong"`, string(res))
+	}
+}
@@ -0,0 +1,81 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+type s3Route struct {
+	bucket    string
+	key       string
+	eventName string
+	h         lambda.Handler
+}
+
+// S3 adds a route for notifications of changes to the objects of S3 buckets. The bucket and key
+// parameters are patterns matched against the name of the bucket and the key of the object, in
+// which * matches any run of characters, so "uploads/*" matches keys by prefix and "*.png" by
+// suffix, and which match anything if empty. The eventName parameter is a pattern matched against
+// the type of the event, such as ObjectCreated:Put, with or without the s3: prefix. A type without
+// a colon, such as ObjectRemoved, matches every event of that type. The handler parameter is
+// invoked once for each record of the event, which decodes into an events.S3EventRecord, and is
+// wrapped by the middleware of the router, which should not assume it handles HTTP requests.
+//
+// Records are routed to the first route which matches them in the order they were defined. The
+// invocation fails if a handler fails or a record matches no route, so that S3 retries it.
+func (r *Router) S3(bucket, key, eventName string, handler lambda.Handler) {
+	eventName = strings.TrimPrefix(eventName, "s3:")
+	if eventName != "" && !strings.Contains(eventName, ":") {
+		eventName += ":*"
+	}
+
+	rt := s3Route{bucket: bucket, key: key, eventName: eventName, h: r.sourceHandler(handler)}
+
+	r.addSource(func(s *sourceRoutes) {
+		s.s3 = append(s.s3[:len(s.s3):len(s.s3)], rt)
+	})
+}
+
+func (r Router) invokeS3(ctx context.Context, routes []s3Route, payload []byte) ([]byte, error) {
+	codec := r.jsonCodec()
+
+	var batch struct {
+		Records []json.RawMessage
+	}
+	if err := codec.Unmarshal(payload, &batch); err != nil {
+		return nil, err
+	}
+
+	for _, raw := range batch.Records {
+		var record events.S3EventRecord
+		if err := codec.Unmarshal(raw, &record); err != nil {
+			return nil, err
+		}
+
+		if err := invokeS3Route(ctx, routes, record, raw); err != nil {
+			r.logf("S3 %s of %s/%s: %v", record.EventName, record.S3.Bucket.Name, record.S3.Object.Key, err)
+			return nil, err
+		}
+	}
+
+	return []byte("null"), nil
+}
+
+func invokeS3Route(ctx context.Context, routes []s3Route, record events.S3EventRecord, raw []byte) error {
+	bucket, key := record.S3.Bucket.Name, record.S3.Object.URLDecodedKey
+	eventName := strings.TrimPrefix(record.EventName, "s3:")
+
+	for _, rt := range routes {
+		if matchPattern(rt.bucket, bucket) && matchPattern(rt.key, key) && matchPattern(rt.eventName, eventName) {
+			_, err := rt.h.Invoke(ctx, raw)
+			return err
+		}
+	}
+
+	return fmt.Errorf("no route for %s of %s/%s", record.EventName, bucket, key)
+}
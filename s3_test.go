@@ -0,0 +1,56 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestS3(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with S3 routes and")
+	var handled []string
+	handler := func(name string) lambda.Handler {
+		return lambda.NewHandler(func(record events.S3EventRecord) error {
+			handled = append(handled, name+" "+record.S3.Object.Key)
+			return nil
+		})
+	}
+	r := New("prefix")
+	r.S3("uploads", "images/*.png", "ObjectCreated", handler("thumbnail"))
+	r.S3("uploads", "images/*", "s3:ObjectRemoved:*", handler("cleanup"))
+	r.S3("", "", "ObjectCreated:Put", handler("audit"))
+
+	invoke := func(records ...events.S3EventRecord) error {
+		handled = nil
+		payload, _ := json.Marshal(events.S3Event{Records: records})
+		_, err := r.Invoke(context.Background(), payload)
+		return err
+	}
+	record := func(bucket, key, eventName string) events.S3EventRecord {
+		var rec events.S3EventRecord
+		rec.EventSource, rec.EventName = "aws:s3", eventName
+		rec.S3.Bucket.Name, rec.S3.Object.Key = bucket, key
+		return rec
+	}
+
+	desc(t, 2, "S3 method should")
+	{
+		desc(t, 4, "route records by bucket, key, and event type")
+		a.NoError(invoke(
+			record("uploads", "images/cat.png", "ObjectCreated:CompleteMultipartUpload"),
+			record("uploads", "images/cat+1.png", "ObjectRemoved:Delete"),
+			record("reports", "daily.csv", "ObjectCreated:Put"),
+		))
+		a.Exactly([]string{"thumbnail images/cat.png", "cleanup images/cat+1.png", "audit daily.csv"}, handled)
+
+		desc(t, 4, "fail for records which match no route")
+		err := invoke(record("reports", "daily.csv", "ObjectRemoved:Delete"))
+		a.EqualError(err, "no route for ObjectRemoved:Delete of reports/daily.csv")
+	}
+}
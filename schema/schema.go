@@ -0,0 +1,130 @@
+// Package schema provides middleware which validates the JSON bodies of requests against a JSON
+// Schema before the handler of a route is invoked, for services whose contracts are schema-first.
+package schema
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/mitchell/lambdarouter/respond"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// Schema is a compiled JSON Schema document.
+type Schema struct {
+	s *jsonschema.Schema
+}
+
+// Compile parses and compiles the JSON Schema document doc.
+func Compile(doc string) (*Schema, error) {
+	s, err := jsonschema.CompileString("schema.json", doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Schema{s: s}, nil
+}
+
+// MustCompile is like Compile but panics if the document cannot be compiled. It simplifies the
+// definition of routes with a static schema, which the router would panic on anyway.
+func MustCompile(doc string) *Schema {
+	s, err := Compile(doc)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// ValidationError describes a single location in a request body which violates the schema.
+type ValidationError struct {
+	// Location is a JSON pointer to the invalid value within the request body.
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+// Validate returns a route option which validates the body of every request to the route against
+// s. Requests whose bodies are not valid JSON or violate the schema are responded to with a 422
+// listing the validation errors, and the handler of the route is not invoked.
+func Validate(s *Schema) lambdarouter.RouteOption {
+	return lambdarouter.WithMiddleware(Middleware(s))
+}
+
+// Middleware returns middleware which validates request bodies against s, as described by
+// Validate.
+func Middleware(s *Schema) lambdarouter.Middleware {
+	return func(next lambda.Handler) lambda.Handler {
+		return validator{s: s, next: next}
+	}
+}
+
+type validator struct {
+	s    *Schema
+	next lambda.Handler
+}
+
+func (v validator) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := lambdarouter.RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if errs := v.s.validate(req); len(errs) > 0 {
+		return lambdarouter.CodecFrom(ctx).Marshal(respond.JSONContext(ctx, http.StatusUnprocessableEntity, map[string]interface{}{
+			"message": "request body does not match the schema",
+			"errors":  errs,
+		}))
+	}
+
+	return v.next.Invoke(ctx, payload)
+}
+
+func (s *Schema) validate(req events.APIGatewayProxyRequest) []ValidationError {
+	body := []byte(req.Body)
+	if req.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(req.Body)
+		if err != nil {
+			return []ValidationError{{Location: "", Message: "body is not valid base64"}}
+		}
+		body = decoded
+	}
+
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	if err := dec.Decode(&doc); err != nil {
+		return []ValidationError{{Location: "", Message: "body is not valid JSON: " + err.Error()}}
+	}
+
+	err := s.s.Validate(doc)
+	if err == nil {
+		return nil
+	}
+
+	ve, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return []ValidationError{{Location: "", Message: err.Error()}}
+	}
+
+	return leafErrors(ve, nil)
+}
+
+// leafErrors flattens the tree of causes of a validation error into its most specific errors.
+func leafErrors(ve *jsonschema.ValidationError, errs []ValidationError) []ValidationError {
+	if len(ve.Causes) == 0 {
+		return append(errs, ValidationError{Location: ve.InstanceLocation, Message: ve.Message})
+	}
+
+	for _, cause := range ve.Causes {
+		errs = leafErrors(cause, errs)
+	}
+
+	return errs
+}
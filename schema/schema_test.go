@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+const orderSchema = `{
+	"type": "object",
+	"required": ["item", "quantity"],
+	"properties": {
+		"item": {"type": "string"},
+		"quantity": {"type": "integer", "minimum": 1}
+	}
+}`
+
+func TestValidate(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router and")
+	r := lambdarouter.New("prefix")
+	r.Post("orders", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusCreated}, nil
+	}), Validate(MustCompile(orderSchema)))
+
+	invoke := func(body string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodPost,
+			Path:       "/prefix/orders",
+			Body:       body,
+		})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "Validate option should")
+	{
+		desc(t, 4, "invoke the handler when the body matches the schema")
+		a.Exactly(http.StatusCreated, invoke(`{"item": "tea", "quantity": 2}`).StatusCode)
+
+		desc(t, 4, "respond with a 422 listing the errors when the body violates the schema")
+		res := invoke(`{"item": "tea", "quantity": 0}`)
+		a.Exactly(http.StatusUnprocessableEntity, res.StatusCode)
+
+		var body struct {
+			Errors []ValidationError `json:"errors"`
+		}
+		a.NoError(json.Unmarshal([]byte(res.Body), &body))
+		a.Len(body.Errors, 1)
+		a.Exactly("/quantity", body.Errors[0].Location)
+
+		desc(t, 4, "respond with a 422 when the body is not JSON")
+		a.Exactly(http.StatusUnprocessableEntity, invoke(`nope`).StatusCode)
+	}
+
+	desc(t, 2, "MustCompile function should")
+	{
+		desc(t, 4, "panic when the schema is invalid")
+		a.Panics(func() {
+			MustCompile(`{"type": 1}`)
+		})
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
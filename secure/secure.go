@@ -0,0 +1,173 @@
+// Package secure provides middleware which adds security headers, such as
+// Strict-Transport-Security and Content-Security-Policy, to responses.
+package secure
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+)
+
+// Config holds the values of the security headers. Headers whose value is empty are not added.
+type Config struct {
+	// StrictTransportSecurity is the value of the Strict-Transport-Security header.
+	StrictTransportSecurity string
+
+	// ContentTypeOptions is the value of the X-Content-Type-Options header.
+	ContentTypeOptions string
+
+	// FrameOptions is the value of the X-Frame-Options header.
+	FrameOptions string
+
+	// ContentSecurityPolicy is the value of the Content-Security-Policy header.
+	ContentSecurityPolicy string
+
+	// ReferrerPolicy is the value of the Referrer-Policy header.
+	ReferrerPolicy string
+}
+
+// DefaultConfig returns a configuration suited to APIs, whose responses are not meant to be
+// rendered as pages: HTTPS is required for two years, including subdomains, and responses may not
+// be sniffed, framed, or load any content.
+func DefaultConfig() Config {
+	return Config{
+		StrictTransportSecurity: "max-age=63072000; includeSubDomains",
+		ContentTypeOptions:      "nosniff",
+		FrameOptions:            "DENY",
+		ContentSecurityPolicy:   "default-src 'none'; frame-ancestors 'none'",
+		ReferrerPolicy:          "no-referrer",
+	}
+}
+
+func (c Config) headers() map[string]string {
+	return map[string]string{
+		"Strict-Transport-Security": c.StrictTransportSecurity,
+		"X-Content-Type-Options":    c.ContentTypeOptions,
+		"X-Frame-Options":           c.FrameOptions,
+		"Content-Security-Policy":   c.ContentSecurityPolicy,
+		"Referrer-Policy":           c.ReferrerPolicy,
+	}
+}
+
+type contextKey int
+
+const overridesKey contextKey = iota
+
+// Middleware returns middleware which adds the security headers of cfg to every response of the
+// routes it wraps, including those rendered from HTTPErrors, except where the handler set the
+// header itself. The headers of single routes can be changed with Override.
+func Middleware(cfg Config) lambdarouter.Middleware {
+	return func(next lambda.Handler) lambda.Handler {
+		return headerSetter{headers: cfg.headers(), next: next}
+	}
+}
+
+type headerSetter struct {
+	headers map[string]string
+	next    lambda.Handler
+}
+
+func (hs headerSetter) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	codec := lambdarouter.CodecFrom(ctx)
+
+	overrides := map[string]string{}
+
+	resjson, err := hs.next.Invoke(context.WithValue(ctx, overridesKey, overrides), payload)
+
+	headers := map[string]string{}
+	for name, value := range hs.headers {
+		headers[name] = value
+	}
+	for name, value := range overrides {
+		headers[name] = value
+	}
+
+	// HTTPErrors are rendered by the router once they reach it, so the headers are added to the
+	// error rather than to a response.
+	var httpErr *lambdarouter.HTTPError
+	if errors.As(err, &httpErr) {
+		errHeaders := map[string]string{}
+		for name, value := range httpErr.Headers {
+			errHeaders[name] = value
+		}
+		addHeaders(errHeaders, nil, headers)
+
+		withHeaders := *httpErr
+		withHeaders.Headers = errHeaders
+		return nil, &withHeaders
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var res events.APIGatewayProxyResponse
+	if err := codec.Unmarshal(resjson, &res); err != nil {
+		return resjson, nil
+	}
+
+	if res.Headers == nil {
+		res.Headers = map[string]string{}
+	}
+	addHeaders(res.Headers, res.MultiValueHeaders, headers)
+
+	return codec.Marshal(res)
+}
+
+// addHeaders adds the non-empty headers to dst, except those already in dst or multi.
+func addHeaders(dst map[string]string, multi map[string][]string, headers map[string]string) {
+	for name, value := range headers {
+		if value != "" && !hasHeader(dst, name) && !hasHeader(multi, name) {
+			dst[name] = value
+		}
+	}
+}
+
+// Override returns a route option which changes the security headers added to the responses of a
+// single route by Middleware, such as to allow a page to be framed. The overrides map the names of
+// headers to their values, and an empty value stops the header from being added. Headers other
+// than those of Config, such as Permissions-Policy, may be added as well.
+func Override(overrides map[string]string) lambdarouter.RouteOption {
+	return lambdarouter.WithMiddleware(func(next lambda.Handler) lambda.Handler {
+		return overrider{overrides: overrides, next: next}
+	})
+}
+
+type overrider struct {
+	overrides map[string]string
+	next      lambda.Handler
+}
+
+func (o overrider) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	if overrides, ok := ctx.Value(overridesKey).(map[string]string); ok {
+		for name, value := range o.overrides {
+			overrides[canonical(name)] = value
+		}
+	}
+
+	return o.next.Invoke(ctx, payload)
+}
+
+// canonical returns the name a security header is known by, regardless of the case it is given in.
+func canonical(name string) string {
+	for known := range (Config{}).headers() {
+		if strings.EqualFold(known, name) {
+			return known
+		}
+	}
+
+	return name
+}
+
+func hasHeader[V any](headers map[string]V, name string) bool {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,109 @@
+package secure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with security headers and")
+	r := lambdarouter.New("prefix")
+	r.Use(Middleware(DefaultConfig()))
+	r.Get("api", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}))
+	r.Get("teapot", lambda.NewHandler(func() error {
+		return &lambdarouter.HTTPError{Status: http.StatusTeapot}
+	}))
+	r.Get("page", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusOK,
+			Headers:    map[string]string{"content-security-policy": "default-src 'self'"},
+		}, nil
+	}), Override(map[string]string{
+		"x-frame-options":    "",
+		"Permissions-Policy": "camera=()",
+	}))
+
+	invoke := func(path string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "Middleware should")
+	{
+		desc(t, 4, "add the security headers to responses")
+		res := invoke("/prefix/api")
+		a.Exactly(map[string]string{
+			"Strict-Transport-Security": "max-age=63072000; includeSubDomains",
+			"X-Content-Type-Options":    "nosniff",
+			"X-Frame-Options":           "DENY",
+			"Content-Security-Policy":   "default-src 'none'; frame-ancestors 'none'",
+			"Referrer-Policy":           "no-referrer",
+		}, res.Headers)
+
+		desc(t, 4, "add them to error responses")
+		res = invoke("/prefix/teapot")
+		a.Exactly(http.StatusTeapot, res.StatusCode)
+		a.Exactly("nosniff", res.Headers["X-Content-Type-Options"])
+
+		desc(t, 4, "keep headers set by handlers and apply overrides")
+		res = invoke("/prefix/page")
+		a.Exactly("default-src 'self'", res.Headers["content-security-policy"])
+		a.NotContains(res.Headers, "Content-Security-Policy")
+		a.NotContains(res.Headers, "X-Frame-Options")
+		a.Exactly("camera=()", res.Headers["Permissions-Policy"])
+		a.Exactly("nosniff", res.Headers["X-Content-Type-Options"])
+
+		desc(t, 4, "decode and encode responses with the codec of the router")
+		codec := &recordingCodec{}
+		r2 := lambdarouter.New("", lambdarouter.WithCodec(codec))
+		r2.Use(Middleware(DefaultConfig()))
+		r2.Get("api", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+		}))
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/api"})
+		_, err := r2.Invoke(context.Background(), payload)
+		a.NoError(err)
+		a.Contains(codec.decoded, "*events.APIGatewayProxyResponse")
+		a.Contains(codec.encoded, "events.APIGatewayProxyResponse")
+	}
+}
+
+// recordingCodec is a lambdarouter.Codec which records the types it decodes and encodes.
+type recordingCodec struct {
+	decoded, encoded []string
+}
+
+func (c *recordingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.encoded = append(c.encoded, fmt.Sprintf("%T", v))
+	return json.Marshal(v)
+}
+
+func (c *recordingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.decoded = append(c.decoded, fmt.Sprintf("%T", v))
+	return json.Unmarshal(data, v)
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
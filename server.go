@@ -0,0 +1,151 @@
+package lambdarouter
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// ListenAndServe listens on the TCP network address addr and serves the routes defined on r as an
+// HTTP server. It is intended for local development, allowing a function to be run and curled
+// without deploying it or emulating API Gateway.
+func ListenAndServe(addr string, r Router) error {
+	return http.ListenAndServe(addr, r)
+}
+
+// ServeHTTP implements the http.Handler interface for the Router type. The incoming request is
+// converted into an API Gateway proxy request, routed and invoked exactly as it would be in Lambda,
+// and the resulting proxy response is written back to w.
+func (r Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	proxyReq, err := proxyRequest(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if events, params, found := r.lookup(r.requestMethod(proxyReq), proxyReq.Path, &proxyReq); found {
+		proxyReq.Resource = events[0].rt.Path
+		proxyReq.RequestContext.ResourcePath = events[0].rt.Path
+		proxyReq.PathParameters = params
+	}
+
+	payload, err := r.jsonCodec().Marshal(proxyReq)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Lambda never invokes a function concurrently within an instance, and handlers such as those
+	// created by lambda.NewHandler rely on it by reusing their buffers.
+	if r.table != nil {
+		r.table.serving.Lock()
+		defer r.table.serving.Unlock()
+	}
+	ctx := r.invocationContext(req.Context())
+
+	// The request is always in the version 1.0 format, whatever the payload format of the router.
+	res, err := r.route(ctx, proxyReq, payload)
+	if err != nil {
+		// API Gateway responds with a 502 when the function itself returns an error.
+		http.Error(w, `{"message": "Internal server error"}`, http.StatusBadGateway)
+		return
+	}
+
+	if err := writeProxyResponse(w, r.jsonCodec(), res); err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	}
+}
+
+func proxyRequest(req *http.Request) (events.APIGatewayProxyRequest, error) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return events.APIGatewayProxyRequest{}, err
+	}
+
+	proxyReq := events.APIGatewayProxyRequest{
+		HTTPMethod:                      req.Method,
+		Path:                            req.URL.Path,
+		Headers:                         map[string]string{},
+		MultiValueHeaders:               map[string][]string(req.Header),
+		QueryStringParameters:           map[string]string{},
+		MultiValueQueryStringParameters: map[string][]string(req.URL.Query()),
+		RequestContext: events.APIGatewayProxyRequestContext{
+			Stage:      "local",
+			HTTPMethod: req.Method,
+			RequestID:  localRequestID(),
+		},
+	}
+
+	if req.Host != "" {
+		proxyReq.Headers["Host"] = req.Host
+	}
+	for name, values := range req.Header {
+		proxyReq.Headers[name] = values[0]
+	}
+	for name, values := range proxyReq.MultiValueQueryStringParameters {
+		proxyReq.QueryStringParameters[name] = values[0]
+	}
+
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		proxyReq.RequestContext.Identity.SourceIP = host
+	}
+	proxyReq.RequestContext.Identity.UserAgent = req.UserAgent()
+
+	if utf8.Valid(body) {
+		proxyReq.Body = string(body)
+	} else {
+		proxyReq.Body = base64.StdEncoding.EncodeToString(body)
+		proxyReq.IsBase64Encoded = true
+	}
+
+	return proxyReq, nil
+}
+
+func localRequestID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
+
+func writeProxyResponse(w http.ResponseWriter, codec Codec, payload []byte) error {
+	var res events.APIGatewayProxyResponse
+
+	if err := codec.Unmarshal(payload, &res); err != nil {
+		return err
+	}
+
+	body := []byte(res.Body)
+	if res.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(res.Body)
+		if err != nil {
+			return err
+		}
+		body = decoded
+	}
+
+	for name, value := range res.Headers {
+		w.Header().Set(name, value)
+	}
+	for name, values := range res.MultiValueHeaders {
+		w.Header().Del(name)
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+
+	if res.StatusCode == 0 {
+		res.StatusCode = http.StatusOK
+	}
+
+	w.WriteHeader(res.StatusCode)
+	_, err := w.Write(body)
+
+	return err
+}
@@ -0,0 +1,88 @@
+package lambdarouter
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeHTTP(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router and")
+	r := New("prefix")
+
+	r.Get("hello/{name}", lambda.NewHandler(func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusOK,
+			Headers:    map[string]string{"X-Route": req.Resource},
+			Body:       "hello " + req.PathParameters["name"],
+		}, nil
+	}))
+	r.Post("echo", lambda.NewHandler(func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusCreated,
+			Body:       req.Body + " " + req.QueryStringParameters["q"],
+		}, nil
+	}))
+
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	desc(t, 2, "ServeHTTP method should")
+	{
+		desc(t, 4, "route a request with path parameters")
+		res, err := http.Get(srv.URL + "/prefix/hello/mitchell")
+		a.NoError(err)
+
+		body, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("/prefix/hello/{name}", res.Header.Get("X-Route"))
+		a.Exactly("hello mitchell", string(body))
+
+		desc(t, 4, "pass along the body and query string")
+		res, err = http.Post(srv.URL+"/prefix/echo?q=there", "text/plain", strings.NewReader("hi"))
+		a.NoError(err)
+
+		body, _ = ioutil.ReadAll(res.Body)
+		res.Body.Close()
+
+		a.Exactly(http.StatusCreated, res.StatusCode)
+		a.Exactly("hi there", string(body))
+
+		desc(t, 4, "write the not found response of the router")
+		res, err = http.Get(srv.URL + "/prefix/nothing")
+		a.NoError(err)
+		res.Body.Close()
+
+		a.Exactly(http.StatusNotFound, res.StatusCode)
+
+		desc(t, 4, "route requests with the codec and response marshaler of the router")
+		codec := &countingCodec{}
+		r2 := New("", WithCodec(codec), WithResponseMarshaler(Envelope(nil)))
+		r2.Get("typed", HandlerOf(func(ctx context.Context, in struct{}) (map[string]int, error) {
+			a.Same(codec, CodecFrom(ctx))
+			return map[string]int{"n": 1}, nil
+		}))
+		srv2 := httptest.NewServer(r2)
+		defer srv2.Close()
+
+		res, err = http.Get(srv2.URL + "/typed")
+		a.NoError(err)
+
+		body, _ = ioutil.ReadAll(res.Body)
+		res.Body.Close()
+
+		a.Exactly(`{"data":{"n":1}}`, string(body))
+		a.NotZero(codec.marshals)
+	}
+}
@@ -0,0 +1,62 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/mitchell/lambdarouter/internal/dynamo"
+)
+
+// DynamoDBStore is a Store which holds sessions in a DynamoDB table, so that they are shared by
+// every container of a function. The table must have a string partition key named pk. Each item
+// has a ttl attribute holding the time it expires, after which it can be deleted by enabling time
+// to live on the table; expired items are ignored either way.
+type DynamoDBStore struct {
+	table  string
+	client *dynamo.Client
+}
+
+// NewDynamoDBStore returns a store holding sessions in table. Requests to DynamoDB are signed with
+// the credentials of the execution role of the function, in the region it runs in.
+func NewDynamoDBStore(table string) *DynamoDBStore {
+	return &DynamoDBStore{table: table, client: dynamo.FromEnv()}
+}
+
+// Load implements the Store interface for the DynamoDBStore type.
+func (s *DynamoDBStore) Load(ctx context.Context, id string) (map[string]string, bool, error) {
+	item, err := s.client.GetItem(ctx, s.table, dynamo.Item{"pk": dynamo.S(id)})
+	if err != nil || item == nil {
+		return nil, false, err
+	}
+
+	if time.Now().Unix() >= item["ttl"].Int() {
+		return nil, false, nil
+	}
+
+	var values map[string]string
+	if err := json.Unmarshal([]byte(item["data"].String()), &values); err != nil {
+		return nil, false, err
+	}
+
+	return values, true, nil
+}
+
+// Save implements the Store interface for the DynamoDBStore type.
+func (s *DynamoDBStore) Save(ctx context.Context, id string, values map[string]string, ttl time.Duration) error {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	return s.client.PutItem(ctx, s.table, dynamo.Item{
+		"pk":   dynamo.S(id),
+		"data": dynamo.S(string(data)),
+		"ttl":  dynamo.N(time.Now().Add(ttl).Unix()),
+	}, "", nil, nil)
+}
+
+// Delete implements the Store interface for the DynamoDBStore type.
+func (s *DynamoDBStore) Delete(ctx context.Context, id string) error {
+	return s.client.DeleteItem(ctx, s.table, dynamo.Item{"pk": dynamo.S(id)})
+}
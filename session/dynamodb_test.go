@@ -0,0 +1,42 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mitchell/lambdarouter/internal/dynamotest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamoDBStore(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a fake DynamoDB and")
+	srv := dynamotest.NewServer()
+	defer srv.Close()
+
+	s := NewDynamoDBStore("sessions")
+	s.client = srv.Client()
+	ctx := context.Background()
+
+	desc(t, 2, "DynamoDBStore type should")
+	{
+		desc(t, 4, "load saved sessions")
+		a.NoError(s.Save(ctx, "s1", map[string]string{"user": "42"}, time.Hour))
+		values, found, err := s.Load(ctx, "s1")
+		a.NoError(err)
+		a.True(found)
+		a.Exactly(map[string]string{"user": "42"}, values)
+
+		desc(t, 4, "not load deleted or expired sessions")
+		a.NoError(s.Delete(ctx, "s1"))
+		_, found, err = s.Load(ctx, "s1")
+		a.NoError(err)
+		a.False(found)
+
+		a.NoError(s.Save(ctx, "s2", map[string]string{}, -time.Minute))
+		_, found, _ = s.Load(ctx, "s2")
+		a.False(found)
+	}
+}
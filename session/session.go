@@ -0,0 +1,293 @@
+// Package session provides middleware which keeps per-client session data between requests,
+// identified by a cookie, for applications which render HTML or carry out OAuth flows through the
+// router.
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+)
+
+// Session holds the data of the session of the client making the current request. Its values are
+// strings, so structured values should be encoded, such as in JSON, before they are set.
+type Session struct {
+	id      string
+	oldID   string
+	values  map[string]string
+	changed bool
+	deleted bool
+}
+
+// ID returns the identifier of the session, which is empty for sessions which have not been saved
+// yet.
+func (s *Session) ID() string {
+	return s.id
+}
+
+// Get returns the value stored under key, or an empty string if there is none.
+func (s *Session) Get(key string) string {
+	return s.values[key]
+}
+
+// Set stores value under key.
+func (s *Session) Set(key, value string) {
+	if s.values == nil {
+		s.values = map[string]string{}
+	}
+
+	s.values[key] = value
+	s.changed = true
+}
+
+// Delete removes the value stored under key.
+func (s *Session) Delete(key string) {
+	if _, ok := s.values[key]; ok {
+		delete(s.values, key)
+		s.changed = true
+	}
+}
+
+// RenewID gives the session a new identifier, keeping its values, and deletes it under the old
+// one. It should be called when the privileges of the client change, such as when signing in, to
+// prevent session fixation.
+func (s *Session) RenewID() {
+	if s.oldID == "" {
+		s.oldID = s.id
+	}
+
+	s.id = ""
+	s.changed = true
+}
+
+// Destroy deletes the session and its values, and expires its cookie, such as when signing out.
+func (s *Session) Destroy() {
+	s.values = nil
+	s.deleted = true
+}
+
+type contextKey int
+
+const sessionKey contextKey = iota
+
+// FromContext returns the session of the current invocation. It returns nil if the invocation was
+// not handled by the session middleware.
+func FromContext(ctx context.Context) *Session {
+	s, _ := ctx.Value(sessionKey).(*Session)
+	return s
+}
+
+// Store holds the values of sessions.
+type Store interface {
+	// Load returns the values of the session id, reporting whether it exists and has not
+	// expired.
+	Load(ctx context.Context, id string) (values map[string]string, found bool, err error)
+
+	// Save stores the values of the session id for ttl.
+	Save(ctx context.Context, id string, values map[string]string, ttl time.Duration) error
+
+	// Delete removes the session id.
+	Delete(ctx context.Context, id string) error
+}
+
+// MemoryStore is a Store which holds sessions in the memory of the container. Each concurrent
+// execution environment of a function holds different sessions, so it is only suited to tests
+// and development.
+type MemoryStore struct {
+	mu       sync.Mutex
+	sessions map[string]memorySession
+}
+
+type memorySession struct {
+	data    []byte
+	expires time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: map[string]memorySession{}}
+}
+
+// Load implements the Store interface for the MemoryStore type.
+func (s *MemoryStore) Load(_ context.Context, id string) (map[string]string, bool, error) {
+	s.mu.Lock()
+	ms, ok := s.sessions[id]
+	s.mu.Unlock()
+
+	if !ok || time.Now().After(ms.expires) {
+		return nil, false, nil
+	}
+
+	var values map[string]string
+	err := json.Unmarshal(ms.data, &values)
+
+	return values, err == nil, err
+}
+
+// Save implements the Store interface for the MemoryStore type.
+func (s *MemoryStore) Save(_ context.Context, id string, values map[string]string, ttl time.Duration) error {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sessions == nil {
+		s.sessions = map[string]memorySession{}
+	}
+	s.sessions[id] = memorySession{data: data, expires: time.Now().Add(ttl)}
+
+	return nil
+}
+
+// Delete implements the Store interface for the MemoryStore type.
+func (s *MemoryStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, id)
+
+	return nil
+}
+
+// Config configures the session middleware.
+type Config struct {
+	// Store holds the sessions.
+	Store Store
+
+	// TTL is how long sessions last after they were last changed. If zero, it is 24 hours.
+	TTL time.Duration
+
+	// CookieName is the name of the cookie holding the session ID. If empty, it is "session".
+	CookieName string
+
+	// CookiePath and CookieDomain scope the cookie. If CookiePath is empty, it is "/".
+	CookiePath   string
+	CookieDomain string
+
+	// Insecure allows the cookie to be sent over plain HTTP, such as when running locally.
+	Insecure bool
+
+	// SameSite sets the SameSite attribute of the cookie. If zero, it is http.SameSiteLaxMode,
+	// which still sends the cookie when the client is redirected back by an OAuth provider.
+	SameSite http.SameSite
+}
+
+// Middleware returns middleware which places the session of the client, identified by its cookie,
+// in the context of the handler, from which it can be retrieved with FromContext. Clients without
+// a valid session cookie are given an empty session. Once the handler returns, changed sessions are
+// saved to the store and their cookie set, and destroyed sessions are deleted and their cookie
+// expired. Sessions are not saved if the handler fails. The cookie is HttpOnly and, unless
+// cfg.Insecure is set, Secure.
+func Middleware(cfg Config) lambdarouter.Middleware {
+	if cfg.TTL == 0 {
+		cfg.TTL = 24 * time.Hour
+	}
+	if cfg.CookieName == "" {
+		cfg.CookieName = "session"
+	}
+	if cfg.CookiePath == "" {
+		cfg.CookiePath = "/"
+	}
+	if cfg.SameSite == 0 {
+		cfg.SameSite = http.SameSiteLaxMode
+	}
+
+	return func(next lambda.Handler) lambda.Handler {
+		return sessions{cfg: cfg, next: next}
+	}
+}
+
+type sessions struct {
+	cfg  Config
+	next lambda.Handler
+}
+
+func (ss sessions) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	codec := lambdarouter.CodecFrom(ctx)
+
+	req, err := lambdarouter.RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Session{}
+	if c, err := lambdarouter.Cookie(req, ss.cfg.CookieName); err == nil && c.Value != "" {
+		values, found, err := ss.cfg.Store.Load(ctx, c.Value)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			s.id, s.values = c.Value, values
+		}
+	}
+
+	resjson, err := ss.next.Invoke(context.WithValue(ctx, sessionKey, s), payload)
+	if err != nil || (!s.changed && !s.deleted) {
+		return resjson, err
+	}
+
+	var res events.APIGatewayProxyResponse
+	if err := codec.Unmarshal(resjson, &res); err != nil {
+		return nil, err
+	}
+
+	for _, id := range []string{s.oldID, s.id} {
+		if id == "" || (id == s.id && !s.deleted) {
+			continue
+		}
+		if err := ss.cfg.Store.Delete(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.deleted {
+		lambdarouter.SetCookie(&res, ss.cookie("", -1))
+		return codec.Marshal(res)
+	}
+
+	if s.id == "" {
+		if s.id, err = newID(); err != nil {
+			return nil, err
+		}
+	}
+	if err := ss.cfg.Store.Save(ctx, s.id, s.values, ss.cfg.TTL); err != nil {
+		return nil, err
+	}
+	lambdarouter.SetCookie(&res, ss.cookie(s.id, int(ss.cfg.TTL/time.Second)))
+
+	return codec.Marshal(res)
+}
+
+func (ss sessions) cookie(value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     ss.cfg.CookieName,
+		Value:    value,
+		Path:     ss.cfg.CookiePath,
+		Domain:   ss.cfg.CookieDomain,
+		MaxAge:   maxAge,
+		Secure:   !ss.cfg.Insecure,
+		HttpOnly: true,
+		SameSite: ss.cfg.SameSite,
+	}
+}
+
+// newID returns a random session ID of 256 bits.
+func newID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,109 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with sessions and")
+	store := NewMemoryStore()
+	r := lambdarouter.New("prefix")
+	r.Use(Middleware(Config{Store: store}))
+	r.Get("visit", lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+		s := FromContext(ctx)
+		s.Set("visits", s.Get("visits")+"I")
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: s.Get("visits")}, nil
+	}))
+	r.Get("peek", lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: FromContext(ctx).Get("visits")}, nil
+	}))
+	r.Post("login", lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+		FromContext(ctx).RenewID()
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusNoContent}, nil
+	}))
+	r.Post("logout", lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+		FromContext(ctx).Destroy()
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusNoContent}, nil
+	}))
+
+	invoke := func(method, path, cookie string) (events.APIGatewayProxyResponse, *http.Cookie) {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod: method,
+			Path:       path,
+			Headers:    map[string]string{"Cookie": cookie},
+		})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+
+		hr := http.Response{Header: http.Header{"Set-Cookie": res.MultiValueHeaders["Set-Cookie"]}}
+		if cookies := hr.Cookies(); len(cookies) > 0 {
+			return res, cookies[0]
+		}
+		return res, nil
+	}
+
+	desc(t, 2, "Middleware should")
+	{
+		desc(t, 4, "save changed sessions and set their cookie")
+		res, c := invoke(http.MethodGet, "/prefix/visit", "")
+		a.Exactly("I", res.Body)
+		a.NotNil(c)
+		a.True(c.HttpOnly)
+		a.True(c.Secure)
+		a.Exactly(http.SameSiteLaxMode, c.SameSite)
+		a.Exactly(86400, c.MaxAge)
+		cookie := "session=" + c.Value
+
+		desc(t, 4, "load sessions by their cookie")
+		res, _ = invoke(http.MethodGet, "/prefix/visit", cookie)
+		a.Exactly("II", res.Body)
+
+		desc(t, 4, "not set cookies for unchanged sessions")
+		res, c = invoke(http.MethodGet, "/prefix/peek", cookie)
+		a.Exactly("II", res.Body)
+		a.Nil(c)
+
+		desc(t, 4, "give clients with unknown cookies an empty session")
+		res, _ = invoke(http.MethodGet, "/prefix/peek", "session=forged")
+		a.Exactly("", res.Body)
+
+		desc(t, 4, "move renewed sessions to a new ID")
+		_, c = invoke(http.MethodPost, "/prefix/login", cookie)
+		a.NotEqual(strings.TrimPrefix(cookie, "session="), c.Value)
+		res, _ = invoke(http.MethodGet, "/prefix/peek", cookie)
+		a.Exactly("", res.Body)
+		cookie = "session=" + c.Value
+		res, _ = invoke(http.MethodGet, "/prefix/peek", cookie)
+		a.Exactly("II", res.Body)
+
+		desc(t, 4, "delete destroyed sessions and expire their cookie")
+		_, c = invoke(http.MethodPost, "/prefix/logout", cookie)
+		a.Exactly(-1, c.MaxAge)
+		res, _ = invoke(http.MethodGet, "/prefix/peek", cookie)
+		a.Exactly("", res.Body)
+		a.Empty(store.sessions)
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
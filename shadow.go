@@ -0,0 +1,91 @@
+package lambdarouter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+// ShadowResult is the outcome of an invocation of the handler of a route or of its shadow.
+type ShadowResult struct {
+	Response []byte
+	Err      error
+	Duration time.Duration
+}
+
+// ShadowReport receives the results of the handler of a route and of its shadow for a request, to
+// compare them.
+type ShadowReport func(ctx context.Context, primary, shadow ShadowResult)
+
+// WithShadow sets a handler which is invoked with a copy of every request to a route alongside its
+// handler, to validate a rewritten handler against production traffic. The response of the route
+// is always that of its handler, and report is called with the results of both, such as to log
+// the requests to which they responded differently. The shadow runs concurrently with the handler,
+// and the invocation waits for it before responding, as Lambda freezes the function once it has
+// responded. The shadow is not wrapped by the middleware given with WithMiddleware, its panics are
+// recovered and reported as errors, and it can tell it is a shadow with IsShadow, so it can avoid
+// side effects such as writing to a database the handler also writes to. The response of the shadow
+// of a streamed route is buffered, so it never takes the place of that of the handler.
+func WithShadow(h lambda.Handler, report ShadowReport) RouteOption {
+	return func(e *event) {
+		e.shadow = &shadow{h: h, report: report}
+	}
+}
+
+// IsShadow reports whether the current invocation is of the shadow of a route.
+func IsShadow(ctx context.Context) bool {
+	is, _ := ctx.Value(shadowKey).(bool)
+	return is
+}
+
+type shadow struct {
+	h      lambda.Handler
+	report ShadowReport
+}
+
+// shadowHandler invokes a handler along with its shadow.
+type shadowHandler struct {
+	next   lambda.Handler
+	shadow *shadow
+}
+
+func (sh shadowHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	var shadowed ShadowResult
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		shadowed = sh.invokeShadow(withoutStream(context.WithValue(ctx, shadowKey, true)), append([]byte(nil), payload...))
+	}()
+
+	start := time.Now()
+	res, err := sh.next.Invoke(ctx, payload)
+	primary := ShadowResult{Response: res, Err: err, Duration: time.Since(start)}
+
+	wg.Wait()
+
+	if sh.shadow.report != nil {
+		sh.shadow.report(ctx, primary, shadowed)
+	}
+
+	return res, err
+}
+
+func (sh shadowHandler) invokeShadow(ctx context.Context, payload []byte) (result ShadowResult) {
+	start := time.Now()
+
+	defer func() {
+		if p := recover(); p != nil {
+			result = ShadowResult{Err: fmt.Errorf("panic: %v", p)}
+		}
+		result.Duration = time.Since(start)
+	}()
+
+	res, err := sh.shadow.h.Invoke(ctx, payload)
+
+	return ShadowResult{Response: append([]byte(nil), res...), Err: err}
+}
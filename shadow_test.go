@@ -0,0 +1,71 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithShadow(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with shadowed routes and")
+	var primary, shadowed ShadowResult
+	var primaryShadow, shadowShadow bool
+	report := func(ctx context.Context, p, s ShadowResult) {
+		primary, shadowed = p, s
+	}
+
+	r := New("")
+	r.Get("users/{id}", lambda.NewHandler(func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		primaryShadow = IsShadow(ctx)
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: "v1 " + req.PathParameters["id"]}, nil
+	}), WithShadow(lambda.NewHandler(func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		shadowShadow = IsShadow(ctx)
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: "v2 " + req.PathParameters["id"]}, nil
+	}), report))
+	r.Get("panicking", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}), WithShadow(lambda.NewHandler(func() error {
+		panic("oops")
+	}), report))
+
+	invoke := func(path string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+	body := func(res []byte) string {
+		var out events.APIGatewayProxyResponse
+		_ = json.Unmarshal(res, &out)
+		return out.Body
+	}
+
+	desc(t, 2, "WithShadow option should")
+	{
+		desc(t, 4, "respond with the response of the handler")
+		a.Exactly("v1 42", invoke("/users/42").Body)
+
+		desc(t, 4, "report the results of the handler and of the shadow")
+		a.Exactly("v1 42", body(primary.Response))
+		a.Exactly("v2 42", body(shadowed.Response))
+
+		desc(t, 4, "tell the shadow it is a shadow")
+		a.False(primaryShadow)
+		a.True(shadowShadow)
+
+		desc(t, 4, "recover panics of the shadow")
+		a.Exactly(http.StatusOK, invoke("/panicking").StatusCode)
+		a.EqualError(shadowed.Err, "panic: oops")
+		a.NoError(primary.Err)
+	}
+}
@@ -0,0 +1,69 @@
+package lambdarouter
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// packagePath is the import path of the package, whose own frames are skipped when finding where a
+// route was defined.
+var packagePath = reflect.TypeOf(Router{}).PkgPath()
+
+// definitionSite returns the file and line, as in "routes.go:42" with the full path of the file,
+// of the call outside of the package which is defining a route, or the empty string if there is
+// none. Calls from the tests of the package count as outside of it.
+func definitionSite() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, packagePath+".") || strings.HasSuffix(f.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// withSite returns a route option which records where the route was first defined, for routes
+// defined again elsewhere, such as by Mount.
+func withSite(site string) RouteOption {
+	return func(e *event) {
+		e.site = site
+	}
+}
+
+// sites describes where existing and e were defined, for the errors of routes which conflict with
+// existing routes, or returns the empty string if that is unknown.
+func sites(existing, e event) string {
+	if existing.site == "" || e.site == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(" (first defined at %s, again at %s)", existing.site, e.site)
+}
+
+// conflicting returns the event of a route which rt conflicts with in the matcher, as their
+// templates only differ by the names of their parameters.
+func (t *routeTable) conflicting(rt Route) (event, bool) {
+	for _, g := range t.groups {
+		existing := g.events[0]
+		if existing.rt.Method != rt.Method {
+			continue
+		}
+
+		for _, a := range matcherTemplates(existing.rt.Path) {
+			for _, b := range matcherTemplates(rt.Path) {
+				if strings.EqualFold(normalize(a), normalize(b)) {
+					return existing, true
+				}
+			}
+		}
+	}
+
+	return event{}, false
+}
@@ -0,0 +1,52 @@
+package lambdarouter
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+// defineUsers defines a route at a known line, as a package of an application would in init.
+func defineUsers(r *Router) (site string) {
+	_, file, line, _ := runtime.Caller(0)
+	r.Get("users/{id}", lambda.NewHandler(handler))
+	return fmt.Sprintf("%s:%d", file, line+1)
+}
+
+func TestDefinitionSites(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with a route and")
+	r := New("")
+	first := defineUsers(&r)
+
+	desc(t, 2, "TryHandle method should")
+	{
+		desc(t, 4, "report where both definitions of a duplicate route are")
+		_, file, line, _ := runtime.Caller(0)
+		err := r.TryGet("users/{id}", lambda.NewHandler(handler))
+		a.EqualError(err, fmt.Sprintf("event 'GET/users/{id}' already exists (first defined at %s, again at %s:%d)",
+			first, file, line+1))
+
+		desc(t, 4, "report where a route whose template conflicts is defined")
+		_, file, line, _ = runtime.Caller(0)
+		err = r.TryGet("users/{name}", lambda.NewHandler(handler))
+		a.EqualError(err, fmt.Sprintf("event 'GET/users/{name}' could not be added: "+
+			"a route matching the same paths as 'GET/users/{name}' already exists "+
+			"(first defined at %s, again at %s:%d)", first, file, line+1))
+	}
+
+	desc(t, 2, "Mount method should")
+	{
+		desc(t, 4, "keep where mounted routes were defined")
+		sub := New("")
+		subSite := defineUsers(&sub)
+		parent := New("")
+		parent.Mount("/", sub)
+		a.PanicsWithValue(fmt.Sprintf("event 'GET/users/{id}' already exists (first defined at %s, again at %s)",
+			subSite, subSite), func() { parent.Mount("/", sub) })
+	}
+}
@@ -0,0 +1,23 @@
+//go:build go1.21
+
+package lambdarouter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// WithSlog sets the structured logger the router reports its diagnostics to, each as a message
+// logged at the warning level.
+func WithSlog(l *slog.Logger) Option {
+	return WithLogger(slogLogger{l: l})
+}
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (s slogLogger) Printf(format string, v ...interface{}) {
+	s.l.Log(context.Background(), slog.LevelWarn, fmt.Sprintf(format, v...))
+}
@@ -0,0 +1,36 @@
+//go:build go1.21
+
+package lambdarouter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSlog(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with a structured logger and")
+	var out bytes.Buffer
+	r := New("", WithSlog(slog.New(slog.NewJSONHandler(&out, nil))))
+
+	desc(t, 2, "WithSlog option should")
+	{
+		desc(t, 4, "log diagnostics as warnings")
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/nothing"})
+		_, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var entry map[string]interface{}
+		a.NoError(json.Unmarshal(out.Bytes(), &entry))
+		a.Exactly("WARN", entry["level"])
+		a.Exactly("no route for GET /nothing", entry["msg"])
+	}
+}
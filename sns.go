@@ -0,0 +1,112 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+type snsRoute struct {
+	topic      string
+	attributes map[string]string
+	h          lambda.Handler
+}
+
+// SNS adds a route for notifications delivered by SNS topics. The topic parameter is a pattern
+// matched against the ARN of the topic a notification was published to, in which * matches any run
+// of characters, and which matches any topic if empty. The attributes parameter maps the names of
+// message attributes to patterns their values must match, and may be nil. The handler parameter
+// is invoked once for each record of the event, which decodes into an events.SNSEventRecord, and
+// is wrapped by the middleware of the router, which should not assume it handles HTTP requests.
+// SNSHandlerOf creates handlers which receive the message decoded from JSON.
+//
+// Notifications are routed to the first route which matches them in the order they were defined.
+// The invocation fails if a handler fails or a notification matches no route, so that SNS retries
+// it.
+func (r *Router) SNS(topic string, attributes map[string]string, handler lambda.Handler) {
+	rt := snsRoute{topic: topic, attributes: attributes, h: r.sourceHandler(handler)}
+
+	r.addSource(func(s *sourceRoutes) {
+		s.sns = append(s.sns[:len(s.sns):len(s.sns)], rt)
+	})
+}
+
+// SNSHandlerOf returns a lambda.Handler for SNS routes which decodes the message of a notification
+// from JSON into a T, and invokes fn with it along with the notification. Messages which cannot be
+// decoded fail the invocation.
+func SNSHandlerOf[T any](fn func(ctx context.Context, n events.SNSEntity, msg T) error) lambda.Handler {
+	return snsHandler[T]{fn: fn}
+}
+
+type snsHandler[T any] struct {
+	fn func(ctx context.Context, n events.SNSEntity, msg T) error
+}
+
+func (sh snsHandler[T]) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	codec := CodecFrom(ctx)
+
+	var record events.SNSEventRecord
+	if err := codec.Unmarshal(payload, &record); err != nil {
+		return nil, err
+	}
+
+	var msg T
+	if err := codec.Unmarshal([]byte(record.SNS.Message), &msg); err != nil {
+		return nil, fmt.Errorf("decoding message %s: %w", record.SNS.MessageID, err)
+	}
+
+	return nil, sh.fn(ctx, record.SNS, msg)
+}
+
+func (r Router) invokeSNS(ctx context.Context, routes []snsRoute, payload []byte) ([]byte, error) {
+	codec := r.jsonCodec()
+
+	var batch struct {
+		Records []json.RawMessage
+	}
+	if err := codec.Unmarshal(payload, &batch); err != nil {
+		return nil, err
+	}
+
+	for _, raw := range batch.Records {
+		var record events.SNSEventRecord
+		if err := codec.Unmarshal(raw, &record); err != nil {
+			return nil, err
+		}
+
+		if err := invokeSNSRoute(ctx, routes, record.SNS, raw); err != nil {
+			r.logf("SNS message %s: %v", record.SNS.MessageID, err)
+			return nil, err
+		}
+	}
+
+	return []byte("null"), nil
+}
+
+func invokeSNSRoute(ctx context.Context, routes []snsRoute, n events.SNSEntity, record []byte) error {
+	for _, rt := range routes {
+		if matchPattern(rt.topic, n.TopicArn) && snsAttributesMatch(rt.attributes, n.MessageAttributes) {
+			_, err := rt.h.Invoke(ctx, record)
+			return err
+		}
+	}
+
+	return fmt.Errorf("no route for message from %q", n.TopicArn)
+}
+
+// snsAttributesMatch reports whether the message attributes of a notification, which are objects
+// holding the Type and Value of each attribute, match patterns.
+func snsAttributesMatch(patterns map[string]string, attributes map[string]interface{}) bool {
+	for name, pattern := range patterns {
+		attr, _ := attributes[name].(map[string]interface{})
+		value, ok := attr["Value"].(string)
+		if !ok || !matchPattern(pattern, value) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,73 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSNS(t *testing.T) {
+	a := assert.New(t)
+
+	type signup struct {
+		Email string `json:"email"`
+	}
+
+	desc(t, 0, "Initialize Router with SNS routes and")
+	var handled []string
+	r := New("prefix")
+	r.SNS("*:users", map[string]string{"event": "signup"}, SNSHandlerOf(func(ctx context.Context, n events.SNSEntity, msg signup) error {
+		handled = append(handled, "signup "+msg.Email)
+		return nil
+	}))
+	r.SNS("*:users", nil, lambda.NewHandler(func(record events.SNSEventRecord) error {
+		handled = append(handled, "user "+record.SNS.Message)
+		return nil
+	}))
+
+	invoke := func(topic, message string, attributes map[string]interface{}) error {
+		handled = nil
+		payload, _ := json.Marshal(events.SNSEvent{Records: []events.SNSEventRecord{{
+			EventSource: "aws:sns",
+			SNS: events.SNSEntity{
+				MessageID:         "n1",
+				TopicArn:          "arn:aws:sns:us-east-1:123456789012:" + topic,
+				Message:           message,
+				MessageAttributes: attributes,
+			},
+		}}})
+		res, err := r.Invoke(context.Background(), payload)
+		if err == nil {
+			a.Exactly("null", string(res))
+		}
+		return err
+	}
+
+	desc(t, 2, "SNS method should")
+	{
+		desc(t, 4, "route notifications by topic and message attributes")
+		a.NoError(invoke("users", `{"email": "a@example.com"}`, map[string]interface{}{
+			"event": map[string]interface{}{"Type": "String", "Value": "signup"},
+		}))
+		a.Exactly([]string{"signup a@example.com"}, handled)
+
+		a.NoError(invoke("users", "deleted", nil))
+		a.Exactly([]string{"user deleted"}, handled)
+
+		desc(t, 4, "fail for notifications which match no route")
+		a.EqualError(invoke("orders", "{}", nil), `no route for message from "arn:aws:sns:us-east-1:123456789012:orders"`)
+	}
+
+	desc(t, 2, "SNSHandlerOf function should")
+	{
+		desc(t, 4, "fail for messages which are not JSON")
+		err := invoke("users", "not json", map[string]interface{}{
+			"event": map[string]interface{}{"Type": "String", "Value": "signup"},
+		})
+		a.ErrorContains(err, "decoding message n1")
+	}
+}
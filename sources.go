@@ -0,0 +1,264 @@
+package lambdarouter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+// sourceRoutes holds the routes of events which are not HTTP requests, by the service they come
+// from. Its slices are replaced rather than appended to in place, so they may be used after the
+// table is unlocked.
+type sourceRoutes struct {
+	custom      []customRoute
+	eventBridge []eventBridgeRoute
+	sqs         []sqsRoute
+	sns         []snsRoute
+	s3          []s3Route
+	dynamoDB    []dynamoDBRoute
+	kinesis     []kinesisRoute
+	appSync     []appSyncRoute
+	cloudFront  []cloudFrontRoute
+	rpc         map[string]lambda.Handler
+}
+
+func (s sourceRoutes) empty() bool {
+	return len(s.custom) == 0 && len(s.eventBridge) == 0 && len(s.sqs) == 0 && len(s.sns) == 0 &&
+		len(s.s3) == 0 && len(s.dynamoDB) == 0 && len(s.kinesis) == 0 &&
+		len(s.appSync) == 0 && len(s.cloudFront) == 0 && len(s.rpc) == 0
+}
+
+// merge returns the routes of s followed by those of other. The RPC routes of other for actions s
+// already has a route for are left out, and an error is returned for each.
+func (s sourceRoutes) merge(other sourceRoutes) (sourceRoutes, []error) {
+	merged := sourceRoutes{
+		custom:      append(s.custom[:len(s.custom):len(s.custom)], other.custom...),
+		eventBridge: append(s.eventBridge[:len(s.eventBridge):len(s.eventBridge)], other.eventBridge...),
+		sqs:         append(s.sqs[:len(s.sqs):len(s.sqs)], other.sqs...),
+		sns:         append(s.sns[:len(s.sns):len(s.sns)], other.sns...),
+		s3:          append(s.s3[:len(s.s3):len(s.s3)], other.s3...),
+		dynamoDB:    append(s.dynamoDB[:len(s.dynamoDB):len(s.dynamoDB)], other.dynamoDB...),
+		kinesis:     append(s.kinesis[:len(s.kinesis):len(s.kinesis)], other.kinesis...),
+		appSync:     append(s.appSync[:len(s.appSync):len(s.appSync)], other.appSync...),
+		cloudFront:  append(s.cloudFront[:len(s.cloudFront):len(s.cloudFront)], other.cloudFront...),
+		rpc:         s.rpc,
+	}
+	if len(other.rpc) == 0 {
+		return merged, nil
+	}
+
+	var errs []error
+	merged.rpc = make(map[string]lambda.Handler, len(s.rpc)+len(other.rpc))
+	for action, h := range s.rpc {
+		merged.rpc[action] = h
+	}
+	for action, h := range other.rpc {
+		if _, exists := merged.rpc[action]; exists {
+			errs = append(errs, fmt.Errorf("action '%s' already exists", action))
+			continue
+		}
+		merged.rpc[action] = h
+	}
+
+	return merged, errs
+}
+
+// sourceProbe holds the fields which tell which service an event comes from. The source is kept
+// raw, as events may have a field of the same name but another type, such as the source of AppSync
+// resolver events, which is an object.
+type sourceProbe struct {
+	RawSource  json.RawMessage `json:"source"`
+	Source     string          `json:"-"`
+	DetailType string          `json:"detail-type"`
+	Records    []struct {
+		EventSource string          `json:"eventSource"`
+		CF          json.RawMessage `json:"cf"`
+	}
+	Info *appSyncInfo `json:"info"`
+}
+
+// addSource adds a route for events which are not HTTP requests to the table of the router.
+func (r *Router) addSource(add func(s *sourceRoutes)) {
+	if r.table == nil {
+		panic("router not initialized")
+	}
+
+	r.table.mu.Lock()
+	defer r.table.mu.Unlock()
+
+	add(&r.table.sources)
+}
+
+// invokeSource routes payload if it is an event from a service the router has routes for, which is
+// reported by the second return value.
+func (r Router) invokeSource(ctx context.Context, payload []byte) ([]byte, bool, error) {
+	if r.table == nil {
+		return nil, false, nil
+	}
+
+	r.table.mu.RLock()
+	sources := r.table.sources
+	r.table.mu.RUnlock()
+
+	if sources.empty() {
+		return nil, false, nil
+	}
+
+	for _, rt := range sources.custom {
+		if rt.c.Classify(payload) {
+			res, err := rt.h.Invoke(ctx, payload)
+			return res, true, err
+		}
+	}
+
+	if len(sources.appSync) > 0 {
+		if res, routed, err := r.invokeAppSyncBatch(ctx, sources.appSync, payload); routed {
+			return res, true, err
+		}
+	}
+
+	// Most payloads are HTTP requests, which should not pay for decoding the payload again to tell
+	// they are not events, so it is only decoded if it holds a key which tells events apart.
+	if hasKey(payload, "Records") || hasKey(payload, "detail-type") || hasKey(payload, "info") {
+		if res, routed, err := r.invokeProbed(ctx, sources, payload); routed {
+			return res, true, err
+		}
+	}
+
+	if len(sources.rpc) > 0 && hasKey(payload, r.rpcFieldName()) {
+		return r.invokeRPC(ctx, sources.rpc, payload)
+	}
+
+	return nil, false, nil
+}
+
+// invokeProbed routes payload if it is an event of a service which is told apart by the fields of
+// sourceProbe, which is reported by the second return value.
+func (r Router) invokeProbed(ctx context.Context, sources sourceRoutes, payload []byte) ([]byte, bool, error) {
+	codec := r.jsonCodec()
+
+	var probe sourceProbe
+	if err := codec.Unmarshal(payload, &probe); err != nil {
+		return nil, false, nil
+	}
+	if len(probe.RawSource) > 0 && probe.RawSource[0] == '"' {
+		if err := codec.Unmarshal(probe.RawSource, &probe.Source); err != nil {
+			return nil, false, nil
+		}
+	}
+
+	if probe.Source != "" && probe.DetailType != "" && len(sources.eventBridge) > 0 {
+		res, err := r.invokeEventBridge(ctx, sources.eventBridge, probe, payload)
+		return res, true, err
+	}
+
+	if len(probe.Records) > 0 && len(probe.Records[0].CF) > 0 && len(sources.cloudFront) > 0 {
+		res, err := r.invokeCloudFront(ctx, sources.cloudFront, payload)
+		return res, true, err
+	}
+
+	if len(probe.Records) > 0 {
+		switch probe.Records[0].EventSource {
+		case "aws:sqs":
+			if len(sources.sqs) > 0 {
+				res, err := r.invokeSQS(ctx, sources.sqs, payload)
+				return res, true, err
+			}
+		case "aws:sns":
+			if len(sources.sns) > 0 {
+				res, err := r.invokeSNS(ctx, sources.sns, payload)
+				return res, true, err
+			}
+		case "aws:s3":
+			if len(sources.s3) > 0 {
+				res, err := r.invokeS3(ctx, sources.s3, payload)
+				return res, true, err
+			}
+		case "aws:dynamodb":
+			if len(sources.dynamoDB) > 0 {
+				res, err := r.invokeDynamoDB(ctx, sources.dynamoDB, payload)
+				return res, true, err
+			}
+		case "aws:kinesis":
+			if len(sources.kinesis) > 0 {
+				res, err := r.invokeKinesis(ctx, sources.kinesis, payload)
+				return res, true, err
+			}
+		}
+	}
+
+	if probe.Info != nil && len(sources.appSync) > 0 {
+		res, err := r.invokeAppSync(ctx, sources.appSync, *probe.Info, payload)
+		return res, true, err
+	}
+
+	return nil, false, nil
+}
+
+// hasKey reports whether payload may hold the key name, by looking for it in quotes. Quotes within
+// the strings of JSON are escaped, so the key cannot be found within the body of a request, and a
+// payload without it cannot hold the key, while one with it is decoded to be sure.
+func hasKey(payload []byte, name string) bool {
+	return bytes.Contains(payload, []byte(`"`+name+`"`))
+}
+
+// sourceHandler returns handler wrapped by the middleware of the router, for a route for events
+// which are not HTTP requests. A panic of the handler or its middleware is recovered and logged
+// with its stack, and returned as an error, as invokeRoute does for HTTP routes, so the routes of
+// batches of records report the record as a batch item failure rather than crashing the function.
+func (r Router) sourceHandler(handler lambda.Handler) lambda.Handler {
+	e := event{middleware: r.middleware}
+	e.wrap(handler)
+
+	return recoverHandler{h: e.h, logf: r.logf}
+}
+
+type recoverHandler struct {
+	h    lambda.Handler
+	logf func(format string, v ...interface{})
+}
+
+func (rh recoverHandler) Invoke(ctx context.Context, payload []byte) (res []byte, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			res, err = nil, fmt.Errorf("panic: %v", p)
+			rh.logf("%v\n%s", err, debug.Stack())
+		}
+	}()
+
+	return rh.h.Invoke(ctx, payload)
+}
+
+// matchPattern reports whether s matches pattern, in which * matches any run of characters. An
+// empty pattern matches anything.
+func matchPattern(pattern, s string) bool {
+	if pattern == "" {
+		return true
+	}
+
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+
+	return strings.HasSuffix(s, last)
+}
@@ -0,0 +1,60 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvokeSource(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with SQS, RPC, and HTTP routes and")
+	r := New("prefix")
+	r.SQS("", nil, lambda.NewHandler(func(msg events.SQSMessage) error { return nil }))
+	r.RPC("ping", lambda.NewHandler(func() (string, error) { return "pong", nil }))
+	r.Post("echo", lambda.NewHandler(func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: req.Body}, nil
+	}))
+
+	desc(t, 2, "hasKey function should")
+	{
+		desc(t, 4, "find keys of the payload")
+		a.True(hasKey([]byte(`{"Records": []}`), "Records"))
+
+		desc(t, 4, "not find keys within the strings of the payload")
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{Body: `{"Records": [], "action": "ping"}`})
+		a.False(hasKey(payload, "Records"))
+		a.False(hasKey(payload, "action"))
+	}
+
+	desc(t, 2, "Invoke method should")
+	{
+		desc(t, 4, "route HTTP requests whose bodies look like events as HTTP requests")
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodPost,
+			Path:       "/prefix/echo",
+			Body:       `{"Records": [{"eventSource": "aws:sqs"}], "action": "ping"}`,
+		})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		a.Exactly(http.StatusOK, res.StatusCode)
+
+		desc(t, 4, "still route events and direct invocations")
+		resjson, err = r.Invoke(context.Background(), []byte(`{"action": "ping"}`))
+		a.NoError(err)
+		a.Exactly(`"pong"`, string(resjson))
+
+		resjson, err = r.Invoke(context.Background(), []byte(`{"Records": [{"messageId": "m1", "eventSource": "aws:sqs"}]}`))
+		a.NoError(err)
+		a.JSONEq(`{"batchItemFailures": []}`, string(resjson))
+	}
+}
@@ -0,0 +1,94 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+type sqsRoute struct {
+	queue      string
+	attributes map[string]string
+	h          lambda.Handler
+}
+
+// SQS adds a route for messages delivered from SQS queues. The queue parameter is a pattern
+// matched against the ARN of the queue a message comes from, in which * matches any run of
+// characters, such as "*:orders", and which matches any queue if empty. The attributes parameter
+// maps the names of message attributes to patterns their string values must match, and may be
+// nil. The handler parameter is invoked once for each message, which decodes into an
+// events.SQSMessage, and is wrapped by the middleware of the router, which should not assume it
+// handles HTTP requests.
+//
+// Messages are routed to the first route which matches them in the order they were defined. Those
+// whose handler fails or which match no route are reported as batch item failures, so only they are
+// retried, which requires the event source mapping to have ReportBatchItemFailures enabled. As
+// messages of FIFO queues must be handled in order, every message of a FIFO queue after one which
+// fails is reported as failed without being handled.
+func (r *Router) SQS(queue string, attributes map[string]string, handler lambda.Handler) {
+	rt := sqsRoute{queue: queue, attributes: attributes, h: r.sourceHandler(handler)}
+
+	r.addSource(func(s *sourceRoutes) {
+		s.sqs = append(s.sqs[:len(s.sqs):len(s.sqs)], rt)
+	})
+}
+
+func (r Router) invokeSQS(ctx context.Context, routes []sqsRoute, payload []byte) ([]byte, error) {
+	codec := r.jsonCodec()
+
+	var batch struct {
+		Records []json.RawMessage
+	}
+	if err := codec.Unmarshal(payload, &batch); err != nil {
+		return nil, err
+	}
+
+	res := events.SQSEventResponse{BatchItemFailures: []events.SQSBatchItemFailure{}}
+	failed := false
+
+	for _, record := range batch.Records {
+		var msg events.SQSMessage
+		if err := codec.Unmarshal(record, &msg); err != nil {
+			return nil, err
+		}
+
+		if failed && strings.HasSuffix(msg.EventSourceARN, ".fifo") {
+			res.BatchItemFailures = append(res.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: msg.MessageId})
+			continue
+		}
+
+		if err := invokeSQSRoute(ctx, routes, msg, record); err != nil {
+			r.logf("SQS message %s: %v", msg.MessageId, err)
+			res.BatchItemFailures = append(res.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: msg.MessageId})
+			failed = true
+		}
+	}
+
+	return codec.Marshal(res)
+}
+
+func invokeSQSRoute(ctx context.Context, routes []sqsRoute, msg events.SQSMessage, record []byte) error {
+	for _, rt := range routes {
+		if matchPattern(rt.queue, msg.EventSourceARN) && sqsAttributesMatch(rt.attributes, msg.MessageAttributes) {
+			_, err := rt.h.Invoke(ctx, record)
+			return err
+		}
+	}
+
+	return fmt.Errorf("no route for message from %q", msg.EventSourceARN)
+}
+
+func sqsAttributesMatch(patterns map[string]string, attributes map[string]events.SQSMessageAttribute) bool {
+	for name, pattern := range patterns {
+		attr, ok := attributes[name]
+		if !ok || attr.StringValue == nil || !matchPattern(pattern, *attr.StringValue) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,86 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQS(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with SQS routes and")
+	var handled []string
+	r := New("prefix")
+	r.SQS("*:orders", map[string]string{"kind": "refund"}, lambda.NewHandler(func(msg events.SQSMessage) error {
+		handled = append(handled, "refund "+msg.Body)
+		return nil
+	}))
+	r.SQS("*:orders", nil, lambda.NewHandler(func(msg events.SQSMessage) error {
+		handled = append(handled, "order "+msg.Body)
+		if msg.Body == "bad" {
+			return errors.New("bad order")
+		}
+		if msg.Body == "panic" {
+			panic("broken order")
+		}
+		return nil
+	}))
+	r.SQS("*:jobs.fifo", nil, lambda.NewHandler(func(msg events.SQSMessage) error {
+		handled = append(handled, "job "+msg.Body)
+		if msg.Body == "bad" {
+			return errors.New("bad job")
+		}
+		return nil
+	}))
+
+	invoke := func(msgs ...events.SQSMessage) events.SQSEventResponse {
+		handled = nil
+		payload, _ := json.Marshal(events.SQSEvent{Records: msgs})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.SQSEventResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+	msg := func(id, queue, body string) events.SQSMessage {
+		return events.SQSMessage{
+			MessageId:      id,
+			EventSource:    "aws:sqs",
+			EventSourceARN: "arn:aws:sqs:us-east-1:123456789012:" + queue,
+			Body:           body,
+		}
+	}
+
+	desc(t, 2, "SQS method should")
+	{
+		desc(t, 4, "route messages by queue and message attributes")
+		kind := "refund"
+		refund := msg("m2", "orders", "r1")
+		refund.MessageAttributes = map[string]events.SQSMessageAttribute{"kind": {StringValue: &kind, DataType: "String"}}
+		res := invoke(msg("m1", "orders", "o1"), refund)
+		a.Empty(res.BatchItemFailures)
+		a.Exactly([]string{"order o1", "refund r1"}, handled)
+
+		desc(t, 4, "report the messages which fail or match no route")
+		res = invoke(msg("m1", "orders", "bad"), msg("m2", "orders", "o2"), msg("m3", "users", "u1"))
+		a.Exactly([]events.SQSBatchItemFailure{{ItemIdentifier: "m1"}, {ItemIdentifier: "m3"}}, res.BatchItemFailures)
+		a.Exactly([]string{"order bad", "order o2"}, handled)
+
+		desc(t, 4, "report the messages whose handler panics")
+		res = invoke(msg("m1", "orders", "panic"), msg("m2", "orders", "o2"))
+		a.Exactly([]events.SQSBatchItemFailure{{ItemIdentifier: "m1"}}, res.BatchItemFailures)
+		a.Exactly([]string{"order panic", "order o2"}, handled)
+
+		desc(t, 4, "fail the messages of FIFO queues after one which fails")
+		res = invoke(msg("m1", "jobs.fifo", "j1"), msg("m2", "jobs.fifo", "bad"), msg("m3", "jobs.fifo", "j3"))
+		a.Exactly([]events.SQSBatchItemFailure{{ItemIdentifier: "m2"}, {ItemIdentifier: "m3"}}, res.BatchItemFailures)
+		a.Exactly([]string{"job j1", "job bad"}, handled)
+	}
+}
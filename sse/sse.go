@@ -0,0 +1,182 @@
+// Package sse streams server-sent events to clients, for endpoints reporting the progress of long
+// running work or pushing notifications. Events are only delivered as they are sent when the
+// router is invoked by InvokeStream, and are otherwise delivered together once the handler
+// returns.
+package sse
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+)
+
+// Event is a server-sent event.
+type Event struct {
+	// ID is the ID of the event, which the browser sends back in the Last-Event-ID header when it
+	// reconnects. It is omitted if empty.
+	ID string
+
+	// Event is the type of the event, which is message if empty.
+	Event string
+
+	// Data is the payload of the event, which may span several lines.
+	Data string
+
+	// Retry is how long the browser waits before reconnecting if the stream is interrupted. It is
+	// omitted if zero.
+	Retry time.Duration
+}
+
+// Func sends events to a client with send until it returns. The request can be retrieved from ctx
+// with lambdarouter.RequestFromContext. ctx is cancelled once the client disconnects, after which
+// events sent are discarded, so Func should return when it is done.
+type Func func(ctx context.Context, send func(Event)) error
+
+// Config configures the streams of events created by its Stream method.
+type Config struct {
+	// Heartbeat is how often a comment is sent while no events are, so proxies do not close idle
+	// streams and disconnected clients are noticed. If zero, it is 15 seconds. If negative, no
+	// comments are sent.
+	Heartbeat time.Duration
+}
+
+// Stream returns a handler streaming the events sent by fn to the client, with the default
+// configuration.
+func Stream(fn Func) lambda.Handler {
+	return Config{}.Stream(fn)
+}
+
+// Stream returns a handler streaming the events sent by fn to the client as a text/event-stream
+// response. An error returned by fn before it sends an event is handled by the router as that of
+// any other handler. The stream ends early if fn returns an error after sending one.
+func (cfg Config) Stream(fn Func) lambda.Handler {
+	if cfg.Heartbeat == 0 {
+		cfg.Heartbeat = 15 * time.Second
+	}
+
+	return lambdarouter.Stream(func(ctx context.Context, _ events.APIGatewayProxyRequest, w *lambdarouter.StreamWriter) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		header := w.Header()
+		header.Set("Content-Type", "text/event-stream")
+		header.Set("Cache-Control", "no-cache")
+
+		s := &stream{w: w, cancel: cancel}
+
+		var wg sync.WaitGroup
+		if cfg.Heartbeat > 0 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.heartbeat(ctx, cfg.Heartbeat)
+			}()
+		}
+
+		err := fn(ctx, s.send)
+		cancel()
+		wg.Wait()
+
+		if s.disconnected() && errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	})
+}
+
+// stream writes events to a StreamWriter, from both the Func and the heartbeat.
+type stream struct {
+	mu     sync.Mutex
+	w      *lambdarouter.StreamWriter
+	cancel context.CancelFunc
+	closed bool
+	sent   time.Time
+}
+
+func (s *stream) send(e Event) {
+	s.write(format(e))
+}
+
+// heartbeat writes a comment whenever nothing was written for interval, until ctx is done. It
+// waits for the first event, so that errors returned by the Func before it sends one can still be
+// handled by the router.
+func (s *stream) heartbeat(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			idle := !s.sent.IsZero() && now.Sub(s.sent) >= interval
+			s.mu.Unlock()
+
+			if idle {
+				s.write(":\n\n")
+			}
+		}
+	}
+}
+
+func (s *stream) write(str string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	if _, err := io.WriteString(s.w, str); err != nil {
+		s.closed = true
+		s.cancel()
+		return
+	}
+
+	s.w.Flush()
+	s.sent = time.Now()
+}
+
+func (s *stream) disconnected() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closed
+}
+
+// format encodes e in the text/event-stream format.
+func format(e Event) string {
+	var b strings.Builder
+
+	if e.ID != "" {
+		b.WriteString("id: " + singleLine(e.ID) + "\n")
+	}
+	if e.Event != "" {
+		b.WriteString("event: " + singleLine(e.Event) + "\n")
+	}
+	if e.Retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(e.Retry.Milliseconds(), 10) + "\n")
+	}
+
+	data := strings.ReplaceAll(strings.ReplaceAll(e.Data, "\r\n", "\n"), "\r", "\n")
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// singleLine removes line breaks from s, which would otherwise end the field they are part of.
+func singleLine(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
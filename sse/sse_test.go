@@ -0,0 +1,106 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStream(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with event streams and")
+	disconnected := make(chan error, 1)
+	r := lambdarouter.New("prefix")
+	r.Get("progress", Stream(func(ctx context.Context, send func(Event)) error {
+		for i := 1; i <= 2; i++ {
+			send(Event{ID: strconv.Itoa(i), Event: "progress", Data: fmt.Sprintf("%d%%\n", i*50)})
+		}
+		send(Event{Data: "done", Retry: 3 * time.Second})
+		return nil
+	}))
+	r.Get("failed", Stream(func(ctx context.Context, send func(Event)) error {
+		return &lambdarouter.HTTPError{Status: http.StatusNotFound, Detail: "no such job"}
+	}))
+	r.Get("notifications", Config{Heartbeat: time.Millisecond}.Stream(func(ctx context.Context, send func(Event)) error {
+		send(Event{Data: "hello"})
+		<-ctx.Done()
+		disconnected <- ctx.Err()
+		return ctx.Err()
+	}))
+
+	stream := func(path string) *events.LambdaFunctionURLStreamingResponse {
+		var req events.APIGatewayV2HTTPRequest
+		req.RawPath = path
+		req.RequestContext.HTTP.Method = http.MethodGet
+		payload, _ := json.Marshal(req)
+
+		res, err := r.InvokeStream(context.Background(), payload)
+		a.NoError(err)
+		return res
+	}
+
+	desc(t, 2, "Stream function should")
+	{
+		desc(t, 4, "format the events sent")
+		res := stream("/prefix/progress")
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("text/event-stream", res.Headers["Content-Type"])
+		a.Exactly("no-cache", res.Headers["Cache-Control"])
+		body, err := io.ReadAll(res.Body)
+		a.NoError(err)
+		a.Exactly(
+			"id: 1\nevent: progress\ndata: 50%\ndata: \n\n"+
+				"id: 2\nevent: progress\ndata: 100%\ndata: \n\n"+
+				"retry: 3000\ndata: done\n\n",
+			string(body),
+		)
+
+		desc(t, 4, "leave errors returned before sending events to the router")
+		res = stream("/prefix/failed")
+		a.Exactly(http.StatusNotFound, res.StatusCode)
+
+		desc(t, 4, "send heartbeats while idle")
+		res = stream("/prefix/notifications")
+		lines := bufio.NewReader(res.Body)
+		for _, want := range []string{"data: hello\n", "\n", ":\n", "\n"} {
+			line, err := lines.ReadString('\n')
+			a.NoError(err)
+			a.Exactly(want, line)
+		}
+
+		desc(t, 4, "cancel the context once the client disconnects")
+		a.NoError(res.Body.(io.Closer).Close())
+		select {
+		case err := <-disconnected:
+			a.True(errors.Is(err, context.Canceled))
+		case <-time.After(time.Second):
+			a.Fail("context not cancelled")
+		}
+	}
+
+	desc(t, 2, "format function should")
+	{
+		desc(t, 4, "keep line breaks out of single line fields")
+		a.Exactly("id: ab\ndata: x\ndata: y\n\n", format(Event{ID: "a\nb", Data: "x\r\ny"}))
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
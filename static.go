@@ -0,0 +1,109 @@
+package lambdarouter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Static defines a GET route serving the files of fsys, such as an embed.FS, so small frontends or
+// documentation can be served by the same function as an API. The route path must end with a
+// greedy parameter, as in "assets/{proxy+}", or a wildcard, as in "assets/{path...}", whose value
+// names the file to serve. Directories are served by their index.html file, and requests for
+// missing files are responded to with a 404.
+//
+// Responses have a Content-Type chosen by the extension of the file, or by sniffing its content,
+// and an ETag, so clients can revalidate their copy and receive a 304 if it is unchanged. Binary
+// files are base64 encoded, as API Gateway requires for binary media types.
+func (r *Router) Static(path string, fsys fs.FS, opts ...RouteOption) {
+	segs := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	last := segs[len(segs)-1]
+	var param string
+	switch {
+	case isWildcard("/" + last):
+		param = last[1 : len(last)-4]
+	case strings.HasPrefix(last, "{") && strings.HasSuffix(last, "+}"):
+		param = last[1 : len(last)-2]
+	default:
+		panic("static path must end with a greedy or wildcard parameter, such as {proxy+}")
+	}
+
+	sh := staticHandler{fsys: fsys, param: param}
+	r.Get(path, HandlerFunc(sh.serve), opts...)
+}
+
+type staticHandler struct {
+	fsys  fs.FS
+	param string
+}
+
+func (sh staticHandler) serve(_ context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	name := path.Clean("/" + req.PathParameters[sh.param])[1:]
+	if name == "" {
+		name = "."
+	}
+
+	content, name, err := sh.read(name)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	sum := sha256.Sum256(content)
+	etag := `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+
+	headers := map[string]string{"ETag": etag, "Cache-Control": "public, no-cache"}
+	if values := headerValues(req, "If-None-Match"); len(values) > 0 && strings.Contains(values[0], etag) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusNotModified, Headers: headers}, nil
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+	headers["Content-Type"] = contentType
+
+	res := events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Headers: headers}
+	if isText(contentType) && utf8.Valid(content) {
+		res.Body = string(content)
+	} else {
+		res.Body = base64.StdEncoding.EncodeToString(content)
+		res.IsBase64Encoded = true
+	}
+
+	return res, nil
+}
+
+// read returns the content of the named file, or of the index.html file of the named directory,
+// along with the name of the file read.
+func (sh staticHandler) read(name string) ([]byte, string, error) {
+	info, err := fs.Stat(sh.fsys, name)
+	if err == nil && info.IsDir() {
+		name = path.Join(name, "index.html")
+	}
+
+	content, err := fs.ReadFile(sh.fsys, name)
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) {
+		return nil, "", &HTTPError{Status: http.StatusNotFound}
+	}
+
+	return content, name, err
+}
+
+// isText reports whether the media type of contentType is textual, so bodies of it can be
+// returned without base64 encoding.
+func isText(contentType string) bool {
+	mt, _, _ := strings.Cut(contentType, ";")
+	mt = strings.TrimSpace(mt)
+
+	return strings.HasPrefix(mt, "text/") || strings.HasSuffix(mt, "+xml") || strings.HasSuffix(mt, "+json") ||
+		mt == "application/json" || mt == "application/javascript" || mt == "application/xml"
+}
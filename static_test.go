@@ -0,0 +1,81 @@
+package lambdarouter
+
+import (
+	"context"
+	"embed"
+	"encoding/base64"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+//go:embed testdata/static
+var staticFiles embed.FS
+
+func TestStatic(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with static files and")
+	files, _ := fs.Sub(staticFiles, "testdata/static")
+	r := New("prefix")
+	r.Static("assets/{proxy+}", files)
+
+	invoke := func(path string, headers map[string]string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path, Headers: headers})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "Static method should")
+	{
+		desc(t, 4, "serve text files with their content type")
+		res := invoke("/prefix/assets/site.css", nil)
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("text/css; charset=utf-8", res.Headers["Content-Type"])
+		a.Exactly("body { color: red; }\n", res.Body)
+		a.False(res.IsBase64Encoded)
+
+		desc(t, 4, "base64 encode binary files")
+		res = invoke("/prefix/assets/logo.png", nil)
+		a.Exactly("image/png", res.Headers["Content-Type"])
+		a.True(res.IsBase64Encoded)
+		png, _ := base64.StdEncoding.DecodeString(res.Body)
+		a.Exactly([]byte("\x89PNG\r\n\x1a\n\x00\x00"), png)
+
+		desc(t, 4, "serve the index of directories")
+		res = invoke("/prefix/assets/docs", nil)
+		a.Exactly("<h1>Docs</h1>\n", res.Body)
+		a.Exactly("text/html; charset=utf-8", res.Headers["Content-Type"])
+
+		desc(t, 4, "respond with a 304 when the client's copy is current")
+		etag := res.Headers["ETag"]
+		a.NotEmpty(etag)
+		res = invoke("/prefix/assets/docs/index.html", map[string]string{"If-None-Match": etag})
+		a.Exactly(http.StatusNotModified, res.StatusCode)
+		a.Empty(res.Body)
+
+		desc(t, 4, "respond with a 404 for missing files and paths outside the files")
+		res = invoke("/prefix/assets/missing.js", nil)
+		a.Exactly(http.StatusNotFound, res.StatusCode)
+		res = invoke("/prefix/assets/../router.go", nil)
+		a.Exactly(http.StatusNotFound, res.StatusCode)
+
+		desc(t, 4, "serve files at a wildcard parameter")
+		r.Static("docs/{path...}", files)
+		res = invoke("/prefix/docs/site.css", nil)
+		a.Exactly("body { color: red; }\n", res.Body)
+		res = invoke("/prefix/docs/docs", nil)
+		a.Exactly("<h1>Docs</h1>\n", res.Body)
+
+		desc(t, 4, "panic without a greedy parameter")
+		a.Panics(func() { r.Static("files/{name}", files) })
+	}
+}
@@ -0,0 +1,300 @@
+package lambdarouter
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+// StreamFunc writes the response to a request to w as it is produced. It must not retain w after
+// it returns.
+type StreamFunc func(ctx context.Context, req events.APIGatewayProxyRequest, w *StreamWriter) error
+
+// StreamWriter is the http.ResponseWriter handlers created by Stream write their response to. The
+// status and headers of the response are sent when it is first written to, after which they cannot
+// be changed.
+type StreamWriter struct {
+	header http.Header
+	status int
+	w      io.Writer
+
+	once    sync.Once
+	started chan struct{}
+}
+
+// Header returns the headers of the response, which may be changed until the first call to Write
+// or WriteHeader.
+func (w *StreamWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader sends the status and headers of the response. It is called with a 200 by the first
+// call to Write if it was not called before.
+func (w *StreamWriter) WriteHeader(status int) {
+	w.once.Do(func() {
+		w.status = status
+		close(w.started)
+	})
+}
+
+// Write writes b to the body of the response. When streaming, it blocks until the client has
+// received the preceding data.
+func (w *StreamWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.w.Write(b)
+}
+
+// Flush implements the http.Flusher interface for the StreamWriter type. Data is sent as it is
+// written, so it does nothing.
+func (w *StreamWriter) Flush() {}
+
+// response returns the status and headers of the response, without a body.
+func (w *StreamWriter) response() events.APIGatewayProxyResponse {
+	rw := responseWriter{header: w.header, status: w.status}
+	return rw.response()
+}
+
+type streamKey struct{}
+
+// errStreamAbandoned ends a stream whose invocation has responded without it, such as after the
+// route timed out before its headers were sent.
+var errStreamAbandoned = errors.New("stream abandoned")
+
+// streamSink receives the body of a streamed response while the router is invoked by
+// InvokeStream.
+type streamSink struct {
+	ctx context.Context
+
+	mu       sync.Mutex
+	body     *io.PipeReader
+	detached bool
+}
+
+// attach sets the body of the streamed response, reporting false if the invocation has already
+// responded without it.
+func (s *streamSink) attach(body *io.PipeReader) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.detached {
+		return false
+	}
+
+	s.body = body
+	return true
+}
+
+// detach returns the body of the streamed response, or nil if none was attached, after which no
+// body can be attached.
+func (s *streamSink) detach() *io.PipeReader {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.detached = true
+	return s.body
+}
+
+// context returns the context a response is streamed with, which carries the values of ctx, the
+// context of the handler, but is only cancelled with the invocation, or with ctx if ctx is
+// cancelled before started is closed. The handler returns once the headers are sent, so the time
+// limit of its route would otherwise cut the stream short.
+func (s *streamSink) context(ctx context.Context, started <-chan struct{}) (context.Context, context.CancelFunc) {
+	streamCtx, cancel := context.WithCancel(s.ctx)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			select {
+			case <-started:
+			default:
+				cancel()
+			}
+		case <-streamCtx.Done():
+		}
+	}()
+
+	return valuesContext{Context: streamCtx, values: ctx}, cancel
+}
+
+// valuesContext is a context whose values are those of another.
+type valuesContext struct {
+	context.Context
+	values context.Context
+}
+
+func (c valuesContext) Value(key interface{}) interface{} {
+	return c.values.Value(key)
+}
+
+// withoutStream returns a copy of ctx without the sink of the invocation, so the response of a
+// handler run with it, such as a shadow, is buffered rather than streamed.
+func withoutStream(ctx context.Context) context.Context {
+	return context.WithValue(ctx, streamKey{}, nil)
+}
+
+// Stream returns a handler whose response is written to a StreamWriter by fn as it is produced.
+// When the router is invoked by InvokeStream, the response is streamed to the client, so it may
+// exceed the 6 MB limit of buffered responses and its first bytes arrive sooner. Otherwise, such as
+// behind API Gateway, the response is buffered and returned once fn returns.
+//
+// Errors returned by fn before it writes to w are handled by the router as those of any other
+// handler. Errors returned afterwards end the stream early. Middleware which changes the body of
+// responses, such as compression, does not see the body of streamed responses.
+func Stream(fn StreamFunc) lambda.Handler {
+	return streamHandler{fn: fn}
+}
+
+type streamHandler struct {
+	fn StreamFunc
+}
+
+func (sh streamHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	sink, streaming := ctx.Value(streamKey{}).(*streamSink)
+	if !streaming {
+		rw := &responseWriter{header: http.Header{}}
+		w := &StreamWriter{header: rw.header, w: rw, started: make(chan struct{})}
+		if err := sh.fn(ctx, req, w); err != nil {
+			return nil, err
+		}
+
+		rw.status = w.status
+		return CodecFrom(ctx).Marshal(rw.response())
+	}
+
+	pr, pw := io.Pipe()
+	w := &StreamWriter{header: http.Header{}, w: pw, started: make(chan struct{})}
+	done := make(chan error, 1)
+	streamCtx, cancel := sink.context(ctx, w.started)
+
+	go func() {
+		defer cancel()
+
+		err := sh.fn(streamCtx, req, w)
+		done <- err
+		w.WriteHeader(http.StatusOK)
+		pw.CloseWithError(err)
+	}()
+
+	select {
+	case <-w.started:
+	case err := <-done:
+		if err != nil {
+			return nil, err
+		}
+		<-w.started
+	}
+
+	if !sink.attach(pr) {
+		pr.CloseWithError(errStreamAbandoned)
+		return nil, errStreamAbandoned
+	}
+
+	return CodecFrom(ctx).Marshal(w.response())
+}
+
+// InvokeStream routes an invocation of a Lambda function URL whose invoke mode is RESPONSE_STREAM,
+// which is made with the version 2.0 payload format. Responses of handlers created with Stream are
+// streamed as they are written, while those of other handlers are sent whole. It is passed to
+// lambda.Start in place of the router, as in lambda.Start(r.InvokeStream), which requires building
+// with the lambda.norpc tag or using an OS-only runtime.
+func (r Router) InvokeStream(ctx context.Context, payload json.RawMessage) (*events.LambdaFunctionURLStreamingResponse, error) {
+	ctx = r.invocationContext(ctx)
+
+	codec := r.jsonCodec()
+
+	if limit, tooLarge := r.payloadTooLarge(payload); tooLarge {
+		resjson, err := r.rejectPayload(ctx, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		var res events.APIGatewayProxyResponse
+		if err := codec.Unmarshal(resjson, &res); err != nil {
+			return nil, err
+		}
+		return streamedResponse(res, nil)
+	}
+
+	var v2 events.APIGatewayV2HTTPRequest
+	if err := codec.Unmarshal(payload, &v2); err != nil {
+		return nil, err
+	}
+
+	req := proxyRequestV2(v2)
+	reqjson, err := codec.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	sink := &streamSink{ctx: ctx}
+	resjson, err := r.route(context.WithValue(ctx, streamKey{}, sink), req, reqjson)
+
+	body := sink.detach()
+	if err == nil {
+		var res events.APIGatewayProxyResponse
+		if err = codec.Unmarshal(resjson, &res); err == nil {
+			return streamedResponse(res, body)
+		}
+	}
+
+	// The body is not sent, so it is closed for the handler writing it to return.
+	if body != nil {
+		body.CloseWithError(err)
+	}
+
+	return nil, err
+}
+
+// streamedResponse converts res to a streamed response whose body is body, or the body of res if
+// body is nil.
+func streamedResponse(res events.APIGatewayProxyResponse, body *io.PipeReader) (*events.LambdaFunctionURLStreamingResponse, error) {
+	streamed := &events.LambdaFunctionURLStreamingResponse{
+		StatusCode: res.StatusCode,
+		Headers:    map[string]string{},
+	}
+	if body != nil {
+		streamed.Body = body
+	}
+
+	for name, value := range res.Headers {
+		if strings.EqualFold(name, "Set-Cookie") {
+			streamed.Cookies = append(streamed.Cookies, value)
+			continue
+		}
+		streamed.Headers[name] = value
+	}
+	for name, values := range res.MultiValueHeaders {
+		if strings.EqualFold(name, "Set-Cookie") {
+			streamed.Cookies = append(streamed.Cookies, values...)
+			continue
+		}
+		streamed.Headers[name] = strings.Join(values, ",")
+	}
+
+	if streamed.Body == nil {
+		body := []byte(res.Body)
+		if res.IsBase64Encoded {
+			var err error
+			if body, err = base64.StdEncoding.DecodeString(res.Body); err != nil {
+				return nil, err
+			}
+		}
+		streamed.Body = bytes.NewReader(body)
+	}
+
+	return streamed, nil
+}
@@ -0,0 +1,184 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStream(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with streamed routes and")
+	r := New("prefix")
+	r.Get("numbers", Stream(func(ctx context.Context, req events.APIGatewayProxyRequest, w *StreamWriter) error {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Add("Set-Cookie", "seen=1")
+		for i := 1; i <= 3; i++ {
+			fmt.Fprintf(w, "%d\n", i)
+			w.Flush()
+		}
+		return nil
+	}))
+	r.Get("missing", Stream(func(ctx context.Context, req events.APIGatewayProxyRequest, w *StreamWriter) error {
+		return &HTTPError{Status: http.StatusNotFound, Detail: "no such report"}
+	}))
+	r.Get("broken", Stream(func(ctx context.Context, req events.APIGatewayProxyRequest, w *StreamWriter) error {
+		w.WriteHeader(http.StatusAccepted)
+		io.WriteString(w, "partial")
+		return errors.New("broken")
+	}))
+	r.Get("plain", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{
+			StatusCode:        http.StatusOK,
+			Body:              "aGVsbG8=",
+			IsBase64Encoded:   true,
+			MultiValueHeaders: map[string][]string{"Vary": {"Accept", "Origin"}},
+		}, nil
+	}))
+
+	stream := func(path string) *events.LambdaFunctionURLStreamingResponse {
+		var req events.APIGatewayV2HTTPRequest
+		req.RawPath = path
+		req.RequestContext.HTTP.Method = http.MethodGet
+		payload, _ := json.Marshal(req)
+
+		res, err := r.InvokeStream(context.Background(), payload)
+		a.NoError(err)
+		return res
+	}
+
+	desc(t, 2, "Stream function should")
+	{
+		desc(t, 4, "buffer the response when the router is invoked by Invoke")
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/prefix/numbers"})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("1\n2\n3\n", res.Body)
+		a.Exactly("text/plain", res.Headers["Content-Type"])
+	}
+
+	desc(t, 2, "InvokeStream method should")
+	{
+		desc(t, 4, "stream the response of streamed routes")
+		sres := stream("/prefix/numbers")
+		a.Exactly(http.StatusOK, sres.StatusCode)
+		a.Exactly("text/plain", sres.Headers["Content-Type"])
+		a.Exactly([]string{"seen=1"}, sres.Cookies)
+		body, err := io.ReadAll(sres.Body)
+		a.NoError(err)
+		a.Exactly("1\n2\n3\n", string(body))
+
+		desc(t, 4, "render errors returned before the response is written")
+		sres = stream("/prefix/missing")
+		a.Exactly(http.StatusNotFound, sres.StatusCode)
+
+		desc(t, 4, "end the stream with errors returned afterwards")
+		sres = stream("/prefix/broken")
+		a.Exactly(http.StatusAccepted, sres.StatusCode)
+		body, err = io.ReadAll(sres.Body)
+		a.EqualError(err, "broken")
+		a.Exactly("partial", string(body))
+
+		desc(t, 4, "send the responses of other routes whole")
+		sres = stream("/prefix/plain")
+		a.Exactly("Accept,Origin", sres.Headers["Vary"])
+		body, _ = io.ReadAll(sres.Body)
+		a.Exactly("hello", string(body))
+
+		desc(t, 4, "respond to unmatched requests")
+		sres = stream("/prefix/nothing")
+		a.Exactly(http.StatusNotFound, sres.StatusCode)
+	}
+
+	desc(t, 2, "InvokeStream method should")
+	{
+		desc(t, 4, "stream responses for longer than the time limit of their route")
+		r.Get("slow", Stream(func(ctx context.Context, req events.APIGatewayProxyRequest, w *StreamWriter) error {
+			io.WriteString(w, "a")
+			time.Sleep(60 * time.Millisecond)
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			io.WriteString(w, "b")
+			return nil
+		}), WithTimeout(20*time.Millisecond))
+		sres := stream("/prefix/slow")
+		body, err := io.ReadAll(sres.Body)
+		a.NoError(err)
+		a.Exactly("ab", string(body))
+
+		desc(t, 4, "end the streams of routes which timed out before sending their headers")
+		abandoned := make(chan error, 1)
+		r.Get("late", Stream(func(ctx context.Context, req events.APIGatewayProxyRequest, w *StreamWriter) error {
+			time.Sleep(60 * time.Millisecond)
+			_, err := io.WriteString(w, "late")
+			abandoned <- err
+			return err
+		}), WithTimeout(20*time.Millisecond))
+		sres = stream("/prefix/late")
+		a.Exactly(http.StatusGatewayTimeout, sres.StatusCode)
+		select {
+		case err := <-abandoned:
+			a.Error(err)
+		case <-time.After(time.Second):
+			a.Fail("the handler is still writing its response")
+		}
+
+		desc(t, 4, "end the stream when the invocation fails after it started")
+		failing := New("prefix", WithResponseMarshaler(func(context.Context, events.APIGatewayProxyResponse) (events.APIGatewayProxyResponse, error) {
+			return events.APIGatewayProxyResponse{}, errors.New("failed")
+		}))
+		written := make(chan error, 1)
+		failing.Get("numbers", Stream(func(ctx context.Context, req events.APIGatewayProxyRequest, w *StreamWriter) error {
+			_, err := io.WriteString(w, "1")
+			written <- err
+			return err
+		}))
+		var req events.APIGatewayV2HTTPRequest
+		req.RawPath = "/prefix/numbers"
+		req.RequestContext.HTTP.Method = http.MethodGet
+		payload, _ := json.Marshal(req)
+		_, err = failing.InvokeStream(context.Background(), payload)
+		a.EqualError(err, "failed")
+		select {
+		case err := <-written:
+			a.EqualError(err, "failed")
+		case <-time.After(time.Second):
+			a.Fail("the handler is still writing its response")
+		}
+
+		desc(t, 4, "buffer the responses of the shadows of streamed routes")
+		shadowed := make(chan []byte, 1)
+		r.Get("shadowed", Stream(func(ctx context.Context, req events.APIGatewayProxyRequest, w *StreamWriter) error {
+			io.WriteString(w, "primary")
+			return nil
+		}), WithShadow(Stream(func(ctx context.Context, req events.APIGatewayProxyRequest, w *StreamWriter) error {
+			io.WriteString(w, "shadow")
+			return nil
+		}), func(ctx context.Context, primary, shadow ShadowResult) {
+			shadowed <- shadow.Response
+		}))
+		sres = stream("/prefix/shadowed")
+		body, err = io.ReadAll(sres.Body)
+		a.NoError(err)
+		a.Exactly("primary", string(body))
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(<-shadowed, &res))
+		a.Exactly("shadow", res.Body)
+	}
+}
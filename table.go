@@ -0,0 +1,502 @@
+package lambdarouter
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	iradix "github.com/hashicorp/go-immutable-radix"
+)
+
+// routeTable holds the routes of a router. It is shared by every copy of the router, and guarded
+// by a mutex so routes may be defined concurrently with each other and with invocations.
+//
+// Routes which share a method and path are kept together in an eventGroup, which is what the
+// matcher stores, while the routes tree holds every route under its key for listing.
+type routeTable struct {
+	mu      sync.RWMutex
+	matcher Matcher
+	routes  *iradix.Tree
+	groups  map[string]*eventGroup
+	methods []string
+	errs    []error
+
+	// prioritized holds the groups of the routes given a priority by WithPriority, in the order
+	// they were added, as matchers know nothing of priorities.
+	prioritized []*eventGroup
+
+	// versions holds the versions defined beneath each prefix by Version, when versions are
+	// segments of the path.
+	versions map[string][]string
+
+	// sources holds the routes of events which are not HTTP requests.
+	sources sourceRoutes
+
+	// largestBody is the largest body limit given to a route with WithRouteMaxBodySize, or -1 if
+	// a route was given none.
+	largestBody int64
+
+	// coldStart holds the hooks run before the first invocation.
+	coldStart coldStart
+
+	// hooks holds the hooks run around every request.
+	hooks lifecycleHooks
+
+	// flags decides the feature flags which gate routes.
+	flags FlagProvider
+
+	// tenancy takes the tenants of requests from them.
+	tenancy []TenantResolver
+
+	// serving serializes the invocations of requests served over HTTP.
+	serving sync.Mutex
+}
+
+// eventGroup holds the events of the routes which share a method and path, ordered from most to
+// least specific. Its events are replaced rather than modified, so they may be used after the
+// table is unlocked.
+type eventGroup struct {
+	events []event
+
+	// wildcard is the name of the wildcard parameter the path of the routes ends with, if any.
+	wildcard string
+
+	// priority is the highest priority of the routes, if prioritized is set.
+	priority    int
+	prioritized bool
+}
+
+// groupMatch is a group of routes which matches a path, with the values of its path parameters. It
+// holds the fields of the group as they were when it was matched, so it may be used after the table
+// is unlocked.
+type groupMatch struct {
+	group       *eventGroup
+	events      []event
+	params      map[string]string
+	wildcard    string
+	priority    int
+	prioritized bool
+}
+
+// match returns the match of the group with the values of params.
+func (g *eventGroup) match(params map[string]string) groupMatch {
+	return groupMatch{
+		group:       g,
+		events:      g.events,
+		params:      params,
+		wildcard:    g.wildcard,
+		priority:    g.priority,
+		prioritized: g.prioritized,
+	}
+}
+
+func newRouteTable() *routeTable {
+	return &routeTable{routes: iradix.New(), groups: map[string]*eventGroup{}}
+}
+
+// setMatcher replaces the matcher of the table, which must not have any routes yet.
+func (t *routeTable) setMatcher(m Matcher) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.routes.Len() > 0 {
+		return errors.New("matcher must be set before routes are defined")
+	}
+
+	t.matcher = m
+	return nil
+}
+
+// add inserts the event of a route.
+func (t *routeTable) add(e event) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := e.key()
+	if existing, exists := t.routes.Get([]byte(key)); exists {
+		return fmt.Errorf("event '%s' already exists%s", key, sites(existing.(event), e))
+	}
+
+	g, exists := t.groups[e.rt.Method+e.rt.Path]
+	if !exists {
+		g = &eventGroup{wildcard: wildcardName(e.rt.Path)}
+		if err := t.insert(e.rt, g); err != nil {
+			var at string
+			if existing, ok := t.conflicting(e.rt); ok {
+				at = sites(existing, e)
+			}
+			return fmt.Errorf("event '%s' could not be added: %w%s", key, err, at)
+		}
+		t.groups[e.rt.Method+e.rt.Path] = g
+	}
+
+	// More specific events are tried first, and events equally specific in the order they were
+	// added.
+	i := sort.Search(len(g.events), func(i int) bool {
+		return len(g.events[i].predicates) < len(e.predicates)
+	})
+
+	events := make([]event, 0, len(g.events)+1)
+	events = append(events, g.events[:i]...)
+	events = append(events, e)
+	g.events = append(events, g.events[i:]...)
+
+	t.routes, _, _ = t.routes.Insert([]byte(key), e)
+	t.addMethod(e.rt.Method)
+	t.prioritize(g, e)
+	t.limitBody(e)
+
+	return nil
+}
+
+// insert adds the group of the routes with the method and path of rt to the matcher, under each
+// of the templates the path stands for. If one cannot be added, those already added are removed
+// again where the matcher allows it.
+func (t *routeTable) insert(rt Route, g *eventGroup) error {
+	templates := matcherTemplates(rt.Path)
+
+	for i, template := range templates {
+		if err := t.matcher.Insert(rt.Method, template, g); err != nil {
+			if rm, ok := t.matcher.(RemovableMatcher); ok {
+				for _, inserted := range templates[:i] {
+					rm.Remove(rt.Method, inserted)
+				}
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// remove deletes every route stored under the method and path of key.
+func (t *routeTable) remove(key string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	g, exists := t.groups[key]
+	if !exists {
+		return fmt.Errorf("event '%s' does not exist", key)
+	}
+
+	rm, ok := t.matcher.(RemovableMatcher)
+	if !ok {
+		return fmt.Errorf("matcher %T does not support removing routes", t.matcher)
+	}
+
+	rt := g.events[0].rt
+	for _, template := range matcherTemplates(rt.Path) {
+		if !rm.Remove(rt.Method, template) {
+			return fmt.Errorf("event '%s' could not be removed from the matcher", key)
+		}
+	}
+
+	delete(t.groups, key)
+	t.unprioritize(g)
+	for _, e := range g.events {
+		t.routes, _, _ = t.routes.Delete([]byte(e.key()))
+	}
+
+	t.methods = nil
+	t.routes.Root().Walk(func(_ []byte, v interface{}) bool {
+		t.addMethod(v.(event).rt.Method)
+		return false
+	})
+
+	return nil
+}
+
+// replace swaps the handler of the route without conditions stored under the method and path of
+// key.
+func (t *routeTable) replace(key string, handler lambda.Handler) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	v, exists := t.routes.Get([]byte(key))
+	if !exists {
+		return fmt.Errorf("event '%s' does not exist", key)
+	}
+
+	e := v.(event)
+	e.wrap(handler)
+
+	g := t.groups[e.rt.Method+e.rt.Path]
+	events := make([]event, len(g.events))
+	for i, existing := range g.events {
+		events[i] = existing
+		if existing.key() == key {
+			events[i] = e
+		}
+	}
+	g.events = events
+
+	t.routes, _, _ = t.routes.Insert([]byte(key), e)
+
+	return nil
+}
+
+// swap replaces every route of the table with the routes of next.
+func (t *routeTable) swap(next *routeTable) {
+	next.mu.RLock()
+	matcher, routes, groups, methods := next.matcher, next.routes, next.groups, next.methods
+	versions, sources, prioritized := next.versions, next.sources, next.prioritized
+	largestBody := next.largestBody
+	next.mu.RUnlock()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.matcher, t.routes, t.groups, t.methods = matcher, routes, groups, methods
+	t.versions, t.sources, t.prioritized = versions, sources, prioritized
+	t.largestBody = largestBody
+}
+
+// mergeState adds the state of from other than its HTTP routes to the table: its routes of other
+// events, its hooks, and its versions, after those of the table. The feature flag provider and
+// tenant resolvers of from are only taken if the table has none. It returns an error for every
+// action of from the table already has an RPC route for.
+func (t *routeTable) mergeState(from *routeTable) []error {
+	from.mu.RLock()
+	sources, hooks, versions := from.sources, from.hooks, from.versions
+	coldStart := from.coldStart.hooks
+	flags, tenancy := from.flags, from.tenancy
+	from.mu.RUnlock()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var errs []error
+	t.sources, errs = t.sources.merge(sources)
+
+	t.coldStart.hooks = append(t.coldStart.hooks[:len(t.coldStart.hooks):len(t.coldStart.hooks)], coldStart...)
+	t.hooks.request = append(t.hooks.request[:len(t.hooks.request):len(t.hooks.request)], hooks.request...)
+	t.hooks.response = append(t.hooks.response[:len(t.hooks.response):len(t.hooks.response)], hooks.response...)
+
+	for prefix, vs := range versions {
+		if t.versions == nil {
+			t.versions = map[string][]string{}
+		}
+	next:
+		for _, v := range vs {
+			for _, existing := range t.versions[prefix] {
+				if existing == v {
+					continue next
+				}
+			}
+			t.versions[prefix] = append(t.versions[prefix], v)
+		}
+	}
+
+	if t.flags == nil {
+		t.flags = flags
+	}
+	if t.tenancy == nil {
+		t.tenancy = tenancy
+	}
+
+	return errs
+}
+
+// addMethod records that the table has routes for method, keeping the methods sorted.
+func (t *routeTable) addMethod(method string) {
+	i := sort.SearchStrings(t.methods, method)
+	if i < len(t.methods) && t.methods[i] == method {
+		return
+	}
+
+	methods := make([]string, 0, len(t.methods)+1)
+	methods = append(methods, t.methods[:i]...)
+	methods = append(methods, method)
+	t.methods = append(methods, t.methods[i:]...)
+}
+
+// addVersion records that version is defined beneath prefix.
+func (t *routeTable) addVersion(prefix, version string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.versions == nil {
+		t.versions = map[string][]string{}
+	}
+	for _, v := range t.versions[prefix] {
+		if v == version {
+			return
+		}
+	}
+
+	t.versions[prefix] = append(t.versions[prefix], version)
+}
+
+// versionsOf returns the versions defined beneath each prefix of path, keyed by the part of path
+// the prefix matches, as prefixes may hold path parameters.
+func (t *routeTable) versionsOf(path string) map[string][]string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var versions map[string][]string
+	for prefix, vs := range t.versions {
+		base, ok := matchPrefix(prefix, path)
+		if !ok {
+			continue
+		}
+		if versions == nil {
+			versions = map[string][]string{}
+		}
+		versions[base] = vs
+	}
+
+	return versions
+}
+
+// matchPrefix reports whether path begins with the segments of prefix, which ends with a slash and
+// may hold path parameters, and returns the part of path they match.
+func matchPrefix(prefix, path string) (string, bool) {
+	n := 0
+
+	for rest := prefix[:len(prefix)-1]; rest != ""; {
+		var tseg, pseg string
+		tseg, rest = nextSegment(rest)
+
+		if n == len(path) {
+			return "", false
+		}
+		pseg, _ = nextSegment(path[n:])
+
+		if isParam(tseg) && len(pseg) < 2 || !isParam(tseg) && tseg != pseg {
+			return "", false
+		}
+		n += len(pseg)
+	}
+
+	if n == len(path) || path[n] != '/' {
+		return "", false
+	}
+
+	return path[:n+1], true
+}
+
+// recordError records an error encountered while defining a route, to be reported by Validate.
+func (t *routeTable) recordError(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.errs = append(t.errs, err)
+}
+
+// errors returns a copy of the errors recorded while defining routes.
+func (t *routeTable) errors() []error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return append([]error(nil), t.errs...)
+}
+
+// list returns every route in the table, ordered by method and then path.
+func (t *routeTable) list() []Route {
+	var list []Route
+
+	for _, e := range t.events() {
+		list = append(list, e.rt)
+	}
+
+	return list
+}
+
+// events returns the events of every route in the table, ordered by method and then path.
+func (t *routeTable) events() []event {
+	t.mu.RLock()
+	routes := t.routes
+	t.mu.RUnlock()
+
+	var events []event
+	routes.Root().Walk(func(_ []byte, v interface{}) bool {
+		events = append(events, v.(event))
+		return false
+	})
+
+	return events
+}
+
+// lookup finds the events of the routes which match method and path, along with the values of
+// their path parameters. Routes with a priority are only preferred if their predicates match req,
+// unless it is nil.
+func (t *routeTable) lookup(method, path string, req *events.APIGatewayProxyRequest) ([]event, map[string]string, bool) {
+	// The predicates of routes with a priority may lock the table themselves, such as to read its
+	// tenancy, so the routes are only preferred once it is unlocked.
+	t.mu.RLock()
+	m, prioritized, found := t.find(method, path)
+	t.mu.RUnlock()
+
+	if !found {
+		return nil, nil, false
+	}
+	if len(prioritized) > 0 {
+		m = prefer(m, prioritized, req)
+	}
+
+	params := m.params
+
+	// A wildcard which matches nothing is still given to handlers, as an empty parameter.
+	if _, ok := params[m.wildcard]; m.wildcard != "" && !ok {
+		withWildcard := map[string]string{m.wildcard: ""}
+		for name, value := range params {
+			withWildcard[name] = value
+		}
+		params = withWildcard
+	}
+
+	return m.events, params, true
+}
+
+// candidates returns the keys the matcher considers when looking up method and path, or nil if it
+// cannot list them.
+func (t *routeTable) candidates(method, path string) []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if cl, ok := t.matcher.(candidateLister); ok {
+		return cl.candidates(method, path)
+	}
+
+	return nil
+}
+
+// allowedMethods returns the methods of every route which matches path, sorted.
+func (t *routeTable) allowedMethods(path string) []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var methods []string
+
+	for _, method := range t.methods {
+		if _, _, found := t.find(method, path); found {
+			methods = append(methods, method)
+		}
+	}
+
+	return methods
+}
+
+// find returns the match of the group of routes the matcher finds for method and path, along with
+// the matches of the groups of routes with a priority which may be preferred to it, for callers
+// which hold the lock.
+func (t *routeTable) find(method, path string) (groupMatch, []groupMatch, bool) {
+	v, params, found := t.matcher.Lookup(method, path)
+	if !found {
+		return groupMatch{}, nil, false
+	}
+
+	g, ok := v.(*eventGroup)
+	if !ok {
+		panic(fmt.Sprintf("matcher returned %T rather than the value of a route", v))
+	}
+
+	if len(t.prioritized) == 0 {
+		return g.match(params), nil, true
+	}
+
+	return g.match(params), t.prioritizedMatches(method, path, g), true
+}
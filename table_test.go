@@ -0,0 +1,51 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentRoutes(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router and")
+	r := New("prefix")
+	// Handlers created by lambda.NewHandler may not be invoked concurrently.
+	var handler okHandler
+	payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/prefix/thing/0"})
+
+	desc(t, 2, "Get method should")
+	{
+		desc(t, 4, "insert routes concurrently with each other and with invocations")
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				a.NoError(r.TryGet(fmt.Sprintf("thing/%d", i), handler))
+			}(i)
+			go func() {
+				defer wg.Done()
+				_, err := r.Invoke(context.Background(), payload)
+				a.NoError(err)
+			}()
+		}
+		wg.Wait()
+
+		a.Len(r.Routes(), 50)
+		a.NoError(r.Validate())
+	}
+}
+
+type okHandler struct{}
+
+func (okHandler) Invoke(context.Context, []byte) ([]byte, error) {
+	return []byte(`{"statusCode":200}`), nil
+}
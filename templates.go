@@ -0,0 +1,54 @@
+package lambdarouter
+
+import "strings"
+
+// isOptional reports whether a segment, including its leading slash, is an optional parameter,
+// such as {month?}.
+func isOptional(seg string) bool {
+	return isParam(seg) && strings.HasSuffix(seg, "?}") && !strings.HasSuffix(seg, "+?}")
+}
+
+// isWildcard reports whether a segment, including its leading slash, is a wildcard parameter,
+// such as {rest...}, which captures the rest of the path, however many segments it has.
+func isWildcard(seg string) bool {
+	return isParam(seg) && strings.HasSuffix(seg, "...}")
+}
+
+// wildcardName returns the name of the wildcard parameter path ends with, if it does.
+func wildcardName(path string) string {
+	if seg := path[strings.LastIndexByte(path, '/')+1:]; isWildcard("/" + seg) {
+		return seg[1 : len(seg)-4]
+	}
+
+	return ""
+}
+
+// matcherTemplates returns the templates the route with the given path is stored under in the
+// matcher. A path whose last segment is an optional parameter, as in /reports/{year}/{month?}, is
+// stored both with the parameter, as /reports/{year}/{month}, and without it, as /reports/{year},
+// so matchers need not know of optional parameters. A path ending with a wildcard, as in
+// /files/{path...}, is likewise stored both with a greedy parameter, as /files/{path+}, and
+// without it. Every other path is stored as it is.
+func matcherTemplates(path string) []string {
+	i := strings.LastIndexByte(path, '/')
+	if i < 0 {
+		return []string{path}
+	}
+
+	var full string
+	switch seg := path[i:]; {
+	case isOptional(seg):
+		full = path[:len(path)-2] + "}"
+	case isWildcard(seg):
+		full = path[:len(path)-4] + "+}"
+	default:
+		return []string{path}
+	}
+
+	short := path[:i]
+	if short == "" {
+		short = "/"
+	}
+
+	return []string{full, short}
+}
@@ -0,0 +1,108 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionalParameters(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with an optional parameter and")
+	r := New("")
+	var params map[string]string
+	r.Get("reports/{year}/{month?}", lambda.NewHandler(func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		params = req.PathParameters
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}))
+
+	invoke := func(path string) int {
+		params = nil
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res.StatusCode
+	}
+
+	desc(t, 2, "Get method should")
+	{
+		desc(t, 4, "match paths with the optional segment")
+		a.Exactly(http.StatusOK, invoke("/reports/2024/05"))
+		a.Exactly(map[string]string{"year": "2024", "month": "05"}, params)
+
+		desc(t, 4, "match paths without it, leaving out its parameter")
+		a.Exactly(http.StatusOK, invoke("/reports/2024"))
+		a.Exactly(map[string]string{"year": "2024"}, params)
+
+		desc(t, 4, "list the route once")
+		a.Exactly([]Route{{Method: "GET", Path: "/reports/{year}/{month?}"}}, r.Routes())
+
+		desc(t, 4, "reject routes whose shorter template conflicts with another route")
+		a.Error(r.TryGet("reports/{y}", lambda.NewHandler(handler)))
+
+		desc(t, 4, "reject optional parameters before the last segment, and greedy ones")
+		a.EqualError(r.TryGet("a/{b?}/c", lambda.NewHandler(handler)),
+			"route 'GET /a/{b?}/c' has the optional path parameter '{b?}' before its last segment")
+		a.EqualError(r.TryGet("a/{b+?}", lambda.NewHandler(handler)),
+			"route 'GET /a/{b+?}' has the greedy path parameter '{b+?}', which cannot be optional")
+	}
+
+	desc(t, 2, "Remove method should")
+	{
+		desc(t, 4, "remove both templates of the route")
+		a.NoError(r.Remove(http.MethodGet, "reports/{year}/{month?}"))
+		a.Exactly(http.StatusNotFound, invoke("/reports/2024"))
+		a.Exactly(http.StatusNotFound, invoke("/reports/2024/05"))
+	}
+}
+
+func TestWildcardParameters(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with a wildcard parameter and")
+	r := New("")
+	var params map[string]string
+	r.Get("files/{path...}", lambda.NewHandler(func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		params = req.PathParameters
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}))
+	r.Get("files/special", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		params = map[string]string{"special": "true"}
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}))
+
+	invoke := func(path string) {
+		params = nil
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path})
+		_, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+	}
+
+	desc(t, 2, "Get method should")
+	{
+		desc(t, 4, "capture the rest of the path as one parameter")
+		invoke("/files/docs/guide/intro.md")
+		a.Exactly(map[string]string{"path": "docs/guide/intro.md"}, params)
+
+		desc(t, 4, "match when nothing remains, with an empty parameter")
+		invoke("/files")
+		a.Exactly(map[string]string{"path": ""}, params)
+
+		desc(t, 4, "prefer static routes")
+		invoke("/files/special")
+		a.Exactly(map[string]string{"special": "true"}, params)
+
+		desc(t, 4, "reject wildcards before the last segment")
+		a.EqualError(r.TryGet("a/{b...}/c", lambda.NewHandler(handler)),
+			"route 'GET /a/{b...}/c' has the wildcard path parameter '{b...}' before its last segment")
+	}
+}
@@ -0,0 +1,140 @@
+package lambdarouter
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Tenant is the tenant a request was made on behalf of, for backends serving many tenants from
+// one function.
+type Tenant struct {
+	// ID identifies the tenant, as taken from the request by a TenantResolver.
+	ID string
+}
+
+// TenantResolver takes the ID of the tenant of a request from it, returning the empty string if
+// the request does not name one.
+type TenantResolver func(req events.APIGatewayProxyRequest) string
+
+// TenantFromHost returns a TenantResolver which takes the tenant from the subdomain of domain a
+// request was made to, so with the domain example.com a request to acme.example.com is made for the
+// tenant acme. Requests to the domain itself, or to deeper subdomains, name no tenant.
+func TenantFromHost(domain string) TenantResolver {
+	suffix := "." + strings.ToLower(domain)
+
+	return func(req events.APIGatewayProxyRequest) string {
+		host := requestHost(req)
+		if !strings.HasSuffix(host, suffix) {
+			return ""
+		}
+
+		if label := strings.TrimSuffix(host, suffix); !strings.Contains(label, ".") {
+			return label
+		}
+		return ""
+	}
+}
+
+// TenantFromHeader returns a TenantResolver which takes the tenant from the named header.
+func TenantFromHeader(name string) TenantResolver {
+	return func(req events.APIGatewayProxyRequest) string {
+		if values := headerValues(req, name); len(values) > 0 {
+			return strings.TrimSpace(values[0])
+		}
+		return ""
+	}
+}
+
+// TenantFromPathParameter returns a TenantResolver which takes the tenant from the named path
+// parameter of the route, such as tenant in /{tenant}/orders.
+func TenantFromPathParameter(name string) TenantResolver {
+	return func(req events.APIGatewayProxyRequest) string {
+		return req.PathParameters[name]
+	}
+}
+
+// WithTenancy sets how the router takes the tenant of a request from it. Resolvers are tried in
+// order, and the first to find a tenant decides it. The tenant of a request is placed in the
+// context of the handler of the route it matches, from which it is retrieved by TenantFrom.
+func WithTenancy(resolvers ...TenantResolver) Option {
+	return func(r *Router) {
+		r.table.tenancy = resolvers
+	}
+}
+
+// TenantFrom returns the tenant of the current invocation. The second return value reports
+// whether the router found a tenant in the request.
+func TenantFrom(ctx context.Context) (Tenant, bool) {
+	t, ok := ctx.Value(tenantKey).(Tenant)
+	return t, ok
+}
+
+// WithTenants restricts a route to the requests of the given tenants. Requests of other tenants,
+// or which name none, are routed to another route of the same method and path, or are responded
+// to with a 404, so that tenants cannot learn of the routes of others.
+func WithTenants(ids ...string) RouteOption {
+	return withPredicates(tenantPredicate(ids))
+}
+
+// Tenants allows you to define many routes restricted to the same tenants, as WithTenants does for
+// a single route. The fn parameter is a function in which the routes, or mounted routers, should
+// be defined.
+func (r *Router) Tenants(ids []string, fn func(r *Router)) {
+	original := r.predicates
+	r.predicates = append(r.predicates[:len(r.predicates):len(r.predicates)], tenantPredicate(ids))
+	fn(r)
+	r.predicates = original
+}
+
+func tenantPredicate(ids []string) predicate {
+	ids = append([]string(nil), ids...)
+	sort.Strings(ids)
+
+	return predicate{
+		name:   "tenant=" + strings.Join(ids, ","),
+		rank:   tenantRank,
+		status: http.StatusNotFound,
+		bind: func(t *routeTable) func(req events.APIGatewayProxyRequest) bool {
+			return func(req events.APIGatewayProxyRequest) bool {
+				tenant := t.tenant(req)
+				if tenant == "" {
+					return false
+				}
+
+				i := sort.SearchStrings(ids, tenant)
+				return i < len(ids) && ids[i] == tenant
+			}
+		},
+	}
+}
+
+// tenant returns the ID of the tenant of req, or the empty string if it names none.
+func (t *routeTable) tenant(req events.APIGatewayProxyRequest) string {
+	t.mu.RLock()
+	resolvers := t.tenancy
+	t.mu.RUnlock()
+
+	for _, resolve := range resolvers {
+		if id := resolve(req); id != "" {
+			return id
+		}
+	}
+
+	return ""
+}
+
+// withTenant places the tenant of req, if it names one, in ctx.
+func (r Router) withTenant(ctx context.Context, req events.APIGatewayProxyRequest) context.Context {
+	if r.table == nil {
+		return ctx
+	}
+
+	if id := r.table.tenant(req); id != "" {
+		return context.WithValue(ctx, tenantKey, Tenant{ID: id})
+	}
+	return ctx
+}
@@ -0,0 +1,94 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenancy(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with tenancy and")
+	r := New("", WithTenancy(
+		TenantFromHeader("X-Tenant"),
+		TenantFromHost("example.com"),
+		TenantFromPathParameter("tenant"),
+	))
+
+	tenantOf := func(label string) lambda.Handler {
+		return lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+			tenant, _ := TenantFrom(ctx)
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: label + ":" + tenant.ID}, nil
+		})
+	}
+	r.Get("orders", tenantOf("shared"))
+	r.Get("orders", tenantOf("acme"), WithTenants("acme"))
+	r.Tenants([]string{"globex", "initech"}, func(r *Router) {
+		r.Get("reports", tenantOf("reports"))
+	})
+	r.Get("t/{tenant}/invoices", tenantOf("invoices"))
+
+	invoke := func(req events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+		req.HTTPMethod = http.MethodGet
+		payload, _ := json.Marshal(req)
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "WithTenancy option should")
+	{
+		desc(t, 4, "take the tenant from a header")
+		a.Exactly("shared:umbrella", invoke(events.APIGatewayProxyRequest{
+			Path:    "/orders",
+			Headers: map[string]string{"x-tenant": "umbrella"},
+		}).Body)
+
+		desc(t, 4, "take the tenant from the subdomain of the host")
+		req := events.APIGatewayProxyRequest{Path: "/orders"}
+		req.RequestContext.DomainName = "acme.example.com"
+		a.Exactly("acme:acme", invoke(req).Body)
+
+		desc(t, 4, "take the tenant from a path parameter")
+		a.Exactly("invoices:initech", invoke(events.APIGatewayProxyRequest{Path: "/t/initech/invoices"}).Body)
+
+		desc(t, 4, "place no tenant in the context of requests which name none")
+		a.Exactly("shared:", invoke(events.APIGatewayProxyRequest{Path: "/orders"}).Body)
+	}
+
+	desc(t, 2, "Tenants method should")
+	{
+		desc(t, 4, "route the requests of its tenants to its routes")
+		a.Exactly("reports:globex", invoke(events.APIGatewayProxyRequest{
+			Path:    "/reports",
+			Headers: map[string]string{"X-Tenant": "globex"},
+		}).Body)
+
+		desc(t, 4, "respond with a 404 to the requests of other tenants")
+		a.Exactly(http.StatusNotFound, invoke(events.APIGatewayProxyRequest{
+			Path:    "/reports",
+			Headers: map[string]string{"X-Tenant": "acme"},
+		}).StatusCode)
+		a.Exactly(http.StatusNotFound, invoke(events.APIGatewayProxyRequest{Path: "/reports"}).StatusCode)
+	}
+
+	desc(t, 2, "TenantFromHost function should")
+	{
+		desc(t, 4, "name no tenant for the domain itself or deeper subdomains")
+		resolve := TenantFromHost("example.com")
+		req := events.APIGatewayProxyRequest{}
+		req.RequestContext.DomainName = "example.com"
+		a.Exactly("", resolve(req))
+		req.RequestContext.DomainName = "a.b.example.com"
+		a.Exactly("", resolve(req))
+	}
+}
@@ -0,0 +1,89 @@
+package lambdarouter
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// TerraformOptions configures the output of Router.Terraform. Both fields are written verbatim
+// into the generated configuration, so they may contain Terraform interpolation sequences.
+type TerraformOptions struct {
+	// APIID is the ID of the API Gateway v2 API the routes belong to, for example
+	// "${aws_apigatewayv2_api.hellosrv.id}".
+	APIID string
+
+	// Target is the target of every route, for example
+	// "integrations/${aws_apigatewayv2_integration.hellosrv.id}".
+	Target string
+}
+
+// Terraform writes a Terraform JSON configuration (a .tf.json file) to w which declares an
+// aws_apigatewayv2_route resource for every route defined on the router. This allows the API
+// Gateway routes of a function to be derived from its code rather than maintained by hand.
+func (r Router) Terraform(w io.Writer, opts TerraformOptions) error {
+	resources := map[string]terraformRoute{}
+	defined := map[string]bool{}
+
+	for _, defn := range r.Routes() {
+		// API Gateway has no optional parameters, so routes with one need a route for each of
+		// the templates they stand for.
+		for _, template := range matcherTemplates(defn.Path) {
+			rt := Route{Method: defn.Method, Path: template}
+
+			// Routes which only differ by their conditions share a route in API Gateway.
+			if defined[rt.String()] {
+				continue
+			}
+			defined[rt.String()] = true
+
+			name := terraformName(rt)
+			for i := 2; ; i++ {
+				if _, exists := resources[name]; !exists {
+					break
+				}
+				name = terraformName(rt) + "_" + strconv.Itoa(i)
+			}
+
+			resources[name] = terraformRoute{
+				APIID:    opts.APIID,
+				RouteKey: rt.String(),
+				Target:   opts.Target,
+			}
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(map[string]interface{}{
+		"resource": map[string]interface{}{
+			"aws_apigatewayv2_route": resources,
+		},
+	})
+}
+
+type terraformRoute struct {
+	APIID    string `json:"api_id"`
+	RouteKey string `json:"route_key"`
+	Target   string `json:"target,omitempty"`
+}
+
+// terraformName derives a valid Terraform resource name from a route, e.g. "GET /hello/{name}"
+// becomes "get_hello_name".
+func terraformName(rt Route) string {
+	var b strings.Builder
+
+	for _, c := range strings.ToLower(rt.Method + "/" + rt.Path) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+			b.WriteRune(c)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "_"):
+			b.WriteByte('_')
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "_")
+}
@@ -0,0 +1,68 @@
+package lambdarouter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerraform(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router and")
+	r := New("prefix")
+	handler := lambda.NewHandler(handler)
+
+	r.Get("hello/{name}", handler)
+	r.Post("hello", handler)
+
+	desc(t, 2, "Routes method should")
+	{
+		desc(t, 4, "return every defined route")
+		a.Exactly([]Route{
+			{Method: "GET", Path: "/prefix/hello/{name}"},
+			{Method: "POST", Path: "/prefix/hello"},
+		}, r.Routes())
+	}
+
+	desc(t, 2, "Terraform method should")
+	{
+		desc(t, 4, "write a route resource for every defined route")
+		var buf bytes.Buffer
+		err := r.Terraform(&buf, TerraformOptions{
+			APIID:  "${aws_apigatewayv2_api.api.id}",
+			Target: "integrations/${aws_apigatewayv2_integration.lambda.id}",
+		})
+		a.NoError(err)
+
+		var config struct {
+			Resource struct {
+				Routes map[string]terraformRoute `json:"aws_apigatewayv2_route"`
+			} `json:"resource"`
+		}
+		a.NoError(json.Unmarshal(buf.Bytes(), &config))
+
+		a.Len(config.Resource.Routes, 2)
+		a.Exactly(terraformRoute{
+			APIID:    "${aws_apigatewayv2_api.api.id}",
+			RouteKey: "GET /prefix/hello/{name}",
+			Target:   "integrations/${aws_apigatewayv2_integration.lambda.id}",
+		}, config.Resource.Routes["get_prefix_hello_name"])
+		a.Exactly("POST /prefix/hello", config.Resource.Routes["post_prefix_hello"].RouteKey)
+
+		desc(t, 4, "write a route for each template of routes with an optional parameter")
+		r = New("")
+		r.Get("reports/{year}/{month?}", handler)
+
+		buf.Reset()
+		config.Resource.Routes = nil
+		a.NoError(r.Terraform(&buf, TerraformOptions{}))
+		a.NoError(json.Unmarshal(buf.Bytes(), &config))
+		a.Len(config.Resource.Routes, 2)
+		a.Exactly("GET /reports/{year}/{month}", config.Resource.Routes["get_reports_year_month"].RouteKey)
+		a.Exactly("GET /reports/{year}", config.Resource.Routes["get_reports_year"].RouteKey)
+	}
+}
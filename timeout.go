@@ -0,0 +1,35 @@
+package lambdarouter
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter/internal/timelimit"
+)
+
+// WithTimeout limits the time the handler of a route may take to d, so a slow downstream of one
+// route cannot consume the time the rest of the invocation needs. Once d has passed the context of
+// the handler is cancelled, and the router responds with a 504 without waiting for it. The handler
+// keeps running until it returns, so it should stop once its context is cancelled. The limit
+// applies to the handler and the middleware given with WithMiddleware, which run within it, while
+// the middleware of the router sees the 504 as it would any other response.
+func WithTimeout(d time.Duration) RouteOption {
+	return func(e *event) {
+		e.timeout = d
+	}
+}
+
+// timeoutHandler invokes a handler with a time limit.
+type timeoutHandler struct {
+	d    time.Duration
+	next lambda.Handler
+}
+
+func (th timeoutHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, th.d)
+	defer cancel()
+
+	return timelimit.Invoke(ctx, th.next, payload, &HTTPError{Status: http.StatusGatewayTimeout})
+}
@@ -0,0 +1,64 @@
+// Package timeout provides middleware which responds to requests before Lambda stops the function
+// at its timeout, so clients receive a response instead of the 502 API Gateway returns when the
+// function is stopped.
+package timeout
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/mitchell/lambdarouter/internal/timelimit"
+)
+
+// Config configures the timeout middleware.
+type Config struct {
+	// Buffer is how long before the deadline of the invocation the handler is given up on, which
+	// must leave enough time for the response to be returned. If zero, it is 500 milliseconds.
+	Buffer time.Duration
+
+	// Status is the status of the response to requests whose handler is given up on. If zero, it
+	// is 504 Gateway Timeout, while 503 Service Unavailable suits clients which should retry.
+	Status int
+}
+
+// Middleware returns middleware which gives handlers until cfg.Buffer before the deadline of the
+// invocation, after which the context of the handler is cancelled and the request is responded to
+// with an HTTPError of cfg.Status. The handler keeps running until it returns, so it should stop
+// once its context is cancelled, as Lambda may invoke the function again meanwhile. A panic of the
+// handler is returned as an error. Invocations without a deadline, such as those of tests, are not
+// limited.
+func Middleware(cfg Config) lambdarouter.Middleware {
+	if cfg.Buffer == 0 {
+		cfg.Buffer = 500 * time.Millisecond
+	}
+	if cfg.Status == 0 {
+		cfg.Status = http.StatusGatewayTimeout
+	}
+
+	return func(next lambda.Handler) lambda.Handler {
+		return limiter{cfg: cfg, next: next}
+	}
+}
+
+type limiter struct {
+	cfg  Config
+	next lambda.Handler
+}
+
+func (l limiter) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return l.next.Invoke(ctx, payload)
+	}
+
+	ctx, cancel := context.WithDeadline(ctx, deadline.Add(-l.cfg.Buffer))
+	defer cancel()
+
+	return timelimit.Invoke(ctx, l.next, payload, &lambdarouter.HTTPError{
+		Status: l.cfg.Status,
+		Detail: "the request could not be handled in time",
+	})
+}
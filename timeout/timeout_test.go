@@ -0,0 +1,81 @@
+package timeout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with timeout middleware and")
+	cancelled := make(chan struct{})
+	r := lambdarouter.New("")
+	r.Use(Middleware(Config{Buffer: 50 * time.Millisecond, Status: http.StatusServiceUnavailable}))
+	r.Get("slow", lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+		<-ctx.Done()
+		close(cancelled)
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}))
+	r.Get("fast", lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}))
+	r.Get("panic", lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+		panic("boom")
+	}))
+
+	invoke := func(ctx context.Context, path string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path})
+		resjson, err := r.Invoke(ctx, payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "Middleware should")
+	{
+		desc(t, 4, "respond to requests which outlast the deadline less the buffer")
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		res := invoke(ctx, "/slow")
+		a.Exactly(http.StatusServiceUnavailable, res.StatusCode)
+		a.Less(time.Since(start), 100*time.Millisecond)
+
+		desc(t, 4, "cancel the context of the handler")
+		<-cancelled
+
+		desc(t, 4, "respond with the response of handlers which finish in time")
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		a.Exactly(http.StatusOK, invoke(ctx, "/fast").StatusCode)
+
+		desc(t, 4, "not limit invocations without a deadline")
+		a.Exactly(http.StatusOK, invoke(context.Background(), "/fast").StatusCode)
+
+		desc(t, 4, "return the panics of handlers as errors")
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/panic"})
+		_, err := r.Invoke(ctx, payload)
+		a.EqualError(err, "panic: boom")
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
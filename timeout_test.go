@@ -0,0 +1,67 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTimeout(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with a route with a time limit and")
+	var seen []int
+	r := New("")
+	r.Use(func(next lambda.Handler) lambda.Handler {
+		return lambda.NewHandler(func(ctx context.Context, payload json.RawMessage) (json.RawMessage, error) {
+			res, err := next.Invoke(ctx, payload)
+			if httpErr, ok := err.(*HTTPError); ok {
+				seen = append(seen, httpErr.Status)
+			}
+			return res, err
+		})
+	})
+	r.Get("slow", lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+		<-ctx.Done()
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}), WithTimeout(20*time.Millisecond))
+	r.Get("fast", lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}), WithTimeout(time.Second))
+	r.Get("panic", lambda.NewHandler(func(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+		panic("boom")
+	}), WithTimeout(time.Second))
+
+	invoke := func(path string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path})
+		resjson, err := r.Invoke(context.Background(), payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "WithTimeout option should")
+	{
+		desc(t, 4, "respond with a 504 once the limit has passed")
+		a.Exactly(http.StatusGatewayTimeout, invoke("/slow").StatusCode)
+
+		desc(t, 4, "let the middleware of the router see the 504")
+		a.Exactly([]int{http.StatusGatewayTimeout}, seen)
+
+		desc(t, 4, "respond with the response of handlers which finish in time")
+		a.Exactly(http.StatusOK, invoke("/fast").StatusCode)
+
+		desc(t, 4, "return the panics of handlers as errors")
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/panic"})
+		_, err := r.Invoke(context.Background(), payload)
+		a.EqualError(err, "panic: boom")
+	}
+}
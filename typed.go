@@ -0,0 +1,74 @@
+package lambdarouter
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter/respond"
+)
+
+// Validator is implemented by request types which can check their own contents. HandlerOf calls
+// Validate after decoding a request, and responds with a 400 if it returns an error.
+type Validator interface {
+	Validate() error
+}
+
+// StatusCoder is implemented by response types which choose their own status code. HandlerOf
+// responds with a 200 for response types which do not implement it.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// HandlerOf returns a lambda.Handler which binds the proxy request to a Req, validates it, and
+// invokes fn with it. Req is populated by BindContext, so its fields may be tagged to be set from
+// the path parameters, query string, and headers as well as the JSON body of the request. Requests
+// which cannot be bound or fail validation are responded to with a 400 listing the invalid fields.
+// The Resp returned by fn is rendered as the proxy response by JSONResponse, which encodes it as a
+// JSON body, and given to the response marshaler of the router as any other response. This removes
+// the boilerplate of handling the raw APIGatewayProxyRequest and APIGatewayProxyResponse in every
+// handler. Errors returned by fn are returned by the handler unchanged.
+func HandlerOf[Req, Resp any](fn func(ctx context.Context, in Req) (Resp, error)) lambda.Handler {
+	return typedHandler[Req, Resp]{fn: fn}
+}
+
+type typedHandler[Req, Resp any] struct {
+	fn func(ctx context.Context, in Req) (Resp, error)
+}
+
+func (th typedHandler[Req, Resp]) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	var in Req
+	codec := CodecFrom(ctx)
+
+	req, err := RequestFrom(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := BindContext(ctx, req, &in); err != nil {
+		return codec.Marshal(bindErrorResponse(ctx, err))
+	}
+
+	if v, ok := interface{}(in).(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return codec.Marshal(respond.ErrorContext(ctx, http.StatusBadRequest, err))
+		}
+	}
+
+	out, err := th.fn(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	status := http.StatusOK
+	if sc, ok := interface{}(out).(StatusCoder); ok {
+		status = sc.StatusCode()
+	}
+
+	res, err := JSONResponse(ctx, status, out)
+	if err != nil {
+		return codec.Marshal(respond.ErrorContext(ctx, http.StatusInternalServerError, err))
+	}
+
+	return codec.Marshal(res)
+}
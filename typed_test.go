@@ -0,0 +1,69 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type greetRequest struct {
+	Name string `json:"name"`
+}
+
+func (gr greetRequest) Validate() error {
+	if gr.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+type greetResponse struct {
+	Greeting string `json:"greeting"`
+}
+
+func (greetResponse) StatusCode() int {
+	return http.StatusCreated
+}
+
+func TestHandlerOf(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a typed handler and")
+	h := HandlerOf(func(ctx context.Context, in greetRequest) (greetResponse, error) {
+		return greetResponse{Greeting: "hello " + in.Name}, nil
+	})
+	ctx := context.Background()
+
+	invoke := func(body string) events.APIGatewayProxyResponse {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{Body: body})
+		resjson, err := h.Invoke(ctx, payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 2, "Invoke method should")
+	{
+		desc(t, 4, "decode the request and encode the response")
+		res := invoke(`{"name": "mitchell"}`)
+		a.Exactly(http.StatusCreated, res.StatusCode)
+		a.Exactly("application/json", res.Headers["Content-Type"])
+		a.JSONEq(`{"greeting": "hello mitchell"}`, res.Body)
+
+		desc(t, 4, "respond with a 400 when the request fails validation")
+		res = invoke(`{}`)
+		a.Exactly(http.StatusBadRequest, res.StatusCode)
+		a.JSONEq(`{"message": "name is required"}`, res.Body)
+
+		desc(t, 4, "respond with a 400 when the body is malformed")
+		res = invoke(`{`)
+		a.Exactly(http.StatusBadRequest, res.StatusCode)
+	}
+}
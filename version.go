@@ -0,0 +1,106 @@
+package lambdarouter
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// WithVersionHeader makes the Version method select routes by the value of the named header, such
+// as Accept-Version, rather than by a segment of the path.
+func WithVersionHeader(name string) Option {
+	return func(r *Router) {
+		r.versionHeader = http.CanonicalHeaderKey(name)
+	}
+}
+
+// WithDefaultVersion sets the version used for requests which do not specify one. Without a
+// default, requests must specify a version to match versioned routes.
+func WithDefaultVersion(version string) Option {
+	return func(r *Router) {
+		r.defaultVersion = version
+	}
+}
+
+// Version allows you to define many routes belonging to the same version of an API. The fn
+// parameter is a function in which the routes, or mounted routers, of the version should be
+// defined.
+//
+// By default the version is a segment of the path, so Version("v1", fn) defines its routes
+// beneath v1/, as Group does. If version is the default version of the router, fn is called a
+// second time to define its routes without the segment as well. When a request names a version
+// which has not been defined, but the path would match a route of another version, the router
+// responds with a 400.
+//
+// If the router was created with WithVersionHeader, the routes are instead defined at their
+// paths, and are only matched by requests whose header names the version, or without the header
+// if it is the default. Requests naming any other version are responded to with a 400.
+func (r *Router) Version(version string, fn func(r *Router)) {
+	if r.versionHeader != "" {
+		original := r.predicates
+		r.predicates = append(r.predicates[:len(r.predicates):len(r.predicates)],
+			versionPredicate(r.versionHeader, version, version == r.defaultVersion))
+		fn(r)
+		r.predicates = original
+		return
+	}
+
+	if r.table == nil {
+		panic("router not initialized")
+	}
+	r.table.addVersion(r.prefix, version)
+
+	r.Group(version, fn)
+
+	if version == r.defaultVersion {
+		original, middleware, predicates := r.prefix, r.middleware, r.predicates
+		fn(r)
+		r.prefix, r.middleware, r.predicates = original, middleware, predicates
+	}
+}
+
+func versionPredicate(header, version string, isDefault bool) predicate {
+	return predicate{
+		name:   "version=" + version,
+		rank:   versionRank,
+		status: http.StatusBadRequest,
+		match: func(req events.APIGatewayProxyRequest) bool {
+			values := headerValues(req, header)
+			if len(values) == 0 {
+				return isDefault
+			}
+
+			return strings.TrimSpace(values[0]) == version
+		},
+	}
+}
+
+// unknownVersion reports whether req names a version which has not been defined, in place of the
+// path segment of a version, and the path would match a route of some defined version.
+func (r Router) unknownVersion(req events.APIGatewayProxyRequest) bool {
+	if r.table == nil || r.versionHeader != "" {
+		return false
+	}
+
+	for base, versions := range r.table.versionsOf(req.Path) {
+		seg, rest := nextSegment("/" + strings.TrimPrefix(req.Path, base))
+		if seg == "/" {
+			continue
+		}
+
+		for _, v := range versions {
+			if seg[1:] == v {
+				return false
+			}
+		}
+
+		for _, v := range versions {
+			if _, _, found := r.lookup(req.HTTPMethod, base+v+rest, nil); found {
+				return true
+			}
+		}
+	}
+
+	return false
+}
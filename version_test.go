@@ -0,0 +1,117 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersion(t *testing.T) {
+	a := assert.New(t)
+
+	ctx := context.Background()
+
+	respond := func(body string) lambda.Handler {
+		return lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: body}, nil
+		})
+	}
+	invoke := func(r Router, req events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+		req.HTTPMethod = http.MethodGet
+		payload, _ := json.Marshal(req)
+		resjson, err := r.Invoke(ctx, payload)
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		a.NoError(json.Unmarshal(resjson, &res))
+		return res
+	}
+
+	desc(t, 0, "Initialize Router versioned by path and")
+	r := New("prefix", WithDefaultVersion("v1"))
+	r.Version("v1", func(r *Router) {
+		r.Get("users", respond("v1 users"))
+	})
+	r.Version("v2", func(r *Router) {
+		r.Get("users", respond("v2 users"))
+	})
+
+	desc(t, 2, "Version method should")
+	{
+		desc(t, 4, "define routes beneath the version")
+		res := invoke(r, events.APIGatewayProxyRequest{Path: "/prefix/v2/users"})
+		a.Exactly("v2 users", res.Body)
+
+		desc(t, 4, "define the routes of the default version without it as well")
+		res = invoke(r, events.APIGatewayProxyRequest{Path: "/prefix/users"})
+		a.Exactly("v1 users", res.Body)
+		res = invoke(r, events.APIGatewayProxyRequest{Path: "/prefix/v1/users"})
+		a.Exactly("v1 users", res.Body)
+
+		desc(t, 4, "respond with a 400 for unknown versions")
+		res = invoke(r, events.APIGatewayProxyRequest{Path: "/prefix/v3/users"})
+		a.Exactly(http.StatusBadRequest, res.StatusCode)
+
+		desc(t, 4, "respond with a 404 for unknown paths of known versions")
+		res = invoke(r, events.APIGatewayProxyRequest{Path: "/prefix/v2/orders"})
+		a.Exactly(http.StatusNotFound, res.StatusCode)
+	}
+
+	desc(t, 0, "Initialize Router versioned by path beneath a parameter and")
+	r = New("", WithDefaultVersion("v1"))
+	r.Group("tenants/{tenant}", func(r *Router) {
+		r.Version("v1", func(r *Router) {
+			r.Get("users", respond("v1 users"))
+		})
+	})
+
+	desc(t, 2, "Version method should")
+	{
+		desc(t, 4, "respond with a 400 for unknown versions beneath the parameter")
+		res := invoke(r, events.APIGatewayProxyRequest{Path: "/tenants/acme/v1/users"})
+		a.Exactly("v1 users", res.Body)
+		res = invoke(r, events.APIGatewayProxyRequest{Path: "/tenants/acme/v3/users"})
+		a.Exactly(http.StatusBadRequest, res.StatusCode)
+	}
+
+	desc(t, 0, "Initialize Router versioned by header and")
+	r = New("prefix", WithVersionHeader("accept-version"), WithDefaultVersion("v1"))
+	r.Version("v1", func(r *Router) {
+		r.Get("users", respond("v1 users"))
+	})
+	r.Version("v2", func(r *Router) {
+		r.Get("users", respond("v2 users"))
+	})
+
+	desc(t, 2, "Version method should")
+	{
+		desc(t, 4, "choose between routes by the header")
+		res := invoke(r, events.APIGatewayProxyRequest{
+			Path:    "/prefix/users",
+			Headers: map[string]string{"Accept-Version": "v2"},
+		})
+		a.Exactly("v2 users", res.Body)
+
+		desc(t, 4, "use the default version without the header")
+		res = invoke(r, events.APIGatewayProxyRequest{Path: "/prefix/users"})
+		a.Exactly("v1 users", res.Body)
+
+		desc(t, 4, "respond with a 400 for unknown versions")
+		res = invoke(r, events.APIGatewayProxyRequest{
+			Path:    "/prefix/users",
+			Headers: map[string]string{"Accept-Version": "v3"},
+		})
+		a.Exactly(http.StatusBadRequest, res.StatusCode)
+
+		desc(t, 4, "describe the version in the conditions of routes")
+		a.Exactly([]Route{
+			{Method: "GET", Path: "/prefix/users", Conditions: []string{"version=v1"}},
+			{Method: "GET", Path: "/prefix/users", Conditions: []string{"version=v2"}},
+		}, r.Routes())
+	}
+}
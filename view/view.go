@@ -0,0 +1,124 @@
+// Package view renders html/template pages into API Gateway proxy responses, for functions which
+// serve server-rendered pages.
+package view
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"sort"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Config configures how pages are parsed.
+type Config struct {
+	// Layout is the path of a template within the file system which every page is rendered
+	// inside. It renders the page by executing the "content" template, as in
+	// {{template "content" .}}, and may declare other blocks pages can fill, such as
+	// {{block "title" .}}Default{{end}}. If empty, pages are rendered on their own.
+	Layout string
+
+	// Partials lists glob patterns matching templates within the file system which every page may
+	// execute, such as "partials/*.html". Each is named by its path.
+	Partials []string
+
+	// Funcs are functions pages, partials, and the layout may call.
+	Funcs template.FuncMap
+}
+
+// Views holds parsed pages, ready to be rendered.
+type Views struct {
+	pages map[string]*template.Template
+}
+
+// New parses the pages of fsys matching the glob pattern, as with the layout and partials of cfg.
+// Each page is parsed separately, so pages may fill the blocks of the layout differently, and is
+// named by its path, such as "pages/home.html". Templates are parsed once, when New is called, so
+// it should be called outside of handlers.
+func New(fsys fs.FS, pattern string, cfg Config) (*Views, error) {
+	base := template.New("").Funcs(cfg.Funcs)
+
+	for _, p := range cfg.Partials {
+		matches, err := fs.Glob(fsys, p)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range matches {
+			if err := parseFile(base, fsys, name, name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if cfg.Layout != "" {
+		if err := parseFile(base, fsys, cfg.Layout, cfg.Layout); err != nil {
+			return nil, err
+		}
+	}
+
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("view: no pages match %q", pattern)
+	}
+	sort.Strings(names)
+
+	v := &Views{pages: map[string]*template.Template{}}
+	for _, name := range names {
+		t, err := base.Clone()
+		if err != nil {
+			return nil, err
+		}
+
+		// Within a layout, the page is the content the layout executes.
+		entry, as := name, name
+		if cfg.Layout != "" {
+			entry, as = cfg.Layout, "content"
+		}
+		if err := parseFile(t, fsys, name, as); err != nil {
+			return nil, err
+		}
+
+		v.pages[name] = t.Lookup(entry)
+	}
+
+	return v, nil
+}
+
+func parseFile(t *template.Template, fsys fs.FS, name, as string) error {
+	src, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := t.New(as).Parse(string(src)); err != nil {
+		return fmt.Errorf("view: parsing %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// Render executes the named page with data, returning a response with the given status whose body
+// is the HTML it renders. The page is executed in full before the response is returned, so pages
+// which fail to execute do not produce partial responses.
+func (v *Views) Render(status int, page string, data interface{}) (events.APIGatewayProxyResponse, error) {
+	t, ok := v.pages[page]
+	if !ok {
+		return events.APIGatewayProxyResponse{}, fmt.Errorf("view: no page named %q", page)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: status,
+		Headers:    map[string]string{"Content-Type": "text/html; charset=utf-8"},
+		Body:       buf.String(),
+	}, nil
+}
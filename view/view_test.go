@@ -0,0 +1,79 @@
+package view
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//go:embed testdata
+var files embed.FS
+
+func TestViews(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Parse pages with a layout and")
+	fsys, _ := fs.Sub(files, "testdata")
+	v, err := New(fsys, "pages/*.html", Config{
+		Layout:   "layout.html",
+		Partials: []string{"partials/*.html"},
+		Funcs:    template.FuncMap{"upper": strings.ToUpper},
+	})
+	a.NoError(err)
+
+	desc(t, 2, "Render method should")
+	{
+		desc(t, 4, "render pages inside the layout")
+		res, err := v.Render(http.StatusOK, "pages/home.html", map[string]string{"Name": "<Mitchell>"})
+		a.NoError(err)
+		a.Exactly(http.StatusOK, res.StatusCode)
+		a.Exactly("text/html; charset=utf-8", res.Headers["Content-Type"])
+		a.Exactly("<title>Home</title>\n<main><h1>Hello, &lt;Mitchell&gt;</h1><span>&lt;Mitchell&gt;</span>\n\n</main>\n", res.Body)
+
+		desc(t, 4, "keep the default blocks of the layout for pages which do not fill them")
+		res, err = v.Render(http.StatusOK, "pages/about.html", struct{ Missing *struct{ Field string } }{&struct{ Field string }{"x"}})
+		a.NoError(err)
+		a.True(strings.HasPrefix(res.Body, "<title>Site</title>"))
+		a.Contains(res.Body, "ABOUT x")
+
+		desc(t, 4, "return an error when pages fail to render")
+		_, err = v.Render(http.StatusOK, "pages/about.html", struct{ Missing *struct{ Field string } }{})
+		a.Error(err)
+		_, err = v.Render(http.StatusOK, "pages/missing.html", nil)
+		a.Error(err)
+	}
+
+	desc(t, 0, "Parse pages without a layout and")
+	v, err = New(fsys, "partials/*.html", Config{})
+	a.NoError(err)
+
+	desc(t, 2, "Render method should")
+	{
+		desc(t, 4, "render pages on their own")
+		res, err := v.Render(http.StatusCreated, "partials/badge.html", map[string]string{"Name": "a"})
+		a.NoError(err)
+		a.Exactly(http.StatusCreated, res.StatusCode)
+		a.Exactly("<span>a</span>\n", res.Body)
+	}
+
+	desc(t, 0, "New function should")
+	{
+		desc(t, 2, "return an error when no pages match")
+		_, err := New(fsys, "none/*.html", Config{})
+		a.Error(err)
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
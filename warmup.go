@@ -0,0 +1,88 @@
+package lambdarouter
+
+import "bytes"
+
+// WithWarmup makes the router respond to the events of services which keep functions warm with a
+// 200, before they are routed, so they neither invoke handlers nor are logged as requests which
+// match no route. The events of serverless-plugin-warmup, of lambda-warmer, and of CloudWatch
+// schedules are recognised, along with those of any classifiers given. Scheduled events are only
+// treated as warmup events when no route defined by EventBridge matches them, so routes for
+// schedules still receive them.
+func WithWarmup(classifiers ...EventClassifier) Option {
+	return func(r *Router) {
+		r.warmup = &warmup{classifiers: classifiers}
+	}
+}
+
+// warmup holds the classifiers of warmup events given to WithWarmup.
+type warmup struct {
+	classifiers []EventClassifier
+}
+
+// warmupProbe holds the fields of the events of known warmup services.
+type warmupProbe struct {
+	Source     string `json:"source"`
+	DetailType string `json:"detail-type"`
+	Warmer     bool   `json:"warmer"`
+}
+
+// isWarmup reports whether payload is an event of a known warmup service.
+func (r Router) isWarmup(payload []byte) bool {
+	if !bytes.Contains(payload, []byte("serverless-plugin-warmup")) &&
+		!bytes.Contains(payload, []byte("Scheduled Event")) &&
+		!bytes.Contains(payload, []byte(`"warmer"`)) {
+		return false
+	}
+
+	var probe warmupProbe
+	if err := r.jsonCodec().Unmarshal(payload, &probe); err != nil {
+		return false
+	}
+
+	switch {
+	case probe.Source == "serverless-plugin-warmup", probe.Warmer:
+		return true
+	case probe.Source == "aws.events" && probe.DetailType == "Scheduled Event":
+		return !r.routesEventBridge(probe.Source, probe.DetailType)
+	}
+
+	return false
+}
+
+// routesEventBridge reports whether the router has a route for EventBridge events with source and
+// detailType.
+func (r Router) routesEventBridge(source, detailType string) bool {
+	if r.table == nil {
+		return false
+	}
+
+	r.table.mu.RLock()
+	routes := r.table.sources.eventBridge
+	r.table.mu.RUnlock()
+
+	for _, rt := range routes {
+		if matchPattern(rt.source, source) && matchPattern(rt.detailType, detailType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// warm reports whether payload is a warmup event the router should respond to.
+func (r Router) warm(payload []byte) bool {
+	if r.warmup == nil {
+		return false
+	}
+	if r.isWarmup(payload) {
+		return true
+	}
+
+	for _, c := range r.warmup.classifiers {
+		if c.Classify(payload) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,63 @@
+package lambdarouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithWarmup(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize Router with warmup handling and")
+	var logs logRecorder
+	invoked := 0
+	r := New("", WithLogger(&logs), WithWarmup(EventClassifierFunc(func(payload []byte) bool {
+		return string(payload) == `"ping"`
+	})))
+	r.EventBridge("aws.events", "Scheduled Event", lambda.NewHandler(func(e events.CloudWatchEvent) error {
+		invoked++
+		return nil
+	}))
+
+	invoke := func(payload string) events.APIGatewayProxyResponse {
+		resjson, err := r.Invoke(context.Background(), []byte(payload))
+		a.NoError(err)
+
+		var res events.APIGatewayProxyResponse
+		_ = json.Unmarshal(resjson, &res)
+		return res
+	}
+
+	desc(t, 2, "WithWarmup option should")
+	{
+		desc(t, 4, "respond to the events of known warmup services with a 200")
+		a.Exactly(http.StatusOK, invoke(`{"source": "serverless-plugin-warmup"}`).StatusCode)
+		a.Exactly(http.StatusOK, invoke(`{"warmer": true, "concurrency": 1}`).StatusCode)
+
+		desc(t, 4, "respond to the events of the classifiers given")
+		a.Exactly(http.StatusOK, invoke(`"ping"`).StatusCode)
+		a.Empty(logs)
+
+		desc(t, 4, "route scheduled events which match a route")
+		invoke(`{"source": "aws.events", "detail-type": "Scheduled Event", "detail": {}}`)
+		a.Exactly(1, invoked)
+
+		desc(t, 4, "route other requests")
+		a.Exactly(http.StatusNotFound, invoke(`{"httpMethod": "GET", "path": "/users"}`).StatusCode)
+	}
+
+	desc(t, 0, "Initialize Router with warmup handling and no routes and")
+	r = New("", WithWarmup())
+
+	desc(t, 2, "WithWarmup option should")
+	{
+		desc(t, 4, "respond to scheduled events with a 200")
+		a.Exactly(http.StatusOK, invoke(`{"source": "aws.events", "detail-type": "Scheduled Event"}`).StatusCode)
+	}
+}
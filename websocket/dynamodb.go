@@ -0,0 +1,67 @@
+package websocket
+
+import (
+	"context"
+	"time"
+
+	"github.com/mitchell/lambdarouter/internal/dynamo"
+)
+
+// connectionTTL is how long connections are kept by a DynamoDBStore, which is the longest API
+// Gateway keeps a WebSocket connection open, so that connections whose $disconnect route was not
+// invoked are eventually forgotten.
+const connectionTTL = 2 * time.Hour
+
+// DynamoDBStore is a Store which holds connections in a DynamoDB table, so that they are shared by
+// every container of a function. The table must have a string partition key named pk. Each item
+// has a ttl attribute holding the time API Gateway closes the connection at the latest, after
+// which it can be deleted by enabling time to live on the table; expired items are ignored either
+// way. Listing connections scans the whole table, so it should not hold anything else.
+type DynamoDBStore struct {
+	table  string
+	client *dynamo.Client
+}
+
+// NewDynamoDBStore returns a store holding connections in table. Requests to DynamoDB are signed
+// with the credentials of the execution role of the function, in the region it runs in.
+func NewDynamoDBStore(table string) *DynamoDBStore {
+	return &DynamoDBStore{table: table, client: dynamo.FromEnv()}
+}
+
+// Add implements the Store interface for the DynamoDBStore type.
+func (s *DynamoDBStore) Add(ctx context.Context, connectionID string) error {
+	return s.client.PutItem(ctx, s.table, dynamo.Item{
+		"pk":  dynamo.S(connectionID),
+		"ttl": dynamo.N(time.Now().Add(connectionTTL).Unix()),
+	}, "", nil, nil)
+}
+
+// Remove implements the Store interface for the DynamoDBStore type.
+func (s *DynamoDBStore) Remove(ctx context.Context, connectionID string) error {
+	return s.client.DeleteItem(ctx, s.table, dynamo.Item{"pk": dynamo.S(connectionID)})
+}
+
+// List implements the Store interface for the DynamoDBStore type.
+func (s *DynamoDBStore) List(ctx context.Context) ([]string, error) {
+	now := time.Now().Unix()
+
+	var ids []string
+	var start dynamo.Item
+	for {
+		items, last, err := s.client.Scan(ctx, s.table, start)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			if now < item["ttl"].Int() {
+				ids = append(ids, item["pk"].String())
+			}
+		}
+
+		if last == nil {
+			return ids, nil
+		}
+		start = last
+	}
+}
@@ -0,0 +1,45 @@
+package websocket
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mitchell/lambdarouter/internal/dynamo"
+	"github.com/mitchell/lambdarouter/internal/dynamotest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamoDBStore(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a fake DynamoDB and")
+	srv := dynamotest.NewServer()
+	srv.ScanLimit = 2
+	defer srv.Close()
+
+	s := NewDynamoDBStore("connections")
+	s.client = srv.Client()
+	ctx := context.Background()
+
+	desc(t, 2, "DynamoDBStore type should")
+	{
+		desc(t, 4, "list the connections added, across pages")
+		for _, id := range []string{"a", "b", "c"} {
+			a.NoError(s.Add(ctx, id))
+		}
+		ids, err := s.List(ctx)
+		a.NoError(err)
+		a.Exactly([]string{"a", "b", "c"}, ids)
+
+		desc(t, 4, "forget removed and expired connections")
+		a.NoError(s.Remove(ctx, "b"))
+		_ = s.client.PutItem(ctx, "connections", dynamo.Item{
+			"pk":  dynamo.S("d"),
+			"ttl": dynamo.N(time.Now().Add(-time.Minute).Unix()),
+		}, "", nil, nil)
+		ids, err = s.List(ctx)
+		a.NoError(err)
+		a.Exactly([]string{"a", "c"}, ids)
+	}
+}
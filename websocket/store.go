@@ -0,0 +1,67 @@
+package websocket
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Store holds the IDs of the connections of a WebSocket API, which are typically added by the
+// handler of its $connect route and removed by that of its $disconnect route.
+type Store interface {
+	// Add records the connection with the given ID.
+	Add(ctx context.Context, connectionID string) error
+
+	// Remove forgets the connection with the given ID.
+	Remove(ctx context.Context, connectionID string) error
+
+	// List returns the IDs of every connection.
+	List(ctx context.Context) ([]string, error)
+}
+
+// MemoryStore is a Store which holds connections in the memory of the container. As connections
+// are handled by every container of the function, it is only suitable for testing and functions
+// with a reserved concurrency of one.
+type MemoryStore struct {
+	mu  sync.Mutex
+	ids map[string]struct{}
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{ids: map[string]struct{}{}}
+}
+
+// Add implements the Store interface for the MemoryStore type.
+func (s *MemoryStore) Add(_ context.Context, connectionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ids[connectionID] = struct{}{}
+
+	return nil
+}
+
+// Remove implements the Store interface for the MemoryStore type.
+func (s *MemoryStore) Remove(_ context.Context, connectionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.ids, connectionID)
+
+	return nil
+}
+
+// List implements the Store interface for the MemoryStore type.
+func (s *MemoryStore) List(context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.ids))
+	for id := range s.ids {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
@@ -0,0 +1,115 @@
+// Package websocket helps build the backends of API Gateway WebSocket APIs: it sends messages to
+// connected clients through the API Gateway Management API, keeps track of connections, and
+// broadcasts messages to every connection, without the AWS SDK.
+package websocket
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/mitchell/lambdarouter/internal/sigv4"
+)
+
+// ErrGone is returned when a message is sent to a connection which is no longer connected.
+var ErrGone = errors.New("connection gone")
+
+// Conn sends requests to the connections of a WebSocket API through its API Gateway Management API
+// endpoint.
+type Conn struct {
+	// HTTPClient sends the requests. If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	endpoint    string
+	region      string
+	credentials func() sigv4.Credentials
+}
+
+// New returns a Conn for the WebSocket API whose management endpoint is endpoint, such as
+// https://abc123.execute-api.us-east-1.amazonaws.com/production, which Endpoint derives from the
+// events of the API. Requests are signed with the credentials of the execution role of the
+// function, in the region it runs in, so the role must be allowed execute-api:ManageConnections.
+func New(endpoint string) *Conn {
+	return &Conn{endpoint: endpoint, region: os.Getenv("AWS_REGION"), credentials: sigv4.EnvCredentials}
+}
+
+// Endpoint returns the management endpoint of the API which sent req. APIs reached through custom
+// domain names must be given the endpoint of their execute-api domain name instead.
+func Endpoint(req events.APIGatewayWebsocketProxyRequest) string {
+	return "https://" + req.RequestContext.DomainName + "/" + req.RequestContext.Stage
+}
+
+// Post sends payload to the client of the connection with the given ID. It returns ErrGone if the
+// client is no longer connected.
+func (c *Conn) Post(ctx context.Context, connectionID string, payload []byte) error {
+	return c.do(ctx, http.MethodPost, connectionID, payload)
+}
+
+// Disconnect closes the connection with the given ID. It returns ErrGone if the client is no
+// longer connected.
+func (c *Conn) Disconnect(ctx context.Context, connectionID string) error {
+	return c.do(ctx, http.MethodDelete, connectionID, nil)
+}
+
+func (c *Conn) do(ctx context.Context, method, connectionID string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+"/@connections/"+url.PathEscape(connectionID), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	var creds sigv4.Credentials
+	if c.credentials != nil {
+		creds = c.credentials()
+	}
+	sigv4.Sign(req, body, creds, c.region, "execute-api", time.Now().UTC())
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("websocket: %w", err)
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusGone:
+		return ErrGone
+	case res.StatusCode >= http.StatusMultipleChoices:
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("websocket: %s: %s", res.Status, bytes.TrimSpace(msg))
+	}
+
+	return nil
+}
+
+// Broadcast sends payload to every connection of s, removing those which are no longer connected
+// from s. It carries on when a message cannot be sent, and returns the first error encountered.
+func Broadcast(ctx context.Context, c *Conn, s Store, payload []byte) error {
+	ids, err := s.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	var first error
+	for _, id := range ids {
+		err := c.Post(ctx, id, payload)
+		if errors.Is(err, ErrGone) {
+			err = s.Remove(ctx, id)
+		}
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
@@ -0,0 +1,109 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/mitchell/lambdarouter/internal/sigv4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConn(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a fake management endpoint and")
+	var received []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		id := strings.TrimPrefix(r.URL.Path, "/production/@connections/")
+		received = append(received, r.Method+" "+id+" "+string(body))
+
+		switch {
+		case !validSignature(r, body):
+			w.WriteHeader(http.StatusForbidden)
+		case strings.HasPrefix(id, "gone"):
+			w.WriteHeader(http.StatusGone)
+		case strings.HasPrefix(id, "broken"):
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("internal failure"))
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL + "/production")
+	c.region = "us-east-1"
+	c.credentials = func() sigv4.Credentials { return sigv4.Credentials{AccessKeyID: "AKID"} }
+	ctx := context.Background()
+
+	desc(t, 2, "Conn type should")
+	{
+		desc(t, 4, "post messages to connections")
+		a.NoError(c.Post(ctx, "abc=", []byte("hello")))
+		a.Exactly([]string{"POST abc= hello"}, received)
+
+		desc(t, 4, "disconnect connections")
+		a.NoError(c.Disconnect(ctx, "abc="))
+		a.Exactly("DELETE abc= ", received[1])
+
+		desc(t, 4, "report connections which are gone")
+		a.ErrorIs(c.Post(ctx, "gone1", nil), ErrGone)
+
+		desc(t, 4, "report other failures")
+		a.EqualError(c.Post(ctx, "broken1", nil), "websocket: 500 Internal Server Error: internal failure")
+	}
+
+	desc(t, 2, "Broadcast function should")
+	{
+		desc(t, 4, "post to every connection and remove those which are gone")
+		s := NewMemoryStore()
+		for _, id := range []string{"a", "broken1", "gone1", "z"} {
+			a.NoError(s.Add(ctx, id))
+		}
+
+		received = nil
+		err := Broadcast(ctx, c, s, []byte("news"))
+		a.EqualError(err, "websocket: 500 Internal Server Error: internal failure")
+		a.Len(received, 4)
+
+		ids, _ := s.List(ctx)
+		a.Exactly([]string{"a", "broken1", "z"}, ids)
+	}
+
+	desc(t, 2, "Endpoint function should")
+	{
+		desc(t, 4, "derive the endpoint from the domain name and stage")
+		var req events.APIGatewayWebsocketProxyRequest
+		req.RequestContext.DomainName = "abc123.execute-api.us-east-1.amazonaws.com"
+		req.RequestContext.Stage = "production"
+		a.Exactly("https://abc123.execute-api.us-east-1.amazonaws.com/production", Endpoint(req))
+	}
+}
+
+// validSignature signs the request the server received again, as API Gateway would, from the path
+// it was sent with, and reports whether its signature matches the one it was sent with.
+func validSignature(r *http.Request, body []byte) bool {
+	now, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return false
+	}
+
+	req, _ := http.NewRequest(r.Method, "http://"+r.Host+r.RequestURI, nil)
+	sigv4.Sign(req, body, sigv4.Credentials{AccessKeyID: "AKID"}, "us-east-1", "execute-api", now)
+
+	return req.Header.Get("Authorization") == r.Header.Get("Authorization")
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}
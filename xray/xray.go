@@ -0,0 +1,228 @@
+// Package xray provides middleware which records a subsegment of the AWS X-Ray trace of an
+// invocation for each route it handles, named by the template of the route rather than the path
+// requested, so traces show which logical route was slow. Subsegments are sent to the X-Ray daemon
+// Lambda runs alongside functions with active tracing enabled, without the X-Ray SDK.
+package xray
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+)
+
+// Config configures the X-Ray middleware.
+type Config struct {
+	// DaemonAddress is the UDP address of the X-Ray daemon. If empty, it is taken from the
+	// AWS_XRAY_DAEMON_ADDRESS environment variable, which Lambda sets, or is 127.0.0.1:2000.
+	DaemonAddress string
+}
+
+// Middleware returns middleware which records a subsegment for each invocation of a route whose
+// trace is sampled. The subsegment is named by the method and template of the route, such as
+// GET /users/{id}, and annotated with the method, route, status, and path parameters of the
+// request, so traces can be searched by them. Errors returned by the handler, and responses with
+// a 4xx or 5xx status, mark the subsegment as an error or a fault. Invocations whose trace is not
+// sampled, or which were not made by the Lambda runtime, are not recorded. Subsegments which
+// cannot be sent are dropped, as tracing never fails an invocation.
+func Middleware(cfg Config) lambdarouter.Middleware {
+	if cfg.DaemonAddress == "" {
+		cfg.DaemonAddress = os.Getenv("AWS_XRAY_DAEMON_ADDRESS")
+	}
+	if cfg.DaemonAddress == "" {
+		cfg.DaemonAddress = "127.0.0.1:2000"
+	}
+
+	e := &emitter{addr: cfg.DaemonAddress}
+
+	return func(next lambda.Handler) lambda.Handler {
+		return tracer{e: e, next: next}
+	}
+}
+
+type tracer struct {
+	e    *emitter
+	next lambda.Handler
+}
+
+func (t tracer) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	traceID, parentID, sampled := traceHeader(ctx)
+	if !sampled {
+		return t.next.Invoke(ctx, payload)
+	}
+
+	start := time.Now()
+	res, err := t.next.Invoke(ctx, payload)
+	end := time.Now()
+
+	seg := segment{
+		Name:        "lambdarouter",
+		ID:          newID(),
+		TraceID:     traceID,
+		ParentID:    parentID,
+		Type:        "subsegment",
+		StartTime:   epoch(start),
+		EndTime:     epoch(end),
+		Annotations: map[string]interface{}{},
+	}
+
+	if rt, ok := lambdarouter.RouteFromContext(ctx); ok {
+		seg.Name = rt.Method + " " + rt.Path
+		seg.Annotations["method"] = rt.Method
+		seg.Annotations["route"] = rt.Path
+		seg.HTTP.Request.Method = rt.Method
+	}
+	if req, ok := lambdarouter.RequestFromContext(ctx); ok {
+		seg.HTTP.Request.URL = req.Path
+		for name, value := range req.PathParameters {
+			seg.Annotations["param_"+annotationKey(name)] = value
+		}
+	}
+
+	if err != nil {
+		seg.Fault = true
+		seg.Cause = &cause{Exceptions: []exception{{ID: newID(), Message: err.Error()}}}
+
+		var httpErr *lambdarouter.HTTPError
+		if errors.As(err, &httpErr) {
+			seg.setStatus(httpErr.Status)
+		}
+	} else {
+		var out struct {
+			StatusCode int `json:"statusCode"`
+		}
+		if lambdarouter.CodecFrom(ctx).Unmarshal(res, &out) == nil && out.StatusCode != 0 {
+			seg.setStatus(out.StatusCode)
+		}
+	}
+
+	t.e.emit(seg)
+
+	return res, err
+}
+
+// traceHeader returns the IDs of the trace and of the segment of the current invocation, from the
+// trace header the Lambda runtime places in ctx, and whether the trace is sampled.
+func traceHeader(ctx context.Context) (traceID, parentID string, sampled bool) {
+	header, _ := ctx.Value("x-amzn-trace-id").(string)
+
+	for _, part := range strings.Split(header, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "Root":
+			traceID = value
+		case "Parent":
+			parentID = value
+		case "Sampled":
+			sampled = value == "1"
+		}
+	}
+
+	return traceID, parentID, sampled && traceID != "" && parentID != ""
+}
+
+// segment is the document of a subsegment, in the format the X-Ray daemon accepts.
+type segment struct {
+	Name        string                 `json:"name"`
+	ID          string                 `json:"id"`
+	TraceID     string                 `json:"trace_id"`
+	ParentID    string                 `json:"parent_id"`
+	Type        string                 `json:"type"`
+	StartTime   float64                `json:"start_time"`
+	EndTime     float64                `json:"end_time"`
+	Annotations map[string]interface{} `json:"annotations,omitempty"`
+	HTTP        struct {
+		Request struct {
+			Method string `json:"method,omitempty"`
+			URL    string `json:"url,omitempty"`
+		} `json:"request"`
+		Response struct {
+			Status int `json:"status,omitempty"`
+		} `json:"response"`
+	} `json:"http"`
+	Error    bool   `json:"error,omitempty"`
+	Throttle bool   `json:"throttle,omitempty"`
+	Fault    bool   `json:"fault,omitempty"`
+	Cause    *cause `json:"cause,omitempty"`
+}
+
+type cause struct {
+	Exceptions []exception `json:"exceptions"`
+}
+
+type exception struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+}
+
+// setStatus records the status of the response, marking the subsegment as an error for client
+// errors and a fault for server errors.
+func (s *segment) setStatus(status int) {
+	s.HTTP.Response.Status = status
+	s.Annotations["status"] = status
+
+	switch {
+	case status == http.StatusTooManyRequests:
+		s.Error, s.Throttle, s.Fault = true, true, false
+	case status >= 500:
+		s.Fault = true
+	case status >= 400:
+		s.Error, s.Fault = true, false
+	}
+}
+
+// emitter sends segments to the X-Ray daemon over UDP.
+type emitter struct {
+	addr string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func (e *emitter) emit(seg segment) {
+	doc, err := json.Marshal(seg)
+	if err != nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn == nil {
+		conn, err := net.Dial("udp", e.addr)
+		if err != nil {
+			return
+		}
+		e.conn = conn
+	}
+
+	_, _ = e.conn.Write(append([]byte("{\"format\": \"json\", \"version\": 1}\n"), doc...))
+}
+
+var nonAnnotation = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// annotationKey replaces the characters X-Ray does not allow in the keys of annotations.
+func annotationKey(name string) string {
+	return nonAnnotation.ReplaceAllString(name, "_")
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func epoch(t time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Second)
+}
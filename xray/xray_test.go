@@ -0,0 +1,97 @@
+package xray
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mitchell/lambdarouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	desc(t, 0, "Initialize a fake X-Ray daemon, Router with X-Ray middleware, and")
+	daemon, err := net.ListenPacket("udp", "127.0.0.1:0")
+	a.NoError(err)
+	defer daemon.Close()
+
+	r := lambdarouter.New("prefix")
+	r.Use(Middleware(Config{DaemonAddress: daemon.LocalAddr().String()}))
+	r.Get("users/{user-id}", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}))
+	r.Get("missing", lambda.NewHandler(func() (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{}, &lambdarouter.HTTPError{Status: http.StatusNotFound}
+	}))
+
+	invoke := func(path, header string) {
+		payload, _ := json.Marshal(events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: path})
+		ctx := context.WithValue(context.Background(), "x-amzn-trace-id", header)
+		_, err := r.Invoke(ctx, payload)
+		a.NoError(err)
+	}
+	receive := func() (map[string]interface{}, bool) {
+		buf := make([]byte, 4096)
+		_ = daemon.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		n, _, err := daemon.ReadFrom(buf)
+		if err != nil {
+			return nil, false
+		}
+
+		header, doc, _ := strings.Cut(string(buf[:n]), "\n")
+		a.Exactly(`{"format": "json", "version": 1}`, header)
+
+		var seg map[string]interface{}
+		a.NoError(json.Unmarshal([]byte(doc), &seg))
+		return seg, true
+	}
+
+	desc(t, 2, "Middleware should")
+	{
+		desc(t, 4, "record a subsegment named by the route of sampled invocations")
+		invoke("/prefix/users/42", "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1")
+		seg, ok := receive()
+		a.True(ok)
+		a.Exactly("GET /prefix/users/{user-id}", seg["name"])
+		a.Exactly("1-5759e988-bd862e3fe1be46a994272793", seg["trace_id"])
+		a.Exactly("53995c3f42cd8ad8", seg["parent_id"])
+		a.Exactly("subsegment", seg["type"])
+		a.Exactly(map[string]interface{}{
+			"method":        "GET",
+			"route":         "/prefix/users/{user-id}",
+			"status":        float64(200),
+			"param_user_id": "42",
+		}, seg["annotations"])
+		a.Nil(seg["fault"])
+
+		desc(t, 4, "mark client errors as errors")
+		invoke("/prefix/missing", "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1")
+		seg, ok = receive()
+		a.True(ok)
+		a.Exactly(true, seg["error"])
+		a.Nil(seg["fault"])
+		a.NotNil(seg["cause"])
+
+		desc(t, 4, "not record invocations which are not sampled")
+		invoke("/prefix/users/42", "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=0")
+		_, ok = receive()
+		a.False(ok)
+	}
+}
+
+func desc(t *testing.T, depth int, str string, args ...interface{}) {
+	for i := 0; i < depth; i++ {
+		str = " " + str
+	}
+
+	t.Log(fmt.Sprintf(str, args...))
+}